@@ -25,6 +25,10 @@ type ApplicationLayerSpec struct {
 	// WebApplicationFirewall controls whether or not ModSecurity enforcement is enabled for the cluster.
 	// When enabled, Services may opt-in to having ingress traffic examed by ModSecurity.
 	WebApplicationFirewall *WAFStatusType `json:"webApplicationFirewall,omitempty"`
+	// WebApplicationFirewallSettings tunes the ModSecurity rule engine used by the Web Application Firewall.
+	// It is used when either WebApplicationFirewall or SidecarInjection is enabled.
+	// +optional
+	WebApplicationFirewallSettings *WAFSettings `json:"webApplicationFirewallSettings,omitempty"`
 	// Specification for application layer (L7) log collection.
 	LogCollection *LogCollectionSpec `json:"logCollection,omitempty"`
 	// Application Layer Policy controls whether or not ALP enforcement is enabled for the cluster.
@@ -74,6 +78,90 @@ const (
 	SidecarWebhookStateDisabled    SidecarWebhookStateType          = "Disabled"
 )
 
+// +kubebuilder:validation:Enum=DetectionOnly;Blocking
+type WAFMode string
+
+const (
+	WAFModeDetectionOnly WAFMode = "DetectionOnly"
+	WAFModeBlocking      WAFMode = "Blocking"
+)
+
+type WAFSettings struct {
+	// Mode controls whether requests matching the ruleset are only logged (DetectionOnly) or also
+	// rejected (Blocking). It is written to the ruleset as the SecRuleEngine directive. When neither Mode
+	// nor NamespaceModes is set, the SecRuleEngine directive of the ruleset applies.
+	// Default: DetectionOnly
+	// +optional
+	Mode *WAFMode `json:"mode,omitempty"`
+
+	// ParanoiaLevel sets the OWASP Core Rule Set paranoia level. Higher levels enable more rules,
+	// at the cost of more false positives.
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4
+	// +optional
+	ParanoiaLevel *int32 `json:"paranoiaLevel,omitempty"`
+
+	// InboundAnomalyScoreThreshold is the anomaly score at which a request is considered malicious.
+	// Default: 5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	InboundAnomalyScoreThreshold *int32 `json:"inboundAnomalyScoreThreshold,omitempty"`
+
+	// OutboundAnomalyScoreThreshold is the anomaly score at which a response is considered malicious.
+	// Default: 4
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	OutboundAnomalyScoreThreshold *int32 `json:"outboundAnomalyScoreThreshold,omitempty"`
+
+	// RulePacks lists ConfigMaps in the tigera-operator namespace that contain additional ruleset files.
+	// Every key ending in ".conf" is loaded after the core rule set, in the order the packs are listed.
+	// +optional
+	RulePacks []WAFRulePack `json:"rulePacks,omitempty"`
+
+	// RuleExclusions disables rules, either everywhere or only for requests to the given hosts or paths.
+	// +optional
+	RuleExclusions []WAFRuleExclusion `json:"ruleExclusions,omitempty"`
+
+	// NamespaceModes overrides Mode for requests to the services of the given namespaces. The namespace is
+	// taken from the Host header, so the override only applies to requests that address a service by its DNS
+	// name including the namespace, such as <service>.<namespace> or <service>.<namespace>.svc.
+	// As the client sets the Host header, a namespace can only make the mode stricter than Mode: namespaces can be
+	// set to Blocking while Mode is DetectionOnly, but not the other way around.
+	// +optional
+	NamespaceModes []WAFNamespaceMode `json:"namespaceModes,omitempty"`
+}
+
+type WAFRulePack struct {
+	// ConfigMapName is the name of a ConfigMap in the tigera-operator namespace holding the ruleset files.
+	// The ConfigMap must have the label operator.tigera.io/waf-rule-pack=true.
+	// +kubebuilder:validation:MinLength=1
+	ConfigMapName string `json:"configMapName"`
+}
+
+type WAFRuleExclusion struct {
+	// RuleIDs are the IDs of the rules to disable.
+	// +kubebuilder:validation:MinItems=1
+	RuleIDs []int32 `json:"ruleIDs"`
+
+	// Hosts restricts the exclusion to requests whose Host header matches one of the given hosts.
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+
+	// PathPrefixes restricts the exclusion to requests whose path starts with one of the given prefixes.
+	// +optional
+	PathPrefixes []string `json:"pathPrefixes,omitempty"`
+}
+
+type WAFNamespaceMode struct {
+	// Namespace is the name of the namespace the mode applies to.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Mode is the WAF mode for requests to the services of the namespace.
+	Mode WAFMode `json:"mode"`
+}
+
 type EnvoySettings struct {
 	// The number of additional ingress proxy hops from the right side of the
 	// x-forwarded-for HTTP header to trust when determining the origin client’s
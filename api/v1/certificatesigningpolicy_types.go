@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=ServerAuth;ClientAuth
+type CertificateKeyUsage string
+
+const (
+	CertificateKeyUsageServerAuth CertificateKeyUsage = "ServerAuth"
+	CertificateKeyUsageClientAuth CertificateKeyUsage = "ClientAuth"
+)
+
+// CertificateSigningPolicySpec declares a TLS asset that pods in the given namespace may obtain a certificate
+// for, by submitting a certificate signing request for the tigera.io/operator-signer.
+type CertificateSigningPolicySpec struct {
+	// Namespace is the namespace of the pods that may request the certificate.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// SecretNamePattern is matched against the secret name of the certificate signing request. The request
+	// must be named <secret name>:<pod name> and carry the operator.tigera.io/csr label. The pattern may
+	// use shell glob syntax, for example "my-app-*-tls". A policy with an invalid pattern is rejected. Secret
+	// names of Calico components cannot be claimed by a policy.
+	// +kubebuilder:validation:MinLength=1
+	SecretNamePattern string `json:"secretNamePattern"`
+
+	// ServiceAccountName is the service account, in the namespace, that must make the request.
+	// If omitted, any service account in the namespace may make the request, provided that it is allowed to
+	// create the certificatesigningrequests/common-name subresource in the certificates.tigera.io API group,
+	// in the namespace, for the common name that it requests.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// DNSNames lists the DNS names that may be requested, including the common name. Each label of a name may
+	// use shell glob syntax, and is matched against the label at the same position of the requested name, so a
+	// wildcard never matches across a ".": "*.my-app.svc" matches "api.my-app.svc", but not "a.b.my-app.svc" or
+	// "my-app.svc". Names are compared case-insensitively. A policy with an invalid pattern is rejected. Names
+	// that Calico components trust, such as typha-server, typha-client and the service names in the
+	// calico-system and tigera-* namespaces, are never issued.
+	// +kubebuilder:validation:MinItems=1
+	DNSNames []string `json:"dnsNames"`
+
+	// IPAddresses lists the CIDRs that requested IP addresses must belong to. If omitted, the request may only
+	// contain the IP address of the requesting pod.
+	// +optional
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+
+	// KeyUsages lists the extended key usages of the issued certificate.
+	// Default: ServerAuth, ClientAuth
+	// +optional
+	KeyUsages []CertificateKeyUsage `json:"keyUsages,omitempty"`
+
+	// MaxLifetime is the maximum lifetime of the issued certificate. A request with a shorter
+	// expirationSeconds is issued a certificate with that lifetime instead.
+	// Default: the lifetime of the certificates that the operator issues to Calico components.
+	// +optional
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// CertificateSigningPolicy allows pods that are not part of Calico to obtain certificates from the
+// tigera.io/operator-signer, for example to set up mTLS between workloads. The certificate signing
+// requests are validated in the same way as those of Calico components. The resource is cluster scoped,
+// so that only cluster administrators can allow certificates to be issued.
+type CertificateSigningPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CertificateSigningPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CertificateSigningPolicyList contains a list of CertificateSigningPolicy
+type CertificateSigningPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateSigningPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateSigningPolicy{}, &CertificateSigningPolicyList{})
+}
@@ -42,6 +42,54 @@ type ComplianceSpec struct {
 	// ComplianceReporterPodTemplate configures the Compliance Reporter PodTemplate.
 	// +optional
 	ComplianceReporterPodTemplate *ComplianceReporterPodTemplate `json:"complianceReporterPodTemplate,omitempty"`
+
+	// CustomReportTypes lists ConfigMaps in the tigera-operator namespace that contain additional GlobalReportTypes.
+	// Every key ending in ".yaml" must hold a GlobalReportType manifest. The names of the report types that are
+	// installed by the operator cannot be used.
+	// +optional
+	CustomReportTypes []ComplianceReportTypeSource `json:"customReportTypes,omitempty"`
+
+	// Reports lists GlobalReports that the operator creates and keeps up to date. GlobalReports that were created
+	// from this list are removed when they are no longer listed.
+	// +optional
+	Reports []ComplianceReport `json:"reports,omitempty"`
+}
+
+type ComplianceReportTypeSource struct {
+	// ConfigMapName is the name of a ConfigMap in the tigera-operator namespace holding GlobalReportTypes.
+	// The ConfigMap must have the label operator.tigera.io/compliance-report-types=true.
+	// +kubebuilder:validation:MinLength=1
+	ConfigMapName string `json:"configMapName"`
+}
+
+type ComplianceReport struct {
+	// Name is the name of the GlobalReport.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ReportType is the name of the GlobalReportType to run. This may be one of the report types that are installed
+	// by the operator or one of the CustomReportTypes.
+	// +kubebuilder:validation:MinLength=1
+	ReportType string `json:"reportType"`
+
+	// Schedule is the cron schedule of the report, for example "0 0 * * *" for a daily report. The schedule may
+	// not be more frequent than twice an hour.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// EndpointSelector selects the endpoints that are in scope of the report by their labels. If omitted, all
+	// endpoints are in scope.
+	// +optional
+	EndpointSelector string `json:"endpointSelector,omitempty"`
+
+	// Namespaces restricts the endpoints that are in scope of the report to those in the listed namespaces.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector restricts the endpoints that are in scope of the report to those in namespaces with
+	// matching labels.
+	// +optional
+	NamespaceSelector string `json:"namespaceSelector,omitempty"`
 }
 
 // ComplianceStatus defines the observed state of Tigera compliance reporting capabilities.
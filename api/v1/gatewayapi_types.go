@@ -127,6 +127,17 @@ type GatewayClassSpec struct {
 	// Allows customization of gateway services, for Gateways in this GatewayClass.
 	// +optional
 	GatewayService *GatewayService `json:"gatewayService,omitempty"`
+
+	// Configures access logs and metrics for Gateways in this GatewayClass.  When access logs
+	// are configured here, they replace any access log settings in the custom EnvoyProxy.
+	// +optional
+	Telemetry *GatewayTelemetry `json:"telemetry,omitempty"`
+
+	// When specified, requests to Gateways in this GatewayClass are inspected by the Web
+	// Application Firewall, using the Coraza ruleset that is also used by the ApplicationLayer.
+	// This is only supported with Calico Enterprise, for Gateways deployed as Deployments.
+	// +optional
+	WebApplicationFirewall *GatewayWebApplicationFirewall `json:"webApplicationFirewall,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -468,3 +479,88 @@ type GatewayServiceSpec struct {
 	// +optional
 	LoadBalancerIP *string `json:"loadBalancerIP,omitempty"`
 }
+
+// GatewayTelemetry configures access logs and metrics for Gateways.
+type GatewayTelemetry struct {
+	// +optional
+	AccessLogs *GatewayAccessLogs `json:"accessLogs,omitempty"`
+
+	// +optional
+	Metrics *GatewayMetrics `json:"metrics,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=JSON;Text
+type GatewayAccessLogFormat string
+
+const (
+	GatewayAccessLogFormatJSON GatewayAccessLogFormat = "JSON"
+	GatewayAccessLogFormatText GatewayAccessLogFormat = "Text"
+)
+
+// GatewayAccessLogs configures the Envoy access logs of Gateways.
+//
+// With Calico Enterprise, access logs of Gateways deployed as Deployments are also forwarded to
+// the L7 log collector, so that they appear in the L7 logs alongside those collected by the
+// ApplicationLayer.  Those logs always use the format that the collector requires.
+type GatewayAccessLogs struct {
+	// Format of the access logs that Gateways write to standard output.  When not specified,
+	// the operator does not configure access logs to standard output.
+	// +optional
+	Format *GatewayAccessLogFormat `json:"format,omitempty"`
+
+	// Envoy format string for access logs when Format is Text, for example
+	// "[%START_TIME%] %REQ(:METHOD)% %REQ(:PATH)% %RESPONSE_CODE%\n".  When not specified, the
+	// Envoy default format is used.
+	// +optional
+	Text *string `json:"text,omitempty"`
+
+	// Fields of access logs when Format is JSON, mapping each field name to an Envoy command
+	// operator such as "%RESPONSE_CODE%".  When not specified, the same fields as the L7 logs
+	// are written.
+	// +optional
+	JSON map[string]string `json:"json,omitempty"`
+
+	// Percentage of requests for which an access log is written, from 1 to 100.  Requests are
+	// sampled by request ID, so a sampled request is logged both to standard output and to the
+	// L7 log collector.  Default: 100
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	SamplingPercentage *int32 `json:"samplingPercentage,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type GatewayServiceMonitorType string
+
+const (
+	GatewayServiceMonitorEnabled  GatewayServiceMonitorType = "Enabled"
+	GatewayServiceMonitorDisabled GatewayServiceMonitorType = "Disabled"
+)
+
+// GatewayMetrics configures the scraping of Envoy metrics from Gateways.
+type GatewayMetrics struct {
+	// Whether to create a ServiceMonitor in the tigera-prometheus namespace, so that the
+	// Prometheus instance of the Monitor resource scrapes the Gateways of this class.  This has
+	// no effect when the Monitor resource does not exist.  Only Gateways in the tigera-gateway
+	// namespace are scraped.  Default: Enabled
+	// +optional
+	ServiceMonitor *GatewayServiceMonitorType `json:"serviceMonitor,omitempty"`
+
+	// Interval at which Prometheus scrapes the Gateways, for example "30s".  Default: 30s
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	// +optional
+	ScrapeInterval *string `json:"scrapeInterval,omitempty"`
+}
+
+// GatewayWebApplicationFirewall configures the Web Application Firewall for Gateways.
+type GatewayWebApplicationFirewall struct {
+	// Whether requests that match the ruleset are only logged (DetectionOnly) or are also
+	// rejected (Blocking).  Default: DetectionOnly
+	// +optional
+	Mode *WAFMode `json:"mode,omitempty"`
+
+	// Whether requests are allowed when the Web Application Firewall cannot be reached.
+	// Default: false
+	// +optional
+	FailOpen *bool `json:"failOpen,omitempty"`
+}
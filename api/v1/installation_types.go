@@ -24,6 +24,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // InstallationSpec defines configuration for a Calico or Calico Enterprise installation.
@@ -140,6 +141,16 @@ type InstallationSpec struct {
 	// +optional
 	NodeUpdateStrategy appsv1.DaemonSetUpdateStrategy `json:"nodeUpdateStrategy,omitempty"`
 
+	// NodeRollout configures the operator to roll out updates to calico-node itself, starting with a canary group of
+	// nodes and then proceeding in batches, optionally only within maintenance windows. When set, the calico-node
+	// DaemonSet uses the OnDelete update strategy and NodeUpdateStrategy is ignored.
+	// +optional
+	NodeRollout *CalicoNodeRollout `json:"nodeRollout,omitempty"`
+
+	// FelixOverrides sets Felix configuration from the Installation, both cluster-wide and for groups of nodes.
+	// +optional
+	FelixOverrides *FelixOverrides `json:"felixOverrides,omitempty"`
+
 	// Deprecated. Please use CalicoNodeDaemonSet, TyphaDeployment, and KubeControllersDeployment.
 	// ComponentResources can be used to customize the resource requirements for each component.
 	// Node, Typha, and KubeControllers are supported for installations.
@@ -165,6 +176,14 @@ type InstallationSpec struct {
 	// +optional
 	CalicoNodeDaemonSet *CalicoNodeDaemonSet `json:"calicoNodeDaemonSet,omitempty"`
 
+	// CalicoNodeGroups run calico-node with different settings on groups of Linux nodes. Each group gets its own
+	// calico-node DaemonSet that only runs on the nodes selected by the group, while the default calico-node DaemonSet
+	// runs on all other nodes. The node selectors of the groups must not overlap.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	CalicoNodeGroups []CalicoNodeGroup `json:"calicoNodeGroups,omitempty"`
+
 	// CSINodeDriverDaemonSet configures the csi-node-driver DaemonSet.
 	// +optional
 	CSINodeDriverDaemonSet *CSINodeDriverDaemonSet `json:"csiNodeDriverDaemonSet,omitempty"`
@@ -214,6 +233,47 @@ type InstallationSpec struct {
 	// the cluster (including the API server) are exempt from proxying.
 	// +optional
 	Proxy *Proxy `json:"proxy,omitempty"`
+
+	// ComponentPolicyRollout controls how the allow-tigera tier policies rendered for each component are rolled out.
+	// When set to Staged, new or changed policies are first created as StagedNetworkPolicies and only promoted to
+	// enforced policies once flow logs show no would-be denied traffic for the soak period. In clusters without
+	// access to flow logs, such as managed clusters, staged policies cannot be verified and are only promoted once
+	// the soak period has elapsed if PromoteUnverified is set.
+	// +optional
+	ComponentPolicyRollout *ComponentPolicyRollout `json:"componentPolicyRollout,omitempty"`
+}
+
+// ComponentPolicyRolloutMode is the mode used to roll out allow-tigera tier policies.
+// +kubebuilder:validation:Enum=Enforced;Staged
+type ComponentPolicyRolloutMode string
+
+const (
+	ComponentPolicyRolloutEnforced ComponentPolicyRolloutMode = "Enforced"
+	ComponentPolicyRolloutStaged   ComponentPolicyRolloutMode = "Staged"
+)
+
+type ComponentPolicyRollout struct {
+	// Mode controls whether allow-tigera tier policies are enforced immediately or staged first.
+	// Default: Enforced
+	// +optional
+	Mode *ComponentPolicyRolloutMode `json:"mode,omitempty"`
+
+	// SoakPeriod is how long a staged policy must be in place, without any would-be denied flows, before it is
+	// promoted to an enforced policy.
+	// Default: 24h
+	// +optional
+	SoakPeriod *metav1.Duration `json:"soakPeriod,omitempty"`
+
+	// PromoteUnverified allows staged policies to be promoted once the soak period has elapsed when flow logs are
+	// not available to verify them. Otherwise such policies stay staged and are reported in the TigeraStatus.
+	// Default: false
+	// +optional
+	PromoteUnverified *bool `json:"promoteUnverified,omitempty"`
+}
+
+// StagedRolloutEnabled returns true if allow-tigera tier policies should be staged before they are enforced.
+func (r *ComponentPolicyRollout) StagedRolloutEnabled() bool {
+	return r != nil && r.Mode != nil && *r.Mode == ComponentPolicyRolloutStaged
 }
 
 // BPFNetworkBootstrapType defines how the initial networking configuration is executed.
@@ -485,6 +545,48 @@ var HostPortsTypesString []string = []string{
 	HostPortsDisabled.String(),
 }
 
+// MTUDetectionType specifies whether the operator detects the pod network MTU.
+//
+// One of: Disabled, Report, Enforce
+type MTUDetectionType string
+
+const (
+	MTUDetectionDisabled MTUDetectionType = "Disabled"
+	MTUDetectionReport   MTUDetectionType = "Report"
+	MTUDetectionEnforce  MTUDetectionType = "Enforce"
+)
+
+// WireGuardOption specifies whether a WireGuard setting is enabled.
+//
+// One of: Enabled, Disabled
+type WireGuardOption string
+
+const (
+	WireGuardEnabled  WireGuardOption = "Enabled"
+	WireGuardDisabled WireGuardOption = "Disabled"
+)
+
+// EncryptionSpec configures WireGuard encryption of traffic between nodes. Each setting that is not specified is left
+// as it is configured in the default FelixConfiguration.
+type EncryptionSpec struct {
+	// WireGuardIPv4 controls WireGuard encryption of IPv4 pod traffic between nodes.
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	WireGuardIPv4 *WireGuardOption `json:"wireGuardIPv4,omitempty"`
+
+	// WireGuardIPv6 controls WireGuard encryption of IPv6 pod traffic between nodes.
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	WireGuardIPv6 *WireGuardOption `json:"wireGuardIPv6,omitempty"`
+
+	// HostEncryption controls whether host-to-host traffic is also encrypted with WireGuard. Requires WireGuardIPv4
+	// to be enabled. It can't be disabled on AKS with the AzureVNET CNI or on EKS with the AmazonVPC CNI, which always
+	// use host encryption.
+	// +optional
+	// +kubebuilder:validation:Enum=Enabled;Disabled
+	HostEncryption *WireGuardOption `json:"hostEncryption,omitempty"`
+}
+
 // MultiInterfaceMode describes the method of providing multiple pod interfaces.
 //
 // One of: None, Multus
@@ -521,6 +623,235 @@ const (
 	BGPDisabled BGPOption = "Disabled"
 )
 
+// FelixOverrides sets Felix configuration from the Installation.
+//
+// Settings are keyed by FelixConfiguration spec field name, such as logSeverityScreen, and their values are parsed as
+// the type of the field: for example "Debug", "true", "1440", "30s" or a JSON list or object. The operator writes the
+// settings to the FelixConfiguration and, if the same field is later changed on the FelixConfiguration directly,
+// reports a conflict instead of overwriting it. Removing a setting from the Installation unsets the field.
+//
+// The following fields are owned by the operator and cannot be set here:
+//   - bpfEnabled and nftablesMode, which follow spec.calicoNetwork.linuxDataplane.
+//   - ipipEnabled and vxlanEnabled, which the operator manages while changing IP pool encapsulation.
+//   - wireguardEnabled, wireguardEnabledV6 and wireguardHostEncryptionEnabled, when set in spec.calicoNetwork.encryption.
+//   - policySyncPathPrefix, tproxyMode and wafEventLogsFileEnabled, which are managed by the ApplicationLayer,
+//     EgressGateway and GatewayAPI features.
+//   - captureMaxSizeBytes and captureRotationSeconds, when spec.storage is set in the PacketCaptureAPI.
+//   - Fields that calico-node may set through FELIX_* environment variables, which take precedence over the
+//     FelixConfiguration. These include defaultEndpointToHostAction, healthEnabled, healthPort, interfacePrefix,
+//     ipv6Support, routeSource, prometheusMetricsEnabled, prometheusMetricsPort and the vxlanMTU, ipipMTU and
+//     wireguardMTU fields.
+//
+// All other fields are owned by the user. This includes fields for which the operator only sets a default when they
+// are not set, such as vxlanVNI, vxlanPort and routeTableRange.
+type FelixOverrides struct {
+	// Settings are written to the default FelixConfiguration.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// NodeOverrides are written to a FelixConfiguration named node.<node name> for each node that they select. A node
+	// may be selected by more than one entry, as long as they do not set the same field to different values.
+	// +optional
+	NodeOverrides []FelixNodeOverride `json:"nodeOverrides,omitempty"`
+}
+
+// FelixNodeOverride sets Felix configuration for the nodes matching a label selector.
+type FelixNodeOverride struct {
+	// NodeSelector selects the nodes that the settings apply to.
+	NodeSelector metav1.LabelSelector `json:"nodeSelector"`
+
+	// Settings are written to the per-node FelixConfiguration of each selected node.
+	Settings map[string]string `json:"settings"`
+}
+
+// CalicoNodeGroup configures calico-node for a group of nodes.
+type CalicoNodeGroup struct {
+	// Name identifies the group. The calico-node DaemonSet of the group is named calico-node-<name>, so names that
+	// would collide with other calico-node objects, such as windows, are not allowed.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes in the group by their labels. Two groups must not be able to select the same
+	// node, so each pair of groups must require a different value for at least one label. The default calico-node
+	// DaemonSet excludes every combination of one label per group, and at most 64 combinations are allowed.
+	// +kubebuilder:validation:MinProperties=1
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// CalicoNodeDaemonSet configures the calico-node DaemonSet of the group. These settings are applied on top of
+	// spec.calicoNodeDaemonSet.
+	// +optional
+	CalicoNodeDaemonSet *CalicoNodeGroupDaemonSet `json:"calicoNodeDaemonSet,omitempty"`
+
+	// NodeAddressAutodetectionV4 replaces spec.calicoNetwork.nodeAddressAutodetectionV4 for the nodes in the group.
+	// It can only be set if IPv4 address autodetection is enabled for the cluster.
+	// +optional
+	NodeAddressAutodetectionV4 *NodeAddressAutodetection `json:"nodeAddressAutodetectionV4,omitempty"`
+
+	// NodeAddressAutodetectionV6 replaces spec.calicoNetwork.nodeAddressAutodetectionV6 for the nodes in the group.
+	// It can only be set if IPv6 address autodetection is enabled for the cluster.
+	// +optional
+	NodeAddressAutodetectionV6 *NodeAddressAutodetection `json:"nodeAddressAutodetectionV6,omitempty"`
+}
+
+// CalicoNodeGroupDaemonSet configures the calico-node DaemonSet of a node group. Scheduling is controlled by the
+// nodeSelector of the group, so only the resources, environment and tolerations of calico-node can be changed.
+type CalicoNodeGroupDaemonSet struct {
+	// Resources replaces the compute resources of the calico-node container.
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Env sets environment variables on the calico-node container. Variables that are already set are replaced.
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+
+	// Tolerations replaces the tolerations of the calico-node pods of the group.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+}
+
+// CalicoNodeRollout configures how the operator rolls out updates to calico-node.
+type CalicoNodeRollout struct {
+	// CanaryNodeSelector selects the nodes that are updated first. The rollout only proceeds to the remaining nodes
+	// once calico-node is ready on all of the canary nodes and the soak period has passed.
+	// +optional
+	CanaryNodeSelector *metav1.LabelSelector `json:"canaryNodeSelector,omitempty"`
+
+	// BatchSize is the number or percentage of calico-node pods that are updated at a time, including on the canary
+	// nodes. Percentages are rounded up.
+	// Default: 1
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	BatchSize *intstr.IntOrString `json:"batchSize,omitempty"`
+
+	// SoakPeriod is how long calico-node must have been ready on all updated nodes before the next batch is updated.
+	// Default: 5m
+	// +optional
+	SoakPeriod *metav1.Duration `json:"soakPeriod,omitempty"`
+
+	// BatchTimeout is how long updated calico-node pods may be not ready before the rollout is reported as degraded.
+	// The rollout is paused while any updated calico-node pod is not ready.
+	// Default: 10m
+	// +optional
+	BatchTimeout *metav1.Duration `json:"batchTimeout,omitempty"`
+
+	// MaintenanceWindows restricts the times at which batches are started. A batch that has already started is not
+	// interrupted when its window ends. If not specified, batches can start at any time.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// MaintenanceWindow is a recurring period of time in which calico-node updates may start.
+type MaintenanceWindow struct {
+	// Days are the days of the week on which the window starts. If not specified, the window starts every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the time of day at which the window starts, in UTC, in the format HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is the length of the window.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// NodeToNodeMeshType specifies whether nodes peer with each other in a full BGP mesh.
+//
+// One of: Enabled, Disabled
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type NodeToNodeMeshType string
+
+const (
+	NodeToNodeMeshEnabled  NodeToNodeMeshType = "Enabled"
+	NodeToNodeMeshDisabled NodeToNodeMeshType = "Disabled"
+)
+
+// CalicoBGPConfiguration specifies the BGP settings and peerings of the cluster.
+type CalicoBGPConfiguration struct {
+	// ASNumber is the default AS number used by nodes. Per-node AS numbers from the BGP layout ConfigMap take precedence.
+	// If not specified, the AS number of the default BGPConfiguration is left unchanged, and Calico uses 64512 if it is unset.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4294967295
+	ASNumber *uint32 `json:"asNumber,omitempty"`
+
+	// NodeToNodeMesh configures whether all nodes peer with each other. Disable it when nodes instead peer with
+	// top-of-rack routers or route reflectors listed in Peers.
+	// Default: Enabled
+	// +optional
+	NodeToNodeMesh *NodeToNodeMeshType `json:"nodeToNodeMesh,omitempty"`
+
+	// ServiceClusterIPs lists the CIDRs of the service cluster IP range to advertise.
+	// +optional
+	// +kubebuilder:validation:MaxItems=2
+	ServiceClusterIPs []string `json:"serviceClusterIPs,omitempty"`
+
+	// ServiceExternalIPs lists the CIDRs of service external IPs to advertise.
+	// +optional
+	ServiceExternalIPs []string `json:"serviceExternalIPs,omitempty"`
+
+	// ServiceLoadBalancerIPs lists the CIDRs of service LoadBalancer IPs to advertise.
+	// +optional
+	ServiceLoadBalancerIPs []string `json:"serviceLoadBalancerIPs,omitempty"`
+
+	// RouteReflectors assigns a route reflector cluster ID to the selected nodes, making them act as route reflectors.
+	// +optional
+	RouteReflectors []BGPRouteReflector `json:"routeReflectors,omitempty"`
+
+	// Peers lists the BGP peers of the cluster nodes, such as top-of-rack routers or in-cluster route reflectors.
+	// The operator creates a BGPPeer resource for each of them and removes BGPPeers that it created for peers
+	// that are no longer listed.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Peers []BGPPeer `json:"peers,omitempty"`
+}
+
+// BGPRouteReflector configures a set of nodes as route reflectors with a shared cluster ID.
+type BGPRouteReflector struct {
+	// NodeSelector selects the nodes that act as route reflectors, using their Kubernetes labels.
+	NodeSelector metav1.LabelSelector `json:"nodeSelector"`
+
+	// ClusterID is the route reflector cluster ID of the selected nodes, in IPv4 address format, for example "244.0.0.1".
+	ClusterID string `json:"clusterID"`
+}
+
+// BGPPeer describes a BGP peering of the cluster nodes. Exactly one of PeerIP and PeerSelector must be set.
+type BGPPeer struct {
+	// Name identifies the peer. The BGPPeer resource created for it is named "tigera-operator.<name>".
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=200
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes that have this peering, using their Kubernetes labels, for example the nodes
+	// of one rack. If not specified, all nodes have this peering.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// PeerIP is the IP address of a peer outside the cluster, optionally followed by a port, for example "10.0.0.1",
+	// "10.0.0.1:179" or "[fd00::1]:179".
+	// +optional
+	PeerIP string `json:"peerIP,omitempty"`
+
+	// ASNumber is the AS number of the peer. Required when PeerIP is set.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4294967295
+	ASNumber *uint32 `json:"asNumber,omitempty"`
+
+	// PeerSelector selects the cluster nodes to peer with, using their Kubernetes labels, for example the nodes
+	// configured as route reflectors.
+	// +optional
+	PeerSelector *metav1.LabelSelector `json:"peerSelector,omitempty"`
+
+	// Password references the key of a Secret in the tigera-operator namespace that holds the password of the peering.
+	// +optional
+	Password *v1.SecretKeySelector `json:"password,omitempty"`
+}
+
 // LinuxDataplaneOption controls which dataplane is to be used on Linux nodes.
 //
 // One of: Iptables, BPF, VPP, Nftables
@@ -593,6 +924,12 @@ type CalicoNetworkSpec struct {
 	// +kubebuilder:validation:Enum=Enabled;Disabled
 	BGP *BGPOption `json:"bgp,omitempty"`
 
+	// BGPConfiguration configures the BGP settings and peerings of the cluster. The operator sets the specified fields
+	// of the default BGPConfiguration, leaving the others unchanged, and manages a BGPPeer for each of the listed peers.
+	// Only valid when BGP is Enabled.
+	// +optional
+	BGPConfiguration *CalicoBGPConfiguration `json:"bgpConfiguration,omitempty"`
+
 	// IPPools contains a list of IP pools to manage. If nil, a single IPv4 IP pool
 	// will be created by the operator. If an empty list is provided, the operator will not create any IP pools and will instead
 	// wait for IP pools to be created out-of-band.
@@ -606,6 +943,24 @@ type CalicoNetworkSpec struct {
 	// +optional
 	MTU *int32 `json:"mtu,omitempty"`
 
+	// MTUDetection controls whether the operator discovers the MTU of the host interfaces on each node and checks the
+	// pod network MTU against it. The operator computes the pod MTU for the configured encapsulation and WireGuard
+	// settings, and reports a mismatch in the calico TigeraStatus. With Enforce, the operator also configures the computed
+	// MTU on calico-node when MTU is not specified, so that every node uses the same pod MTU. The computed MTU is only
+	// configured once every calico-node pod has reported the MTU of its host, and each change is reported in the calico
+	// TigeraStatus before it is applied.
+	// Default: Disabled
+	// +optional
+	// +kubebuilder:validation:Enum=Disabled;Report;Enforce
+	MTUDetection *MTUDetectionType `json:"mtuDetection,omitempty"`
+
+	// Encryption configures WireGuard encryption of traffic between nodes. Settings that are specified here are
+	// written to the default FelixConfiguration, and the encryption state of each node is reported in the calico
+	// TigeraStatus. The WireGuard overhead is taken into account when the pod MTU is detected, either by calico-node
+	// when MTU is not specified, or by the operator when MTUDetection is enabled.
+	// +optional
+	Encryption *EncryptionSpec `json:"encryption,omitempty"`
+
 	// NodeAddressAutodetectionV4 specifies an approach to automatically detect node IPv4 addresses. If not specified,
 	// will use default auto-detection settings to acquire an IPv4 address for each node.
 	// +optional
@@ -39,6 +39,138 @@ type IntrusionDetectionSpec struct {
 	// DeepPacketInspectionDaemonset configures the DPI Daemonset
 	// +optional
 	DeepPacketInspectionDaemonset *DeepPacketInspectionDaemonset `json:"deepPacketInspectionDaemonset,omitempty"`
+
+	// ThreatFeeds configures the GlobalThreatFeeds that are created and owned by the operator. GlobalThreatFeeds that
+	// were created from this section are removed when they are no longer listed.
+	// Not supported in multi-tenant management clusters.
+	// +optional
+	ThreatFeeds *ThreatFeeds `json:"threatFeeds,omitempty"`
+
+	// CustomAlertTemplates lists ConfigMaps in the tigera-operator namespace that contain additional
+	// GlobalAlertTemplates. Every key ending in ".yaml" must hold a GlobalAlertTemplate manifest. Templates that are
+	// invalid are not applied and are reported in the status. The names of the templates that are installed by the
+	// operator cannot be used.
+	// Not supported in multi-tenant management clusters.
+	// +optional
+	CustomAlertTemplates []AlertTemplateSource `json:"customAlertTemplates,omitempty"`
+}
+
+type AlertTemplateSource struct {
+	// ConfigMapName is the name of a ConfigMap in the tigera-operator namespace holding GlobalAlertTemplates.
+	// The ConfigMap must have the label operator.tigera.io/alert-templates=true.
+	// +kubebuilder:validation:MinLength=1
+	ConfigMapName string `json:"configMapName"`
+}
+
+type ThreatFeeds struct {
+	// BuiltIn configures the well-known threat feeds that are provided by Tigera.
+	// +optional
+	BuiltIn []BuiltInThreatFeed `json:"builtIn,omitempty"`
+
+	// Custom lists additional threat feeds that are pulled over HTTP.
+	// +optional
+	Custom []CustomThreatFeed `json:"custom,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=AlienVaultIPs;AlienVaultDomains
+type BuiltInThreatFeedName string
+
+const (
+	BuiltInThreatFeedAlienVaultIPs     BuiltInThreatFeedName = "AlienVaultIPs"
+	BuiltInThreatFeedAlienVaultDomains BuiltInThreatFeedName = "AlienVaultDomains"
+)
+
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type ThreatFeedMode string
+
+const (
+	ThreatFeedModeEnabled  ThreatFeedMode = "Enabled"
+	ThreatFeedModeDisabled ThreatFeedMode = "Disabled"
+)
+
+// +kubebuilder:validation:Enum=IPSet;DomainNameSet
+type ThreatFeedContent string
+
+const (
+	ThreatFeedContentIPSet         ThreatFeedContent = "IPSet"
+	ThreatFeedContentDomainNameSet ThreatFeedContent = "DomainNameSet"
+)
+
+type BuiltInThreatFeed struct {
+	// Name identifies the threat feed.
+	Name BuiltInThreatFeedName `json:"name"`
+
+	// Mode determines whether the threat feed is pulled and searched for.
+	// Default: Enabled
+	// +optional
+	Mode *ThreatFeedMode `json:"mode,omitempty"`
+
+	// Block denies traffic between pods and the addresses in the threat feed. Host endpoints and pods in the
+	// calico-system and tigera-* namespaces are not affected. Only supported for feeds of IP addresses.
+	// Default: false
+	// +optional
+	Block bool `json:"block,omitempty"`
+}
+
+type CustomThreatFeed struct {
+	// Name is the name of the GlobalThreatFeed. It may not start with tigera.io.threatfeed., which is reserved for
+	// the built-in threat feeds.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Description is a human-readable description of the threat feed.
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Content is the kind of data that the threat feed provides.
+	// Default: IPSet
+	// +optional
+	Content ThreatFeedContent `json:"content,omitempty"`
+
+	// Mode determines whether the threat feed is pulled and searched for.
+	// Default: Enabled
+	// +optional
+	Mode *ThreatFeedMode `json:"mode,omitempty"`
+
+	// URL is the HTTP(S) URL that the threat feed is pulled from. The feed must contain one entry per line, unless
+	// JSONPath is set.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// JSONPath is the JSONPath expression that selects the entries of a threat feed in JSON format.
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// PullPeriod is how often the threat feed is pulled.
+	// Default: 24h
+	// +optional
+	PullPeriod *metav1.Duration `json:"pullPeriod,omitempty"`
+
+	// Headers are added to the HTTP requests that pull the threat feed, for example to authenticate.
+	// +optional
+	Headers []ThreatFeedHTTPHeader `json:"headers,omitempty"`
+
+	// Block denies traffic between pods and the addresses in the threat feed. Host endpoints and pods in the
+	// calico-system and tigera-* namespaces are not affected. Only supported for IPSet feeds.
+	// Default: false
+	// +optional
+	Block bool `json:"block,omitempty"`
+}
+
+type ThreatFeedHTTPHeader struct {
+	// Name is the name of the header.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Value is the value of the header.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// SecretKeyRef selects the value of the header from a secret in the tigera-operator namespace. The secret must
+	// have the label operator.tigera.io/threat-feed=true, and is copied into the intrusion detection namespace.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 type DeepPacketInspectionDaemonset struct {
@@ -96,6 +228,21 @@ type IntrusionDetectionStatus struct {
 	// Ready, Progressing, Degraded or other customer types.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// InvalidAlertTemplates lists the entries of the CustomAlertTemplates ConfigMaps that could not be applied.
+	// +optional
+	InvalidAlertTemplates []InvalidAlertTemplate `json:"invalidAlertTemplates,omitempty"`
+}
+
+type InvalidAlertTemplate struct {
+	// ConfigMapName is the name of the ConfigMap that holds the template.
+	ConfigMapName string `json:"configMapName"`
+
+	// Key is the key of the ConfigMap that holds the template.
+	Key string `json:"key"`
+
+	// Reason describes why the template could not be applied.
+	Reason string `json:"reason"`
 }
 
 // +kubebuilder:object:root=true
@@ -27,9 +27,97 @@ type NonClusterHostSpec struct {
 
 	// Location of the Typha endpoint for non-cluster host Felix and Typha communication. For example: 5.6.7.8:5473
 	TyphaEndpoint string `json:"typhaEndpoint,omitempty"`
+
+	// Hosts lists the non-cluster hosts that are allowed to enroll with the cluster. Each listed host is issued its
+	// own single-use enrollment token, which is removed once the host has enrolled. Enrolled hosts renew their
+	// certificates with the non-cluster host token, and certificates are only issued to listed hosts that have not
+	// been revoked.
+	// When no hosts are listed, any host whose requestor is permitted to request non-cluster host certificates can enroll.
+	// +optional
+	Hosts []NonClusterHostEnrollment `json:"hosts,omitempty"`
+
+	// EnrollmentTokenTTL is how long an enrollment token can be used to enroll after it has been issued. A host that
+	// has not enrolled before its token expires must be removed from and re-added to the list of hosts to get a new
+	// token.
+	// Default: 24h
+	// +optional
+	EnrollmentTokenTTL *metav1.Duration `json:"enrollmentTokenTTL,omitempty"`
+}
+
+// NonClusterHostEnrollment describes a non-cluster host that is allowed to enroll with the cluster.
+type NonClusterHostEnrollment struct {
+	// Hostname of the non-cluster host. It must match the node of the host's HostEndpoint.
+	// +kubebuilder:validation:MaxLength=219
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	Hostname string `json:"hostname"`
+
+	// Revoked revokes the host when set to true. Its enrollment token is removed and its certificate signing
+	// requests are rejected, so that it can no longer renew its certificate, and its connections to Typha are
+	// refused by network policy based on its HostEndpoint addresses. A certificate that was already issued is
+	// not revoked and remains valid until it expires. The NonClusterHost is reported as degraded while a revoked
+	// host has no HostEndpoint addresses.
+	// +optional
+	Revoked bool `json:"revoked,omitempty"`
+}
+
+// NonClusterHostStatus defines the observed state of NonClusterHost.
+type NonClusterHostStatus struct {
+	// Hosts is the inventory of the non-cluster hosts that are listed in the spec.
+	// +optional
+	Hosts []NonClusterHostInventory `json:"hosts,omitempty"`
+}
+
+// NonClusterHostEnrollmentState is the enrollment state of a non-cluster host.
+type NonClusterHostEnrollmentState string
+
+const (
+	// NonClusterHostPending means an enrollment token has been issued, but the host has not yet used it.
+	NonClusterHostPending NonClusterHostEnrollmentState = "Pending"
+	// NonClusterHostEnrolled means the host has been issued a certificate using its enrollment token. The token is
+	// removed, and the host renews its certificate with the non-cluster host token.
+	NonClusterHostEnrolled NonClusterHostEnrollmentState = "Enrolled"
+	// NonClusterHostExpired means the enrollment token expired before the host used it.
+	NonClusterHostExpired NonClusterHostEnrollmentState = "Expired"
+	// NonClusterHostRevoked means the host has been revoked.
+	NonClusterHostRevoked NonClusterHostEnrollmentState = "Revoked"
+)
+
+// NonClusterHostInventory is the observed state of a single non-cluster host.
+type NonClusterHostInventory struct {
+	// Hostname of the non-cluster host.
+	Hostname string `json:"hostname"`
+
+	// State is the enrollment state of the host.
+	State NonClusterHostEnrollmentState `json:"state"`
+
+	// TokenSecretName is the name of the Secret in the calico-system namespace that holds the enrollment token
+	// for the host, while the host is Pending.
+	// +optional
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
+
+	// TokenExpiry is the time at which the enrollment token for the host expires.
+	// +optional
+	TokenExpiry *metav1.Time `json:"tokenExpiry,omitempty"`
+
+	// EnrolledAt is the time at which the host was first issued a certificate.
+	// +optional
+	EnrolledAt *metav1.Time `json:"enrolledAt,omitempty"`
+
+	// HostEndpoint is the name of the HostEndpoint that the host's certificate is bound to.
+	// +optional
+	HostEndpoint string `json:"hostEndpoint,omitempty"`
+
+	// Addresses are the expected IPs of the host's HostEndpoint.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+
+	// LastSeen is the most recent time at which the host requested a certificate.
+	// +optional
+	LastSeen *metav1.Time `json:"lastSeen,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 
 // NonClusterHost installs the components required for non-cluster host log collection.
@@ -40,6 +128,9 @@ type NonClusterHost struct {
 
 	// Specification of the desired state for non-cluster host log collection.
 	Spec NonClusterHostSpec `json:"spec,omitempty"`
+
+	// Most recently observed state of the non-cluster hosts.
+	Status NonClusterHostStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
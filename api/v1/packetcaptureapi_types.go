@@ -18,6 +18,7 @@ package v1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,6 +28,27 @@ type PacketCaptureAPISpec struct {
 	// PacketCaptureAPIDeployment configures the PacketCaptureAPI Deployment.
 	// +optional
 	PacketCaptureAPIDeployment *PacketCaptureAPIDeployment `json:"packetCaptureAPIDeployment,omitempty"`
+
+	// Storage configures how much packet capture data is kept on each node and how long it is retained.
+	// Capture files are only stored on the nodes; they are not uploaded to external storage.
+	// +optional
+	Storage *PacketCaptureStorage `json:"storage,omitempty"`
+}
+
+// PacketCaptureStorage configures the storage and lifecycle of packet capture files.
+type PacketCaptureStorage struct {
+	// MaxCaptureSizePerNode is the maximum amount of data that a single packet capture stores on each node.
+	// Once reached, the oldest capture file is rotated out.
+	// If omitted, the Felix defaults are used.
+	// +optional
+	MaxCaptureSizePerNode *resource.Quantity `json:"maxCaptureSizePerNode,omitempty"`
+
+	// Retention is how long the data of a running packet capture is kept on each node. Felix rotates capture
+	// files so that older data is rotated out. Files of captures that have finished are kept until they are
+	// deleted through the packet capture API.
+	// If omitted, the Felix defaults are used.
+	// +optional
+	Retention *metav1.Duration `json:"retention,omitempty"`
 }
 
 // PacketCaptureAPIDeployment is the configuration for the PacketCaptureAPI Deployment.
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+type PolicyExceptionProtocol string
+
+const (
+	PolicyExceptionProtocolTCP  PolicyExceptionProtocol = "TCP"
+	PolicyExceptionProtocolUDP  PolicyExceptionProtocol = "UDP"
+	PolicyExceptionProtocolSCTP PolicyExceptionProtocol = "SCTP"
+)
+
+// PolicyExceptionSpec lists the rules to merge into an operator-owned policy in the allow-tigera tier.
+type PolicyExceptionSpec struct {
+	// Policy is the name of the operator-owned policy in the allow-tigera tier that the rules are merged into,
+	// for example "allow-tigera.guardian-access".
+	// +kubebuilder:validation:Pattern=`^allow-tigera\.`
+	Policy string `json:"policy"`
+
+	// Namespace is the namespace of the policy.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Ingress lists additional sources that the endpoints selected by the policy accept traffic from.
+	// +optional
+	Ingress []PolicyExceptionRule `json:"ingress,omitempty"`
+
+	// Egress lists additional destinations that the endpoints selected by the policy may send traffic to.
+	// +optional
+	Egress []PolicyExceptionRule `json:"egress,omitempty"`
+}
+
+// PolicyExceptionRule allows traffic to or from the given peer. The peer is the source of ingress rules and the
+// destination of egress rules. At least one of the peer fields or Ports must be set.
+type PolicyExceptionRule struct {
+	// Protocol of the allowed traffic. Required if Ports is set.
+	// +optional
+	Protocol *PolicyExceptionProtocol `json:"protocol,omitempty"`
+
+	// Ports lists the allowed destination ports, either as a single port such as "8080", or a range
+	// such as "9000:9100".
+	// +optional
+	Ports []string `json:"ports,omitempty"`
+
+	// Nets lists the CIDRs of the peer.
+	// +optional
+	Nets []string `json:"nets,omitempty"`
+
+	// Domains lists the domain names of the peer. Only valid for egress rules.
+	// +optional
+	Domains []string `json:"domains,omitempty"`
+
+	// Selector is a Calico label selector for the peer endpoints, for example "k8s-app == 'scraper'".
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// NamespaceSelector is a Calico label selector for the namespaces of the peer endpoints.
+	// +optional
+	NamespaceSelector string `json:"namespaceSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// PolicyException adds rules to a policy that the operator renders in the allow-tigera tier, such as an egress
+// rule that allows a component to reach a corporate proxy. The operator merges the rules into the policy each time
+// it reconciles the component that owns the policy, and reports invalid exceptions in the status of that component.
+type PolicyException struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PolicyExceptionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PolicyExceptionList contains a list of PolicyException
+type PolicyExceptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PolicyException `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PolicyException{}, &PolicyExceptionList{})
+}
@@ -26,6 +26,62 @@ type PolicyRecommendationSpec struct {
 	// PolicyRecommendation configures the PolicyRecommendation Deployment.
 	// +optional
 	PolicyRecommendationDeployment *PolicyRecommendationDeployment `json:"policyRecommendationDeployment,omitempty"`
+
+	// Scope configures which namespaces policy recommendations are generated for and how they are generated.
+	// If specified, the operator owns the default PolicyRecommendationScope and overwrites any changes made to it
+	// directly. If omitted, a default PolicyRecommendationScope with recommendations disabled is created once and
+	// is not modified by the operator afterwards.
+	// Scope is not supported in multi-tenant management clusters.
+	// +optional
+	Scope *PolicyRecommendationScopeConfig `json:"scope,omitempty"`
+}
+
+// PolicyRecommendationMode controls whether and how policy recommendations are generated.
+// +kubebuilder:validation:Enum=Disabled;Learn;AutoStage
+type PolicyRecommendationMode string
+
+const (
+	// PolicyRecommendationModeDisabled turns off the generation of policy recommendations.
+	PolicyRecommendationModeDisabled PolicyRecommendationMode = "Disabled"
+
+	// PolicyRecommendationModeLearn generates recommended policies that remain in the learning state
+	// until they are acted upon by a user.
+	PolicyRecommendationModeLearn PolicyRecommendationMode = "Learn"
+
+	// PolicyRecommendationModeAutoStage generates recommended policies and, once a recommendation is
+	// stable, takes it over from the recommendation engine and moves it into the staged state so that its
+	// effect can be previewed before enforcement.
+	PolicyRecommendationModeAutoStage PolicyRecommendationMode = "AutoStage"
+)
+
+// PolicyRecommendationScopeConfig configures the default PolicyRecommendationScope.
+type PolicyRecommendationScopeConfig struct {
+	// Mode controls whether policy recommendations are generated and whether stable recommendations are
+	// automatically moved into the staged state. Recommendations are never enforced automatically.
+	// Default: Disabled
+	// +optional
+	Mode *PolicyRecommendationMode `json:"mode,omitempty"`
+
+	// IncludeNamespaces restricts policy recommendations to the listed namespaces. If omitted, recommendations
+	// are generated for all namespaces that are not excluded.
+	// +optional
+	IncludeNamespaces []string `json:"includeNamespaces,omitempty"`
+
+	// ExcludeNamespaces lists namespaces for which policy recommendations are never generated. Namespaces that
+	// belong to Calico, Tigera and Kubernetes (and OpenShift, where applicable) are always excluded.
+	// +optional
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// StabilizationPeriod is the amount of time a recommended policy must remain unchanged before it is
+	// considered stable.
+	// Default: 10m
+	// +optional
+	StabilizationPeriod *metav1.Duration `json:"stabilizationPeriod,omitempty"`
+
+	// Interval is how frequently the recommendation engine runs to create and refine recommended policies.
+	// Default: 150s
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
 // PolicyRecommendationDeployment is the configuration for the PolicyRecommendation Deployment.
@@ -98,6 +154,24 @@ type PolicyRecommendationDeploymentInitContainer struct {
 type PolicyRecommendationStatus struct {
 	// State provides user-readable status.
 	State string `json:"state,omitempty"`
+
+	// Recommendations reports the number of recommended policies in each state. It is only populated
+	// when the operator owns the PolicyRecommendationScope.
+	// +optional
+	Recommendations *PolicyRecommendationCounts `json:"recommendations,omitempty"`
+}
+
+// PolicyRecommendationCounts reports the number of recommended policies in each state.
+type PolicyRecommendationCounts struct {
+	// Learning is the number of recommended policies that are still learning or stabilizing.
+	Learning int32 `json:"learning"`
+
+	// Stable is the number of recommended policies that are stable but not yet staged.
+	Stable int32 `json:"stable"`
+
+	// Staged is the number of recommended policies that have been moved into the staged state, either by a user
+	// or by the operator in AutoStage mode.
+	Staged int32 `json:"staged"`
 }
 
 // +kubebuilder:object:root=true
@@ -33,6 +33,11 @@ type TigeraStatusStatus struct {
 	// Conditions represents the latest observed set of conditions for this component. A component may be one or more of
 	// Available, Progressing, or Degraded.
 	Conditions []TigeraStatusCondition `json:"conditions"`
+
+	// History is a bounded record of the most recent condition transitions and degraded reasons reported for
+	// this component, oldest first. It is kept so that problems which have since cleared can still be investigated.
+	// +optional
+	History []TigeraStatusEvent `json:"history,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -77,6 +82,25 @@ const (
 
 	// Ready indicates that the component is healthy and ready.it is identical to Available and used in Status conditions for CRs.
 	ComponentReady StatusConditionType = "Ready"
+
+	// PoliciesStaged indicates that some of the component's allow-tigera tier policies are staged and waiting to be
+	// promoted to enforced policies.
+	ComponentPoliciesStaged StatusConditionType = "PoliciesStaged"
+
+	// PolicyExceptionsApplied indicates that PolicyExceptions have been merged into some of the component's
+	// allow-tigera tier policies.
+	ComponentPolicyExceptionsApplied StatusConditionType = "PolicyExceptionsApplied"
+
+	// RollingOut indicates that the operator is rolling out an update to the component's pods itself, for example
+	// the canary and batched rollout of calico-node.
+	ComponentRollingOut StatusConditionType = "RollingOut"
+
+	// MTUMismatch indicates that the pod network MTU does not match the MTU of the host interfaces discovered by the
+	// operator.
+	ComponentMTUMismatch StatusConditionType = "MTUMismatch"
+
+	// Encrypted indicates whether every node is encrypting traffic with the WireGuard settings from the Installation.
+	ComponentEncrypted StatusConditionType = "Encrypted"
 )
 
 // TigeraStatusCondition represents a condition attached to a particular component.
@@ -104,6 +128,34 @@ type TigeraStatusCondition struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// TigeraStatusEvent records a change in the status of a particular component.
+// +k8s:deepcopy-gen=true
+type TigeraStatusEvent struct {
+	// The time at which the change was observed.
+	Time metav1.Time `json:"time"`
+
+	// The type of condition that changed. May be Available, Progressing, or Degraded.
+	Type StatusConditionType `json:"type"`
+
+	// The status of the condition after the change. May be True, False, or Unknown.
+	Status ConditionStatus `json:"status"`
+
+	// A brief reason explaining the change.
+	Reason string `json:"reason,omitempty"`
+
+	// Optionally, a detailed message providing additional context.
+	Message string `json:"message,omitempty"`
+
+	// Objects lists the objects that were not ready when the change was observed, for example
+	// "DaemonSet calico-system/calico-node".
+	// +optional
+	Objects []string `json:"objects,omitempty"`
+
+	// ReconcileDuration is how long the most recent reconcile of this component took when the change was observed.
+	// +optional
+	ReconcileDuration *metav1.Duration `json:"reconcileDuration,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // TigeraStatusList contains a list of TigeraStatus
@@ -138,6 +190,13 @@ const (
 	UpgradeError              TigeraStatusReason = "UpgradeError"
 	Unknown                   TigeraStatusReason = "Unknown"
 	ImageSetError             TigeraStatusReason = "ImageSetError"
+	StagedPoliciesPending     TigeraStatusReason = "StagedPoliciesPending"
+	PolicyExceptionsApplied   TigeraStatusReason = "PolicyExceptionsApplied"
+	RolloutProgressing        TigeraStatusReason = "RolloutProgressing"
+	RolloutPaused             TigeraStatusReason = "RolloutPaused"
+	MTUMismatchDetected       TigeraStatusReason = "MTUMismatchDetected"
+	EncryptionActive          TigeraStatusReason = "EncryptionActive"
+	EncryptionPending         TigeraStatusReason = "EncryptionPending"
 )
 
 func init() {
@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -480,6 +481,21 @@ func (in *AlertManagerSpec) DeepCopy() *AlertManagerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertTemplateSource) DeepCopyInto(out *AlertTemplateSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertTemplateSource.
+func (in *AlertTemplateSource) DeepCopy() *AlertTemplateSource {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertTemplateSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AnomalyDetectionSpec) DeepCopyInto(out *AnomalyDetectionSpec) {
 	*out = *in
@@ -562,6 +578,11 @@ func (in *ApplicationLayerSpec) DeepCopyInto(out *ApplicationLayerSpec) {
 		*out = new(WAFStatusType)
 		**out = **in
 	}
+	if in.WebApplicationFirewallSettings != nil {
+		in, out := &in.WebApplicationFirewallSettings, &out.WebApplicationFirewallSettings
+		*out = new(WAFSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LogCollection != nil {
 		in, out := &in.LogCollection, &out.LogCollection
 		*out = new(LogCollectionSpec)
@@ -837,6 +858,77 @@ func (in *Azure) DeepCopy() *Azure {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeer) DeepCopyInto(out *BGPPeer) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ASNumber != nil {
+		in, out := &in.ASNumber, &out.ASNumber
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.PeerSelector != nil {
+		in, out := &in.PeerSelector, &out.PeerSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeer.
+func (in *BGPPeer) DeepCopy() *BGPPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPRouteReflector) DeepCopyInto(out *BGPRouteReflector) {
+	*out = *in
+	in.NodeSelector.DeepCopyInto(&out.NodeSelector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPRouteReflector.
+func (in *BGPRouteReflector) DeepCopy() *BGPRouteReflector {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPRouteReflector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuiltInThreatFeed) DeepCopyInto(out *BuiltInThreatFeed) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(ThreatFeedMode)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuiltInThreatFeed.
+func (in *BuiltInThreatFeed) DeepCopy() *BuiltInThreatFeed {
+	if in == nil {
+		return nil
+	}
+	out := new(BuiltInThreatFeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CNILogging) DeepCopyInto(out *CNILogging) {
 	*out = *in
@@ -1038,6 +1130,60 @@ func (in *CSINodeDriverDaemonSetSpec) DeepCopy() *CSINodeDriverDaemonSetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoBGPConfiguration) DeepCopyInto(out *CalicoBGPConfiguration) {
+	*out = *in
+	if in.ASNumber != nil {
+		in, out := &in.ASNumber, &out.ASNumber
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.NodeToNodeMesh != nil {
+		in, out := &in.NodeToNodeMesh, &out.NodeToNodeMesh
+		*out = new(NodeToNodeMeshType)
+		**out = **in
+	}
+	if in.ServiceClusterIPs != nil {
+		in, out := &in.ServiceClusterIPs, &out.ServiceClusterIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceExternalIPs != nil {
+		in, out := &in.ServiceExternalIPs, &out.ServiceExternalIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceLoadBalancerIPs != nil {
+		in, out := &in.ServiceLoadBalancerIPs, &out.ServiceLoadBalancerIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RouteReflectors != nil {
+		in, out := &in.RouteReflectors, &out.RouteReflectors
+		*out = make([]BGPRouteReflector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]BGPPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoBGPConfiguration.
+func (in *CalicoBGPConfiguration) DeepCopy() *CalicoBGPConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoBGPConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CalicoKubeControllersDeployment) DeepCopyInto(out *CalicoKubeControllersDeployment) {
 	*out = *in
@@ -1202,6 +1348,11 @@ func (in *CalicoNetworkSpec) DeepCopyInto(out *CalicoNetworkSpec) {
 		*out = new(BGPOption)
 		**out = **in
 	}
+	if in.BGPConfiguration != nil {
+		in, out := &in.BGPConfiguration, &out.BGPConfiguration
+		*out = new(CalicoBGPConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.IPPools != nil {
 		in, out := &in.IPPools, &out.IPPools
 		*out = make([]IPPool, len(*in))
@@ -1214,6 +1365,16 @@ func (in *CalicoNetworkSpec) DeepCopyInto(out *CalicoNetworkSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MTUDetection != nil {
+		in, out := &in.MTUDetection, &out.MTUDetection
+		*out = new(MTUDetectionType)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(EncryptionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NodeAddressAutodetectionV4 != nil {
 		in, out := &in.NodeAddressAutodetectionV4, &out.NodeAddressAutodetectionV4
 		*out = new(NodeAddressAutodetection)
@@ -1434,6 +1595,119 @@ func (in *CalicoNodeDaemonSetSpec) DeepCopy() *CalicoNodeDaemonSetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoNodeGroup) DeepCopyInto(out *CalicoNodeGroup) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CalicoNodeDaemonSet != nil {
+		in, out := &in.CalicoNodeDaemonSet, &out.CalicoNodeDaemonSet
+		*out = new(CalicoNodeGroupDaemonSet)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeAddressAutodetectionV4 != nil {
+		in, out := &in.NodeAddressAutodetectionV4, &out.NodeAddressAutodetectionV4
+		*out = new(NodeAddressAutodetection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeAddressAutodetectionV6 != nil {
+		in, out := &in.NodeAddressAutodetectionV6, &out.NodeAddressAutodetectionV6
+		*out = new(NodeAddressAutodetection)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNodeGroup.
+func (in *CalicoNodeGroup) DeepCopy() *CalicoNodeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoNodeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoNodeGroupDaemonSet) DeepCopyInto(out *CalicoNodeGroupDaemonSet) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNodeGroupDaemonSet.
+func (in *CalicoNodeGroupDaemonSet) DeepCopy() *CalicoNodeGroupDaemonSet {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoNodeGroupDaemonSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoNodeRollout) DeepCopyInto(out *CalicoNodeRollout) {
+	*out = *in
+	if in.CanaryNodeSelector != nil {
+		in, out := &in.CanaryNodeSelector, &out.CanaryNodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.SoakPeriod != nil {
+		in, out := &in.SoakPeriod, &out.SoakPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.BatchTimeout != nil {
+		in, out := &in.BatchTimeout, &out.BatchTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNodeRollout.
+func (in *CalicoNodeRollout) DeepCopy() *CalicoNodeRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoNodeRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CalicoNodeWindowsDaemonSet) DeepCopyInto(out *CalicoNodeWindowsDaemonSet) {
 	*out = *in
@@ -1753,6 +2027,99 @@ func (in *CertificateManagement) DeepCopy() *CertificateManagement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSigningPolicy) DeepCopyInto(out *CertificateSigningPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSigningPolicy.
+func (in *CertificateSigningPolicy) DeepCopy() *CertificateSigningPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSigningPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateSigningPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSigningPolicyList) DeepCopyInto(out *CertificateSigningPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CertificateSigningPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSigningPolicyList.
+func (in *CertificateSigningPolicyList) DeepCopy() *CertificateSigningPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSigningPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateSigningPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSigningPolicySpec) DeepCopyInto(out *CertificateSigningPolicySpec) {
+	*out = *in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyUsages != nil {
+		in, out := &in.KeyUsages, &out.KeyUsages
+		*out = make([]CertificateKeyUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSigningPolicySpec.
+func (in *CertificateSigningPolicySpec) DeepCopy() *CertificateSigningPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSigningPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonPrometheusFields) DeepCopyInto(out *CommonPrometheusFields) {
 	*out = *in
@@ -2094,14 +2461,49 @@ func (in *ComplianceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComplianceReporterPodSpec) DeepCopyInto(out *ComplianceReporterPodSpec) {
+func (in *ComplianceReport) DeepCopyInto(out *ComplianceReport) {
 	*out = *in
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]ComplianceReporterPodTemplateInitContainer, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReport.
+func (in *ComplianceReport) DeepCopy() *ComplianceReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReportTypeSource) DeepCopyInto(out *ComplianceReportTypeSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceReportTypeSource.
+func (in *ComplianceReportTypeSource) DeepCopy() *ComplianceReportTypeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ComplianceReportTypeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComplianceReporterPodSpec) DeepCopyInto(out *ComplianceReporterPodSpec) {
+	*out = *in
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]ComplianceReporterPodTemplateInitContainer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Containers != nil {
 		in, out := &in.Containers, &out.Containers
@@ -2488,6 +2890,18 @@ func (in *ComplianceSpec) DeepCopyInto(out *ComplianceSpec) {
 		*out = new(ComplianceReporterPodTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CustomReportTypes != nil {
+		in, out := &in.CustomReportTypes, &out.CustomReportTypes
+		*out = make([]ComplianceReportTypeSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reports != nil {
+		in, out := &in.Reports, &out.Reports
+		*out = make([]ComplianceReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComplianceSpec.
@@ -2522,6 +2936,36 @@ func (in *ComplianceStatus) DeepCopy() *ComplianceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentPolicyRollout) DeepCopyInto(out *ComponentPolicyRollout) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(ComponentPolicyRolloutMode)
+		**out = **in
+	}
+	if in.SoakPeriod != nil {
+		in, out := &in.SoakPeriod, &out.SoakPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PromoteUnverified != nil {
+		in, out := &in.PromoteUnverified, &out.PromoteUnverified
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPolicyRollout.
+func (in *ComponentPolicyRollout) DeepCopy() *ComponentPolicyRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentPolicyRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentResource) DeepCopyInto(out *ComponentResource) {
 	*out = *in
@@ -2542,6 +2986,38 @@ func (in *ComponentResource) DeepCopy() *ComponentResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomThreatFeed) DeepCopyInto(out *CustomThreatFeed) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(ThreatFeedMode)
+		**out = **in
+	}
+	if in.PullPeriod != nil {
+		in, out := &in.PullPeriod, &out.PullPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]ThreatFeedHTTPHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomThreatFeed.
+func (in *CustomThreatFeed) DeepCopy() *CustomThreatFeed {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomThreatFeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DPIDaemonsetInitContainer) DeepCopyInto(out *DPIDaemonsetInitContainer) {
 	*out = *in
@@ -3736,6 +4212,36 @@ func (in *ElasticsearchMetricsDeploymentSpec) DeepCopy() *ElasticsearchMetricsDe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptionSpec) DeepCopyInto(out *EncryptionSpec) {
+	*out = *in
+	if in.WireGuardIPv4 != nil {
+		in, out := &in.WireGuardIPv4, &out.WireGuardIPv4
+		*out = new(WireGuardOption)
+		**out = **in
+	}
+	if in.WireGuardIPv6 != nil {
+		in, out := &in.WireGuardIPv6, &out.WireGuardIPv6
+		*out = new(WireGuardOption)
+		**out = **in
+	}
+	if in.HostEncryption != nil {
+		in, out := &in.HostEncryption, &out.HostEncryption
+		*out = new(WireGuardOption)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptionSpec.
+func (in *EncryptionSpec) DeepCopy() *EncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
@@ -3822,6 +4328,58 @@ func (in *ExternalPrometheus) DeepCopy() *ExternalPrometheus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FelixNodeOverride) DeepCopyInto(out *FelixNodeOverride) {
+	*out = *in
+	in.NodeSelector.DeepCopyInto(&out.NodeSelector)
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FelixNodeOverride.
+func (in *FelixNodeOverride) DeepCopy() *FelixNodeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(FelixNodeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FelixOverrides) DeepCopyInto(out *FelixOverrides) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeOverrides != nil {
+		in, out := &in.NodeOverrides, &out.NodeOverrides
+		*out = make([]FelixNodeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FelixOverrides.
+func (in *FelixOverrides) DeepCopy() *FelixOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(FelixOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FluentdDaemonSet) DeepCopyInto(out *FluentdDaemonSet) {
 	*out = *in
@@ -4051,6 +4609,43 @@ func (in *GatewayAPISpec) DeepCopy() *GatewayAPISpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayAccessLogs) DeepCopyInto(out *GatewayAccessLogs) {
+	*out = *in
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(GatewayAccessLogFormat)
+		**out = **in
+	}
+	if in.Text != nil {
+		in, out := &in.Text, &out.Text
+		*out = new(string)
+		**out = **in
+	}
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SamplingPercentage != nil {
+		in, out := &in.SamplingPercentage, &out.SamplingPercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAccessLogs.
+func (in *GatewayAccessLogs) DeepCopy() *GatewayAccessLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayAccessLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayCertgenJob) DeepCopyInto(out *GatewayCertgenJob) {
 	*out = *in
@@ -4210,6 +4805,16 @@ func (in *GatewayClassSpec) DeepCopyInto(out *GatewayClassSpec) {
 		*out = new(GatewayService)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Telemetry != nil {
+		in, out := &in.Telemetry, &out.Telemetry
+		*out = new(GatewayTelemetry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebApplicationFirewall != nil {
+		in, out := &in.WebApplicationFirewall, &out.WebApplicationFirewall
+		*out = new(GatewayWebApplicationFirewall)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayClassSpec.
@@ -4666,6 +5271,31 @@ func (in *GatewayDeploymentStrategy) DeepCopy() *GatewayDeploymentStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayMetrics) DeepCopyInto(out *GatewayMetrics) {
+	*out = *in
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(GatewayServiceMonitorType)
+		**out = **in
+	}
+	if in.ScrapeInterval != nil {
+		in, out := &in.ScrapeInterval, &out.ScrapeInterval
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayMetrics.
+func (in *GatewayMetrics) DeepCopy() *GatewayMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayService) DeepCopyInto(out *GatewayService) {
 	*out = *in
@@ -4726,6 +5356,56 @@ func (in *GatewayServiceSpec) DeepCopy() *GatewayServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayTelemetry) DeepCopyInto(out *GatewayTelemetry) {
+	*out = *in
+	if in.AccessLogs != nil {
+		in, out := &in.AccessLogs, &out.AccessLogs
+		*out = new(GatewayAccessLogs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(GatewayMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayTelemetry.
+func (in *GatewayTelemetry) DeepCopy() *GatewayTelemetry {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayTelemetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayWebApplicationFirewall) DeepCopyInto(out *GatewayWebApplicationFirewall) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(WAFMode)
+		**out = **in
+	}
+	if in.FailOpen != nil {
+		in, out := &in.FailOpen, &out.FailOpen
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayWebApplicationFirewall.
+func (in *GatewayWebApplicationFirewall) DeepCopy() *GatewayWebApplicationFirewall {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayWebApplicationFirewall)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Goldmane) DeepCopyInto(out *Goldmane) {
 	*out = *in
@@ -5529,6 +6209,16 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 		**out = **in
 	}
 	in.NodeUpdateStrategy.DeepCopyInto(&out.NodeUpdateStrategy)
+	if in.NodeRollout != nil {
+		in, out := &in.NodeRollout, &out.NodeRollout
+		*out = new(CalicoNodeRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FelixOverrides != nil {
+		in, out := &in.FelixOverrides, &out.FelixOverrides
+		*out = new(FelixOverrides)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ComponentResources != nil {
 		in, out := &in.ComponentResources, &out.ComponentResources
 		*out = make([]ComponentResource, len(*in))
@@ -5558,6 +6248,13 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 		*out = new(CalicoNodeDaemonSet)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CalicoNodeGroups != nil {
+		in, out := &in.CalicoNodeGroups, &out.CalicoNodeGroups
+		*out = make([]CalicoNodeGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.CSINodeDriverDaemonSet != nil {
 		in, out := &in.CSINodeDriverDaemonSet, &out.CSINodeDriverDaemonSet
 		*out = new(CSINodeDriverDaemonSet)
@@ -5613,6 +6310,11 @@ func (in *InstallationSpec) DeepCopyInto(out *InstallationSpec) {
 		*out = new(Proxy)
 		**out = **in
 	}
+	if in.ComponentPolicyRollout != nil {
+		in, out := &in.ComponentPolicyRollout, &out.ComponentPolicyRollout
+		*out = new(ComponentPolicyRollout)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallationSpec.
@@ -5881,6 +6583,16 @@ func (in *IntrusionDetectionSpec) DeepCopyInto(out *IntrusionDetectionSpec) {
 		*out = new(DeepPacketInspectionDaemonset)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ThreatFeeds != nil {
+		in, out := &in.ThreatFeeds, &out.ThreatFeeds
+		*out = new(ThreatFeeds)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomAlertTemplates != nil {
+		in, out := &in.CustomAlertTemplates, &out.CustomAlertTemplates
+		*out = make([]AlertTemplateSource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntrusionDetectionSpec.
@@ -5903,6 +6615,11 @@ func (in *IntrusionDetectionStatus) DeepCopyInto(out *IntrusionDetectionStatus)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InvalidAlertTemplates != nil {
+		in, out := &in.InvalidAlertTemplates, &out.InvalidAlertTemplates
+		*out = make([]InvalidAlertTemplate, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntrusionDetectionStatus.
@@ -5915,6 +6632,21 @@ func (in *IntrusionDetectionStatus) DeepCopy() *IntrusionDetectionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvalidAlertTemplate) DeepCopyInto(out *InvalidAlertTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvalidAlertTemplate.
+func (in *InvalidAlertTemplate) DeepCopy() *InvalidAlertTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(InvalidAlertTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kibana) DeepCopyInto(out *Kibana) {
 	*out = *in
@@ -6643,6 +7375,27 @@ func (in *Logging) DeepCopy() *Logging {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementCluster) DeepCopyInto(out *ManagementCluster) {
 	*out = *in
@@ -7356,7 +8109,8 @@ func (in *NonClusterHost) DeepCopyInto(out *NonClusterHost) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NonClusterHost.
@@ -7378,13 +8132,60 @@ func (in *NonClusterHost) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NonClusterHostList) DeepCopyInto(out *NonClusterHostList) {
+func (in *NonClusterHostEnrollment) DeepCopyInto(out *NonClusterHostEnrollment) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]NonClusterHost, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NonClusterHostEnrollment.
+func (in *NonClusterHostEnrollment) DeepCopy() *NonClusterHostEnrollment {
+	if in == nil {
+		return nil
+	}
+	out := new(NonClusterHostEnrollment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NonClusterHostInventory) DeepCopyInto(out *NonClusterHostInventory) {
+	*out = *in
+	if in.TokenExpiry != nil {
+		in, out := &in.TokenExpiry, &out.TokenExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.EnrolledAt != nil {
+		in, out := &in.EnrolledAt, &out.EnrolledAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSeen != nil {
+		in, out := &in.LastSeen, &out.LastSeen
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NonClusterHostInventory.
+func (in *NonClusterHostInventory) DeepCopy() *NonClusterHostInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(NonClusterHostInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NonClusterHostList) DeepCopyInto(out *NonClusterHostList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NonClusterHost, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -7412,6 +8213,16 @@ func (in *NonClusterHostList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NonClusterHostSpec) DeepCopyInto(out *NonClusterHostSpec) {
 	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]NonClusterHostEnrollment, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnrollmentTokenTTL != nil {
+		in, out := &in.EnrollmentTokenTTL, &out.EnrollmentTokenTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NonClusterHostSpec.
@@ -7424,6 +8235,28 @@ func (in *NonClusterHostSpec) DeepCopy() *NonClusterHostSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NonClusterHostStatus) DeepCopyInto(out *NonClusterHostStatus) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]NonClusterHostInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NonClusterHostStatus.
+func (in *NonClusterHostStatus) DeepCopy() *NonClusterHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NonClusterHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PacketCaptureAPI) DeepCopyInto(out *PacketCaptureAPI) {
 	*out = *in
@@ -7620,6 +8453,11 @@ func (in *PacketCaptureAPISpec) DeepCopyInto(out *PacketCaptureAPISpec) {
 		*out = new(PacketCaptureAPIDeployment)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(PacketCaptureStorage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PacketCaptureAPISpec.
@@ -7654,6 +8492,31 @@ func (in *PacketCaptureAPIStatus) DeepCopy() *PacketCaptureAPIStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PacketCaptureStorage) DeepCopyInto(out *PacketCaptureStorage) {
+	*out = *in
+	if in.MaxCaptureSizePerNode != nil {
+		in, out := &in.MaxCaptureSizePerNode, &out.MaxCaptureSizePerNode
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PacketCaptureStorage.
+func (in *PacketCaptureStorage) DeepCopy() *PacketCaptureStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(PacketCaptureStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PathMatch) DeepCopyInto(out *PathMatch) {
 	*out = *in
@@ -7679,13 +8542,135 @@ func (in *PathMatch) DeepCopy() *PathMatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyException) DeepCopyInto(out *PolicyException) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyException.
+func (in *PolicyException) DeepCopy() *PolicyException {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyException)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyException) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyExceptionList) DeepCopyInto(out *PolicyExceptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PolicyException, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyExceptionList.
+func (in *PolicyExceptionList) DeepCopy() *PolicyExceptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyExceptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyExceptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyExceptionRule) DeepCopyInto(out *PolicyExceptionRule) {
+	*out = *in
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(PolicyExceptionProtocol)
+		**out = **in
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nets != nil {
+		in, out := &in.Nets, &out.Nets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyExceptionRule.
+func (in *PolicyExceptionRule) DeepCopy() *PolicyExceptionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyExceptionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyExceptionSpec) DeepCopyInto(out *PolicyExceptionSpec) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]PolicyExceptionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]PolicyExceptionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyExceptionSpec.
+func (in *PolicyExceptionSpec) DeepCopy() *PolicyExceptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyExceptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyRecommendation) DeepCopyInto(out *PolicyRecommendation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendation.
@@ -7706,6 +8691,21 @@ func (in *PolicyRecommendation) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationCounts) DeepCopyInto(out *PolicyRecommendationCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationCounts.
+func (in *PolicyRecommendationCounts) DeepCopy() *PolicyRecommendationCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyRecommendationDeployment) DeepCopyInto(out *PolicyRecommendationDeployment) {
 	*out = *in
@@ -7867,6 +8867,46 @@ func (in *PolicyRecommendationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRecommendationScopeConfig) DeepCopyInto(out *PolicyRecommendationScopeConfig) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(PolicyRecommendationMode)
+		**out = **in
+	}
+	if in.IncludeNamespaces != nil {
+		in, out := &in.IncludeNamespaces, &out.IncludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeNamespaces != nil {
+		in, out := &in.ExcludeNamespaces, &out.ExcludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StabilizationPeriod != nil {
+		in, out := &in.StabilizationPeriod, &out.StabilizationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationScopeConfig.
+func (in *PolicyRecommendationScopeConfig) DeepCopy() *PolicyRecommendationScopeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRecommendationScopeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyRecommendationSpec) DeepCopyInto(out *PolicyRecommendationSpec) {
 	*out = *in
@@ -7875,6 +8915,11 @@ func (in *PolicyRecommendationSpec) DeepCopyInto(out *PolicyRecommendationSpec)
 		*out = new(PolicyRecommendationDeployment)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(PolicyRecommendationScopeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationSpec.
@@ -7890,6 +8935,11 @@ func (in *PolicyRecommendationSpec) DeepCopy() *PolicyRecommendationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyRecommendationStatus) DeepCopyInto(out *PolicyRecommendationStatus) {
 	*out = *in
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = new(PolicyRecommendationCounts)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRecommendationStatus.
@@ -8542,6 +9592,55 @@ func (in *TenantStatus) DeepCopy() *TenantStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreatFeedHTTPHeader) DeepCopyInto(out *ThreatFeedHTTPHeader) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreatFeedHTTPHeader.
+func (in *ThreatFeedHTTPHeader) DeepCopy() *ThreatFeedHTTPHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreatFeedHTTPHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreatFeeds) DeepCopyInto(out *ThreatFeeds) {
+	*out = *in
+	if in.BuiltIn != nil {
+		in, out := &in.BuiltIn, &out.BuiltIn
+		*out = make([]BuiltInThreatFeed, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = make([]CustomThreatFeed, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreatFeeds.
+func (in *ThreatFeeds) DeepCopy() *ThreatFeeds {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreatFeeds)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TigeraStatus) DeepCopyInto(out *TigeraStatus) {
 	*out = *in
@@ -8585,6 +9684,32 @@ func (in *TigeraStatusCondition) DeepCopy() *TigeraStatusCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TigeraStatusEvent) DeepCopyInto(out *TigeraStatusEvent) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReconcileDuration != nil {
+		in, out := &in.ReconcileDuration, &out.ReconcileDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusEvent.
+func (in *TigeraStatusEvent) DeepCopy() *TigeraStatusEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(TigeraStatusEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TigeraStatusList) DeepCopyInto(out *TigeraStatusList) {
 	*out = *in
@@ -8642,6 +9767,13 @@ func (in *TigeraStatusStatus) DeepCopyInto(out *TigeraStatusStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]TigeraStatusEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TigeraStatusStatus.
@@ -8904,6 +10036,118 @@ func (in *UserSearch) DeepCopy() *UserSearch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WAFNamespaceMode) DeepCopyInto(out *WAFNamespaceMode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WAFNamespaceMode.
+func (in *WAFNamespaceMode) DeepCopy() *WAFNamespaceMode {
+	if in == nil {
+		return nil
+	}
+	out := new(WAFNamespaceMode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WAFRuleExclusion) DeepCopyInto(out *WAFRuleExclusion) {
+	*out = *in
+	if in.RuleIDs != nil {
+		in, out := &in.RuleIDs, &out.RuleIDs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PathPrefixes != nil {
+		in, out := &in.PathPrefixes, &out.PathPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WAFRuleExclusion.
+func (in *WAFRuleExclusion) DeepCopy() *WAFRuleExclusion {
+	if in == nil {
+		return nil
+	}
+	out := new(WAFRuleExclusion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WAFRulePack) DeepCopyInto(out *WAFRulePack) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WAFRulePack.
+func (in *WAFRulePack) DeepCopy() *WAFRulePack {
+	if in == nil {
+		return nil
+	}
+	out := new(WAFRulePack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WAFSettings) DeepCopyInto(out *WAFSettings) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(WAFMode)
+		**out = **in
+	}
+	if in.ParanoiaLevel != nil {
+		in, out := &in.ParanoiaLevel, &out.ParanoiaLevel
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InboundAnomalyScoreThreshold != nil {
+		in, out := &in.InboundAnomalyScoreThreshold, &out.InboundAnomalyScoreThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OutboundAnomalyScoreThreshold != nil {
+		in, out := &in.OutboundAnomalyScoreThreshold, &out.OutboundAnomalyScoreThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RulePacks != nil {
+		in, out := &in.RulePacks, &out.RulePacks
+		*out = make([]WAFRulePack, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuleExclusions != nil {
+		in, out := &in.RuleExclusions, &out.RuleExclusions
+		*out = make([]WAFRuleExclusion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamespaceModes != nil {
+		in, out := &in.NamespaceModes, &out.NamespaceModes
+		*out = make([]WAFNamespaceMode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WAFSettings.
+func (in *WAFSettings) DeepCopy() *WAFSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(WAFSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Whisker) DeepCopyInto(out *Whisker) {
 	*out = *in
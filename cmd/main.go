@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net/url"
@@ -34,6 +35,8 @@ import (
 	"github.com/tigera/operator/pkg/awssgsetup"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
+	installationctrl "github.com/tigera/operator/pkg/controller/installation"
+	"github.com/tigera/operator/pkg/controller/migration/convert"
 	"github.com/tigera/operator/pkg/controller/options"
 	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/crds"
@@ -103,6 +106,7 @@ func main() {
 	var sgSetup bool
 	var manageCRDs bool
 	var preDelete bool
+	var migrationPreflight bool
 	var variant string
 
 	// bootstrapCRDs is a flag that can be used to install the CRDs and exit. This is useful for
@@ -130,6 +134,7 @@ If a value other than 'all' is specified, the first CRD with a prefix of the spe
 	flag.BoolVar(&manageCRDs, "manage-crds", false, "Operator should manage the projectcalico.org and operator.tigera.io CRDs.")
 	flag.BoolVar(&preDelete, "pre-delete", false, "Run helm pre-deletion hook logic, then exit.")
 	flag.BoolVar(&bootstrapCRDs, "bootstrap-crds", false, "Install CRDs and exit")
+	flag.BoolVar(&migrationPreflight, "migration-preflight", false, "Check whether an existing Calico installation can be migrated, print every incompatibility found, then exit.")
 	flag.StringVar(&variant, "variant", string(operatortigeraiov1.Calico), "Default product variant to assume during boostrapping.")
 
 	opts := zap.Options{}
@@ -233,12 +238,40 @@ If a value other than 'all' is specified, the first CRD with a prefix of the spe
 		os.Exit(0)
 	}
 
+	if migrationPreflight {
+		report, err := convert.Preflight(ctx, c)
+		if err != nil {
+			log.Error(err, "Failed to run migration preflight")
+			os.Exit(1)
+		}
+		if report == nil {
+			fmt.Println("No existing Calico installation found.")
+			os.Exit(0)
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		if !report.Compatible {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	clusterDomain, err := dns.GetClusterDomain(dns.DefaultResolveConfPath)
+	if err != nil {
+		clusterDomain = dns.DefaultClusterDomain
+		log.Error(err, fmt.Sprintf("Couldn't find the cluster domain from the resolv.conf, defaulting to %s", clusterDomain))
+	}
+
 	// sigHandler is a context that is canceled when we receive a termination
 	// signal. We don't want to immeditely terminate upon receipt of such a signal since
 	// there may be cleanup required. So, we will pass a separate context to our controllers.
 	// That context will be canceled after a successful cleanup.
 	sigHandler := ctrl.SetupSignalHandler()
-	active.WaitUntilActive(cs, c, sigHandler, setupLog)
+	active.WaitUntilActive(cs, c, sigHandler, setupLog, installationctrl.CandidateValidator(c, cs, clusterDomain))
 	log.Info("Active operator: proceeding")
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
@@ -382,12 +415,6 @@ If a value other than 'all' is specified, the first CRD with a prefix of the spe
 	}
 	setupLog.WithValues("required", enterpriseCRDExists).Info("Checking if Enterprise controllers are required")
 
-	clusterDomain, err := dns.GetClusterDomain(dns.DefaultResolveConfPath)
-	if err != nil {
-		clusterDomain = dns.DefaultClusterDomain
-		log.Error(err, fmt.Sprintf("Couldn't find the cluster domain from the resolv.conf, defaulting to %s", clusterDomain))
-	}
-
 	kubernetesVersion, err := common.GetKubernetesVersion(clientset)
 	if err != nil {
 		log.Error(err, "Unable to resolve Kubernetes version, defaulting to v1.18")
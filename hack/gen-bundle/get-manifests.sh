@@ -36,6 +36,9 @@ CALICO_BASE_URL=${CALICO_BASE_URL}/${CALICO_VERSION}
 function downloadOperatorManifests() {
     curl -fsSL ${CALICO_BASE_URL}/manifests/ocp-tigera-operator-no-resource-loading.yaml --output ${BUNDLE_DEPLOY_DIR}/operator.yaml
     curl -fsSL ${CALICO_BASE_URL}/manifests/ocp/02-role-tigera-operator.yaml --output ${BUNDLE_DEPLOY_DIR}/role.yaml
+    # The status manager emits Events for TigeraStatus condition transitions, so add a rule
+    # allowing the operator to create them if the downloaded ClusterRole does not have one.
+    yq -i '(select(.kind == "ClusterRole" and ([.rules[] | select(.resources[] == "events")] | length == 0)) | .rules) += [{"apiGroups": [""], "resources": ["events"], "verbs": ["create"]}]' ${BUNDLE_DEPLOY_DIR}/role.yaml
     # The binding is required unlike in earlier bundle generation. The
     # 'operator-sdk generate bundle' command combines clusterroles bound to service
     # accounts. The resulting permissions is set to the CSV's
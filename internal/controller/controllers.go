@@ -23,6 +23,9 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// The status manager shared by the controllers emits Events for TigeraStatus condition transitions.
+// +kubebuilder:rbac:groups="",resources=events,verbs=create
+
 func AddToManager(mgr ctrl.Manager, options options.AddOptions) error {
 	if err := (&IPPoolReconciler{
 		Client: mgr.GetClient(),
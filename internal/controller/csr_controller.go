@@ -32,6 +32,7 @@ type CSRReconciler struct {
 	Scheme *runtime.Scheme
 }
 
+// +kubebuilder:rbac:groups=operator.tigera.io,resources=certificatesigningpolicies,verbs=get;list;watch
 func (r *CSRReconciler) SetupWithManager(mgr ctrl.Manager, opts options.AddOptions) error {
 	return csr.Add(mgr, opts)
 }
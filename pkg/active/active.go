@@ -94,7 +94,10 @@ func GenerateMyActiveConfigMap() *corev1.ConfigMap {
 var OsExitOverride = os.Exit
 var TickerRateOverride = 1000 * time.Millisecond
 
-func WaitUntilActive(cs *kubernetes.Clientset, client client.Client, ctx context.Context, log logr.Logger) {
+// WaitUntilActive blocks until this operator is the active operator. While it waits, it takes
+// part in any handoff that involves this operator, using validate to check this operator when it
+// is the candidate.
+func WaitUntilActive(cs *kubernetes.Clientset, client client.Client, ctx context.Context, log logr.Logger, validate Validator) {
 	acm := GenerateMyActiveConfigMap()
 	listWatch := cache.NewListWatchFromClient(cs.CoreV1().RESTClient(), "configmaps", acm.Namespace, fields.OneTermEqualSelector("metadata.name", acm.Name))
 
@@ -148,6 +151,7 @@ func WaitUntilActive(cs *kubernetes.Clientset, client client.Client, ctx context
 			inactiveReport = false
 			currentActive = ns
 		}
+		handoffStep(ctx, client, cm, validate, log)
 		select {
 		case <-ticker.C:
 		case <-ctx.Done():
@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package active
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+// The active operator ConfigMap also drives a blue/green handoff from the active operator to a
+// candidate operator running in a second namespace:
+//
+//  1. The candidate is deployed, and the candidate-namespace key is set to its namespace.
+//  2. The candidate, while waiting as an inactive operator, validates that it can reconcile the
+//     cluster and records the result in candidate-status, with any error in candidate-message.
+//  3. When the candidate is Ready, the active operator yields: it makes the candidate active,
+//     records itself in previous-namespace along with the handoff-time, and restarts as an
+//     inactive operator.
+//  4. Until the rollback-grace-period (default 10m) has passed, the previous operator watches the
+//     TigeraStatuses. If any of them becomes degraded it makes itself active again, and marks the
+//     candidate as Failed. Once the grace period has passed it clears the handoff keys.
+const (
+	candidateNamespaceKey = "candidate-namespace"
+	candidateStatusKey    = "candidate-status"
+	candidateMessageKey   = "candidate-message"
+	previousNamespaceKey  = "previous-namespace"
+	handoffTimeKey        = "handoff-time"
+	gracePeriodKey        = "rollback-grace-period"
+
+	defaultGracePeriod = 10 * time.Minute
+)
+
+type CandidateStatus string
+
+const (
+	CandidateReady  CandidateStatus = "Ready"
+	CandidateFailed CandidateStatus = "Failed"
+)
+
+// Validator checks that this operator is able to reconcile the cluster, before it is handed over
+// control as a candidate.
+type Validator func(ctx context.Context) error
+
+// RecordCandidateValidation returns a copy of the ConfigMap with the result of validating this
+// operator as the candidate. It returns nil if this operator is not a candidate that still needs
+// to be validated.
+func RecordCandidateValidation(cm *corev1.ConfigMap, validationErr error) *corev1.ConfigMap {
+	if !needsValidation(cm) {
+		return nil
+	}
+	out := cm.DeepCopy()
+	if validationErr != nil {
+		out.Data[candidateStatusKey] = string(CandidateFailed)
+		out.Data[candidateMessageKey] = validationErr.Error()
+	} else {
+		out.Data[candidateStatusKey] = string(CandidateReady)
+		delete(out.Data, candidateMessageKey)
+	}
+	return out
+}
+
+func needsValidation(cm *corev1.ConfigMap) bool {
+	return cm != nil && cm.Data[candidateNamespaceKey] == operatorNamespace() && cm.Data[candidateStatusKey] == ""
+}
+
+// YieldToCandidate returns a copy of the ConfigMap that hands control from this operator to the
+// candidate operator. It returns nil if this operator is not active or there is no validated
+// candidate.
+func YieldToCandidate(cm *corev1.ConfigMap, now time.Time) *corev1.ConfigMap {
+	if cm == nil || CandidateStatus(cm.Data[candidateStatusKey]) != CandidateReady {
+		return nil
+	}
+	candidate := cm.Data[candidateNamespaceKey]
+	if candidate == "" || candidate == operatorNamespace() {
+		return nil
+	}
+	if isActive, _ := IsThisOperatorActive(cm); !isActive {
+		return nil
+	}
+
+	out := cm.DeepCopy()
+	out.Data[activeNamespaceKey] = candidate
+	out.Data[previousNamespaceKey] = operatorNamespace()
+	out.Data[handoffTimeKey] = now.UTC().Format(time.RFC3339)
+	delete(out.Data, candidateNamespaceKey)
+	delete(out.Data, candidateStatusKey)
+	delete(out.Data, candidateMessageKey)
+	return out
+}
+
+// CheckHandoff is run by the operator that yielded control in a handoff. It returns a copy of the
+// ConfigMap that rolls control back to this operator if any TigeraStatus has become degraded since
+// the handoff, or that completes the handoff once the grace period has passed. It returns nil if
+// no change is needed.
+func CheckHandoff(cm *corev1.ConfigMap, statuses []operatorv1.TigeraStatus, now time.Time) (*corev1.ConfigMap, error) {
+	if cm == nil || cm.Data[previousNamespaceKey] != operatorNamespace() {
+		return nil, nil
+	}
+	handoffTime, err := time.Parse(time.RFC3339, cm.Data[handoffTimeKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", handoffTimeKey, cm.Data[handoffTimeKey], err)
+	}
+	gracePeriod := defaultGracePeriod
+	if v, ok := cm.Data[gracePeriodKey]; ok {
+		if gracePeriod, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", gracePeriodKey, v, err)
+		}
+	}
+
+	out := cm.DeepCopy()
+	delete(out.Data, previousNamespaceKey)
+	delete(out.Data, handoffTimeKey)
+
+	if reason := degradedSince(statuses, handoffTime); reason != "" {
+		out.Data[candidateNamespaceKey] = cm.Data[activeNamespaceKey]
+		out.Data[candidateStatusKey] = string(CandidateFailed)
+		out.Data[candidateMessageKey] = fmt.Sprintf("rolled back: %s", reason)
+		out.Data[activeNamespaceKey] = operatorNamespace()
+		return out, nil
+	}
+	if now.After(handoffTime.Add(gracePeriod)) {
+		return out, nil
+	}
+	return nil, nil
+}
+
+// degradedSince returns a description of the first TigeraStatus that became degraded after the
+// given time, or an empty string if there is none.
+func degradedSince(statuses []operatorv1.TigeraStatus, t time.Time) string {
+	for _, ts := range statuses {
+		for _, cond := range ts.Status.Conditions {
+			if cond.Type == operatorv1.ComponentDegraded && cond.Status == operatorv1.ConditionTrue && !cond.LastTransitionTime.Time.Before(t) {
+				return fmt.Sprintf("TigeraStatus %s is degraded: %s", ts.Name, cond.Message)
+			}
+		}
+	}
+	return ""
+}
+
+// handoffStep carries out the part of the handoff that is owned by an inactive operator: validating
+// itself when it is the candidate, and watching for a rollback when it has yielded control.
+// Failures are logged and the step is retried on the next call.
+func handoffStep(ctx context.Context, c client.Client, cm *corev1.ConfigMap, validate Validator, log logr.Logger) {
+	var update *corev1.ConfigMap
+	if needsValidation(cm) {
+		var err error
+		if validate != nil {
+			err = validate(ctx)
+		}
+		if err != nil {
+			log.Error(err, "Candidate operator failed validation")
+		} else {
+			log.Info("Candidate operator validated, waiting for the active operator to yield")
+		}
+		update = RecordCandidateValidation(cm, err)
+	} else if cm != nil && cm.Data[previousNamespaceKey] == operatorNamespace() {
+		statuses := &operatorv1.TigeraStatusList{}
+		if err := c.List(ctx, statuses); err != nil {
+			log.Error(err, "Failed to list TigeraStatuses to check the handoff")
+			return
+		}
+		var err error
+		if update, err = CheckHandoff(cm, statuses.Items, time.Now()); err != nil {
+			log.Error(err, "Failed to check the handoff")
+			return
+		}
+		if update != nil && update.Data[activeNamespaceKey] == operatorNamespace() {
+			log.Info("Rolling back the handoff", "reason", update.Data[candidateMessageKey])
+		} else if update != nil {
+			log.Info("Handoff completed", "active-namespace", update.Data[activeNamespaceKey])
+		}
+	}
+	if update == nil {
+		return
+	}
+	if err := c.Update(ctx, update); err != nil {
+		log.Error(err, "Failed to update the active operator ConfigMap")
+	}
+}
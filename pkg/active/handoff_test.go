@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package active
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
+)
+
+var _ = Describe("operator handoff", func() {
+	var (
+		c       client.Client
+		ctx     context.Context
+		handoff time.Time
+	)
+
+	activeCM := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ActiveConfigMapName,
+				Namespace: common.CalicoNamespace,
+			},
+			Data: data,
+		}
+	}
+
+	degradedStatus := func(t time.Time) operatorv1.TigeraStatus {
+		return operatorv1.TigeraStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: "calico"},
+			Status: operatorv1.TigeraStatusStatus{
+				Conditions: []operatorv1.TigeraStatusCondition{{
+					Type:               operatorv1.ComponentDegraded,
+					Status:             operatorv1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(t),
+					Message:            "calico-node is crashing",
+				}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		c = ctrlrfake.DefaultFakeClientBuilder(scheme).Build()
+		ctx = context.Background()
+		handoff = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	AfterEach(func() {
+		operatorNamespace = common.OperatorNamespace
+	})
+
+	Context("as the candidate", func() {
+		BeforeEach(func() {
+			operatorNamespace = func() string { return "tigera-operator-green" }
+		})
+
+		It("records whether validation succeeded", func() {
+			cm := activeCM(map[string]string{"active-namespace": "tigera-operator", "candidate-namespace": "tigera-operator-green"})
+			out := RecordCandidateValidation(cm, nil)
+			Expect(out.Data).To(HaveKeyWithValue("candidate-status", "Ready"))
+			Expect(cm.Data).NotTo(HaveKey("candidate-status"))
+
+			out = RecordCandidateValidation(cm, fmt.Errorf("invalid Installation"))
+			Expect(out.Data).To(HaveKeyWithValue("candidate-status", "Failed"))
+			Expect(out.Data).To(HaveKeyWithValue("candidate-message", "invalid Installation"))
+
+			// Validation only happens once.
+			Expect(RecordCandidateValidation(out, nil)).To(BeNil())
+		})
+
+		It("does not validate when it is not the candidate", func() {
+			cm := activeCM(map[string]string{"active-namespace": "tigera-operator", "candidate-namespace": "tigera-operator-blue"})
+			Expect(RecordCandidateValidation(cm, nil)).To(BeNil())
+			Expect(RecordCandidateValidation(nil, nil)).To(BeNil())
+		})
+
+		It("validates itself while waiting to become active", func() {
+			cm := activeCM(map[string]string{"active-namespace": "tigera-operator", "candidate-namespace": "tigera-operator-green"})
+			Expect(c.Create(ctx, cm)).NotTo(HaveOccurred())
+
+			validated := false
+			handoffStep(ctx, c, cm, func(context.Context) error { validated = true; return nil }, logf.Log)
+			Expect(validated).To(BeTrue())
+
+			Expect(c.Get(ctx, types.NamespacedName{Name: ActiveConfigMapName, Namespace: common.CalicoNamespace}, cm)).NotTo(HaveOccurred())
+			Expect(cm.Data).To(HaveKeyWithValue("candidate-status", "Ready"))
+		})
+	})
+
+	Context("as the active operator", func() {
+		It("yields to a validated candidate", func() {
+			cm := activeCM(map[string]string{
+				"active-namespace":    "tigera-operator",
+				"candidate-namespace": "tigera-operator-green",
+				"candidate-status":    "Ready",
+			})
+			out := YieldToCandidate(cm, handoff)
+			Expect(out.Data).To(Equal(map[string]string{
+				"active-namespace":   "tigera-operator-green",
+				"previous-namespace": "tigera-operator",
+				"handoff-time":       "2025-06-01T12:00:00Z",
+			}))
+		})
+
+		It("does not yield to a candidate that is not ready", func() {
+			cm := activeCM(map[string]string{"active-namespace": "tigera-operator", "candidate-namespace": "tigera-operator-green"})
+			Expect(YieldToCandidate(cm, handoff)).To(BeNil())
+			cm.Data["candidate-status"] = "Failed"
+			Expect(YieldToCandidate(cm, handoff)).To(BeNil())
+			Expect(YieldToCandidate(nil, handoff)).To(BeNil())
+		})
+	})
+
+	Context("as the previous operator", func() {
+		var cm *corev1.ConfigMap
+
+		BeforeEach(func() {
+			cm = activeCM(map[string]string{
+				"active-namespace":      "tigera-operator-green",
+				"previous-namespace":    "tigera-operator",
+				"handoff-time":          "2025-06-01T12:00:00Z",
+				"rollback-grace-period": "5m",
+			})
+		})
+
+		It("rolls back when a TigeraStatus degrades within the grace period", func() {
+			out, err := CheckHandoff(cm, []operatorv1.TigeraStatus{degradedStatus(handoff.Add(time.Minute))}, handoff.Add(2*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.Data).To(Equal(map[string]string{
+				"active-namespace":      "tigera-operator",
+				"candidate-namespace":   "tigera-operator-green",
+				"candidate-status":      "Failed",
+				"candidate-message":     "rolled back: TigeraStatus calico is degraded: calico-node is crashing",
+				"rollback-grace-period": "5m",
+			}))
+		})
+
+		It("ignores TigeraStatuses that were degraded before the handoff", func() {
+			out, err := CheckHandoff(cm, []operatorv1.TigeraStatus{degradedStatus(handoff.Add(-time.Minute))}, handoff.Add(2*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(BeNil())
+		})
+
+		It("completes the handoff after the grace period", func() {
+			out, err := CheckHandoff(cm, nil, handoff.Add(6*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.Data).To(Equal(map[string]string{
+				"active-namespace":      "tigera-operator-green",
+				"rollback-grace-period": "5m",
+			}))
+		})
+
+		It("becomes active again after rolling back", func() {
+			Expect(c.Create(ctx, cm)).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, &operatorv1.TigeraStatus{
+				ObjectMeta: metav1.ObjectMeta{Name: "calico"},
+				Status:     degradedStatus(time.Now()).Status,
+			})).NotTo(HaveOccurred())
+			cm.Data["handoff-time"] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+			handoffStep(ctx, c, cm, nil, logf.Log)
+
+			Expect(c.Get(ctx, types.NamespacedName{Name: ActiveConfigMapName, Namespace: common.CalicoNamespace}, cm)).NotTo(HaveOccurred())
+			isActive, _ := IsThisOperatorActive(cm)
+			Expect(isActive).To(BeTrue())
+		})
+
+		It("rejects an invalid grace period", func() {
+			cm.Data["rollback-grace-period"] = "soon"
+			_, err := CheckHandoff(cm, nil, handoff)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
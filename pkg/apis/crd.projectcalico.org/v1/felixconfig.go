@@ -415,6 +415,13 @@ type FelixConfigurationSpec struct {
 	// WAFEventLogsFileEnabled controls logging WAFEvent logs to a file. If false no WAFEvent logging to file will occur.
 	// [Default: false]
 	WAFEventLogsFileEnabled *bool `json:"wafEventLogsFileEnabled,omitempty"`
+
+	// CaptureMaxSizeBytes controls the max size of a file capture. [Default: 10000000]
+	CaptureMaxSizeBytes *int `json:"captureMaxSizeBytes,omitempty"`
+	// CaptureRotationSeconds controls the time rotation of a packet capture. [Default: 3600]
+	CaptureRotationSeconds *int `json:"captureRotationSeconds,omitempty"`
+	// CaptureMaxFiles controls number of rotated capture file to keep. [Default: 2]
+	CaptureMaxFiles *int `json:"captureMaxFiles,omitempty"`
 }
 
 type RouteTableRange struct {
@@ -42,6 +42,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&KubeControllersConfigurationList{},
 		&BGPConfiguration{},
 		&BGPConfigurationList{},
+		&BGPPeer{},
+		&BGPPeerList{},
 		&ExternalNetwork{},
 		&ExternalNetworkList{},
 		&ClusterInformation{},
@@ -168,6 +168,89 @@ func (in *BGPPassword) DeepCopy() *BGPPassword {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeer) DeepCopyInto(out *BGPPeer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeer.
+func (in *BGPPeer) DeepCopy() *BGPPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BGPPeer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeerList) DeepCopyInto(out *BGPPeerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BGPPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeerList.
+func (in *BGPPeerList) DeepCopy() *BGPPeerList {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BGPPeerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BGPPeerSpec) DeepCopyInto(out *BGPPeerSpec) {
+	*out = *in
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(BGPPassword)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxRestartTime != nil {
+		in, out := &in.MaxRestartTime, &out.MaxRestartTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BGPPeerSpec.
+func (in *BGPPeerSpec) DeepCopy() *BGPPeerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BGPPeerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterInformation) DeepCopyInto(out *ClusterInformation) {
 	*out = *in
@@ -795,6 +878,21 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CaptureMaxSizeBytes != nil {
+		in, out := &in.CaptureMaxSizeBytes, &out.CaptureMaxSizeBytes
+		*out = new(int)
+		**out = **in
+	}
+	if in.CaptureRotationSeconds != nil {
+		in, out := &in.CaptureRotationSeconds, &out.CaptureRotationSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.CaptureMaxFiles != nil {
+		in, out := &in.CaptureMaxFiles, &out.CaptureMaxFiles
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FelixConfigurationSpec.
@@ -44,4 +44,17 @@ const (
 
 	// Sidecar common names
 	SidecarMutatingWebhookConfigName = "tigera-sidecar-webhook-configuration"
+
+	// StagedRolloutLabel is set on allow-tigera tier StagedNetworkPolicies that the operator is soaking before
+	// promoting them to enforced NetworkPolicies.
+	StagedRolloutLabel = "operator.tigera.io/policy-rollout"
+	// StagedRolloutHashAnnotation records the hash of the policy spec that was staged, so that an enforced policy
+	// that has already been promoted is not staged again.
+	StagedRolloutHashAnnotation = "operator.tigera.io/policy-rollout-hash"
+	// StagedRolloutSinceAnnotation records when the current policy spec was staged, in RFC3339 format.
+	StagedRolloutSinceAnnotation = "operator.tigera.io/staged-since"
+	// StagedRolloutDeniedFlowsAnnotation records the number of flows the staged policy would have denied.
+	StagedRolloutDeniedFlowsAnnotation = "operator.tigera.io/denied-flows"
+	// StagedRolloutUnverifiedAnnotation records why the staged policy could not be checked against flow logs.
+	StagedRolloutUnverifiedAnnotation = "operator.tigera.io/unverified"
 )
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
@@ -111,10 +112,13 @@ func add(mgr manager.Manager, c ctrlruntime.Controller) error {
 	// Watch for configmap changes in tigera-operator namespace; the cm contains config for Coraza library:
 	err = utils.AddConfigMapWatch(c, applicationlayer.WAFRulesetConfigMapName, common.OperatorNamespace(), &handler.EnqueueRequestForObject{})
 	if err != nil {
-		return fmt.Errorf(
-			"applicationlayer-controller failed to watch ConfigMap %s: %v",
-			applicationlayer.WAFRulesetConfigMapName, err,
-		)
+		return fmt.Errorf("applicationlayer-controller failed to watch ConfigMap %s: %v", applicationlayer.WAFRulesetConfigMapName, err)
+	}
+
+	// Watch the WAF rule pack ConfigMaps.
+	err = utils.AddLabeledConfigMapWatch(c, ruleset.RulePackLabel, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("applicationlayer-controller failed to watch WAF rule pack ConfigMaps: %v", err)
 	}
 
 	// Watch mutatingwebhookconfiguration responsible for sidecar injetion
@@ -168,6 +172,7 @@ type ReconcileApplicationLayer struct {
 // Reconcile reads that state of the cluster for a ApplicationLayer object and makes changes
 // based on the state read and what is in the ApplicationLayer.Spec.
 func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling ApplicationLayer")
 
@@ -189,7 +194,7 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 	}
 	r.status.OnCRFound()
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating application layer status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -248,21 +253,29 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 	}
 
 	var passthroughWAFRulesetConfig bool
-	var wafRulesetConfig, defaultCoreRuleSet *corev1.ConfigMap
+	var wafRulesetConfig, baseWAFRulesetConfig, defaultCoreRuleSet *corev1.ConfigMap
 	if r.isWAFEnabled(&instance.Spec) || r.isSidecarInjectionEnabled(&instance.Spec) {
 		if defaultCoreRuleSet, err = ruleset.GetOWASPCoreRuleSet(); err != nil {
 			r.status.SetDegraded(operatorv1.ResourceReadError, "Error getting Web Application Firewall OWASP core ruleset", err, reqLogger)
 			return reconcile.Result{}, err
 		}
 
-		if wafRulesetConfig, passthroughWAFRulesetConfig, err = r.getWAFRulesetConfig(ctx); err != nil {
+		if baseWAFRulesetConfig, passthroughWAFRulesetConfig, err = r.getWAFRulesetConfig(ctx); err != nil {
 			r.status.SetDegraded(operatorv1.ResourceReadError, "Error getting Web Application Firewall ruleset config", err, reqLogger)
 			return reconcile.Result{}, err
 		}
-		if err = ruleset.ValidateWAFRulesetConfig(wafRulesetConfig); err != nil {
+
+		wafSettings := instance.Spec.WebApplicationFirewallSettings
+		rulePacks, err := utils.GetLabeledConfigMaps(ctx, r.client, ruleset.RulePackLabel, ruleset.RulePackNames(wafSettings))
+		if err != nil {
+			return reconcile.Result{}, utils.SetLabeledConfigMapDegraded(r.status, err, "Web Application Firewall rule pack ConfigMap", reqLogger)
+		}
+		if err = ruleset.ValidateWAFRulesetConfig(baseWAFRulesetConfig, wafSettings, rulePacks); err != nil {
 			r.status.SetDegraded(operatorv1.ResourceValidationError, "Error validating Web Application Firewall ruleset config", err, reqLogger)
 			return reconcile.Result{}, err
 		}
+		// Only the base config is copied to the operator namespace; the settings are merged in on every reconcile.
+		wafRulesetConfig = ruleset.ApplyWAFSettings(baseWAFRulesetConfig, wafSettings, rulePacks)
 	}
 
 	lcSpec := instance.Spec.LogCollection
@@ -292,7 +305,7 @@ func (r *ReconcileApplicationLayer) Reconcile(ctx context.Context, request recon
 	}
 
 	if passthroughWAFRulesetConfig {
-		err = ch.CreateOrUpdateOrDelete(ctx, render.NewPassthrough(wafRulesetConfig), r.status)
+		err = ch.CreateOrUpdateOrDelete(ctx, render.NewPassthrough(baseWAFRulesetConfig), r.status)
 		if err != nil {
 			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error creating / updating resource", err, reqLogger)
 			return reconcile.Result{}, err
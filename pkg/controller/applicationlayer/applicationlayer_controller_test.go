@@ -25,6 +25,7 @@ import (
 	admregv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,7 +41,9 @@ import (
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
 	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
+	"github.com/tigera/operator/pkg/ptr"
 	"github.com/tigera/operator/pkg/render/applicationlayer"
+	"github.com/tigera/operator/pkg/render/applicationlayer/ruleset"
 	"github.com/tigera/operator/test"
 )
 
@@ -364,6 +367,94 @@ var _ = Describe("Application layer controller tests", func() {
 
 			Expect(*instance.Status.SidecarWebhook).To(Equal(operatorv1.SidecarWebhookStateDisabled))
 		})
+
+		It("should merge WAF settings and rule packs into the ruleset config", func() {
+			mockStatus.On("AddDaemonsets", mock.Anything).Return()
+			mockStatus.On("AddDeployments", mock.Anything).Return()
+			mockStatus.On("IsAvailable").Return(true)
+			mockStatus.On("AddStatefulSets", mock.Anything).Return()
+			mockStatus.On("AddCronJobs", mock.Anything)
+			mockStatus.On("ClearDegraded")
+			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetMetaData", mock.Anything).Return()
+			Expect(c.Create(ctx, installation)).NotTo(HaveOccurred())
+
+			By("creating a rule pack in the operator namespace")
+			Expect(c.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-rules",
+					Namespace: common.OperatorNamespace(),
+					Labels:    map[string]string{ruleset.RulePackLabel: "true"},
+				},
+				Data: map[string]string{"custom.conf": "SecRule REQUEST_URI \"@streq /admin\" \"id:1001,phase:1,deny\""},
+			})).NotTo(HaveOccurred())
+
+			By("applying the ApplicationLayer CR with WAF settings")
+			waf := operatorv1.WAFEnabled
+			blocking := operatorv1.WAFModeBlocking
+			Expect(c.Create(ctx, &operatorv1.ApplicationLayer{
+				ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+				Spec: operatorv1.ApplicationLayerSpec{
+					WebApplicationFirewall: &waf,
+					WebApplicationFirewallSettings: &operatorv1.WAFSettings{
+						Mode:          &blocking,
+						ParanoiaLevel: ptr.Int32ToPtr(2),
+						RulePacks:     []operatorv1.WAFRulePack{{ConfigMapName: "my-rules"}},
+					},
+				},
+			})).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			By("ensuring the rendered ruleset config contains the settings and the rule pack")
+			cm := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: applicationlayer.WAFRulesetConfigMapName, Namespace: common.CalicoNamespace},
+			}
+			Expect(test.GetResource(c, &cm)).To(BeNil())
+			Expect(cm.Data).To(HaveKey("my-rules-custom.conf"))
+			Expect(cm.Data[ruleset.BeforeCRSFile]).To(ContainSubstring("setvar:tx.blocking_paranoia_level=2"))
+			Expect(cm.Data[ruleset.AfterCRSFile]).To(ContainSubstring("Include my-rules-custom.conf"))
+			Expect(cm.Data[ruleset.AfterCRSFile]).To(ContainSubstring("SecRuleEngine On"))
+
+			By("ensuring the copy in the operator namespace is the unmodified default")
+			base := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: applicationlayer.WAFRulesetConfigMapName, Namespace: common.OperatorNamespace()},
+			}
+			Expect(test.GetResource(c, &base)).To(BeNil())
+			Expect(base.Data).NotTo(HaveKey(ruleset.AfterCRSFile))
+			Expect(base.Data).NotTo(HaveKey("my-rules-custom.conf"))
+
+			By("ensuring only labelled rule packs are watched")
+			pack := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "my-rules", Namespace: common.OperatorNamespace()}, pack)).To(Succeed())
+			Expect(utils.IsLabeledConfigMap(pack, ruleset.RulePackLabel)).To(BeTrue())
+			Expect(utils.IsLabeledConfigMap(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-config", Namespace: common.OperatorNamespace()},
+			}, ruleset.RulePackLabel)).To(BeFalse())
+		})
+
+		It("should degrade when a referenced rule pack does not exist", func() {
+			mockStatus.On("SetMetaData", mock.Anything).Return()
+			mockStatus.On("SetDegraded", operatorv1.ResourceNotFound, "Web Application Firewall rule pack ConfigMap not found", mock.Anything, mock.Anything).Return()
+			Expect(c.Create(ctx, installation)).NotTo(HaveOccurred())
+
+			waf := operatorv1.WAFEnabled
+			Expect(c.Create(ctx, &operatorv1.ApplicationLayer{
+				ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+				Spec: operatorv1.ApplicationLayerSpec{
+					WebApplicationFirewall: &waf,
+					WebApplicationFirewallSettings: &operatorv1.WAFSettings{
+						RulePacks: []operatorv1.WAFRulePack{{ConfigMapName: "missing"}},
+					},
+				},
+			})).NotTo(HaveOccurred())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			mockStatus.AssertExpectations(GinkgoT())
+		})
+
 		Context("Reconcile for Condition status", func() {
 			generation := int64(2)
 			BeforeEach(func() {
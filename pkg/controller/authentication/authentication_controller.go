@@ -17,6 +17,7 @@ package authentication
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"golang.org/x/net/http/httpproxy"
 	v1 "k8s.io/api/apps/v1"
@@ -180,6 +181,7 @@ type ReconcileAuthentication struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling ", "controller", controllerName)
 
@@ -195,7 +197,7 @@ func (r *ReconcileAuthentication) Reconcile(ctx context.Context, request reconci
 	r.status.OnCRFound()
 
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&authentication.ObjectMeta)
+	defer r.status.SetMetaData(authentication)
 
 	// Changes for updating application layer status conditions
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	rcertificatemanagement "github.com/tigera/operator/pkg/render/certificatemanagement"
 
@@ -202,6 +203,7 @@ type ReconcileConnection struct {
 // processed again if the returned error is non-nil or Result.Requeue is true, otherwise upon completion it will
 // remove the work from the queue.
 func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.V(2).Info("Reconciling the management cluster connection")
 	result := reconcile.Result{}
@@ -228,7 +230,7 @@ func (r *ReconcileConnection) Reconcile(ctx context.Context, request reconcile.R
 	}
 	r.status.OnCRFound()
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&managementClusterConnection.ObjectMeta)
+	defer r.status.SetMetaData(managementClusterConnection)
 
 	// Changes for updating ManagementClusterConnection status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
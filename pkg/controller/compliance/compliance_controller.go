@@ -17,7 +17,9 @@ package compliance
 import (
 	"context"
 	"fmt"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -136,6 +138,13 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("compliance-controller failed to watch resource: %w", err)
 	}
 
+	if !opts.MultiTenant {
+		// Watch the ConfigMaps that hold custom report types.
+		if err = utils.AddLabeledConfigMapWatch(complianceController, render.CustomReportTypeConfigMapLabel, eventHandler); err != nil {
+			return fmt.Errorf("compliance-controller failed to watch custom report type ConfigMaps: %w", err)
+		}
+	}
+
 	// Watch for changes to TigeraStatus.
 	if err = utils.AddTigeraStatusWatch(complianceController, ResourceName); err != nil {
 		return fmt.Errorf("compliance-controller failed to watch compliance Tigerastatus: %w", err)
@@ -198,6 +207,7 @@ func GetCompliance(ctx context.Context, cli client.Client, mt bool, ns string) (
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	helper := utils.NewNamespaceHelper(r.multiTenant, render.ComplianceNamespace, request.Namespace)
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "installNS", helper.InstallNamespace(), "truthNS", helper.TruthNamespace())
 	reqLogger.Info("Reconciling Compliance")
@@ -235,7 +245,7 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating Compliance status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -446,6 +456,35 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 		CreateNamespace: !tenant.MultiTenant(),
 	})
 
+	var customReportTypeConfigMaps []*corev1.ConfigMap
+	var customReportTypesDeployed []string
+	var reportsDeployed []string
+	if !tenant.MultiTenant() {
+		var names []string
+		for _, src := range instance.Spec.CustomReportTypes {
+			names = append(names, src.ConfigMapName)
+		}
+		if customReportTypeConfigMaps, err = utils.GetLabeledConfigMaps(ctx, r.client, render.CustomReportTypeConfigMapLabel, names); err != nil {
+			return reconcile.Result{}, utils.SetLabeledConfigMapDegraded(r.status, err, "custom report type ConfigMap", reqLogger)
+		}
+		reportTypes := &v3.GlobalReportTypeList{}
+		if err = r.client.List(ctx, reportTypes, client.HasLabels{render.CustomReportTypeLabel}); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying GlobalReportTypes", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		for _, rt := range reportTypes.Items {
+			customReportTypesDeployed = append(customReportTypesDeployed, rt.Name)
+		}
+		reports := &v3.GlobalReportList{}
+		if err = r.client.List(ctx, reports, client.HasLabels{render.ComplianceReportLabel}); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying GlobalReports", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		for _, report := range reports.Items {
+			reportsDeployed = append(reportsDeployed, report.Name)
+		}
+	}
+
 	hasNoLicense := !utils.IsFeatureActive(license, common.ComplianceFeature)
 	openshift := r.provider.IsOpenShift()
 	complianceCfg := &render.ComplianceConfiguration{
@@ -468,6 +507,9 @@ func (r *ReconcileCompliance) Reconcile(ctx context.Context, request reconcile.R
 		Tenant:                      tenant,
 		Compliance:                  instance,
 		ExternalElastic:             r.externalElastic,
+		CustomReportTypeConfigMaps:  customReportTypeConfigMaps,
+		CustomReportTypesDeployed:   customReportTypesDeployed,
+		ReportsDeployed:             reportsDeployed,
 	}
 
 	// Render the desired objects from the CRD and create or update them.
@@ -190,6 +190,70 @@ var _ = Describe("Compliance controller tests", func() {
 		Expect(dpl.Spec.Template.ObjectMeta.Name).To(Equal(render.ComplianceControllerName))
 	})
 
+	It("should install custom report types and remove those that are no longer provided", func() {
+		reportType := func(name string) string {
+			return "apiVersion: projectcalico.org/v3\nkind: GlobalReportType\nmetadata:\n  name: " + name + "\nspec:\n  includeEndpointData: true\n"
+		}
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "custom-reports", Namespace: common.OperatorNamespace()},
+			Data:       map[string]string{"soc2.yaml": reportType("soc2")},
+		}
+		Expect(c.Create(ctx, cm)).NotTo(HaveOccurred())
+		cr.Spec.CustomReportTypes = []operatorv1.ComplianceReportTypeSource{{ConfigMapName: "custom-reports"}}
+		Expect(c.Update(ctx, cr)).NotTo(HaveOccurred())
+
+		By("rejecting a ConfigMap without the custom report type label")
+		mockStatus.On("SetDegraded", operatorv1.ResourceValidationError, "Custom report type ConfigMap is missing the "+render.CustomReportTypeConfigMapLabel+" label", mock.Anything, mock.Anything).Return().Once()
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(utils.IsLabeledConfigMap(cm, render.CustomReportTypeConfigMapLabel)).To(BeFalse())
+		Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "soc2"}, &v3.GlobalReportType{}))).To(BeTrue())
+
+		cm.Labels = map[string]string{render.CustomReportTypeConfigMapLabel: "true"}
+		Expect(c.Update(ctx, cm)).NotTo(HaveOccurred())
+		Expect(utils.IsLabeledConfigMap(cm, render.CustomReportTypeConfigMapLabel)).To(BeTrue())
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "pci-dss-network-segmentation"}, &v3.GlobalReportType{})).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "nsa-cisa-kubernetes-hardening"}, &v3.GlobalReportType{})).NotTo(HaveOccurred())
+		rt := &v3.GlobalReportType{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "soc2"}, rt)).NotTo(HaveOccurred())
+		Expect(rt.Labels).To(HaveKeyWithValue(render.CustomReportTypeLabel, "custom-reports"))
+
+		cm.Data = map[string]string{"hipaa.yaml": reportType("hipaa")}
+		Expect(c.Update(ctx, cm)).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "hipaa"}, &v3.GlobalReportType{})).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "soc2"}, &v3.GlobalReportType{}))).To(BeTrue())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "cis-benchmark"}, &v3.GlobalReportType{})).NotTo(HaveOccurred())
+	})
+
+	It("should own the reports listed in the Compliance CR", func() {
+		cr.Spec.Reports = []operatorv1.ComplianceReport{
+			{Name: "daily-inventory", ReportType: "inventory", Schedule: "0 0 * * *"},
+			{Name: "weekly-audit", ReportType: "policy-audit", Schedule: "0 0 * * 0", Namespaces: []string{"payments"}},
+		}
+		Expect(c.Update(ctx, cr)).NotTo(HaveOccurred())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		report := &v3.GlobalReport{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "daily-inventory"}, report)).NotTo(HaveOccurred())
+		Expect(report.Labels).To(HaveKeyWithValue(render.ComplianceReportLabel, "true"))
+		Expect(report.Spec.Schedule).To(Equal("0 0 * * *"))
+		Expect(c.Get(ctx, client.ObjectKey{Name: "weekly-audit"}, &v3.GlobalReport{})).NotTo(HaveOccurred())
+
+		By("removing the reports that are no longer listed")
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cr), cr)).NotTo(HaveOccurred())
+		cr.Spec.Reports = cr.Spec.Reports[:1]
+		Expect(c.Update(ctx, cr)).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "daily-inventory"}, &v3.GlobalReport{})).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "weekly-audit"}, &v3.GlobalReport{}))).To(BeTrue())
+	})
+
 	It("should reconcile if the compliance server cert is user-supplied", func() {
 		// This test just validates that user-provided certs reconcile and do
 		// not overwrite the certs.
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"net"
 	"reflect"
 	"strings"
 	"time"
@@ -39,6 +40,7 @@ import (
 	"github.com/tigera/operator/pkg/ctrlruntime"
 	"github.com/tigera/operator/pkg/render"
 	rmonitor "github.com/tigera/operator/pkg/render/monitor"
+	"github.com/tigera/operator/pkg/render/nonclusterhost"
 	"github.com/tigera/operator/pkg/tls"
 	"github.com/tigera/operator/pkg/tls/certificatemanagement"
 	authv1 "k8s.io/api/authorization/v1"
@@ -112,6 +114,10 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("monitor-controller failed to watch primary resource: %w", err)
 	}
 
+	if err = c.WatchObject(&operatorv1.CertificateSigningPolicy{}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("csr-controller failed to watch CertificateSigningPolicy resource: %w", err)
+	}
+
 	return utils.AddCSRWatchWithRelevancyFn(c, relevantCSR)
 }
 
@@ -119,6 +125,17 @@ type tlsAsset struct {
 	serviceaccountName      string
 	serviceaccountNamespace string
 	validDNSNames           []string
+
+	// The following are only set for assets declared by a CertificateSigningPolicy.
+
+	// reviewNamespace is the namespace of the SubjectAccessReview that is performed when no service account is set.
+	reviewNamespace string
+	// validIPNets are the networks that requested IPs may belong to, besides the IP of the pod.
+	validIPNets []*net.IPNet
+	// extKeyUsage overrides the default extended key usages.
+	extKeyUsage []x509.ExtKeyUsage
+	// maxLifetime overrides the default certificate duration.
+	maxLifetime time.Duration
 }
 
 func newReconciler(mgr manager.Manager, opts options.AddOptions) (reconcile.Reconciler, error) {
@@ -147,6 +164,7 @@ func newReconciler(mgr manager.Manager, opts options.AddOptions) (reconcile.Reco
 //   - DNS names: these will be checked against pre-defined dns names for that specific secret name.
 //
 // The combination of this information (among other checks) will help us reject/approve requests.
+// Additional assets may be declared by CertificateSigningPolicies, see withPolicyAssets.
 func allowedAssets(clusterDomain string) map[string]tlsAsset {
 	return map[string]tlsAsset{
 		rmonitor.PrometheusServerTLSSecretName: {
@@ -193,7 +211,13 @@ func (r *reconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 		return reconcile.Result{}, err
 	}
 
-	needsCSRRole := instance.Spec.CertificateManagement != nil
+	// Pods that are not part of Calico may obtain certificates for the assets declared by CertificateSigningPolicies.
+	policies := &operatorv1.CertificateSigningPolicyList{}
+	if err := r.client.List(ctx, policies); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	needsCSRRole := instance.Spec.CertificateManagement != nil || len(policies.Items) > 0
 	if !needsCSRRole && r.enterpriseCRDExists {
 		monitorCR := &operatorv1.Monitor{}
 		if err := r.client.Get(ctx, utils.DefaultTSEEInstanceKey, monitorCR); err != nil {
@@ -244,7 +268,7 @@ func (r *reconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 		return reconcile.Result{}, err
 	}
 
-	for _, csr := range csrList.Items {
+	for i, csr := range csrList.Items {
 		if !relevantCSR(&csr) {
 			// Not for us, or already signed.
 			continue
@@ -253,15 +277,20 @@ func (r *reconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 		reqLogger.V(5).Info("Inspecting CSR with name : %v.", csr.Name)
 		var certificateTemplate *x509.Certificate
 		var err error
-		if v, ok := csr.Labels["nonclusterhost.tigera.io/hostname"]; ok {
+		if v, ok := csr.Labels[nonclusterhost.HostnameLabel]; ok {
 			var hep *v3.HostEndpoint
-			if hep, err = r.getHostEndpoint(ctx, v); err == nil {
-				certificateTemplate, err = validate(r.clientset, &csr, hep, r.allowedTLSAssets)
+			if err = r.checkEnrollment(ctx, &csr, v, csrList.Items); err == nil {
+				if hep, err = r.getHostEndpoint(ctx, v); err == nil {
+					certificateTemplate, err = validate(r.clientset, &csr, hep, r.allowedTLSAssets)
+				}
 			}
 		} else {
 			var pod *corev1.Pod
+			var assets map[string]tlsAsset
 			if pod, err = r.getPod(ctx, &csr); err == nil {
-				certificateTemplate, err = validate(r.clientset, &csr, pod, r.allowedTLSAssets)
+				if assets, err = withPolicyAssets(r.allowedTLSAssets, policies.Items, &csr); err == nil {
+					certificateTemplate, err = validate(r.clientset, &csr, pod, assets)
+				}
 			}
 		}
 
@@ -292,6 +321,8 @@ func (r *reconcileCSR) Reconcile(ctx context.Context, request reconcile.Request)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		// Keep the list up to date, so that a second CSR made with the same enrollment token is rejected.
+		csrList.Items[i] = csr
 		reqLogger.V(5).Info("Approved CSR with name : %v.", csr.Name)
 
 		certificatePEM, err := certificateManager.SignCertificate(certificateTemplate)
@@ -320,7 +351,7 @@ type PodOrHostEndpoint interface {
 // - Verify that the issuer of the CSR (the pod) indeed is the pod that belongs to the IP in the CSR.
 // - Verify that the CSR was not previously denied or failed.
 // - Verify that the public key matches the signature on the CSR for the provider algorithm.
-// - Key usages are fixed, so the CSR won't be able to affect these settings.
+// - Key usages are fixed, or set by a CertificateSigningPolicy, so the CSR won't be able to affect these settings.
 func validate[T PodOrHostEndpoint](
 	clientset kubernetes.Interface,
 	csr *certificatesv1.CertificateSigningRequest,
@@ -396,6 +427,12 @@ func validate[T PodOrHostEndpoint](
 				},
 			},
 		}
+		if asset.reviewNamespace != "" {
+			// A CertificateSigningPolicy without a service account: the requestor must be allowed to request the
+			// common name in the namespace of the policy.
+			review.Spec.ResourceAttributes.Namespace = asset.reviewNamespace
+			review.Spec.ResourceAttributes.Name = certificateRequest.Subject.CommonName
+		}
 
 		if allowed, err := performSubjectAccessReview(clientset, review); err != nil {
 			return nil, err
@@ -408,7 +445,7 @@ func validate[T PodOrHostEndpoint](
 	for _, name := range append(certificateRequest.DNSNames, certificateRequest.Subject.CommonName) {
 		var found bool
 		for _, valid := range asset.validDNSNames {
-			if valid == name {
+			if matchDNSName(valid, name) {
 				found = true
 				break
 			}
@@ -418,7 +455,13 @@ func validate[T PodOrHostEndpoint](
 		}
 	}
 
-	if expectedIP != "" {
+	if len(asset.validIPNets) > 0 {
+		for _, ip := range certificateRequest.IPAddresses {
+			if !validIP(ip, expectedIP, asset.validIPNets) {
+				return nil, fmt.Errorf("invalid IP %s found in CSR with name %s", ip, csr.Name)
+			}
+		}
+	} else if expectedIP != "" {
 		if len(certificateRequest.IPAddresses) == 1 {
 			if certificateRequest.IPAddresses[0].String() != expectedIP {
 				return nil, fmt.Errorf("invalid pod IP for CSR with name %s", csr.Name)
@@ -431,6 +474,20 @@ func validate[T PodOrHostEndpoint](
 		return nil, fmt.Errorf("invalid: cannot request IP for CSR with name %s", csr.Name)
 	}
 
+	usages := extKeyUsage
+	if len(asset.extKeyUsage) > 0 {
+		usages = asset.extKeyUsage
+	}
+	lifetime := tls.DefaultCertificateDuration
+	if asset.maxLifetime > 0 {
+		lifetime = asset.maxLifetime
+		if csr.Spec.ExpirationSeconds != nil {
+			if requested := time.Duration(*csr.Spec.ExpirationSeconds) * time.Second; requested < lifetime {
+				lifetime = requested
+			}
+		}
+	}
+
 	bigint, _ := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
 	return &x509.Certificate{
 		// We don't rely on any other part of the subject. Common name is validated already.
@@ -440,16 +497,29 @@ func validate[T PodOrHostEndpoint](
 		PublicKeyAlgorithm: certificateRequest.PublicKeyAlgorithm,
 		PublicKey:          certificateRequest.PublicKey,
 		NotBefore:          time.Now(),
-		// We currently don't implement the Duration for certificate requests.
-		NotAfter: time.Now().Add(tls.DefaultCertificateDuration),
-		// For the time being we simply issue the standard usages. There are very few, if any, exceptions in our product.
+		// The requested duration is only honored for assets with a maximum lifetime.
+		NotAfter: time.Now().Add(lifetime),
+		// For the time being we simply issue the standard usages, unless a CertificateSigningPolicy restricts them.
 		KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage: extKeyUsage,
+		ExtKeyUsage: usages,
 		DNSNames:    certificateRequest.DNSNames,
 		IPAddresses: certificateRequest.IPAddresses,
 	}, nil
 }
 
+// validIP returns true if the IP is the expected IP of the requestor or belongs to one of the valid networks.
+func validIP(ip net.IP, expectedIP string, validIPNets []*net.IPNet) bool {
+	if expectedIP != "" && ip.Equal(net.ParseIP(expectedIP)) {
+		return true
+	}
+	for _, ipNet := range validIPNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 func convertExtraValue(extra map[string]certificatesv1.ExtraValue) map[string]authv1.ExtraValue {
 	res := make(map[string]authv1.ExtraValue)
 	for k, v := range extra {
@@ -511,6 +581,86 @@ func (r *reconcileCSR) getPod(ctx context.Context, csr *certificatesv1.Certifica
 	return nil, nil
 }
 
+// checkEnrollment verifies that a non-cluster host is allowed to be issued a certificate. When the NonClusterHost lists
+// the hosts that may enroll, the host must be listed and not revoked. Until it has enrolled, its CSR must be made with
+// its own unexpired enrollment token, which can only be used once: a CSR made with the token is rejected if another one
+// has already been approved. Once enrolled, the host renews its certificate with the non-cluster host token.
+func (r *reconcileCSR) checkEnrollment(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, hostname string, csrs []certificatesv1.CertificateSigningRequest) error {
+	instance, err := utils.GetNonClusterHost(ctx, r.client)
+	if err != nil {
+		return err
+	}
+	return checkEnrollment(instance, csr, hostname, csrs, time.Now())
+}
+
+func checkEnrollment(instance *operatorv1.NonClusterHost, csr *certificatesv1.CertificateSigningRequest, hostname string, csrs []certificatesv1.CertificateSigningRequest, now time.Time) error {
+	if instance == nil || len(instance.Spec.Hosts) == 0 {
+		return nil
+	}
+
+	listed := false
+	for _, host := range instance.Spec.Hosts {
+		if host.Hostname == hostname {
+			if host.Revoked {
+				return fmt.Errorf("invalid: non-cluster host %s has been revoked", hostname)
+			}
+			listed = true
+			break
+		}
+	}
+	if !listed {
+		return fmt.Errorf("invalid: non-cluster host %s is not listed in the NonClusterHost resource", hostname)
+	}
+
+	for _, h := range instance.Status.Hosts {
+		if h.Hostname != hostname {
+			continue
+		}
+		switch h.State {
+		case operatorv1.NonClusterHostEnrolled:
+			if csr.Spec.Username == nonclusterhost.EnrollmentUsername(hostname) {
+				return fmt.Errorf("invalid: the enrollment token of non-cluster host %s has already been used", hostname)
+			}
+			if csr.Spec.Username != nonclusterhost.Username() {
+				return fmt.Errorf("invalid requestor %s for CSR with name %s: non-cluster host %s must renew with the non-cluster host token", csr.Spec.Username, csr.Name, hostname)
+			}
+			return nil
+		case operatorv1.NonClusterHostPending:
+			if csr.Spec.Username != nonclusterhost.EnrollmentUsername(hostname) {
+				return fmt.Errorf("invalid requestor %s for CSR with name %s: non-cluster host %s must enroll with its enrollment token", csr.Spec.Username, csr.Name, hostname)
+			}
+			if h.TokenExpiry == nil || !now.Before(h.TokenExpiry.Time) {
+				return fmt.Errorf("invalid: the enrollment token of non-cluster host %s has expired", hostname)
+			}
+			if enrollmentTokenUsed(csrs, csr, hostname) {
+				return fmt.Errorf("invalid: the enrollment token of non-cluster host %s has already been used", hostname)
+			}
+			return nil
+		default:
+			return fmt.Errorf("invalid: non-cluster host %s cannot enroll in state %s", hostname, h.State)
+		}
+	}
+	return fmt.Errorf("invalid: no enrollment token has been issued for non-cluster host %s", hostname)
+}
+
+// enrollmentTokenUsed returns true if a CSR other than the given one was made with the enrollment token of the host
+// and approved. The NonClusterHost status only records the enrollment once the CSR has been approved, so this closes
+// the window in which the token could be used twice.
+func enrollmentTokenUsed(csrs []certificatesv1.CertificateSigningRequest, csr *certificatesv1.CertificateSigningRequest, hostname string) bool {
+	for _, other := range csrs {
+		if other.Name == csr.Name || other.Labels[nonclusterhost.HostnameLabel] != hostname ||
+			other.Spec.Username != nonclusterhost.EnrollmentUsername(hostname) {
+			continue
+		}
+		for _, c := range other.Status.Conditions {
+			if c.Type == certificatesv1.CertificateApproved && c.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r *reconcileCSR) getHostEndpoint(ctx context.Context, hostname string) (*v3.HostEndpoint, error) {
 	if hostname == "" {
 		return nil, errors.New("hostname can not be empty")
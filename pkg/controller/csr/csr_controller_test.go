@@ -24,6 +24,7 @@ import (
 	"encoding/asn1"
 	"encoding/pem"
 	"net"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -172,6 +173,110 @@ var _ = Describe("CSR controller tests", func() {
 		})
 	})
 
+	Context("certificate signing policies", func() {
+		It("should sign a CSR for an asset declared by a policy", func() {
+			policy := appPolicy()
+			policy.Spec.KeyUsages = []operatorv1.CertificateKeyUsage{operatorv1.CertificateKeyUsageServerAuth}
+			policy.Spec.MaxLifetime = &metav1.Duration{Duration: 24 * time.Hour}
+			Expect(cli.Create(ctx, policy)).NotTo(HaveOccurred())
+			Expect(cli.Create(ctx, appPod())).NotTo(HaveOccurred())
+			csr := appCSR(appX509CR(), appPod())
+			expirationSeconds := int32(3600)
+			csr.Spec.ExpirationSeconds = &expirationSeconds
+			Expect(cli.Create(ctx, csr)).NotTo(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(r.client.Get(ctx, client.ObjectKey{Name: csr.Name}, csr)).NotTo(HaveOccurred())
+			Expect(csr.Status.Conditions).To(HaveLen(1))
+			Expect(csr.Status.Conditions[0].Type).To(Equal(certificatesv1.CertificateApproved))
+
+			block, _ := pem.Decode(csr.Status.Certificate)
+			Expect(block).NotTo(BeNil())
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(certificate.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}))
+			Expect(certificate.DNSNames).To(Equal([]string{"my-app", "api.my-app.svc"}))
+			Expect(certificate.NotAfter.Sub(certificate.NotBefore)).To(BeNumerically("~", time.Hour, time.Minute))
+		})
+
+		It("should not let a policy claim the secret of a Calico component", func() {
+			policy := appPolicy()
+			policy.Spec.Namespace = "tigera-prometheus"
+			policy.Spec.SecretNamePattern = "*"
+			policy.Spec.DNSNames = []string{"*"}
+			csr := invalidPodCSR(invalidX509CR(invalidDNSNames), validPod())
+			assets, err := withPolicyAssets(allowedAssets(dns.DefaultClusterDomain), []operatorv1.CertificateSigningPolicy{*policy}, csr)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = validate(clientset, csr, validPod(), assets)
+			Expect(err).To(HaveOccurred())
+		})
+
+		table.DescribeTable("csr validation", func(mutate func(*operatorv1.CertificateSigningPolicy), cr *x509.CertificateRequest, username string, subjectAccessReviewAllowed, expectError bool) {
+			clientset.Fake.PrependReactor("create", "subjectaccessreviews", func(action testing.Action) (handled bool, ret runtime.Object, err error) {
+				review := action.(testing.CreateAction).GetObject().(*authv1.SubjectAccessReview)
+				Expect(review.Spec.ResourceAttributes.Namespace).To(Equal("my-app"))
+				Expect(review.Spec.ResourceAttributes.Name).To(Equal("my-app"))
+				return true, &authv1.SubjectAccessReview{
+					Status: authv1.SubjectAccessReviewStatus{
+						Allowed: subjectAccessReviewAllowed,
+					},
+				}, nil
+			})
+			policy := appPolicy()
+			if mutate != nil {
+				mutate(policy)
+			}
+			csr := appCSR(cr, appPod())
+			csr.Spec.Username = username
+
+			assets, err := withPolicyAssets(allowedAssets(dns.DefaultClusterDomain), []operatorv1.CertificateSigningPolicy{*policy}, csr)
+			if err == nil {
+				_, err = validate(clientset, csr, appPod(), assets)
+			}
+			if expectError {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+			table.Entry("matching policy", nil, appX509CR(), appUser, false, false),
+			table.Entry("policy in another namespace", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.Namespace = "other" }, appX509CR(), appUser, false, true),
+			table.Entry("another service account", nil, appX509CR(), "system:serviceaccount:my-app:other", false, true),
+			table.Entry("secret name does not match", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.SecretNamePattern = "other-*" }, appX509CR(), appUser, false, true),
+			table.Entry("DNS name not in the policy", nil, withSANs(appX509CR(), []string{"my-app", "google.com"}, nil), appUser, false, true),
+			table.Entry("common name trusted by Typha", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.DNSNames = []string{"*"} },
+				withCommonName(appX509CR(), "typha-client"), appUser, false, true),
+			table.Entry("service name in calico-system", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.DNSNames = []string{"*"} },
+				withSANs(appX509CR(), []string{"my-app", "calico-typha.calico-system.svc"}, nil), appUser, false, true),
+			table.Entry("service name in a tigera namespace", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.DNSNames = []string{"*"} },
+				withSANs(appX509CR(), []string{"my-app", "tigera-manager.tigera-manager.svc.cluster.local"}, nil), appUser, false, true),
+			table.Entry("IP in the CIDRs of the policy", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.IPAddresses = []string{"192.168.1.0/24"} },
+				withSANs(appX509CR(), nil, []net.IP{net.ParseIP("10.0.0.5"), net.ParseIP("192.168.1.7")}), appUser, false, false),
+			table.Entry("IP outside the CIDRs of the policy", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.IPAddresses = []string{"192.168.1.0/24"} },
+				withSANs(appX509CR(), nil, []net.IP{net.ParseIP("8.8.8.8")}), appUser, false, true),
+			table.Entry("IP other than the pod IP", nil, withSANs(appX509CR(), nil, []net.IP{net.ParseIP("8.8.8.8")}), appUser, false, true),
+			table.Entry("invalid CIDR in the policy", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.IPAddresses = []string{"10.0.0.5"} }, appX509CR(), appUser, false, true),
+			table.Entry("any service account / subject access review allowed", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.ServiceAccountName = "" }, appX509CR(), "system:serviceaccount:my-app:other", true, false),
+			table.Entry("any service account / subject access review denied", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.ServiceAccountName = "" }, appX509CR(), "system:serviceaccount:my-app:other", false, true),
+			table.Entry("wildcard matching across a label", nil, withSANs(appX509CR(), []string{"my-app", "a.b.my-app.svc"}, nil), appUser, false, true),
+			table.Entry("invalid secret name pattern in the policy", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.SecretNamePattern = "my-app-[" }, appX509CR(), appUser, false, true),
+			table.Entry("invalid DNS name pattern in the policy", func(p *operatorv1.CertificateSigningPolicy) { p.Spec.DNSNames = []string{"my-app", "[.my-app.svc"} }, appX509CR(), appUser, false, true),
+		)
+	})
+
+	table.DescribeTable("DNS name matching", func(pattern, name string, expectMatch bool) {
+		Expect(matchDNSName(pattern, name)).To(Equal(expectMatch))
+	},
+		table.Entry("exact name", "my-app.svc", "my-app.svc", true),
+		table.Entry("case and trailing dot", "My-App.svc", "my-app.svc.", true),
+		table.Entry("wildcard label", "*.my-app.svc", "api.my-app.svc", true),
+		table.Entry("wildcard within a label", "api-*.my-app.svc", "api-v1.my-app.svc", true),
+		table.Entry("wildcard does not cross a dot", "*.my-app.svc", "a.b.my-app.svc", false),
+		table.Entry("wildcard does not match an empty label", "*.my-app.svc", "my-app.svc", false),
+		table.Entry("single wildcard only matches one label", "*", "my-app.svc", false),
+	)
+
 	table.DescribeTable("csr validation for pods", func(csr *certificatesv1.CertificateSigningRequest, pod *corev1.Pod, expectError, expectRelevant bool) {
 		certificate, err := validate(clientset, csr, pod, allowedAssets(dns.DefaultClusterDomain))
 		if expectError {
@@ -236,6 +341,62 @@ var _ = Describe("CSR controller tests", func() {
 		table.Entry("irrelevant signer name", invalidNonClusterHostCSR(invalidX509CR(), validHostEndpoint(), invalidSignername), validHostEndpoint(), false, false, true),
 	)
 
+	table.DescribeTable("enrollment of non-cluster hosts", func(revoked bool, state operatorv1.NonClusterHostEnrollmentState, username string, expiresIn time.Duration, tokenUsed bool, expectError bool) {
+		now := time.Now()
+		expiry := metav1.NewTime(now.Add(expiresIn))
+		instance := &operatorv1.NonClusterHost{
+			Spec: operatorv1.NonClusterHostSpec{
+				Hosts: []operatorv1.NonClusterHostEnrollment{{Hostname: "host-a", Revoked: revoked}},
+			},
+			Status: operatorv1.NonClusterHostStatus{
+				Hosts: []operatorv1.NonClusterHostInventory{{Hostname: "host-a", State: state, TokenExpiry: &expiry}},
+			},
+		}
+		csr := &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-certs-noncluster-host:host-a"},
+			Spec:       certificatesv1.CertificateSigningRequestSpec{Username: username},
+		}
+		var csrs []certificatesv1.CertificateSigningRequest
+		if tokenUsed {
+			csrs = append(csrs, certificatesv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-certs-noncluster-host:host-a-1",
+					Labels: map[string]string{"nonclusterhost.tigera.io/hostname": "host-a"},
+				},
+				Spec: certificatesv1.CertificateSigningRequestSpec{Username: enrollmentUser},
+				Status: certificatesv1.CertificateSigningRequestStatus{
+					Conditions: []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}},
+				},
+			})
+		}
+		err := checkEnrollment(instance, csr, "host-a", append(csrs, *csr), now)
+		if expectError {
+			Expect(err).To(HaveOccurred())
+		} else {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	},
+		table.Entry("pending host using its token", false, operatorv1.NonClusterHostPending, enrollmentUser, time.Hour, false, false),
+		table.Entry("pending host using its token a second time", false, operatorv1.NonClusterHostPending, enrollmentUser, time.Hour, true, true),
+		table.Entry("pending host using another requestor", false, operatorv1.NonClusterHostPending, "system:serviceaccount:calico-system:tigera-noncluster-host", time.Hour, false, true),
+		table.Entry("pending host with an expired token", false, operatorv1.NonClusterHostPending, enrollmentUser, -time.Minute, false, true),
+		table.Entry("enrolled host renewing its certificate", false, operatorv1.NonClusterHostEnrolled, "system:serviceaccount:calico-system:tigera-noncluster-host", -time.Minute, true, false),
+		table.Entry("enrolled host reusing its enrollment token", false, operatorv1.NonClusterHostEnrolled, enrollmentUser, time.Duration(0), true, true),
+		table.Entry("enrolled host renewed by another requestor", false, operatorv1.NonClusterHostEnrolled, "system:serviceaccount:calico-system:other", time.Duration(0), true, true),
+		table.Entry("expired host", false, operatorv1.NonClusterHostExpired, enrollmentUser, time.Duration(0), false, true),
+		table.Entry("revoked host", true, operatorv1.NonClusterHostEnrolled, enrollmentUser, time.Duration(0), true, true),
+	)
+
+	It("should only allow listed non-cluster hosts to enroll", func() {
+		csr := &certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "node-certs-noncluster-host:host-b"}}
+		Expect(checkEnrollment(nil, csr, "host-b", nil, time.Now())).NotTo(HaveOccurred())
+		Expect(checkEnrollment(&operatorv1.NonClusterHost{}, csr, "host-b", nil, time.Now())).NotTo(HaveOccurred())
+		instance := &operatorv1.NonClusterHost{
+			Spec: operatorv1.NonClusterHostSpec{Hosts: []operatorv1.NonClusterHostEnrollment{{Hostname: "host-a"}}},
+		}
+		Expect(checkEnrollment(instance, csr, "host-b", nil, time.Now())).To(HaveOccurred())
+	})
+
 	table.DescribeTable("getPod", func(csr *certificatesv1.CertificateSigningRequest, pod *corev1.Pod, expectPodNil bool) {
 		if pod != nil {
 			Expect(cli.Create(ctx, pod)).NotTo(HaveOccurred())
@@ -444,6 +605,65 @@ func validHostEndpoint() *v3.HostEndpoint {
 	}
 }
 
+// appUser is the requestor of CSRs made by the pod of a workload that is not part of Calico.
+const appUser = "system:serviceaccount:my-app:my-app"
+
+func appPolicy() *operatorv1.CertificateSigningPolicy {
+	return &operatorv1.CertificateSigningPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app"},
+		Spec: operatorv1.CertificateSigningPolicySpec{
+			Namespace:          "my-app",
+			SecretNamePattern:  "my-app-*",
+			ServiceAccountName: "my-app",
+			DNSNames:           []string{"my-app", "*.my-app.svc"},
+		},
+	}
+}
+
+func appPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-0", Namespace: "my-app", UID: "app-uid"},
+		Spec:       corev1.PodSpec{ServiceAccountName: "my-app"},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.5"},
+	}
+}
+
+func appX509CR() *x509.CertificateRequest {
+	return &x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: "my-app"},
+		DNSNames:           []string{"my-app", "api.my-app.svc"},
+		IPAddresses:        []net.IP{net.ParseIP("10.0.0.5")},
+		SignatureAlgorithm: x509.SHA256WithRSA,
+	}
+}
+
+func withCommonName(cr *x509.CertificateRequest, commonName string) *x509.CertificateRequest {
+	cr.Subject.CommonName = commonName
+	cr.DNSNames = []string{commonName}
+	return cr
+}
+
+func withSANs(cr *x509.CertificateRequest, dnsNames []string, ips []net.IP) *x509.CertificateRequest {
+	if dnsNames != nil {
+		cr.DNSNames = dnsNames
+	}
+	if ips != nil {
+		cr.IPAddresses = ips
+	}
+	return cr
+}
+
+func appCSR(cr *x509.CertificateRequest, pod *corev1.Pod) *certificatesv1.CertificateSigningRequest {
+	csr := validPodCSR(cr, pod)
+	csr.Name = "my-app-tls:" + pod.Name
+	csr.Labels = map[string]string{"operator.tigera.io/csr": "my-app"}
+	csr.Spec.Username = appUser
+	return csr
+}
+
+// enrollmentUser is the requestor of CSRs made with the enrollment token of host-a.
+const enrollmentUser = "system:serviceaccount:calico-system:tigera-noncluster-host-enrollment-host-a"
+
 const (
 	invalidUID invalidation = iota
 	invalidName
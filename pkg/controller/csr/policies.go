@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csr
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strings"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// reservedNames are the names that Calico components trust in the certificates of their peers. No policy can grant them.
+var reservedNames = map[string]bool{
+	render.TyphaCommonName: true,
+	render.FelixCommonName: true,
+	render.TyphaCommonName + render.TyphaNonClusterHostSuffix: true,
+	render.FelixCommonName + render.TyphaNonClusterHostSuffix: true,
+	"calico-node":             true,
+	"calico-typha":            true,
+	"calico-kube-controllers": true,
+	"calico-api":              true,
+	"tigera-operator":         true,
+}
+
+// reservedName returns whether the DNS name is trusted by Calico components, either because it is one of the reserved
+// names, or because it is a service name in one of the Calico namespaces.
+func reservedName(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if reservedNames[name] {
+		return true
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	namespace := labels[1]
+	return namespace == common.CalicoNamespace || namespace == common.OperatorNamespace() ||
+		strings.HasPrefix(namespace, "calico-") || strings.HasPrefix(namespace, "tigera-")
+}
+
+// checkReservedNames rejects a CSR that requests a name that Calico components trust. If the request cannot be parsed,
+// this is left to validate to report.
+func checkReservedNames(csr *certificatesv1.CertificateSigningRequest) error {
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil {
+		return nil
+	}
+	certificateRequest, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	for _, name := range append(certificateRequest.DNSNames, certificateRequest.Subject.CommonName) {
+		if reservedName(name) {
+			return fmt.Errorf("invalid: dns name \"%s\" in CSR with name %s is reserved for Calico components", name, csr.Name)
+		}
+	}
+	return nil
+}
+
+// withPolicyAssets returns the TLS assets that the CSR may be validated against. If the secret name of the CSR is not one
+// of the pre-defined assets, the first CertificateSigningPolicy (by name) for the namespace of the requestor that matches
+// the secret name and requestor is added as an asset. CSRs that request a reserved name are rejected before any policy
+// is considered.
+func withPolicyAssets(
+	allowedTLSAssets map[string]tlsAsset,
+	policies []operatorv1.CertificateSigningPolicy,
+	csr *certificatesv1.CertificateSigningRequest,
+) (map[string]tlsAsset, error) {
+	secretName, _, found := strings.Cut(csr.Name, ":")
+	if !found {
+		return allowedTLSAssets, nil
+	}
+	if _, ok := allowedTLSAssets[secretName]; ok {
+		// Policies cannot be used to obtain a certificate for one of our own components.
+		return allowedTLSAssets, nil
+	}
+	chunks := strings.Split(csr.Spec.Username, ":")
+	if len(chunks) != 4 || chunks[0] != "system" || chunks[1] != "serviceaccount" {
+		return allowedTLSAssets, nil
+	}
+	namespace, serviceaccountName := chunks[2], chunks[3]
+
+	if err := checkReservedNames(csr); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+	for _, policy := range policies {
+		if policy.Spec.Namespace != namespace {
+			continue
+		}
+		if policy.Spec.ServiceAccountName != "" && policy.Spec.ServiceAccountName != serviceaccountName {
+			continue
+		}
+		if _, err := path.Match(policy.Spec.SecretNamePattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid secretNamePattern %q in CertificateSigningPolicy %s: %w", policy.Spec.SecretNamePattern, policy.Name, err)
+		}
+		if match, _ := path.Match(policy.Spec.SecretNamePattern, secretName); !match {
+			continue
+		}
+
+		asset, err := policyAsset(policy)
+		if err != nil {
+			return nil, err
+		}
+		assets := map[string]tlsAsset{secretName: asset}
+		for k, v := range allowedTLSAssets {
+			assets[k] = v
+		}
+		return assets, nil
+	}
+	return allowedTLSAssets, nil
+}
+
+// policyAsset converts a CertificateSigningPolicy to the TLS asset that it declares.
+func policyAsset(policy operatorv1.CertificateSigningPolicy) (tlsAsset, error) {
+	asset := tlsAsset{
+		validDNSNames:   policy.Spec.DNSNames,
+		reviewNamespace: policy.Spec.Namespace,
+	}
+	for _, pattern := range policy.Spec.DNSNames {
+		if err := validateDNSNamePattern(pattern); err != nil {
+			return tlsAsset{}, fmt.Errorf("invalid DNS name %q in CertificateSigningPolicy %s: %w", pattern, policy.Name, err)
+		}
+	}
+	if policy.Spec.ServiceAccountName != "" {
+		asset.serviceaccountName = policy.Spec.ServiceAccountName
+		asset.serviceaccountNamespace = policy.Spec.Namespace
+	}
+	for _, cidr := range policy.Spec.IPAddresses {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return tlsAsset{}, fmt.Errorf("invalid CIDR %q in CertificateSigningPolicy %s: %w", cidr, policy.Name, err)
+		}
+		asset.validIPNets = append(asset.validIPNets, ipNet)
+	}
+	for _, usage := range policy.Spec.KeyUsages {
+		switch usage {
+		case operatorv1.CertificateKeyUsageServerAuth:
+			asset.extKeyUsage = append(asset.extKeyUsage, x509.ExtKeyUsageServerAuth)
+		case operatorv1.CertificateKeyUsageClientAuth:
+			asset.extKeyUsage = append(asset.extKeyUsage, x509.ExtKeyUsageClientAuth)
+		default:
+			return tlsAsset{}, fmt.Errorf("invalid key usage %q in CertificateSigningPolicy %s", usage, policy.Name)
+		}
+	}
+	if policy.Spec.MaxLifetime != nil {
+		asset.maxLifetime = policy.Spec.MaxLifetime.Duration
+	}
+	return asset, nil
+}
+
+// matchDNSName returns whether the DNS name matches the pattern. Each label of the pattern uses shell glob syntax and is
+// matched against the label of the name at the same position, so the pattern must have as many labels as the name and
+// a wildcard never matches across a ".": "*.my-app.svc" matches "a.my-app.svc", but not "a.b.my-app.svc" or
+// "my-app.svc". Names are compared case-insensitively and a trailing "." is ignored.
+func matchDNSName(pattern, name string) bool {
+	patternLabels := dnsLabels(pattern)
+	nameLabels := dnsLabels(name)
+	if len(patternLabels) != len(nameLabels) {
+		return false
+	}
+	for i := range patternLabels {
+		if match, err := path.Match(patternLabels[i], nameLabels[i]); err != nil || !match {
+			return false
+		}
+	}
+	return true
+}
+
+// validateDNSNamePattern returns an error if a label of the pattern is not valid shell glob syntax.
+func validateDNSNamePattern(pattern string) error {
+	for _, label := range dnsLabels(pattern) {
+		if _, err := path.Match(label, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dnsLabels(name string) []string {
+	return strings.Split(strings.ToLower(strings.TrimSuffix(name, ".")), ".")
+}
@@ -139,6 +139,7 @@ type ReconcileEgressGateway struct {
 // Reconcile reads that state of the cluster for an EgressGateway object and makes changes
 // based on the state read and what is in the EgressGateway.Spec.
 func (r *ReconcileEgressGateway) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling EgressGateway")
 
@@ -17,6 +17,7 @@ package gatewayapi
 import (
 	"context"
 	"fmt"
+	"time"
 
 	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
 	v1 "k8s.io/api/apps/v1"
@@ -40,12 +41,15 @@ import (
 	envoyapi "github.com/envoyproxy/gateway/api/v1alpha1"
 	"github.com/go-logr/logr"
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/options"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/controller/utils/imageset"
 	"github.com/tigera/operator/pkg/ctrlruntime"
 	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/applicationlayer"
+	"github.com/tigera/operator/pkg/render/applicationlayer/ruleset"
 	"github.com/tigera/operator/pkg/render/gatewayapi"
 )
 
@@ -89,6 +93,25 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("gatewayapi-controller failed to watch Tigera network resource: %w", err)
 	}
 
+	if opts.EnterpriseCRDExists {
+		// Watch for the Monitor CR, which determines whether gateway metrics are scraped.
+		if err = c.WatchObject(&operatorv1.Monitor{}, &handler.EnqueueRequestForObject{}); err != nil {
+			return fmt.Errorf("gatewayapi-controller failed to watch Monitor resource: %w", err)
+		}
+
+		// Watch for the ApplicationLayer and the ConfigMaps in the tigera-operator namespace that make up the
+		// WAF ruleset of the gateways.
+		if err = c.WatchObject(&operatorv1.ApplicationLayer{}, &handler.EnqueueRequestForObject{}); err != nil {
+			return fmt.Errorf("gatewayapi-controller failed to watch ApplicationLayer resource: %w", err)
+		}
+		if err = utils.AddConfigMapWatch(c, applicationlayer.WAFRulesetConfigMapName, common.OperatorNamespace(), &handler.EnqueueRequestForObject{}); err != nil {
+			return fmt.Errorf("gatewayapi-controller failed to watch ConfigMap %s: %w", applicationlayer.WAFRulesetConfigMapName, err)
+		}
+		if err = utils.AddLabeledConfigMapWatch(c, ruleset.RulePackLabel, &handler.EnqueueRequestForObject{}); err != nil {
+			return fmt.Errorf("gatewayapi-controller failed to watch WAF rule pack ConfigMaps: %w", err)
+		}
+	}
+
 	// Perform periodic reconciliation. This acts as a backstop to catch reconcile issues,
 	// and also makes sure we spot when things change that might not trigger a reconciliation.
 	if err = utils.AddPeriodicReconcile(c, utils.PeriodicReconcileTime, &handler.EnqueueRequestForObject{}); err != nil {
@@ -131,6 +154,19 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return nil
 	}
 
+	watchingGateways := false
+	r.watchGateways = func() error {
+		if !watchingGateways {
+			log.V(1).Info("Adding watch for Gateways")
+			if err = c.WatchObject(&gapi.Gateway{}, &handler.EnqueueRequestForObject{}); err != nil {
+				log.V(5).Info("Failed to create Gateway watch", "err", err)
+				return fmt.Errorf("gatewayapi-controller failed to watch Gateway resource: %w", err)
+			}
+			watchingGateways = true
+		}
+		return nil
+	}
+
 	return nil
 }
 
@@ -148,6 +184,7 @@ type ReconcileGatewayAPI struct {
 	newComponentHandler func(log logr.Logger, client client.Client, scheme *runtime.Scheme, cr metav1.Object) utils.ComponentHandler
 	watchEnvoyProxy     func(namespacedName operatorv1.NamespacedName) error
 	watchEnvoyGateway   func(namespacedName operatorv1.NamespacedName) error
+	watchGateways       func() error
 }
 
 // Reconcile reads that state of the cluster for a GatewayAPI object and makes changes based on the state read
@@ -156,6 +193,7 @@ type ReconcileGatewayAPI struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileGatewayAPI) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.V(2).Info("Reconciling GatewayAPI")
 
@@ -179,7 +217,7 @@ func (r *ReconcileGatewayAPI) Reconcile(ctx context.Context, request reconcile.R
 	r.status.OnCRFound()
 
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&gatewayAPI.ObjectMeta)
+	defer r.status.SetMetaData(gatewayAPI)
 
 	// Get the Installation, for private registry and pull secret config.
 	variant, installation, err := utils.GetInstallation(ctx, r.client)
@@ -274,12 +312,27 @@ func (r *ReconcileGatewayAPI) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 
+	// Gateway metrics are scraped by the Prometheus instance of the Monitor CR, when it exists.
+	monitorEnabled := false
+	if r.enterpriseCRDsExist {
+		if err = r.client.Get(ctx, utils.DefaultTSEEInstanceKey, &operatorv1.Monitor{}); err == nil {
+			monitorEnabled = true
+		} else if !errors.IsNotFound(err) {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying Monitor resource", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
 	gatewayConfig := &gatewayapi.GatewayAPIImplementationConfig{
 		Installation:          installation,
 		PullSecrets:           pullSecrets,
 		GatewayAPI:            gatewayAPI,
 		CustomEnvoyProxies:    make(map[string]*envoyapi.EnvoyProxy),
 		CurrentGatewayClasses: set.New[string](),
+		CurrentWAFPolicies:    set.New[string](),
+		MonitorEnabled:        monitorEnabled,
+
+		ServiceMonitorCRDExists: r.enterpriseCRDsExist,
 	}
 
 	if gatewayAPI.Spec.EnvoyGatewayConfigRef != nil {
@@ -401,6 +454,83 @@ func (r *ReconcileGatewayAPI) Reconcile(ctx context.Context, request reconcile.R
 		}
 	}
 
+	// Check whether the WAF ruleset exists, in case it is no longer needed and should be
+	// cleaned up.
+	gatewayConfig.WAFRulesetDeployed, err = r.objectExists(ctx, &corev1.ConfigMap{}, applicationlayer.WAFRulesetConfigMapName)
+	if err != nil {
+		r.status.SetDegraded(operatorv1.ResourceReadError, "Error reading WAF ruleset ConfigMap", err, log)
+		return reconcile.Result{}, err
+	}
+
+	// The Web Application Firewall needs the ruleset, and the Gateways of its classes so that it
+	// can be attached to them.
+	wafClasses := set.New[string]()
+	for i := range gatewayAPI.Spec.GatewayClasses {
+		if gatewayAPI.Spec.GatewayClasses[i].WebApplicationFirewall != nil {
+			wafClasses.Insert(gatewayAPI.Spec.GatewayClasses[i].Name)
+		}
+	}
+	if variant == operatorv1.TigeraSecureEnterprise && wafClasses.Len() > 0 {
+		if gatewayConfig.WAFRulesetConfigMap, err = r.getWAFRulesetConfig(ctx); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error reading WAF ruleset", err, log)
+			return reconcile.Result{}, err
+		}
+		// The gateways share the WAF settings and rule packs of the ApplicationLayer, so that the same rules
+		// are enforced for gateway and per-host traffic.
+		applicationLayer, err := utils.GetApplicationLayer(ctx, r.client)
+		if err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error reading ApplicationLayer", err, log)
+			return reconcile.Result{}, err
+		}
+		if applicationLayer != nil {
+			gatewayConfig.WAFSettings = applicationLayer.Spec.WebApplicationFirewallSettings
+		}
+		if gatewayConfig.WAFRulePacks, err = utils.GetLabeledConfigMaps(ctx, r.client, ruleset.RulePackLabel, ruleset.RulePackNames(gatewayConfig.WAFSettings)); err != nil {
+			return reconcile.Result{}, utils.SetLabeledConfigMapDegraded(r.status, err, "Web Application Firewall rule pack ConfigMap", log)
+		}
+		if err = ruleset.ValidateWAFRulesetConfig(gatewayConfig.WAFRulesetConfigMap, gatewayConfig.WAFSettings, gatewayConfig.WAFRulePacks); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceValidationError, "Error validating Web Application Firewall ruleset config", err, log)
+			return reconcile.Result{}, err
+		}
+		if gatewayConfig.DefaultCoreRulesetConfigMap, err = ruleset.GetOWASPCoreRuleSet(); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error reading OWASP core ruleset", err, log)
+			return reconcile.Result{}, err
+		}
+
+		if err = r.watchGateways(); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error watching Gateways", err, log)
+			return reconcile.Result{}, err
+		}
+		var gwList gapi.GatewayList
+		if err = r.client.List(ctx, &gwList); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error reading Gateways", err, log)
+			return reconcile.Result{}, err
+		}
+		for i := range gwList.Items {
+			if wafClasses.Has(string(gwList.Items[i].Spec.GatewayClassName)) {
+				gatewayConfig.Gateways = append(gatewayConfig.Gateways, &gwList.Items[i])
+			}
+		}
+	}
+
+	// Enumerate the WAF EnvoyExtensionPolicies that we previously created, in case some of
+	// them will need to be cleaned up.
+	var eepList envoyapi.EnvoyExtensionPolicyList
+	if err = r.client.List(ctx, &eepList); err != nil {
+		r.status.SetDegraded(operatorv1.ResourceReadError, "Error reading EnvoyExtensionPolicies", err, log)
+		return reconcile.Result{}, err
+	}
+	for i := range eepList.Items {
+		operatorOwned, err := controllerutil.HasOwnerReference(eepList.Items[i].GetOwnerReferences(), gatewayAPI, r.scheme)
+		if err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error reading EnvoyExtensionPolicy owner references", err, log)
+			return reconcile.Result{}, err
+		}
+		if operatorOwned {
+			gatewayConfig.CurrentWAFPolicies.Insert(types.NamespacedName{Namespace: eepList.Items[i].Namespace, Name: eepList.Items[i].Name}.String())
+		}
+	}
+
 	// Render non-CRD resources for Gateway API support, i.e. for our specific bundled
 	// implementation of the Gateway API.  For these we specify the GatewayAPI CR as the owner,
 	// so that they all get automatically cleaned up if the GatewayAPI CR is removed again.
@@ -478,6 +608,30 @@ func (r *ReconcileGatewayAPI) patchFelixConfiguration(ctx context.Context) error
 	return err
 }
 
+// objectExists returns whether the named object exists in the tigera-gateway namespace.
+func (r *ReconcileGatewayAPI) objectExists(ctx context.Context, obj client.Object, name string) (bool, error) {
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: "tigera-gateway", Name: name}, obj)
+	if err == nil {
+		return true, nil
+	} else if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// getWAFRulesetConfig returns the user's WAF ruleset config from the tigera-operator namespace, which is shared
+// with the ApplicationLayer, or the Tigera ruleset config if there is none.
+func (r *ReconcileGatewayAPI) getWAFRulesetConfig(ctx context.Context) (*corev1.ConfigMap, error) {
+	cm := new(corev1.ConfigMap)
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: common.OperatorNamespace(), Name: applicationlayer.WAFRulesetConfigMapName}, cm)
+	if err == nil {
+		return cm, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return ruleset.GetWAFRulesetConfig()
+}
+
 // maintainFinalizer manages this controller's finalizer on the Installation resource.
 // We add a finalizer to the Installation when the API server has been installed, and only remove that finalizer when
 // the API server has been deleted and its pods have stopped running. This allows for a graceful cleanup of API server resources
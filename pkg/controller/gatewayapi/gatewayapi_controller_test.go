@@ -34,16 +34,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gapi "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/yaml" // gopkg.in/yaml.v2 didn't parse all the fields but this package did
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
 	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
 	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/applicationlayer/ruleset"
 	"github.com/tigera/operator/pkg/render/gatewayapi"
 )
 
@@ -103,6 +107,7 @@ var _ = Describe("Gateway API controller tests", func() {
 			newComponentHandler: FakeComponentHandler,
 			watchEnvoyProxy:     func(namespacedName operatorv1.NamespacedName) error { return nil },
 			watchEnvoyGateway:   func(namespacedName operatorv1.NamespacedName) error { return nil },
+			watchGateways:       func() error { return nil },
 		}
 	})
 
@@ -629,6 +634,140 @@ var _ = Describe("Gateway API controller tests", func() {
 		Expect(*gwapi.Spec.CRDManagement).To(Equal(operatorv1.CRDManagementReconcile))
 	})
 
+	It("enables gateway metrics when the Monitor CR exists", func() {
+		Expect(c.Create(ctx, installation)).NotTo(HaveOccurred())
+		r.enterpriseCRDsExist = true
+
+		By("applying the GatewayAPI CR to the fake cluster")
+		gwapi := &operatorv1.GatewayAPI{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+		}
+		Expect(c.Create(ctx, gwapi)).NotTo(HaveOccurred())
+
+		By("triggering a reconcile without the Monitor CR")
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeComponentHandlers).To(HaveLen(2))
+		gatewayAPIImplementationConfig := fakeComponentHandlers[1].lastComponent.(gatewayapi.GatewayAPIImplementationConfigInterface).GetConfig()
+		Expect(gatewayAPIImplementationConfig.MonitorEnabled).To(BeFalse())
+
+		By("creating the Monitor CR and reconciling again")
+		Expect(c.Create(ctx, &operatorv1.Monitor{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})).NotTo(HaveOccurred())
+		fakeComponentHandlers = nil
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeComponentHandlers).To(HaveLen(2))
+		gatewayAPIImplementationConfig = fakeComponentHandlers[1].lastComponent.(gatewayapi.GatewayAPIImplementationConfigInterface).GetConfig()
+		Expect(gatewayAPIImplementationConfig.MonitorEnabled).To(BeTrue())
+	})
+
+	It("passes the WAF ruleset and the gateways of WAF-enabled classes", func() {
+		Expect(c.Create(ctx, installation)).NotTo(HaveOccurred())
+
+		By("applying the GatewayAPI CR to the fake cluster")
+		gwapi := &operatorv1.GatewayAPI{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operatorv1.GatewayAPISpec{
+				GatewayClasses: []operatorv1.GatewayClassSpec{
+					{Name: "class-a", WebApplicationFirewall: &operatorv1.GatewayWebApplicationFirewall{}},
+					{Name: "class-b"},
+				},
+			},
+		}
+		Expect(c.Create(ctx, gwapi)).NotTo(HaveOccurred())
+
+		By("creating gateways and a previously rendered WAF policy")
+		Expect(c.Create(ctx, &gapi.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gw-a", Namespace: "shop"},
+			Spec:       gapi.GatewaySpec{GatewayClassName: "class-a"},
+		})).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, &gapi.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gw-b", Namespace: "shop"},
+			Spec:       gapi.GatewaySpec{GatewayClassName: "class-b"},
+		})).NotTo(HaveOccurred())
+		policy := &envoyapi.EnvoyExtensionPolicy{ObjectMeta: metav1.ObjectMeta{Name: "gw-old-waf", Namespace: "shop"}}
+		Expect(controllerutil.SetOwnerReference(gwapi, policy, scheme)).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, policy)).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, &envoyapi.EnvoyExtensionPolicy{ObjectMeta: metav1.ObjectMeta{Name: "user-policy", Namespace: "shop"}})).NotTo(HaveOccurred())
+
+		By("triggering a reconcile")
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeComponentHandlers).To(HaveLen(2))
+		gatewayAPIImplementationConfig := fakeComponentHandlers[1].lastComponent.(gatewayapi.GatewayAPIImplementationConfigInterface).GetConfig()
+		Expect(gatewayAPIImplementationConfig.WAFRulesetConfigMap).NotTo(BeNil())
+		Expect(gatewayAPIImplementationConfig.DefaultCoreRulesetConfigMap).NotTo(BeNil())
+		Expect(gatewayAPIImplementationConfig.Gateways).To(HaveLen(1))
+		Expect(gatewayAPIImplementationConfig.Gateways[0].Name).To(Equal("gw-a"))
+		Expect(gatewayAPIImplementationConfig.CurrentWAFPolicies.UnsortedList()).To(ConsistOf("shop/gw-old-waf"))
+		Expect(gatewayAPIImplementationConfig.WAFRulesetDeployed).To(BeFalse())
+	})
+
+	It("uses the user's WAF ruleset and the WAF settings of the ApplicationLayer", func() {
+		Expect(c.Create(ctx, installation)).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, &operatorv1.GatewayAPI{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operatorv1.GatewayAPISpec{
+				GatewayClasses: []operatorv1.GatewayClassSpec{
+					{Name: "class-a", WebApplicationFirewall: &operatorv1.GatewayWebApplicationFirewall{}},
+				},
+			},
+		})).NotTo(HaveOccurred())
+
+		By("creating the user's ruleset config and an ApplicationLayer with a rule pack")
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-waf-config", Namespace: common.OperatorNamespace()},
+			Data: map[string]string{
+				"tigera.conf":    "Include coraza.conf\nInclude crs-setup.conf\n# user config\n",
+				"coraza.conf":    "",
+				"crs-setup.conf": "",
+			},
+		})).NotTo(HaveOccurred())
+		Expect(c.Create(ctx, &operatorv1.ApplicationLayer{
+			ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"},
+			Spec: operatorv1.ApplicationLayerSpec{
+				WebApplicationFirewallSettings: &operatorv1.WAFSettings{
+					RulePacks: []operatorv1.WAFRulePack{{ConfigMapName: "my-rules"}},
+				},
+			},
+		})).NotTo(HaveOccurred())
+
+		By("waiting for the rule pack")
+		mockStatus.On("SetDegraded", operatorv1.ResourceNotFound, mock.Anything, mock.Anything, mock.Anything).Return()
+		_, err := r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", operatorv1.ResourceNotFound, "Web Application Firewall rule pack ConfigMap not found", mock.Anything, mock.Anything)
+
+		By("reporting a rule pack without the rule pack label")
+		rulePack := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-rules", Namespace: common.OperatorNamespace()},
+			Data:       map[string]string{"custom.conf": "# custom"},
+		}
+		Expect(c.Create(ctx, rulePack)).NotTo(HaveOccurred())
+		mockStatus.On("SetDegraded", operatorv1.ResourceValidationError, mock.Anything, mock.Anything, mock.Anything).Return()
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		mockStatus.AssertCalled(GinkgoT(), "SetDegraded", operatorv1.ResourceValidationError, "Web Application Firewall rule pack ConfigMap is missing the operator.tigera.io/waf-rule-pack label", mock.Anything, mock.Anything)
+
+		By("labelling the rule pack")
+		rulePack.Labels = map[string]string{ruleset.RulePackLabel: "true"}
+		Expect(c.Update(ctx, rulePack)).NotTo(HaveOccurred())
+		Expect(utils.IsLabeledConfigMap(rulePack, ruleset.RulePackLabel)).To(BeTrue())
+		Expect(utils.IsLabeledConfigMap(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-config", Namespace: common.OperatorNamespace()},
+		}, ruleset.RulePackLabel)).To(BeFalse())
+		fakeComponentHandlers = nil
+		_, err = r.Reconcile(ctx, reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeComponentHandlers).To(HaveLen(2))
+		gatewayAPIImplementationConfig := fakeComponentHandlers[1].lastComponent.(gatewayapi.GatewayAPIImplementationConfigInterface).GetConfig()
+		Expect(gatewayAPIImplementationConfig.WAFRulesetConfigMap.Data).To(HaveKey("tigera.conf"))
+		Expect(gatewayAPIImplementationConfig.WAFRulesetConfigMap.Data["tigera.conf"]).To(ContainSubstring("# user config"))
+		Expect(gatewayAPIImplementationConfig.WAFSettings.RulePacks).To(HaveLen(1))
+		Expect(gatewayAPIImplementationConfig.WAFRulePacks).To(HaveLen(1))
+		Expect(gatewayAPIImplementationConfig.WAFRulePacks[0].Name).To(Equal("my-rules"))
+	})
+
 	It("Check felix configuration patching is set if it's not alreadyconfigured", func() {
 		Expect(c.Create(ctx, installation)).NotTo(HaveOccurred())
 
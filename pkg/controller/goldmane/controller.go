@@ -17,6 +17,7 @@ package goldmane
 import (
 	"context"
 	"fmt"
+	"time"
 
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -147,6 +148,7 @@ type Reconciler struct {
 // processed again if the returned error is non-nil or Result.Requeue is true, otherwise upon completion it will
 // remove the work from the queue.
 func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.V(2).Info("Reconciling Goldmane")
 
@@ -166,7 +168,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	}
 	r.status.OnCRFound()
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&goldmaneCR.ObjectMeta)
+	defer r.status.SetMetaData(goldmaneCR)
 
 	variant, installation, err := utils.GetInstallation(ctx, r.cli)
 	if err != nil {
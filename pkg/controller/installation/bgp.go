@@ -0,0 +1,175 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	// routeReflectorClusterIDAnnotation holds the route reflector cluster ID of a Calico node, which Calico stores on
+	// the Kubernetes node.
+	routeReflectorClusterIDAnnotation = "projectcalico.org/RouteReflectorClusterID"
+
+	// routeReflectorManagedAnnotation marks the nodes whose route reflector cluster ID was set by the operator, so that
+	// cluster IDs set by other means are not removed.
+	routeReflectorManagedAnnotation = "operator.tigera.io/route-reflector"
+)
+
+// setBGPConfiguration sets the fields of the default BGPConfiguration that are specified in the Installation. Fields
+// that are not specified are left as they are, so that they can still be managed directly. It returns whether the
+// BGPConfiguration was changed.
+func setBGPConfiguration(bgp *operator.CalicoBGPConfiguration, bc *crdv1.BGPConfiguration) bool {
+	orig := bc.Spec.DeepCopy()
+
+	if bgp.ASNumber != nil {
+		asNumber := numorstring.ASNumber(*bgp.ASNumber)
+		bc.Spec.ASNumber = &asNumber
+	}
+	if bgp.NodeToNodeMesh != nil {
+		enabled := *bgp.NodeToNodeMesh == operator.NodeToNodeMeshEnabled
+		bc.Spec.NodeToNodeMeshEnabled = &enabled
+	}
+	if bgp.ServiceClusterIPs != nil {
+		bc.Spec.ServiceClusterIPs = nil
+		for _, cidr := range bgp.ServiceClusterIPs {
+			bc.Spec.ServiceClusterIPs = append(bc.Spec.ServiceClusterIPs, crdv1.ServiceClusterIPBlock{CIDR: cidr})
+		}
+	}
+	if bgp.ServiceExternalIPs != nil {
+		bc.Spec.ServiceExternalIPs = nil
+		for _, cidr := range bgp.ServiceExternalIPs {
+			bc.Spec.ServiceExternalIPs = append(bc.Spec.ServiceExternalIPs, crdv1.ServiceExternalIPBlock{CIDR: cidr})
+		}
+	}
+	if bgp.ServiceLoadBalancerIPs != nil {
+		bc.Spec.ServiceLoadBalancerIPs = nil
+		for _, cidr := range bgp.ServiceLoadBalancerIPs {
+			bc.Spec.ServiceLoadBalancerIPs = append(bc.Spec.ServiceLoadBalancerIPs, crdv1.ServiceLoadBalancerIPBlock{CIDR: cidr})
+		}
+	}
+
+	return !reflect.DeepEqual(orig, &bc.Spec)
+}
+
+// reconcileRouteReflectors sets the route reflector cluster ID of the nodes selected by the route reflectors in the
+// Installation, and removes it from nodes that the operator configured but that are no longer selected.
+func reconcileRouteReflectors(ctx context.Context, cli client.Client, bgp *operator.CalicoBGPConfiguration) error {
+	var routeReflectors []operator.BGPRouteReflector
+	if bgp != nil {
+		routeReflectors = bgp.RouteReflectors
+	}
+	selectors := make([]labels.Selector, len(routeReflectors))
+	for i := range routeReflectors {
+		sel, err := metav1.LabelSelectorAsSelector(&routeReflectors[i].NodeSelector)
+		if err != nil {
+			return err
+		}
+		selectors[i] = sel
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := cli.List(ctx, nodes); err != nil {
+		return err
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		clusterID := ""
+		for j, sel := range selectors {
+			if sel.Matches(labels.Set(node.Labels)) {
+				clusterID = routeReflectors[j].ClusterID
+				break
+			}
+		}
+
+		_, managed := node.Annotations[routeReflectorManagedAnnotation]
+		patchFrom := client.MergeFrom(node.DeepCopy())
+		switch {
+		case clusterID != "" && (!managed || node.Annotations[routeReflectorClusterIDAnnotation] != clusterID):
+			if node.Annotations == nil {
+				node.Annotations = map[string]string{}
+			}
+			node.Annotations[routeReflectorClusterIDAnnotation] = clusterID
+			node.Annotations[routeReflectorManagedAnnotation] = "true"
+		case clusterID == "" && managed:
+			delete(node.Annotations, routeReflectorClusterIDAnnotation)
+			delete(node.Annotations, routeReflectorManagedAnnotation)
+		default:
+			continue
+		}
+		if err := cli.Patch(ctx, node, patchFrom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeBGPConfiguration returns the configuration for rendering the BGP peers of the Installation, including the
+// password secrets they reference and the BGPPeers and secrets that the operator previously created.
+func nodeBGPConfiguration(ctx context.Context, cli client.Client, install *operator.InstallationSpec) (*render.NodeBGPConfiguration, error) {
+	cfg := &render.NodeBGPConfiguration{Installation: install}
+
+	if install.CalicoNetwork != nil && install.CalicoNetwork.BGPConfiguration != nil {
+		seen := map[string]bool{}
+		for _, peer := range install.CalicoNetwork.BGPConfiguration.Peers {
+			if peer.Password == nil {
+				continue
+			}
+			s, err := utils.GetSecret(ctx, cli, peer.Password.Name, common.OperatorNamespace())
+			if err != nil {
+				return nil, err
+			} else if s == nil {
+				return nil, fmt.Errorf("BGP password secret %s/%s for peer %q not found", common.OperatorNamespace(), peer.Password.Name, peer.Name)
+			} else if _, ok := s.Data[peer.Password.Key]; !ok {
+				return nil, fmt.Errorf("BGP password secret %s/%s for peer %q does not have key %q", common.OperatorNamespace(), peer.Password.Name, peer.Name, peer.Password.Key)
+			}
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				cfg.PasswordSecrets = append(cfg.PasswordSecrets, s)
+			}
+		}
+	}
+
+	peers := &crdv1.BGPPeerList{}
+	if err := cli.List(ctx, peers, client.MatchingLabels{render.BGPManagedByLabel: render.BGPManagedByLabelValue}); err != nil {
+		return nil, err
+	}
+	for _, peer := range peers.Items {
+		cfg.ExistingPeers = append(cfg.ExistingPeers, peer.Name)
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := cli.List(ctx, secrets, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{render.BGPPasswordSecretLabel: "true"}); err != nil {
+		return nil, err
+	}
+	for _, s := range secrets.Items {
+		cfg.ExistingPasswordSecrets = append(cfg.ExistingPasswordSecrets, s.Name)
+	}
+	return cfg, nil
+}
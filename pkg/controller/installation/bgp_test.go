@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("BGP configuration tests", func() {
+	var c client.Client
+	var ctx context.Context
+	asNumber := uint32(64513)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = ctrlrfake.DefaultFakeClientBuilder(scheme).Build()
+		ctx = context.Background()
+	})
+
+	It("sets only the specified fields of the BGPConfiguration", func() {
+		bindMode := "NodeIP"
+		bc := &crdv1.BGPConfiguration{Spec: crdv1.BGPConfigurationSpec{
+			BindMode:          bindMode,
+			ServiceClusterIPs: []crdv1.ServiceClusterIPBlock{{CIDR: "10.96.0.0/12"}},
+		}}
+		disabled := operator.NodeToNodeMeshDisabled
+		bgp := &operator.CalicoBGPConfiguration{
+			ASNumber:           &asNumber,
+			NodeToNodeMesh:     &disabled,
+			ServiceExternalIPs: []string{"172.16.0.0/24"},
+		}
+
+		Expect(setBGPConfiguration(bgp, bc)).To(BeTrue())
+		Expect(*bc.Spec.ASNumber).To(Equal(numorstring.ASNumber(64513)))
+		Expect(*bc.Spec.NodeToNodeMeshEnabled).To(BeFalse())
+		Expect(bc.Spec.ServiceExternalIPs).To(Equal([]crdv1.ServiceExternalIPBlock{{CIDR: "172.16.0.0/24"}}))
+		Expect(bc.Spec.ServiceClusterIPs).To(Equal([]crdv1.ServiceClusterIPBlock{{CIDR: "10.96.0.0/12"}}))
+		Expect(bc.Spec.BindMode).To(Equal(bindMode))
+
+		// Applying the same settings again is not a change.
+		Expect(setBGPConfiguration(bgp, bc)).To(BeFalse())
+	})
+
+	It("sets the route reflector cluster ID of the selected nodes", func() {
+		nodes := []*corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "rr", Labels: map[string]string{"route-reflector": "true"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "worker"}},
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:        "manual",
+				Annotations: map[string]string{routeReflectorClusterIDAnnotation: "244.0.0.9"},
+			}},
+		}
+		for _, n := range nodes {
+			Expect(c.Create(ctx, n)).NotTo(HaveOccurred())
+		}
+		bgp := &operator.CalicoBGPConfiguration{
+			RouteReflectors: []operator.BGPRouteReflector{{
+				NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"route-reflector": "true"}},
+				ClusterID:    "244.0.0.1",
+			}},
+		}
+		Expect(reconcileRouteReflectors(ctx, c, bgp)).NotTo(HaveOccurred())
+
+		node := &corev1.Node{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "rr"}, node)).NotTo(HaveOccurred())
+		Expect(node.Annotations).To(HaveKeyWithValue(routeReflectorClusterIDAnnotation, "244.0.0.1"))
+		Expect(c.Get(ctx, client.ObjectKey{Name: "worker"}, node)).NotTo(HaveOccurred())
+		Expect(node.Annotations).NotTo(HaveKey(routeReflectorClusterIDAnnotation))
+
+		// Removing the route reflectors only clears the cluster IDs that the operator set.
+		Expect(reconcileRouteReflectors(ctx, c, nil)).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, client.ObjectKey{Name: "rr"}, node)).NotTo(HaveOccurred())
+		Expect(node.Annotations).NotTo(HaveKey(routeReflectorClusterIDAnnotation))
+		Expect(node.Annotations).NotTo(HaveKey(routeReflectorManagedAnnotation))
+		Expect(c.Get(ctx, client.ObjectKey{Name: "manual"}, node)).NotTo(HaveOccurred())
+		Expect(node.Annotations).To(HaveKeyWithValue(routeReflectorClusterIDAnnotation, "244.0.0.9"))
+	})
+
+	Context("BGP peers", func() {
+		var install *operator.InstallationSpec
+
+		BeforeEach(func() {
+			install = &operator.InstallationSpec{
+				CalicoNetwork: &operator.CalicoNetworkSpec{
+					BGPConfiguration: &operator.CalicoBGPConfiguration{
+						Peers: []operator.BGPPeer{{
+							Name:     "tor",
+							PeerIP:   "10.0.1.1",
+							ASNumber: &asNumber,
+							Password: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "bgp-secrets"}, Key: "tor"},
+						}},
+					},
+				},
+			}
+		})
+
+		It("collects the password secrets and the resources the operator created", func() {
+			Expect(c.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bgp-secrets", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"tor": []byte("secret")},
+			})).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, &crdv1.BGPPeer{ObjectMeta: metav1.ObjectMeta{
+				Name:   render.BGPPeerNamePrefix + "old",
+				Labels: map[string]string{render.BGPManagedByLabel: render.BGPManagedByLabelValue},
+			}})).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, &crdv1.BGPPeer{ObjectMeta: metav1.ObjectMeta{Name: "user-peer"}})).NotTo(HaveOccurred())
+
+			cfg, err := nodeBGPConfiguration(ctx, c, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.PasswordSecrets).To(HaveLen(1))
+			Expect(cfg.PasswordSecrets[0].Name).To(Equal("bgp-secrets"))
+			Expect(cfg.ExistingPeers).To(ConsistOf(render.BGPPeerNamePrefix + "old"))
+			Expect(cfg.ExistingPasswordSecrets).To(BeEmpty())
+		})
+
+		It("reports a missing password key", func() {
+			Expect(c.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bgp-secrets", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"other": []byte("secret")},
+			})).NotTo(HaveOccurred())
+
+			_, err := nodeBGPConfiguration(ctx, c, install)
+			Expect(err).To(MatchError(ContainSubstring(`does not have key "tor"`)))
+		})
+
+		It("reports a missing password secret", func() {
+			_, err := nodeBGPConfiguration(ctx, c, install)
+			Expect(err).To(MatchError(ContainSubstring("not found")))
+		})
+	})
+})
@@ -15,10 +15,14 @@
 package installation
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"strconv"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/controller/utils"
 
 	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
@@ -82,6 +86,22 @@ func isRolloutCompleteWithBPFVolumes(ds *appsv1.DaemonSet) bool {
 	return false
 }
 
+// isRolloutCompleteWithBPFVolumesOnAllDaemonSets checks that the rollout of BPF volumes is complete for the default
+// calico-node DaemonSet and the DaemonSet of every node group in the Installation, since Felix only reads bpfEnabled
+// from the default FelixConfiguration which applies to all of them.
+func isRolloutCompleteWithBPFVolumesOnAllDaemonSets(ctx context.Context, cli client.Client, install *operator.InstallationSpec) (bool, error) {
+	for _, name := range calicoNodeDaemonSetNames(install) {
+		ds := &appsv1.DaemonSet{}
+		if err := cli.Get(ctx, name, ds); err != nil {
+			return false, err
+		}
+		if !isRolloutCompleteWithBPFVolumes(ds) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func setBPFEnabledOnFelixConfiguration(fc *crdv1.FelixConfiguration, bpfEnabled bool) error {
 	err := bpfValidateAnnotations(fc)
 	if err != nil {
@@ -15,8 +15,10 @@
 package installation
 
 import (
+	"context"
 	"strconv"
 
+	operator "github.com/tigera/operator/api/v1"
 	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
 	"github.com/tigera/operator/pkg/common"
 
@@ -28,6 +30,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("BPF functional tests", func() {
@@ -163,6 +167,31 @@ var _ = Describe("BPF functional tests", func() {
 			completed := isRolloutCompleteWithBPFVolumes(ds)
 			Expect(completed).To(BeTrue())
 		})
+
+		It("should wait for the DaemonSets of all node groups", func() {
+			ds.Spec.Template.Spec.Volumes = append(ds.Spec.Template.Spec.Volumes, bpfVolume)
+			ds.Status.CurrentNumberScheduled = 4
+			ds.Status.UpdatedNumberScheduled = 4
+			ds.Status.NumberAvailable = 4
+			group := ds.DeepCopy()
+			group.Name = render.CalicoNodeGroupDaemonSetName("gpu")
+			group.Status.UpdatedNumberScheduled = 2
+
+			scheme := runtime.NewScheme()
+			Expect(appsv1.AddToScheme(scheme)).NotTo(HaveOccurred())
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ds, group).Build()
+			install := &operator.InstallationSpec{CalicoNodeGroups: []operator.CalicoNodeGroup{{Name: "gpu"}}}
+
+			completed, err := isRolloutCompleteWithBPFVolumesOnAllDaemonSets(context.Background(), cli, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(completed).To(BeFalse())
+
+			group.Status.UpdatedNumberScheduled = 4
+			Expect(cli.Status().Update(context.Background(), group)).NotTo(HaveOccurred())
+			completed, err = isRolloutCompleteWithBPFVolumesOnAllDaemonSets(context.Background(), cli, install)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(completed).To(BeTrue())
+		})
 	})
 
 	Context("BPFEnabled on daemonset variable tests", func() {
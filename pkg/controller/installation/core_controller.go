@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 
@@ -272,6 +273,15 @@ func add(c ctrlruntime.Controller, r *ReconcileInstallation) error {
 		return fmt.Errorf("tigera-installation-controller failed to watch FelixConfiguration resource: %w", err)
 	}
 
+	// Watch for nodes being added, removed or relabeled, which changes the nodes selected as route reflectors, for
+	// per-node Felix overrides and by node groups, and for Felix publishing the WireGuard public key of a node. Whether
+	// any of those are configured is decided in Reconcile. Changes to other node annotations, such as the route
+	// reflector cluster IDs set by this controller, are ignored.
+	err = c.WatchObject(&corev1.Node{}, &handler.EnqueueRequestForObject{}, predicate.Or(predicate.LabelChangedPredicate{}, wireguardPublicKeyChangedPredicate))
+	if err != nil {
+		return fmt.Errorf("tigera-installation-controller failed to watch Node resource: %w", err)
+	}
+
 	// Watch for changes to BGPConfiguration.
 	err = c.WatchObject(&crdv1.BGPConfiguration{}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -297,6 +307,31 @@ func add(c ctrlruntime.Controller, r *ReconcileInstallation) error {
 			return fmt.Errorf("tigera-installation-controller failed to watch primary resource: %v", err)
 		}
 
+		// Watch PacketCaptureAPI, whose spec.storage determines which capture settings spec.felixOverrides may set.
+		err = c.WatchObject(&operator.PacketCaptureAPI{}, &handler.EnqueueRequestForObject{})
+		if err != nil {
+			return fmt.Errorf("tigera-installation-controller failed to watch PacketCaptureAPI resource: %w", err)
+		}
+
+		// Watch NonClusterHost, so that Typha refuses connections from revoked hosts. Only the addresses of the
+		// revoked hosts are rendered, so other changes, such as the hosts being seen, are ignored.
+		err = c.WatchObject(&operator.NonClusterHost{}, &handler.EnqueueRequestForObject{}, predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldNCH, ok := e.ObjectOld.(*operator.NonClusterHost)
+				if !ok {
+					return true
+				}
+				newNCH, ok := e.ObjectNew.(*operator.NonClusterHost)
+				if !ok {
+					return true
+				}
+				return !reflect.DeepEqual(render.RevokedNonClusterHostNets(oldNCH), render.RevokedNonClusterHostNets(newNCH))
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("tigera-installation-controller failed to watch NonClusterHost resource: %v", err)
+		}
+
 		// Watch the internal manager TLS secret in the operator namespace, which included in the bundle for es-kube-controllers.
 		if err = utils.AddSecretsWatch(c, render.ManagerInternalTLSSecretName, common.OperatorNamespace()); err != nil {
 			return fmt.Errorf("tigera-installation-controller failed to watch secret: %v", err)
@@ -398,6 +433,15 @@ func getActivePools(ctx context.Context, client client.Client) (*crdv1.IPPoolLis
 	return &filtered, nil
 }
 
+// getPendingIPv6Pools returns the IPv6 pools that are disabled while the cluster transitions to dual-stack.
+func getPendingIPv6Pools(ctx context.Context, client client.Client, instance *operator.Installation) ([]crdv1.IPPool, error) {
+	allPools := crdv1.IPPoolList{}
+	if err := client.List(ctx, &allPools); err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("unable to list IPPools: %s", err.Error())
+	}
+	return ippool.PendingIPv6Pools(instance, &allPools), nil
+}
+
 // updateInstallationWithDefaults returns the default installation instance with defaults populated.
 func updateInstallationWithDefaults(ctx context.Context, client client.Client, instance *operator.Installation, provider operator.Provider) error {
 	// Determine the provider in use by combining any auto-detected value with any value
@@ -422,6 +466,14 @@ func updateInstallationWithDefaults(ctx context.Context, client client.Client, i
 		return fmt.Errorf("unable to list IPPools: %s", err.Error())
 	}
 
+	// An IPv6 pool being added to an IPv4 cluster stays disabled until calico-node is ready for IPv6 on every node,
+	// but calico-node needs IPv6 address autodetection defaulted in order to get there.
+	pendingPools, err := getPendingIPv6Pools(ctx, client, instance)
+	if err != nil {
+		return err
+	}
+	currentPools.Items = append(currentPools.Items, pendingPools...)
+
 	err = MergeAndFillDefaults(instance, awsNode, currentPools)
 	if err != nil {
 		return err
@@ -754,6 +806,7 @@ func mergeProvider(cr *operator.Installation, provider operator.Provider) error
 }
 
 func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.V(2).Info("Reconciling Installation.operator.tigera.io")
 
@@ -784,7 +837,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	// Mark CR found so we can report converter problems via tigerastatus
 	r.status.OnCRFound()
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating Installation status conditions.
 	if request.Name == InstallationName && request.Namespace == "" {
@@ -812,7 +865,15 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 			install, err := convert.Convert(ctx, r.client)
 			if err != nil {
 				if errors.As(err, &convert.ErrIncompatibleCluster{}) {
-					r.status.SetDegraded(operator.MigrationError, "Existing Calico installation can not be managed by Tigera Operator as it is configured in a way that Operator does not currently support. Please update your existing Calico install config", err, reqLogger)
+					// Conversion stops at the first problem, so publish every incompatibility for the user to fix at once.
+					if report, err := convert.Preflight(ctx, r.client); err != nil {
+						reqLogger.Error(err, "Failed to run migration preflight")
+					} else if report != nil {
+						if err := convert.PublishReport(ctx, r.client, report); err != nil {
+							reqLogger.Error(err, "Failed to publish migration report")
+						}
+					}
+					r.status.SetDegraded(operator.MigrationError, fmt.Sprintf("Existing Calico installation can not be managed by Tigera Operator as it is configured in a way that Operator does not currently support. Please update your existing Calico install config. All incompatibilities are listed in ConfigMap %s/%s", common.OperatorNamespace(), convert.ReportConfigMapName), err, reqLogger)
 					// We should always requeue a convert problem. Don't return error
 					// to make sure we never back off retrying.
 					return reconcile.Result{RequeueAfter: utils.StandardRetry}, nil
@@ -821,6 +882,9 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 				return reconcile.Result{}, err
 			}
 			instance.Spec = utils.OverrideInstallationSpec(install.Spec, instance.Spec)
+			if err := convert.DeleteReport(ctx, r.client); err != nil {
+				reqLogger.Error(err, "Failed to delete migration report")
+			}
 		}
 	}
 
@@ -837,6 +901,21 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	// The capture settings that the PacketCaptureAPI derives from spec.storage can't be overridden.
+	captureStorage := false
+	if r.enterpriseCRDsExist {
+		packetCaptureAPI, err := utils.GetPacketCaptureAPI(ctx, r.client)
+		if err != nil && !apierrors.IsNotFound(err) {
+			r.status.SetDegraded(operator.ResourceReadError, "Error reading PacketCaptureAPI", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		captureStorage = packetCaptureAPI != nil && packetCaptureAPI.Spec.Storage != nil
+	}
+	if err := validateFelixOverrides(&instance.Spec, captureStorage); err != nil {
+		r.status.SetDegraded(operator.InvalidConfigurationError, "Invalid Installation provided", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	// See the section 'Use of Finalizers for graceful termination' at the top of this file for details.
 	if installationMarkedForDeletion {
 		// This controller manages a finalizer to track whether its own pods have been properly torn down. Only remove it
@@ -1137,6 +1216,18 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, err
 	}
 
+	// Write the Felix settings from the Installation before any defaults, so that the settings take precedence.
+	if _, err := utils.PatchFelixConfiguration(ctx, r.client, func(fc *crdv1.FelixConfiguration) (bool, error) {
+		return setFelixOverridesOnFelixConfiguration(instance, fc)
+	}); err != nil {
+		r.status.SetDegraded(operator.ResourceUpdateError, "Error applying spec.felixOverrides to FelixConfiguration", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	if err := reconcileNodeFelixOverrides(ctx, r.client, instance); err != nil {
+		r.status.SetDegraded(operator.ResourceUpdateError, "Error applying spec.felixOverrides.nodeOverrides to per-node FelixConfigurations", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	// Set any non-default FelixConfiguration values that we need.
 	felixConfiguration, err := utils.PatchFelixConfiguration(ctx, r.client, func(fc *crdv1.FelixConfiguration) (bool, error) {
 		// Configure defaults.
@@ -1150,7 +1241,10 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		if err != nil {
 			return false, err
 		}
-		return u || u2, nil
+
+		// Configure WireGuard.
+		u3 := setWireGuardOnFelixConfiguration(instance, fc, reqLogger)
+		return u || u2 || u3, nil
 	})
 	if err != nil {
 		return reconcile.Result{}, err
@@ -1408,6 +1502,30 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	// Apply the BGP settings from the Installation, if any.
+	if instance.Spec.CalicoNetwork != nil && instance.Spec.CalicoNetwork.BGPConfiguration != nil {
+		_, err = utils.PatchBGPConfiguration(ctx, r.client, func(bc *crdv1.BGPConfiguration) (bool, error) {
+			return setBGPConfiguration(instance.Spec.CalicoNetwork.BGPConfiguration, bc), nil
+		})
+		if err != nil {
+			r.status.SetDegraded(operator.ResourceUpdateError, "Error updating BGPConfiguration", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+	var bgpSpec *operator.CalicoBGPConfiguration
+	if instance.Spec.CalicoNetwork != nil {
+		bgpSpec = instance.Spec.CalicoNetwork.BGPConfiguration
+	}
+	if err = reconcileRouteReflectors(ctx, r.client, bgpSpec); err != nil {
+		r.status.SetDegraded(operator.ResourceUpdateError, "Error configuring BGP route reflectors", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	nodeBGPCfg, err := nodeBGPConfiguration(ctx, r.client, &instance.Spec)
+	if err != nil {
+		r.status.SetDegraded(operator.ResourceReadError, "Error reading BGP peer configuration", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	// Fetch any existing default BGPConfiguration object.
 	bgpConfiguration := &crdv1.BGPConfiguration{}
 	err = r.client.Get(ctx, types.NamespacedName{Name: "default"}, bgpConfiguration)
@@ -1467,6 +1585,56 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	// Compare the pod network MTU with the MTU of the host interfaces, if configured to. When enforcing, configure the
+	// computed MTU on calico-node unless an MTU has been specified, so that every node uses the same pod MTU.
+	var mtuRequeue time.Duration
+	if cn := instance.Spec.CalicoNetwork; cn != nil && cn.MTUDetection != nil && *cn.MTUDetection != operator.MTUDetectionDisabled {
+		mtu, err := detectMTU(ctx, r.client, &instance.Spec, currentPools.Items, felixConfiguration)
+		if err != nil {
+			r.status.SetDegraded(operator.ResourceReadError, "Error detecting host MTU", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		mismatch := mtu.mismatch
+		if *cn.MTUDetection == operator.MTUDetectionEnforce && cn.MTU == nil {
+			applied, err := appliedMTU(ctx, r.client, &instance.Spec)
+			if err != nil {
+				r.status.SetDegraded(operator.ResourceReadError, "Error reading the MTU of the calico-node DaemonSets", err, reqLogger)
+				return reconcile.Result{}, err
+			}
+			enforcement := enforceMTU(mtu, applied, announcedMTU(instance))
+			if enforcement.pending > 0 {
+				reqLogger.Info(enforcement.msg)
+			}
+			// Record the pod MTU that is reported as changing, and forget it once it has been applied.
+			if enforcement.pending > 0 || enforcement.msg == "" {
+				if err = setAnnouncedMTU(ctx, r.client, enforcement.pending); err != nil {
+					r.status.SetDegraded(operator.ResourceUpdateError, "Error recording the announced pod MTU", err, reqLogger)
+					return reconcile.Result{}, err
+				}
+			}
+			if enforcement.mtu > 0 {
+				cn.MTU = &enforcement.mtu
+			}
+			mismatch = enforcement.msg
+			if mismatch != "" {
+				mtuRequeue = mtuRecheckInterval
+			}
+		}
+		if mismatch != "" {
+			r.status.SetCondition(operator.ComponentMTUMismatch, operator.ConditionTrue, operator.MTUMismatchDetected, mismatch)
+		} else {
+			r.status.ClearCondition(operator.ComponentMTUMismatch)
+		}
+	} else {
+		r.status.ClearCondition(operator.ComponentMTUMismatch)
+	}
+
+	removedNodeGroups, err := removedCalicoNodeGroups(ctx, r.client, &instance.Spec)
+	if err != nil {
+		r.status.SetDegraded(operator.ResourceReadError, "Error querying calico-node DaemonSets", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	// Build a configuration for rendering calico/node.
 	nodeCfg := render.NodeConfiguration{
 		GoldmaneRunning:               goldmaneRunning,
@@ -1491,6 +1659,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		BindMode:                      bgpConfiguration.Spec.BindMode,
 		FelixPrometheusMetricsEnabled: utils.IsFelixPrometheusMetricsEnabled(felixConfiguration),
 		FelixPrometheusMetricsPort:    felixPrometheusMetricsPort,
+		RemovedNodeGroups:             removedNodeGroups,
 	}
 	// Check if BPFNetworkBootstrap is Enabled and its requirements are met.
 	bpfBootstrapReq, err := utils.BPFBootstrapRequirements(ctx, r.client, &instance.Spec)
@@ -1520,7 +1689,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		warnOnce.Reset()
 	}
 
-	components = append(components, render.Node(&nodeCfg))
+	components = append(components, render.Node(&nodeCfg), render.NodeBGP(nodeBGPCfg))
 
 	csiCfg := render.CSIConfiguration{
 		Installation: &instance.Spec,
@@ -1598,7 +1767,14 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 
 	// TODO: We handle too many components in this controller at the moment. Once we are done consolidating,
 	// we can have the CreateOrUpdate logic handle this for us.
-	r.status.AddDaemonsets([]types.NamespacedName{{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace}})
+	r.status.AddDaemonsets(calicoNodeDaemonSetNames(&instance.Spec))
+	if len(removedNodeGroups) > 0 {
+		var removed []types.NamespacedName
+		for _, name := range removedNodeGroups {
+			removed = append(removed, types.NamespacedName{Name: render.CalicoNodeGroupDaemonSetName(name), Namespace: common.CalicoNamespace})
+		}
+		r.status.RemoveDaemonsets(removed...)
+	}
 	r.status.AddDeployments([]types.NamespacedName{{Name: common.KubeControllersDeploymentName, Namespace: common.CalicoNamespace}})
 	certificateManager.AddToStatusManager(r.status, common.CalicoNamespace)
 
@@ -1620,6 +1796,52 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 		}
 	}
 
+	// Roll out updates to calico-node ourselves if configured, deleting outdated pods a batch at a time.
+	var rolloutRequeue time.Duration
+	if instance.Spec.NodeRollout != nil {
+		rollout, err := reconcileNodeRollout(ctx, r.client, instance.Spec.NodeRollout, time.Now(), reqLogger)
+		if err != nil {
+			r.status.SetDegraded(operator.ResourceUpdateError, "Error rolling out calico-node", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		if rollout.State != nil {
+			reason := operator.RolloutProgressing
+			if rollout.State.Paused {
+				reason = operator.RolloutPaused
+			}
+			r.status.SetCondition(operator.ComponentRollingOut, operator.ConditionTrue, reason, rollout.State.Message)
+		} else {
+			r.status.ClearCondition(operator.ComponentRollingOut)
+		}
+		if rollout.Degraded != nil {
+			r.status.SetDegraded(operator.ResourceNotReady, "calico-node rollout is paused", rollout.Degraded, reqLogger)
+			return reconcile.Result{RequeueAfter: rollout.RequeueAfter}, nil
+		}
+		rolloutRequeue = rollout.RequeueAfter
+	} else {
+		r.status.ClearCondition(operator.ComponentRollingOut)
+	}
+
+	// Report whether every node is encrypting traffic with the WireGuard settings from the Installation. The node watch
+	// triggers a reconcile when Felix publishes the WireGuard public key of a node.
+	encryption, err := getEncryptionState(ctx, r.client, instance)
+	if err != nil {
+		r.status.SetDegraded(operator.ResourceReadError, "Error reading node encryption state", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	switch {
+	case encryption == nil:
+		r.status.ClearCondition(operator.ComponentEncrypted)
+	case encryption.Ready:
+		r.status.SetCondition(operator.ComponentEncrypted, operator.ConditionTrue, operator.EncryptionActive, encryption.Message)
+	default:
+		r.status.SetCondition(operator.ComponentEncrypted, operator.ConditionFalse, operator.EncryptionPending, encryption.Message)
+	}
+	requeueAfter := rolloutRequeue
+	if mtuRequeue > 0 && (requeueAfter == 0 || requeueAfter > mtuRequeue) {
+		requeueAfter = mtuRequeue
+	}
+
 	// Determine which MTU to use in the status fields.
 	statusMTU := 0
 	if instance.Spec.CalicoNetwork != nil && instance.Spec.CalicoNetwork.MTU != nil {
@@ -1705,7 +1927,7 @@ func (r *ReconcileInstallation) Reconcile(ctx context.Context, request reconcile
 	}
 
 	reqLogger.V(1).Info("Finished reconciling Installation")
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func readMTUFile() (int, error) {
@@ -1918,7 +2140,7 @@ func (r *ReconcileInstallation) setDefaultsOnFelixConfiguration(ctx context.Cont
 		case operator.ProviderRKE2:
 			dnsService = "k8s-service:kube-system/rke2-coredns-rke2-coredns"
 		}
-		if dnsService != "" {
+		if dnsService != "" && !hasFelixOverride(&install.Spec, "dnsTrustedServers") {
 			felixDefault := "k8s-service:kube-dns"
 			trustedServers := []string{dnsService}
 			// Keep any other values that are already configured, excepting the value
@@ -1989,12 +2211,11 @@ func (r *ReconcileInstallation) setBPFUpdatesOnFelixConfiguration(ctx context.Co
 
 	bpfEnabledOnInstall := install.Spec.BPFEnabled()
 	if bpfEnabledOnInstall {
-		ds := &appsv1.DaemonSet{}
-		err := r.client.Get(ctx, types.NamespacedName{Namespace: common.CalicoNamespace, Name: common.NodeDaemonSetName}, ds)
+		rolledOut, err := isRolloutCompleteWithBPFVolumesOnAllDaemonSets(ctx, r.client, &install.Spec)
 		if err != nil {
 			return false, err
 		}
-		if !bpfEnabledOnFelixConfig(fc) && isRolloutCompleteWithBPFVolumes(ds) {
+		if !bpfEnabledOnFelixConfig(fc) && rolledOut {
 			err := setBPFEnabledOnFelixConfiguration(fc, bpfEnabledOnInstall)
 			if err != nil {
 				reqLogger.Error(err, "Unable to enable eBPF data plane")
@@ -2070,7 +2291,7 @@ var osExitOverride = os.Exit
 
 // checkActive verifies the operator that calls this function is designated as the active operator.
 // If this operator is not designated as active then this function does an os.Exit(0) so the operator
-// gets restarted.
+// gets restarted. The same happens after this operator hands off to a validated candidate operator.
 // If this operator is the designated operator (or assumed because there is no designation) then
 // this function returns with no error.
 // If the active operator designation needs to be set then the first return field is a ConfigMap that
@@ -2094,6 +2315,20 @@ func (r *ReconcileInstallation) checkActive(log logr.Logger) (*corev1.ConfigMap,
 		return nil, fmt.Errorf("returning error for test purposes")
 	}
 
+	// Hand control over to a candidate operator once it has validated itself.
+	if handoff := active.YieldToCandidate(cm, time.Now()); handoff != nil {
+		if err := r.client.Update(context.Background(), handoff); err != nil {
+			r.status.SetDegraded(operator.ResourceUpdateError, "Error handing off to the candidate operator", err, log)
+			return nil, err
+		}
+		_, candidateNs := active.IsThisOperatorActive(handoff)
+		log.Info("Exiting because this operator has handed off to the candidate operator",
+			"my-namespace", common.OperatorNamespace(),
+			"active-namespace", candidateNs)
+		osExitOverride(0)
+		return nil, fmt.Errorf("returning error for test purposes")
+	}
+
 	if cm == nil {
 		return active.GenerateMyActiveConfigMap(), nil
 	} else {
@@ -161,6 +161,8 @@ var _ = Describe("Testing core-controller installation", func() {
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockStatus.On("ClearCondition", mock.Anything)
 			mockStatus.On("SetMetaData", mock.Anything).Return()
 
 			// Create the indexer and informer used by the typhaAutoscaler
@@ -687,6 +689,8 @@ var _ = Describe("Testing core-controller installation", func() {
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockStatus.On("ClearCondition", mock.Anything)
 			mockStatus.On("SetMetaData", mock.Anything).Return()
 
 			// Create the indexer and informer used by the typhaAutoscaler
@@ -905,6 +909,8 @@ var _ = Describe("Testing core-controller installation", func() {
 			mockStatus.On("ClearDegraded")
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockStatus.On("ClearCondition", mock.Anything)
 			mockStatus.On("SetMetaData", mock.Anything).Return()
 
 			// Create the indexer and informer used by the typhaAutoscaler
@@ -1622,6 +1628,40 @@ var _ = Describe("Testing core-controller installation", func() {
 			Expect(cm.Data["active-namespace"]).To(Equal("tigera-operator"))
 		})
 
+		It("should hand off to a validated candidate operator", func() {
+			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "active-operator",
+					Namespace: common.CalicoNamespace,
+				},
+				Data: map[string]string{
+					"active-namespace":    "tigera-operator",
+					"candidate-namespace": "tigera-operator-green",
+					"candidate-status":    "Ready",
+				},
+			})).NotTo(HaveOccurred())
+
+			exited := false
+			osExitOverride = func(_ int) { exited = true }
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).Should(HaveOccurred())
+			Expect(exited).Should(BeTrue())
+			cm := corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "active-operator",
+					Namespace: common.CalicoNamespace,
+				},
+			}
+			Expect(test.GetResource(c, &cm)).To(BeNil())
+			Expect(cm.Data["active-namespace"]).To(Equal("tigera-operator-green"))
+			Expect(cm.Data["previous-namespace"]).To(Equal("tigera-operator"))
+			Expect(cm.Data).To(HaveKey("handoff-time"))
+			Expect(cm.Data).NotTo(HaveKey("candidate-namespace"))
+		})
+
 		It("should not overwrite active-operator CM when it already exists", func() {
 			Expect(c.Create(ctx, cr)).NotTo(HaveOccurred())
 			Expect(c.Create(ctx, &corev1.ConfigMap{
@@ -1995,6 +2035,8 @@ var _ = Describe("Testing core-controller installation", func() {
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockStatus.On("ClearCondition", mock.Anything)
 			mockStatus.On("SetMetaData", mock.Anything).Return()
 
 			// Create the indexer and informer used by the typhaAutoscaler
@@ -2129,6 +2171,8 @@ var _ = Describe("Testing core-controller installation", func() {
 			mockStatus.On("AddCertificateSigningRequests", mock.Anything)
 			mockStatus.On("RemoveCertificateSigningRequests", mock.Anything)
 			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("SetCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockStatus.On("ClearCondition", mock.Anything)
 			mockStatus.On("SetMetaData", mock.Anything).Return()
 
 			// Create the indexer and informer used by the typhaAutoscaler
@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+)
+
+const (
+	// Felix publishes the public key of each node's WireGuard interface in these annotations once the interface is
+	// up, so a node with the annotation is ready to encrypt traffic.
+	wireguardPublicKeyAnnotation   = "projectcalico.org/WireguardPublicKey"
+	wireguardPublicKeyV6Annotation = "projectcalico.org/WireguardPublicKeyV6"
+
+	// maxEncryptionListedNodes limits the number of nodes included in encryption status messages.
+	maxEncryptionListedNodes = 5
+)
+
+// encryptionState is the WireGuard encryption state of the nodes.
+type encryptionState struct {
+	// Ready is true once every node is encrypting traffic.
+	Ready   bool
+	Message string
+}
+
+// wireguardPublicKeyChangedPredicate passes node updates that change a WireGuard public key annotation, so that the
+// encryption state is updated as Felix brings up WireGuard on each node.
+var wireguardPublicKeyChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		for _, annotation := range []string{wireguardPublicKeyAnnotation, wireguardPublicKeyV6Annotation} {
+			if e.ObjectOld.GetAnnotations()[annotation] != e.ObjectNew.GetAnnotations()[annotation] {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// setWireGuardOnFelixConfiguration writes the WireGuard settings from the Installation to the FelixConfiguration.
+// Settings that are not specified in the Installation are left as they are.
+func setWireGuardOnFelixConfiguration(install *operator.Installation, fc *crdv1.FelixConfiguration, reqLogger logr.Logger) bool {
+	if install.Spec.CalicoNetwork == nil || install.Spec.CalicoNetwork.Encryption == nil {
+		return false
+	}
+	e := install.Spec.CalicoNetwork.Encryption
+
+	updated := false
+	set := func(field **bool, option *operator.WireGuardOption, name string) {
+		if option == nil {
+			return
+		}
+		enabled := *option == operator.WireGuardEnabled
+		if *field == nil || **field != enabled {
+			*field = &enabled
+			updated = true
+			reqLogger.Info("Patching WireGuard setting", name, enabled)
+		}
+	}
+	set(&fc.Spec.WireguardEnabled, e.WireGuardIPv4, "wireguardEnabled")
+	set(&fc.Spec.WireguardEnabledV6, e.WireGuardIPv6, "wireguardEnabledV6")
+	set(&fc.Spec.WireguardHostEncryptionEnabled, e.HostEncryption, "wireguardHostEncryptionEnabled")
+	return updated
+}
+
+// getEncryptionState returns the WireGuard encryption state of the nodes, or nil if the Installation does not enable
+// WireGuard. A node is encrypting traffic once Felix has published the public key of its WireGuard interface. Host
+// encryption uses the IPv4 WireGuard interface, so it is reported as part of IPv4 rather than separately.
+func getEncryptionState(ctx context.Context, cli client.Client, install *operator.Installation) (*encryptionState, error) {
+	if install.Spec.CalicoNetwork == nil || install.Spec.CalicoNetwork.Encryption == nil {
+		return nil, nil
+	}
+	e := install.Spec.CalicoNetwork.Encryption
+
+	type family struct {
+		name       string
+		annotation string
+	}
+	var families []family
+	if e.WireGuardIPv4 != nil && *e.WireGuardIPv4 == operator.WireGuardEnabled {
+		name := "IPv4"
+		if e.HostEncryption != nil && *e.HostEncryption == operator.WireGuardEnabled {
+			name = "IPv4 with host encryption"
+		}
+		families = append(families, family{name, wireguardPublicKeyAnnotation})
+	}
+	if e.WireGuardIPv6 != nil && *e.WireGuardIPv6 == operator.WireGuardEnabled {
+		families = append(families, family{"IPv6", wireguardPublicKeyV6Annotation})
+	}
+	if len(families) == 0 {
+		return nil, nil
+	}
+
+	nodes := corev1.NodeList{}
+	if err := cli.List(ctx, &nodes); err != nil {
+		return nil, err
+	}
+	linux := []corev1.Node{}
+	for _, n := range nodes.Items {
+		// WireGuard is not supported on Windows nodes.
+		if n.Labels["kubernetes.io/os"] != "windows" {
+			linux = append(linux, n)
+		}
+	}
+
+	state := &encryptionState{Ready: true}
+	msgs := []string{}
+	for _, f := range families {
+		waiting := []string{}
+		for _, n := range linux {
+			if n.Annotations[f.annotation] == "" {
+				waiting = append(waiting, n.Name)
+			}
+		}
+		msg := fmt.Sprintf("WireGuard %s is active on %d/%d nodes", f.name, len(linux)-len(waiting), len(linux))
+		if len(waiting) > 0 {
+			state.Ready = false
+			msg = fmt.Sprintf("%s, waiting for %s", msg, encryptionNodeList(waiting))
+		}
+		msgs = append(msgs, msg)
+	}
+	state.Message = strings.Join(msgs, "; ")
+	return state, nil
+}
+
+// encryptionNodeList formats nodes for a status message, truncating long lists.
+func encryptionNodeList(nodes []string) string {
+	sort.Strings(nodes)
+	if len(nodes) > maxEncryptionListedNodes {
+		return fmt.Sprintf("%s and %d more", strings.Join(nodes[:maxEncryptionListedNodes], ", "), len(nodes)-maxEncryptionListedNodes)
+	}
+	return strings.Join(nodes, ", ")
+}
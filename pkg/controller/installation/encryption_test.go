@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+var _ = Describe("WireGuard encryption", func() {
+	var install *operator.Installation
+	enabled := operator.WireGuardEnabled
+	disabled := operator.WireGuardDisabled
+
+	newNode := func(name string, annotations map[string]string) client.Object {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+
+	build := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	BeforeEach(func() {
+		install = &operator.Installation{Spec: operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{}}}
+	})
+
+	It("should write the WireGuard settings from the Installation to the FelixConfiguration", func() {
+		fc := &crdv1.FelixConfiguration{}
+		fc.Spec.WireguardEnabledV6 = ptr.BoolToPtr(true)
+		Expect(setWireGuardOnFelixConfiguration(install, fc, logf.Log)).To(BeFalse())
+
+		install.Spec.CalicoNetwork.Encryption = &operator.EncryptionSpec{WireGuardIPv4: &enabled, HostEncryption: &disabled}
+		Expect(setWireGuardOnFelixConfiguration(install, fc, logf.Log)).To(BeTrue())
+		Expect(fc.Spec.WireguardEnabled).To(Equal(ptr.BoolToPtr(true)))
+		Expect(fc.Spec.WireguardHostEncryptionEnabled).To(Equal(ptr.BoolToPtr(false)))
+
+		// Settings that are not in the Installation are left alone.
+		Expect(fc.Spec.WireguardEnabledV6).To(Equal(ptr.BoolToPtr(true)))
+
+		Expect(setWireGuardOnFelixConfiguration(install, fc, logf.Log)).To(BeFalse())
+	})
+
+	It("should not report encryption state unless WireGuard is enabled", func() {
+		state, err := getEncryptionState(context.Background(), build(), install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(BeNil())
+
+		install.Spec.CalicoNetwork.Encryption = &operator.EncryptionSpec{WireGuardIPv4: &disabled}
+		state, err = getEncryptionState(context.Background(), build(), install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(BeNil())
+	})
+
+	It("should report the nodes that are not yet encrypting traffic", func() {
+		install.Spec.CalicoNetwork.Encryption = &operator.EncryptionSpec{WireGuardIPv4: &enabled, WireGuardIPv6: &enabled}
+		cli := build(
+			newNode("node-a", map[string]string{wireguardPublicKeyAnnotation: "a", wireguardPublicKeyV6Annotation: "a6"}),
+			newNode("node-b", nil),
+			newNode("node-c", map[string]string{wireguardPublicKeyAnnotation: "c"}),
+		)
+		state, err := getEncryptionState(context.Background(), cli, install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.Ready).To(BeFalse())
+		Expect(state.Message).To(Equal("WireGuard IPv4 is active on 2/3 nodes, waiting for node-b; WireGuard IPv6 is active on 1/3 nodes, waiting for node-b, node-c"))
+
+		cli = build(newNode("node-a", map[string]string{wireguardPublicKeyAnnotation: "a", wireguardPublicKeyV6Annotation: "a6"}))
+		state, err = getEncryptionState(context.Background(), cli, install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.Ready).To(BeTrue())
+		Expect(state.Message).To(Equal("WireGuard IPv4 is active on 1/1 nodes; WireGuard IPv6 is active on 1/1 nodes"))
+	})
+
+	It("should report host encryption as part of IPv4", func() {
+		install.Spec.CalicoNetwork.Encryption = &operator.EncryptionSpec{WireGuardIPv4: &enabled, HostEncryption: &enabled}
+		cli := build(newNode("node-a", map[string]string{wireguardPublicKeyAnnotation: "a"}), newNode("node-b", nil))
+		state, err := getEncryptionState(context.Background(), cli, install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.Ready).To(BeFalse())
+		Expect(state.Message).To(Equal("WireGuard IPv4 with host encryption is active on 1/2 nodes, waiting for node-b"))
+	})
+
+	It("should pass node updates that change a WireGuard public key", func() {
+		oldNode := newNode("node-a", map[string]string{"other": "a"})
+		newNodeWithKey := newNode("node-a", map[string]string{"other": "b", wireguardPublicKeyAnnotation: "a"})
+		Expect(wireguardPublicKeyChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNodeWithKey})).To(BeTrue())
+		Expect(wireguardPublicKeyChangedPredicate.Update(event.UpdateEvent{ObjectOld: newNodeWithKey, ObjectNew: newNodeWithKey})).To(BeFalse())
+	})
+})
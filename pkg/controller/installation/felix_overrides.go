@@ -0,0 +1,422 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	// felixOverridesAnnotation records the settings from spec.felixOverrides that the operator last wrote to a
+	// FelixConfiguration, as a JSON object. It allows the operator to detect when someone else has since modified
+	// one of those fields.
+	felixOverridesAnnotation = "operator.tigera.io/felix-overrides"
+
+	// felixOverridesLabel marks the per-node FelixConfigurations that the operator created for spec.felixOverrides,
+	// so that they can be deleted once they are no longer needed.
+	felixOverridesLabel = "operator.tigera.io/felix-overrides"
+
+	// nodeFelixConfigurationPrefix is the prefix of the name of the FelixConfiguration that Felix reads for a node.
+	nodeFelixConfigurationPrefix = "node."
+)
+
+// felixEnvVarFields maps the FelixConfiguration fields that calico-node may set through FELIX_* environment variables
+// to the name of the variable. Felix matches the variables to its settings by name, ignoring case.
+var felixEnvVarFields = func() map[string]string {
+	fields := map[string]string{}
+	spec := reflect.TypeOf(crdv1.FelixConfigurationSpec{})
+	for _, env := range render.CalicoNodeFelixEnvVars {
+		setting := strings.TrimPrefix(env, "FELIX_")
+		for i := 0; i < spec.NumField(); i++ {
+			field := spec.Field(i)
+			name := field.Name
+			if n := field.Tag.Get("confignamev1"); n != "" {
+				name = n
+			}
+			if strings.EqualFold(name, setting) {
+				fields[strings.Split(field.Tag.Get("json"), ",")[0]] = env
+			}
+		}
+	}
+	return fields
+}()
+
+// operatorOwnedFelixFields returns the FelixConfiguration fields that can't be set in spec.felixOverrides, mapped to
+// what manages them. captureStorage is true if the PacketCaptureAPI sets spec.storage. This must be kept in sync with
+// the list in the FelixOverrides API documentation.
+func operatorOwnedFelixFields(install *operator.InstallationSpec, captureStorage bool) map[string]string {
+	owned := map[string]string{
+		"bpfEnabled":              "spec.calicoNetwork.linuxDataplane",
+		"nftablesMode":            "spec.calicoNetwork.linuxDataplane",
+		"ipipEnabled":             "spec.calicoNetwork.ipPools",
+		"vxlanEnabled":            "spec.calicoNetwork.ipPools",
+		"policySyncPathPrefix":    "the ApplicationLayer, EgressGateway and GatewayAPI features",
+		"tproxyMode":              "the ApplicationLayer feature",
+		"wafEventLogsFileEnabled": "the ApplicationLayer feature",
+	}
+	if captureStorage {
+		owned["captureMaxSizeBytes"] = "spec.storage of the PacketCaptureAPI"
+		owned["captureRotationSeconds"] = "spec.storage of the PacketCaptureAPI"
+	}
+	if install.CalicoNetwork != nil && install.CalicoNetwork.Encryption != nil {
+		e := install.CalicoNetwork.Encryption
+		if e.WireGuardIPv4 != nil {
+			owned["wireguardEnabled"] = "spec.calicoNetwork.encryption.wireGuardIPv4"
+		}
+		if e.WireGuardIPv6 != nil {
+			owned["wireguardEnabledV6"] = "spec.calicoNetwork.encryption.wireGuardIPv6"
+		}
+		if e.HostEncryption != nil {
+			owned["wireguardHostEncryptionEnabled"] = "spec.calicoNetwork.encryption.hostEncryption"
+		}
+	}
+	// Felix settings from the environment take precedence over the FelixConfiguration.
+	for field, env := range felixEnvVarFields {
+		if _, ok := owned[field]; !ok {
+			owned[field] = fmt.Sprintf("the %s environment variable of calico-node", env)
+		}
+	}
+	return owned
+}
+
+// validateFelixOverrides checks that every setting in spec.felixOverrides is a user-owned FelixConfiguration field
+// with a valid value.
+func validateFelixOverrides(install *operator.InstallationSpec, captureStorage bool) error {
+	fo := install.FelixOverrides
+	if fo == nil {
+		return nil
+	}
+	owned := operatorOwnedFelixFields(install, captureStorage)
+	validate := func(path string, settings map[string]string) error {
+		for key := range settings {
+			if owner, ok := owned[key]; ok {
+				return fmt.Errorf("%s.%s is managed by the operator through %s and cannot be overridden", path, key, owner)
+			}
+		}
+		if _, err := parseFelixSettings(settings); err != nil {
+			return fmt.Errorf("%s is not valid: %w", path, err)
+		}
+		return nil
+	}
+
+	if err := validate("spec.felixOverrides.settings", fo.Settings); err != nil {
+		return err
+	}
+	for i, o := range fo.NodeOverrides {
+		path := fmt.Sprintf("spec.felixOverrides.nodeOverrides[%d]", i)
+		if _, err := metav1.LabelSelectorAsSelector(&o.NodeSelector); err != nil {
+			return fmt.Errorf("%s.nodeSelector is not valid: %w", path, err)
+		}
+		if err := validate(path+".settings", o.Settings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasFelixOverride returns true if spec.felixOverrides sets the given field on the default FelixConfiguration.
+func hasFelixOverride(install *operator.InstallationSpec, field string) bool {
+	if install.FelixOverrides == nil {
+		return false
+	}
+	_, ok := install.FelixOverrides.Settings[field]
+	return ok
+}
+
+// setFelixOverridesOnFelixConfiguration writes spec.felixOverrides.settings to the default FelixConfiguration.
+func setFelixOverridesOnFelixConfiguration(install *operator.Installation, fc *crdv1.FelixConfiguration) (bool, error) {
+	var settings map[string]string
+	if install.Spec.FelixOverrides != nil {
+		settings = install.Spec.FelixOverrides.Settings
+	}
+	parsed, err := parseFelixSettings(settings)
+	if err != nil {
+		return false, err
+	}
+	return applyFelixSettings(fc, parsed)
+}
+
+// reconcileNodeFelixOverrides writes spec.felixOverrides.nodeOverrides to the per-node FelixConfiguration of each
+// selected node, and removes them from the nodes that are no longer selected.
+func reconcileNodeFelixOverrides(ctx context.Context, cli client.Client, install *operator.Installation) error {
+	desired := map[string]map[string]json.RawMessage{}
+	if fo := install.Spec.FelixOverrides; fo != nil && len(fo.NodeOverrides) > 0 {
+		nodes := corev1.NodeList{}
+		if err := cli.List(ctx, &nodes); err != nil {
+			return err
+		}
+		for _, o := range fo.NodeOverrides {
+			selector, err := metav1.LabelSelectorAsSelector(&o.NodeSelector)
+			if err != nil {
+				return err
+			}
+			settings, err := parseFelixSettings(o.Settings)
+			if err != nil {
+				return err
+			}
+			for _, n := range nodes.Items {
+				if !selector.Matches(labels.Set(n.Labels)) {
+					continue
+				}
+				if desired[n.Name] == nil {
+					desired[n.Name] = map[string]json.RawMessage{}
+				}
+				for key, value := range settings {
+					if existing, ok := desired[n.Name][key]; ok && !bytes.Equal(existing, value) {
+						return fmt.Errorf("spec.felixOverrides.nodeOverrides set %s to both %s and %s for node %s", key, existing, value, n.Name)
+					}
+					desired[n.Name][key] = value
+				}
+			}
+		}
+	}
+
+	// Remove the settings from the per-node FelixConfigurations of nodes that are no longer selected.
+	fcs := crdv1.FelixConfigurationList{}
+	if err := cli.List(ctx, &fcs); err != nil {
+		return err
+	}
+	for i := range fcs.Items {
+		fc := &fcs.Items[i]
+		nodeName, isNode := strings.CutPrefix(fc.Name, nodeFelixConfigurationPrefix)
+		if !isNode || fc.Annotations[felixOverridesAnnotation] == "" {
+			continue
+		}
+		if _, ok := desired[nodeName]; ok {
+			continue
+		}
+		patchFrom := client.MergeFrom(fc.DeepCopy())
+		if _, err := applyFelixSettings(fc, nil); err != nil {
+			return err
+		}
+		if fc.Labels[felixOverridesLabel] == "true" && reflect.DeepEqual(fc.Spec, crdv1.FelixConfigurationSpec{}) {
+			if err := cli.Delete(ctx, fc); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		if err := cli.Patch(ctx, fc, patchFrom); err != nil {
+			return err
+		}
+	}
+
+	nodeNames := []string{}
+	for name := range desired {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+	for _, name := range nodeNames {
+		fc := &crdv1.FelixConfiguration{}
+		err := cli.Get(ctx, types.NamespacedName{Name: nodeFelixConfigurationPrefix + name}, fc)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		fc.Name = nodeFelixConfigurationPrefix + name
+		patchFrom := client.MergeFrom(fc.DeepCopy())
+		updated, err := applyFelixSettings(fc, desired[name])
+		if err != nil {
+			return err
+		}
+		if fc.ResourceVersion == "" {
+			fc.Labels = map[string]string{felixOverridesLabel: "true"}
+			if err := cli.Create(ctx, fc); err != nil {
+				return err
+			}
+		} else if updated {
+			if err := cli.Patch(ctx, fc, patchFrom); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyFelixSettings writes the given settings to the FelixConfiguration, and unsets the fields that were previously
+// written by the operator but are no longer in the settings. It returns an error, and leaves the FelixConfiguration
+// unchanged, if any of the fields has been set by someone else.
+func applyFelixSettings(fc *crdv1.FelixConfiguration, settings map[string]json.RawMessage) (bool, error) {
+	applied := map[string]json.RawMessage{}
+	if a := fc.Annotations[felixOverridesAnnotation]; a != "" {
+		if err := json.Unmarshal([]byte(a), &applied); err != nil {
+			return false, fmt.Errorf("unable to parse the %s annotation of FelixConfiguration %q: %w", felixOverridesAnnotation, fc.Name, err)
+		}
+	}
+	if len(settings) == 0 && len(applied) == 0 {
+		return false, nil
+	}
+	current, err := felixSpecFields(&fc.Spec)
+	if err != nil {
+		return false, err
+	}
+
+	// A field conflicts if it was changed since the operator last wrote it, or if it was already set to a different
+	// value before the setting was added to the Installation.
+	conflicts := []string{}
+	for key, value := range settings {
+		existing, isSet := current[key]
+		if last, ok := applied[key]; ok {
+			if !isSet || !bytes.Equal(existing, last) {
+				conflicts = append(conflicts, key)
+			}
+		} else if isSet && !bytes.Equal(existing, value) {
+			conflicts = append(conflicts, key)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return false, fmt.Errorf("FelixConfiguration %q has been modified by someone else, refusing to override potential user configuration of %s; "+
+			"remove the fields from either the FelixConfiguration or spec.felixOverrides", fc.Name, strings.Join(conflicts, ", "))
+	}
+
+	for key, last := range applied {
+		if _, ok := settings[key]; ok {
+			continue
+		}
+		// Only unset the field if it still has the value that the operator wrote. Otherwise, someone else now owns it.
+		if existing, isSet := current[key]; isSet && bytes.Equal(existing, last) {
+			delete(current, key)
+		}
+	}
+	for key, value := range settings {
+		current[key] = value
+	}
+
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return false, err
+	}
+	spec := crdv1.FelixConfigurationSpec{}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return false, err
+	}
+
+	annotation := ""
+	if len(settings) > 0 {
+		raw, err := json.Marshal(settings)
+		if err != nil {
+			return false, err
+		}
+		annotation = string(raw)
+	}
+
+	updated := !reflect.DeepEqual(fc.Spec, spec) || fc.Annotations[felixOverridesAnnotation] != annotation
+	fc.Spec = spec
+	if annotation == "" {
+		delete(fc.Annotations, felixOverridesAnnotation)
+	} else {
+		if fc.Annotations == nil {
+			fc.Annotations = map[string]string{}
+		}
+		fc.Annotations[felixOverridesAnnotation] = annotation
+	}
+	return updated, nil
+}
+
+// parseFelixSettings converts each setting to the JSON value of its FelixConfiguration field, as the field is
+// serialized in the FelixConfiguration.
+func parseFelixSettings(settings map[string]string) (map[string]json.RawMessage, error) {
+	keys := []string{}
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parsed := map[string]json.RawMessage{}
+	for _, key := range keys {
+		value, err := parseFelixSetting(key, settings[key])
+		if err != nil {
+			return nil, err
+		}
+		parsed[key] = value
+	}
+	return parsed, nil
+}
+
+// parseFelixSetting converts a setting to the JSON value of its FelixConfiguration field. The value is used as is if
+// it is valid JSON for the field, such as a number, boolean, list or object, and as a string otherwise.
+func parseFelixSetting(key, value string) (json.RawMessage, error) {
+	candidates := []json.RawMessage{}
+	if json.Valid([]byte(value)) {
+		candidates = append(candidates, json.RawMessage(value))
+	}
+	quoted, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, quoted)
+
+	var parseErr error
+	for _, c := range candidates {
+		raw, err := json.Marshal(map[string]json.RawMessage{key: c})
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		spec := crdv1.FelixConfigurationSpec{}
+		if err := decoder.Decode(&spec); err != nil {
+			parseErr = err
+			continue
+		}
+
+		// Serialize the field again so that values can be compared with the FelixConfiguration.
+		fields, err := felixSpecFields(&spec)
+		if err != nil {
+			return nil, err
+		}
+		parsed, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("%s=%q leaves the field unset, remove the setting instead", key, value)
+		}
+		return parsed, nil
+	}
+	return nil, fmt.Errorf("%s=%q is not a valid FelixConfiguration setting: %w", key, value, parseErr)
+}
+
+// felixSpecFields returns the JSON value of each field that is set in the FelixConfiguration spec. Some string fields
+// are serialized even when empty, so empty strings are treated as unset.
+func felixSpecFields(spec *crdv1.FelixConfigurationSpec) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		if string(value) == `""` || string(value) == "null" {
+			delete(fields, key)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+var _ = Describe("Felix overrides", func() {
+	var install *operator.Installation
+
+	BeforeEach(func() {
+		install = &operator.Installation{Spec: operator.InstallationSpec{FelixOverrides: &operator.FelixOverrides{}}}
+	})
+
+	Context("validation", func() {
+		It("should accept settings of any type", func() {
+			install.Spec.FelixOverrides.Settings = map[string]string{
+				"logSeverityScreen":          "Debug",
+				"prometheusGoMetricsEnabled": "true",
+				"deviceRouteProtocol":        "80",
+				"iptablesRefreshInterval":    "30s",
+				"failsafeInboundHostPorts":   `[{"protocol": "TCP", "port": 22}]`,
+			}
+			Expect(validateFelixOverrides(&install.Spec, false)).To(Succeed())
+		})
+
+		It("should reject unknown fields and invalid values", func() {
+			install.Spec.FelixOverrides.Settings = map[string]string{"notAFelixField": "true"}
+			Expect(validateFelixOverrides(&install.Spec, false)).To(MatchError(ContainSubstring("notAFelixField")))
+
+			install.Spec.FelixOverrides.Settings = map[string]string{"deviceRouteProtocol": "large"}
+			Expect(validateFelixOverrides(&install.Spec, false)).To(MatchError(ContainSubstring(`deviceRouteProtocol="large" is not a valid FelixConfiguration setting`)))
+
+			install.Spec.FelixOverrides.Settings = map[string]string{"logSeverityScreen": ""}
+			Expect(validateFelixOverrides(&install.Spec, false)).To(MatchError(ContainSubstring("leaves the field unset")))
+		})
+
+		It("should reject fields owned by the operator", func() {
+			install.Spec.FelixOverrides.NodeOverrides = []operator.FelixNodeOverride{{Settings: map[string]string{"bpfEnabled": "true"}}}
+			Expect(validateFelixOverrides(&install.Spec, false)).To(MatchError(ContainSubstring("nodeOverrides[0].settings.bpfEnabled is managed by the operator")))
+
+			// WireGuard is only owned by the operator when it is configured in the Installation.
+			install.Spec.FelixOverrides.NodeOverrides = nil
+			install.Spec.FelixOverrides.Settings = map[string]string{"wireguardEnabled": "true"}
+			Expect(validateFelixOverrides(&install.Spec, false)).To(Succeed())
+			enabled := operator.WireGuardEnabled
+			install.Spec.CalicoNetwork = &operator.CalicoNetworkSpec{Encryption: &operator.EncryptionSpec{WireGuardIPv4: &enabled}}
+			Expect(validateFelixOverrides(&install.Spec, false)).To(MatchError(ContainSubstring("spec.calicoNetwork.encryption.wireGuardIPv4")))
+		})
+
+		It("should reject fields that calico-node sets through environment variables", func() {
+			for _, field := range []string{"healthPort", "routeSource", "ipv6Support", "interfacePrefix", "vxlanMTU", "ipipMTU"} {
+				install.Spec.FelixOverrides.Settings = map[string]string{field: "1"}
+				Expect(validateFelixOverrides(&install.Spec, false)).To(MatchError(ContainSubstring("settings.%s is managed by the operator", field)))
+			}
+
+			// Fields that calico-node does not set remain owned by the user.
+			install.Spec.FelixOverrides.Settings = map[string]string{"vxlanVNI": "4097", "vxlanPort": "8472"}
+			Expect(validateFelixOverrides(&install.Spec, false)).To(Succeed())
+		})
+
+		It("should reject the capture settings only when the PacketCaptureAPI sets storage", func() {
+			for _, field := range []string{"captureMaxSizeBytes", "captureRotationSeconds"} {
+				install.Spec.FelixOverrides.Settings = map[string]string{field: "3600"}
+				Expect(validateFelixOverrides(&install.Spec, false)).To(Succeed())
+				Expect(validateFelixOverrides(&install.Spec, true)).To(MatchError(ContainSubstring("settings.%s is managed by the operator through spec.storage of the PacketCaptureAPI", field)))
+			}
+		})
+	})
+
+	Context("default FelixConfiguration", func() {
+		It("should write, track and remove settings", func() {
+			fc := &crdv1.FelixConfiguration{}
+			fc.Spec.HealthPort = ptr.ToPtr(9099)
+			install.Spec.FelixOverrides.Settings = map[string]string{"logSeverityScreen": "Debug", "iptablesRefreshInterval": "30s"}
+
+			updated, err := setFelixOverridesOnFelixConfiguration(install, fc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeTrue())
+			Expect(fc.Spec.LogSeverityScreen).To(Equal("Debug"))
+			Expect(fc.Spec.IptablesRefreshInterval).To(Equal(&metav1.Duration{Duration: 30 * time.Second}))
+			Expect(fc.Spec.HealthPort).To(Equal(ptr.ToPtr(9099)))
+			Expect(fc.Annotations).To(HaveKeyWithValue(felixOverridesAnnotation, `{"iptablesRefreshInterval":"30s","logSeverityScreen":"Debug"}`))
+
+			updated, err = setFelixOverridesOnFelixConfiguration(install, fc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeFalse())
+
+			delete(install.Spec.FelixOverrides.Settings, "iptablesRefreshInterval")
+			updated, err = setFelixOverridesOnFelixConfiguration(install, fc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeTrue())
+			Expect(fc.Spec.IptablesRefreshInterval).To(BeNil())
+			Expect(fc.Spec.LogSeverityScreen).To(Equal("Debug"))
+
+			install.Spec.FelixOverrides = nil
+			_, err = setFelixOverridesOnFelixConfiguration(install, fc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fc.Spec.LogSeverityScreen).To(BeEmpty())
+			Expect(fc.Annotations).NotTo(HaveKey(felixOverridesAnnotation))
+		})
+
+		It("should refuse to override fields changed by someone else", func() {
+			fc := &crdv1.FelixConfiguration{}
+			fc.Spec.LogSeverityScreen = "Warning"
+			install.Spec.FelixOverrides.Settings = map[string]string{"logSeverityScreen": "Debug"}
+
+			// Set directly on the FelixConfiguration before it was added to the Installation.
+			_, err := setFelixOverridesOnFelixConfiguration(install, fc)
+			Expect(err).To(MatchError(ContainSubstring("refusing to override potential user configuration of logSeverityScreen")))
+			Expect(fc.Spec.LogSeverityScreen).To(Equal("Warning"))
+
+			// Changed on the FelixConfiguration after the operator wrote it.
+			fc.Spec.LogSeverityScreen = "Debug"
+			_, err = setFelixOverridesOnFelixConfiguration(install, fc)
+			Expect(err).NotTo(HaveOccurred())
+			fc.Spec.LogSeverityScreen = "Info"
+			_, err = setFelixOverridesOnFelixConfiguration(install, fc)
+			Expect(err).To(HaveOccurred())
+
+			// Removing the setting from the Installation hands the field over.
+			install.Spec.FelixOverrides = nil
+			_, err = setFelixOverridesOnFelixConfiguration(install, fc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fc.Spec.LogSeverityScreen).To(Equal("Info"))
+		})
+	})
+
+	Context("per-node FelixConfigurations", func() {
+		var ctx context.Context
+		var cli client.Client
+
+		node := func(name string, labels map[string]string) *corev1.Node {
+			return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		}
+		get := func(name string) (*crdv1.FelixConfiguration, error) {
+			fc := &crdv1.FelixConfiguration{}
+			return fc, cli.Get(ctx, types.NamespacedName{Name: name}, fc)
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			scheme := runtime.NewScheme()
+			Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+			Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+			// node-b already has a per-node FelixConfiguration that the operator did not create.
+			existing := &crdv1.FelixConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "node.node-b"}}
+			existing.Spec.LogSeverityScreen = "Info"
+			cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				node("node-a", map[string]string{"pool": "gpu"}),
+				node("node-b", map[string]string{"pool": "gpu", "zone": "a"}),
+				node("node-c", map[string]string{"pool": "cpu"}),
+				existing,
+			).Build()
+
+			install.Spec.FelixOverrides.NodeOverrides = []operator.FelixNodeOverride{
+				{NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}}, Settings: map[string]string{"bpfLogLevel": "Debug"}},
+				{NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}, Settings: map[string]string{"deviceRouteProtocol": "80"}},
+			}
+		})
+
+		It("should write the settings to each selected node", func() {
+			Expect(reconcileNodeFelixOverrides(ctx, cli, install)).To(Succeed())
+
+			fc, err := get("node.node-a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fc.Labels).To(HaveKeyWithValue(felixOverridesLabel, "true"))
+			Expect(fc.Spec.BPFLogLevel).To(Equal("Debug"))
+			Expect(fc.Spec.DeviceRouteProtocol).To(BeNil())
+
+			fc, err = get("node.node-b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fc.Spec.BPFLogLevel).To(Equal("Debug"))
+			Expect(fc.Spec.DeviceRouteProtocol).To(Equal(ptr.ToPtr(80)))
+			Expect(fc.Spec.LogSeverityScreen).To(Equal("Info"))
+
+			_, err = get("node.node-c")
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should remove the settings from nodes that are no longer selected", func() {
+			Expect(reconcileNodeFelixOverrides(ctx, cli, install)).To(Succeed())
+			install.Spec.FelixOverrides = nil
+			Expect(reconcileNodeFelixOverrides(ctx, cli, install)).To(Succeed())
+
+			// The operator's FelixConfiguration is deleted, while the existing one only loses the settings.
+			_, err := get("node.node-a")
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			fc, err := get("node.node-b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fc.Spec.BPFLogLevel).To(BeEmpty())
+			Expect(fc.Spec.DeviceRouteProtocol).To(BeNil())
+			Expect(fc.Spec.LogSeverityScreen).To(Equal("Info"))
+			Expect(fc.Annotations).NotTo(HaveKey(felixOverridesAnnotation))
+		})
+
+		It("should report conflicting settings for a node", func() {
+			install.Spec.FelixOverrides.NodeOverrides[1].Settings["bpfLogLevel"] = "Info"
+			err := reconcileNodeFelixOverrides(ctx, cli, install)
+			Expect(err).To(MatchError(ContainSubstring(`set bpfLogLevel to both "Debug" and "Info" for node node-b`)))
+		})
+	})
+})
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/active"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/certificatemanager"
+	"github.com/tigera/operator/pkg/controller/k8sapi"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/controller/utils/imageset"
+	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/kubecontrollers"
+)
+
+// candidateFelixHealthPort is used to render calico-node and Typha for validation. The port only
+// affects the rendered probes, so the FelixConfiguration does not need to be read.
+const candidateFelixHealthPort = 9099
+
+// CandidateValidator returns the validation that this operator runs before it takes over from the
+// active operator in a handoff. The Installation is computed the way the core controller computes
+// it, and must be valid for this version of the operator. The ImageSet for this version must exist
+// and be valid if ImageSets are in use, and the core components must render with its images.
+func CandidateValidator(c client.Client, clientset kubernetes.Interface, clusterDomain string) active.Validator {
+	return func(ctx context.Context) error {
+		provider, err := utils.AutoDiscoverProvider(ctx, clientset)
+		if err != nil {
+			return fmt.Errorf("failed to discover the provider: %w", err)
+		}
+
+		_, spec, err := utils.GetInstallation(ctx, c)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// Nothing is installed yet, so there is nothing to take over.
+				return nil
+			}
+			return fmt.Errorf("failed to read Installation: %w", err)
+		}
+		instance := &operator.Installation{Spec: *spec}
+		if err := updateInstallationWithDefaults(ctx, c, instance, provider); err != nil {
+			return err
+		}
+		if err := validateCustomResource(instance); err != nil {
+			return fmt.Errorf("invalid Installation: %w", err)
+		}
+
+		is, err := imageset.GetImageSet(ctx, c, instance.Spec.Variant)
+		if err != nil {
+			return err
+		}
+		if err := imageset.ValidateImageSet(is); err != nil {
+			return err
+		}
+		return renderCoreComponents(c, &instance.Spec, is, clusterDomain)
+	}
+}
+
+// renderCoreComponents renders calico-node, Typha, calico-kube-controllers and the CSI driver with
+// the images of this operator. Nothing is written to the cluster; certificates that do not exist
+// yet are only created in memory.
+func renderCoreComponents(c client.Client, installation *operator.InstallationSpec, is *operator.ImageSet, clusterDomain string) error {
+	certificateManager, err := certificatemanager.Create(c, installation, clusterDomain, common.OperatorNamespace(), certificatemanager.AllowCACreation())
+	if err != nil {
+		return fmt.Errorf("failed to read the Tigera CA: %w", err)
+	}
+	typhaNodeTLS, err := GetOrCreateTyphaNodeTLSConfig(c, certificateManager)
+	if err != nil {
+		return fmt.Errorf("failed to read the Typha and Felix certificates: %w", err)
+	}
+
+	var pools []operator.IPPool
+	if installation.CalicoNetwork != nil {
+		pools = installation.CalicoNetwork.IPPools
+	}
+	components := []render.Component{
+		render.Node(&render.NodeConfiguration{
+			K8sServiceEp:    k8sapi.Endpoint,
+			Installation:    installation,
+			IPPools:         pools,
+			TLS:             typhaNodeTLS,
+			ClusterDomain:   clusterDomain,
+			FelixHealthPort: candidateFelixHealthPort,
+		}),
+		render.Typha(&render.TyphaConfiguration{
+			K8sServiceEp:    k8sapi.Endpoint,
+			Installation:    installation,
+			TLS:             typhaNodeTLS,
+			ClusterDomain:   clusterDomain,
+			FelixHealthPort: candidateFelixHealthPort,
+		}),
+		kubecontrollers.NewCalicoKubeControllers(&kubecontrollers.KubeControllersConfiguration{
+			K8sServiceEp:      k8sapi.Endpoint,
+			Installation:      installation,
+			ClusterDomain:     clusterDomain,
+			TrustedBundle:     typhaNodeTLS.TrustedBundle,
+			Namespace:         common.CalicoNamespace,
+			BindingNamespaces: []string{common.CalicoNamespace},
+		}),
+		render.CSI(&render.CSIConfiguration{
+			Installation: installation,
+			OpenShift:    installation.KubernetesProvider.IsOpenShift(),
+		}),
+	}
+	for _, component := range components {
+		if err := renderComponent(component, is); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderComponent resolves the images of the component and renders its objects. Render code
+// panics on some invalid configurations, which must not take down the candidate operator.
+func renderComponent(component render.Component, is *operator.ImageSet) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to render %T: %v", component, r)
+		}
+	}()
+	if err := component.ResolveImages(is); err != nil {
+		return fmt.Errorf("failed to resolve the images of %T: %w", component, err)
+	}
+	component.Objects()
+	return nil
+}
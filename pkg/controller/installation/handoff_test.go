@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
+	"github.com/tigera/operator/pkg/dns"
+)
+
+var _ = Describe("Handoff candidate validation", func() {
+	var c client.Client
+	var ctx context.Context
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(appsv1.SchemeBuilder.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		c = ctrlrfake.DefaultFakeClientBuilder(scheme).Build()
+		ctx = context.Background()
+	})
+
+	validate := func() error {
+		return CandidateValidator(c, kfake.NewSimpleClientset(), dns.DefaultClusterDomain)(ctx)
+	}
+
+	It("should pass when there is no Installation", func() {
+		Expect(validate()).To(Succeed())
+	})
+
+	It("should render the core components of a valid Installation", func() {
+		Expect(c.Create(ctx, &operator.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).To(Succeed())
+		Expect(validate()).To(Succeed())
+	})
+
+	It("should validate the Installation with its overlay applied", func() {
+		Expect(c.Create(ctx, &operator.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).To(Succeed())
+		zero := uint32(0)
+		Expect(c.Create(ctx, &operator.Installation{
+			ObjectMeta: metav1.ObjectMeta{Name: "overlay"},
+			Spec: operator.InstallationSpec{
+				Logging: &operator.Logging{CNI: &operator.CNILogging{LogFileMaxCount: &zero}},
+			},
+		})).To(Succeed())
+		Expect(validate()).To(MatchError(ContainSubstring("logFileMaxCount value should be greater than zero")))
+	})
+})
@@ -0,0 +1,366 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	// Overhead of each encapsulation, in bytes, matching the values that Felix uses when it detects the MTU itself.
+	ipipOverhead        = 20
+	vxlanOverhead       = 50
+	vxlanV6Overhead     = 70
+	wireguardOverhead   = 60
+	wireguardV6Overhead = 80
+
+	// maxMTUListedNodes limits the number of nodes included in MTU status messages.
+	maxMTUListedNodes = 5
+
+	// mtuRecheckInterval is how often the host MTUs are checked again while an enforced MTU change is waiting for
+	// calico-node pods to report or for the change to be reported in the status. calico-node pods are not watched.
+	mtuRecheckInterval = 15 * time.Second
+
+	// announcedMTUAnnotation records on the Installation the pod MTU that mtuDetection Enforce has reported it is
+	// changing to, so that the change is applied in a later reconcile even if the operator restarts in between.
+	announcedMTUAnnotation = "operator.tigera.io/announced-mtu"
+)
+
+// hostInterfacePattern matches the interfaces that the mtu-detect init container reports.
+var hostInterfacePattern = regexp.MustCompile(render.HostInterfacePattern)
+
+// mtuDetection is the result of comparing the pod network MTU with the MTU of the host interfaces.
+type mtuDetection struct {
+	// podMTU is the largest pod MTU that works on every node, or 0 if no node has reported its host MTU yet.
+	podMTU int32
+
+	// node is the node with the smallest host MTU, which limits the pod MTU, and limit describes that limit.
+	node  string
+	limit string
+
+	// scheduled is the number of calico-node pods scheduled to nodes, and reported is the number of those that
+	// have reported their host MTU.
+	scheduled int
+	reported  int
+
+	// mismatch describes how the pod network MTU differs from the host interfaces. It is empty if there is no
+	// mismatch.
+	mismatch string
+}
+
+// detectMTU computes the pod MTU from the host interface MTUs reported by the mtu-detect init container of each
+// calico-node pod, and the overhead of the encapsulation and WireGuard settings in use. It reports a mismatch if the
+// configured MTU is larger than the computed MTU, or if no MTU is configured and the nodes have different host MTUs,
+// in which case calico-node would pick a different pod MTU on each node.
+func detectMTU(ctx context.Context, cli client.Client, install *operator.InstallationSpec, pools []crdv1.IPPool, fc *crdv1.FelixConfiguration) (*mtuDetection, error) {
+	pods := corev1.PodList{}
+	if err := cli.List(ctx, &pods, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{"k8s-app": render.CalicoNodeObjectName}); err != nil {
+		return nil, err
+	}
+
+	hostMTUs := map[string]int32{}
+	scheduled, reported := 0, 0
+	for _, p := range pods.Items {
+		if p.Spec.NodeName == "" || p.DeletionTimestamp != nil {
+			continue
+		}
+		scheduled++
+		if mtu, ok := hostMTU(&p); ok {
+			hostMTUs[p.Spec.NodeName] = mtu
+			reported++
+		}
+	}
+
+	// Pods may not have been created yet for every node that the calico-node DaemonSets are scheduled to.
+	daemonSets, err := calicoNodeDaemonSets(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	desired := 0
+	for _, ds := range daemonSets {
+		desired += int(ds.Status.DesiredNumberScheduled)
+	}
+	if desired > scheduled {
+		scheduled = desired
+	}
+
+	if len(hostMTUs) == 0 {
+		return &mtuDetection{scheduled: scheduled}, nil
+	}
+
+	overhead, encap := mtuOverhead(pools, fc)
+	minNode := ""
+	for node, mtu := range hostMTUs {
+		if minNode == "" || mtu < hostMTUs[minNode] || (mtu == hostMTUs[minNode] && node < minNode) {
+			minNode = node
+		}
+	}
+	d := &mtuDetection{
+		podMTU:    hostMTUs[minNode] - overhead,
+		node:      minNode,
+		limit:     fmt.Sprintf("host MTU %d with %s", hostMTUs[minNode], encap),
+		scheduled: scheduled,
+		reported:  reported,
+	}
+
+	if install.CalicoNetwork != nil && install.CalicoNetwork.MTU != nil {
+		if mtu := *install.CalicoNetwork.MTU; mtu > d.podMTU {
+			d.mismatch = fmt.Sprintf("MTU %d is larger than the pod MTU %d supported by node %s (%s)", mtu, d.podMTU, minNode, d.limit)
+		}
+		return d, nil
+	}
+
+	byMTU := map[int32][]string{}
+	for node, mtu := range hostMTUs {
+		byMTU[mtu] = append(byMTU[mtu], node)
+	}
+	if len(byMTU) > 1 {
+		d.mismatch = fmt.Sprintf("Nodes have different host MTUs, so pods on different nodes use different MTUs (%s); "+
+			"set mtu to %d, or set mtuDetection to Enforce", hostMTUSummary(byMTU), d.podMTU)
+	}
+	return d, nil
+}
+
+// mtuEnforcement is the outcome of enforcing the detected pod MTU on calico-node.
+type mtuEnforcement struct {
+	// mtu is the MTU to configure on calico-node, or 0 to leave it to calico-node to detect the MTU on each node.
+	mtu int32
+
+	// pending is the detected pod MTU if it has not been applied yet because the change is only being reported.
+	pending int32
+
+	// msg describes why the detected pod MTU has not been applied yet. It is empty once it has been applied.
+	msg string
+}
+
+// enforceMTU decides which MTU to configure on calico-node when mtuDetection is Enforce and no MTU is specified. The
+// MTU currently applied to calico-node is kept until every scheduled calico-node pod has reported its host MTU, so that
+// the pod MTU is not lowered and raised again as nodes report. A change of the MTU is first reported in the status,
+// and only applied once the same pod MTU has been detected again after that, as given by announced.
+func enforceMTU(d *mtuDetection, applied, announced int32) mtuEnforcement {
+	if d.scheduled == 0 {
+		return mtuEnforcement{mtu: applied}
+	}
+	if d.reported < d.scheduled {
+		return mtuEnforcement{
+			mtu: applied,
+			msg: fmt.Sprintf("Waiting for %d of %d calico-node pods to report their host MTU before enforcing the pod MTU", d.scheduled-d.reported, d.scheduled),
+		}
+	}
+	if d.podMTU == applied || d.podMTU == announced {
+		return mtuEnforcement{mtu: d.podMTU}
+	}
+
+	from := "the MTU detected by calico-node on each node"
+	if applied > 0 {
+		from = strconv.Itoa(int(applied))
+	}
+	return mtuEnforcement{
+		mtu:     applied,
+		pending: d.podMTU,
+		msg:     fmt.Sprintf("Changing the pod MTU from %s to %d, the pod MTU supported by node %s (%s)", from, d.podMTU, d.node, d.limit),
+	}
+}
+
+// announcedMTU returns the pod MTU recorded in the announcedMTUAnnotation of the Installation, or 0 if there is none.
+// An invalid value is ignored, so that the change is reported again.
+func announcedMTU(install *operator.Installation) int32 {
+	mtu, err := strconv.ParseInt(install.Annotations[announcedMTUAnnotation], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(mtu)
+}
+
+// setAnnouncedMTU records the given pod MTU in the announcedMTUAnnotation of the default Installation, or removes the
+// annotation if mtu is 0. The Installation is read again so that the values merged into the instance being reconciled
+// are not written back.
+func setAnnouncedMTU(ctx context.Context, cli client.Client, mtu int32) error {
+	install := &operator.Installation{}
+	if err := cli.Get(ctx, utils.DefaultInstanceKey, install); err != nil {
+		return err
+	}
+	current, ok := install.Annotations[announcedMTUAnnotation]
+	if (mtu == 0 && !ok) || (mtu != 0 && current == strconv.Itoa(int(mtu))) {
+		return nil
+	}
+
+	patchFrom := client.MergeFrom(install.DeepCopy())
+	if mtu == 0 {
+		delete(install.Annotations, announcedMTUAnnotation)
+	} else {
+		if install.Annotations == nil {
+			install.Annotations = map[string]string{}
+		}
+		install.Annotations[announcedMTUAnnotation] = strconv.Itoa(int(mtu))
+	}
+	return cli.Patch(ctx, install, patchFrom)
+}
+
+// appliedMTU returns the MTU that is currently configured on the calico-node DaemonSets of the Installation, or 0 if
+// calico-node detects the MTU itself. The DaemonSets normally have the same MTU. While they don't, for example while a
+// new node group is being rolled out, the smallest one is returned so that the pod MTU is not taken to be larger than
+// it is on some nodes.
+func appliedMTU(ctx context.Context, cli client.Client, install *operator.InstallationSpec) (int32, error) {
+	var applied int32
+	for _, name := range calicoNodeDaemonSetNames(install) {
+		ds := &appsv1.DaemonSet{}
+		if err := cli.Get(ctx, name, ds); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return 0, err
+		}
+		for _, c := range ds.Spec.Template.Spec.Containers {
+			if c.Name != render.CalicoNodeObjectName {
+				continue
+			}
+			for _, env := range c.Env {
+				if env.Name != "FELIX_VXLANMTU" {
+					continue
+				}
+				mtu, err := strconv.ParseInt(env.Value, 10, 32)
+				if err != nil {
+					return 0, fmt.Errorf("unable to parse FELIX_VXLANMTU %q of the %s DaemonSet: %w", env.Value, name.Name, err)
+				}
+				if applied == 0 || int32(mtu) < applied {
+					applied = int32(mtu)
+				}
+			}
+		}
+	}
+	return applied, nil
+}
+
+// hostMTU returns the smallest MTU of the host interfaces reported by the mtu-detect init container of the given
+// calico-node pod.
+func hostMTU(pod *corev1.Pod) (int32, bool) {
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.Name != render.MTUDetectContainerName {
+			continue
+		}
+		terminated := s.State.Terminated
+		if terminated == nil {
+			terminated = s.LastTerminationState.Terminated
+		}
+		if terminated == nil || terminated.ExitCode != 0 {
+			return 0, false
+		}
+		return parseHostMTU(terminated.Message)
+	}
+	return 0, false
+}
+
+// parseHostMTU parses the "<interface> <mtu>" lines written by the mtu-detect init container and returns the smallest
+// MTU of the interfaces that Felix would consider.
+func parseHostMTU(msg string) (int32, bool) {
+	var found bool
+	var smallest int32
+	for _, line := range strings.Split(msg, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !hostInterfacePattern.MatchString(fields[0]) {
+			continue
+		}
+		mtu, err := strconv.ParseInt(fields[1], 10, 32)
+		if err != nil || mtu <= 0 {
+			continue
+		}
+		if !found || int32(mtu) < smallest {
+			smallest = int32(mtu)
+			found = true
+		}
+	}
+	return smallest, found
+}
+
+// mtuOverhead returns the largest overhead of the encapsulation and WireGuard settings in use, along with a description
+// of where it comes from.
+func mtuOverhead(pools []crdv1.IPPool, fc *crdv1.FelixConfiguration) (int32, string) {
+	overhead, encap := int32(0), "no encapsulation"
+	use := func(o int32, name string) {
+		if o > overhead {
+			overhead, encap = o, fmt.Sprintf("%s overhead of %d bytes", name, o)
+		}
+	}
+
+	for _, p := range pools {
+		if p.Spec.Disabled {
+			continue
+		}
+		ipv6 := false
+		if addr, _, err := net.ParseCIDR(p.Spec.CIDR); err == nil {
+			ipv6 = addr.To4() == nil
+		}
+		if p.Spec.IPIPMode != "" && p.Spec.IPIPMode != crdv1.IPIPModeNever {
+			use(ipipOverhead, "IPIP")
+		}
+		if p.Spec.VXLANMode != "" && p.Spec.VXLANMode != crdv1.VXLANModeNever {
+			if ipv6 {
+				use(vxlanV6Overhead, "IPv6 VXLAN")
+			} else {
+				use(vxlanOverhead, "VXLAN")
+			}
+		}
+	}
+
+	if fc != nil {
+		if fc.Spec.WireguardEnabled != nil && *fc.Spec.WireguardEnabled {
+			use(wireguardOverhead, "WireGuard")
+		}
+		if fc.Spec.WireguardEnabledV6 != nil && *fc.Spec.WireguardEnabledV6 {
+			use(wireguardV6Overhead, "IPv6 WireGuard")
+		}
+	}
+	return overhead, encap
+}
+
+// hostMTUSummary formats the nodes with each host MTU for a status message, smallest MTU first.
+func hostMTUSummary(byMTU map[int32][]string) string {
+	mtus := []int32{}
+	for mtu := range byMTU {
+		mtus = append(mtus, mtu)
+	}
+	sort.Slice(mtus, func(i, j int) bool { return mtus[i] < mtus[j] })
+
+	parts := []string{}
+	for _, mtu := range mtus {
+		nodes := byMTU[mtu]
+		sort.Strings(nodes)
+		list := strings.Join(nodes, ", ")
+		if len(nodes) > maxMTUListedNodes {
+			list = fmt.Sprintf("%s and %d more", strings.Join(nodes[:maxMTUListedNodes], ", "), len(nodes)-maxMTUListedNodes)
+		}
+		parts = append(parts, fmt.Sprintf("%d on %s", mtu, list))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/ptr"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("MTU detection", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var install *operator.InstallationSpec
+	var pools []crdv1.IPPool
+
+	nodePod := func(nodeName, interfaces string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "calico-node-" + nodeName,
+				Namespace: common.CalicoNamespace,
+				Labels:    map[string]string{"k8s-app": "calico-node"},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name: render.MTUDetectContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Message: interfaces},
+					},
+				}},
+			},
+		}
+	}
+
+	build := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(appsv1.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		install = &operator.InstallationSpec{CalicoNetwork: &operator.CalicoNetworkSpec{}}
+		pools = []crdv1.IPPool{{Spec: crdv1.IPPoolSpec{CIDR: "192.168.0.0/16", VXLANMode: crdv1.VXLANModeAlways}}}
+	})
+
+	It("should parse the host MTU from the interfaces Felix considers", func() {
+		mtu, ok := parseHostMTU("docker0 1400\neth0 9001\nens5 1500\nvxlan.calico 8951\ntunl0 1480\n")
+		Expect(ok).To(BeTrue())
+		Expect(mtu).To(Equal(int32(1500)))
+
+		_, ok = parseHostMTU("docker0 1400\n")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should compute the overhead of the largest encapsulation in use", func() {
+		overhead, _ := mtuOverhead(pools, nil)
+		Expect(overhead).To(Equal(int32(vxlanOverhead)))
+
+		pools = append(pools, crdv1.IPPool{Spec: crdv1.IPPoolSpec{CIDR: "fd00::/64", VXLANMode: crdv1.VXLANModeCrossSubnet}})
+		overhead, _ = mtuOverhead(pools, nil)
+		Expect(overhead).To(Equal(int32(vxlanV6Overhead)))
+
+		fc := &crdv1.FelixConfiguration{Spec: crdv1.FelixConfigurationSpec{WireguardEnabledV6: ptr.BoolToPtr(true)}}
+		overhead, encap := mtuOverhead(pools, fc)
+		Expect(overhead).To(Equal(int32(wireguardV6Overhead)))
+		Expect(encap).To(Equal("IPv6 WireGuard overhead of 80 bytes"))
+
+		overhead, encap = mtuOverhead([]crdv1.IPPool{{Spec: crdv1.IPPoolSpec{CIDR: "10.0.0.0/16"}}}, nil)
+		Expect(overhead).To(BeZero())
+		Expect(encap).To(Equal("no encapsulation"))
+	})
+
+	It("should not report anything until a node has reported its host MTU", func() {
+		pod := nodePod("node-a", "")
+		pod.Status.InitContainerStatuses = nil
+		d, err := detectMTU(ctx, build(pod), install, pools, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.podMTU).To(BeZero())
+		Expect(d.mismatch).To(BeEmpty())
+	})
+
+	It("should report a configured MTU that is too large for a node", func() {
+		install.CalicoNetwork.MTU = ptr.Int32ToPtr(1500)
+		cli := build(nodePod("node-a", "eth0 9001"), nodePod("node-b", "eth0 1500"))
+		d, err := detectMTU(ctx, cli, install, pools, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.podMTU).To(Equal(int32(1450)))
+		Expect(d.mismatch).To(Equal("MTU 1500 is larger than the pod MTU 1450 supported by node node-b (host MTU 1500 with VXLAN overhead of 50 bytes)"))
+
+		install.CalicoNetwork.MTU = ptr.Int32ToPtr(1400)
+		d, err = detectMTU(ctx, cli, install, pools, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.mismatch).To(BeEmpty())
+	})
+
+	It("should report nodes with different host MTUs when the MTU is auto-detected", func() {
+		cli := build(nodePod("node-a", "eth0 9001"), nodePod("node-b", "eth0 1500"), nodePod("node-c", "eth0 9001"))
+		d, err := detectMTU(ctx, cli, install, pools, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.podMTU).To(Equal(int32(1450)))
+		Expect(d.mismatch).To(ContainSubstring("(1500 on node-b; 9001 on node-a, node-c)"))
+		Expect(d.mismatch).To(ContainSubstring("set mtu to 1450"))
+
+		cli = build(nodePod("node-a", "eth0 9001"), nodePod("node-c", "eth0 9001"))
+		d, err = detectMTU(ctx, cli, install, pools, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.podMTU).To(Equal(int32(8951)))
+		Expect(d.mismatch).To(BeEmpty())
+	})
+
+	It("should count the scheduled calico-node pods that have not reported their host MTU", func() {
+		pending := nodePod("node-b", "")
+		pending.Status.InitContainerStatuses = nil
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: render.CalicoNodeObjectName, Namespace: common.CalicoNamespace},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3},
+		}
+		d, err := detectMTU(ctx, build(nodePod("node-a", "eth0 1500"), pending, ds), install, pools, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.podMTU).To(Equal(int32(1450)))
+		Expect(d.scheduled).To(Equal(3))
+		Expect(d.reported).To(Equal(1))
+	})
+
+	It("should only enforce the pod MTU once every pod has reported and the change has been reported", func() {
+		d := &mtuDetection{podMTU: 1450, node: "node-b", limit: "host MTU 1500 with VXLAN overhead of 50 bytes", scheduled: 3, reported: 2}
+		e := enforceMTU(d, 8951, 0)
+		Expect(e.mtu).To(Equal(int32(8951)))
+		Expect(e.pending).To(BeZero())
+		Expect(e.msg).To(Equal("Waiting for 1 of 3 calico-node pods to report their host MTU before enforcing the pod MTU"))
+
+		d.reported = 3
+		e = enforceMTU(d, 8951, 0)
+		Expect(e.mtu).To(Equal(int32(8951)))
+		Expect(e.pending).To(Equal(int32(1450)))
+		Expect(e.msg).To(Equal("Changing the pod MTU from 8951 to 1450, the pod MTU supported by node node-b (host MTU 1500 with VXLAN overhead of 50 bytes)"))
+
+		e = enforceMTU(d, 8951, 1450)
+		Expect(e.mtu).To(Equal(int32(1450)))
+		Expect(e.msg).To(BeEmpty())
+
+		e = enforceMTU(d, 1450, 0)
+		Expect(e.mtu).To(Equal(int32(1450)))
+		Expect(e.msg).To(BeEmpty())
+
+		e = enforceMTU(d, 0, 0)
+		Expect(e.mtu).To(BeZero())
+		Expect(e.msg).To(HavePrefix("Changing the pod MTU from the MTU detected by calico-node on each node to 1450"))
+	})
+
+	It("should read the MTU applied to the calico-node DaemonSet", func() {
+		mtu, err := appliedMTU(ctx, build(), &operator.InstallationSpec{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mtu).To(BeZero())
+
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: render.CalicoNodeObjectName, Namespace: common.CalicoNamespace},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name: render.CalicoNodeObjectName,
+				Env:  []corev1.EnvVar{{Name: "FELIX_VXLANMTU", Value: "1450"}},
+			}}}}},
+		}
+		mtu, err = appliedMTU(ctx, build(ds), &operator.InstallationSpec{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mtu).To(Equal(int32(1450)))
+
+		// The DaemonSets of node groups are read too, and the smallest MTU is used while they differ.
+		group := ds.DeepCopy()
+		group.Name = render.CalicoNodeGroupDaemonSetName("gpu")
+		group.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "FELIX_VXLANMTU", Value: "1400"}}
+		install := &operator.InstallationSpec{CalicoNodeGroups: []operator.CalicoNodeGroup{{Name: "gpu"}}}
+		mtu, err = appliedMTU(ctx, build(ds, group), install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mtu).To(Equal(int32(1400)))
+
+		// A node group whose DaemonSet does not exist yet is skipped.
+		mtu, err = appliedMTU(ctx, build(ds), install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mtu).To(Equal(int32(1450)))
+	})
+
+	It("should persist the announced pod MTU on the Installation", func() {
+		cli := build(&operator.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+		instance := &operator.Installation{}
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, instance)).NotTo(HaveOccurred())
+		Expect(announcedMTU(instance)).To(BeZero())
+
+		Expect(setAnnouncedMTU(ctx, cli, 1450)).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, instance)).NotTo(HaveOccurred())
+		Expect(instance.Annotations).To(HaveKeyWithValue(announcedMTUAnnotation, "1450"))
+		Expect(announcedMTU(instance)).To(Equal(int32(1450)))
+
+		Expect(setAnnouncedMTU(ctx, cli, 0)).NotTo(HaveOccurred())
+		Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, instance)).NotTo(HaveOccurred())
+		Expect(instance.Annotations).NotTo(HaveKey(announcedMTUAnnotation))
+	})
+})
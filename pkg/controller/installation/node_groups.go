@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// calicoNodeDaemonSets returns the calico-node DaemonSets: the default DaemonSet, if it exists, followed by the
+// DaemonSets of the node groups.
+func calicoNodeDaemonSets(ctx context.Context, cli client.Client) ([]appsv1.DaemonSet, error) {
+	var daemonSets []appsv1.DaemonSet
+	ds := &appsv1.DaemonSet{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace}, ds); err == nil {
+		daemonSets = append(daemonSets, *ds)
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	groups := &appsv1.DaemonSetList{}
+	if err := cli.List(ctx, groups, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.CalicoNodeGroupLabel}); err != nil {
+		return nil, err
+	}
+	return append(daemonSets, groups.Items...), nil
+}
+
+// removedCalicoNodeGroups returns the node groups that still have a calico-node DaemonSet but are no longer in the
+// Installation.
+func removedCalicoNodeGroups(ctx context.Context, cli client.Client, install *operator.InstallationSpec) ([]string, error) {
+	groups := &appsv1.DaemonSetList{}
+	if err := cli.List(ctx, groups, client.InNamespace(common.CalicoNamespace), client.HasLabels{render.CalicoNodeGroupLabel}); err != nil {
+		return nil, err
+	}
+	configured := map[string]bool{}
+	for _, g := range install.CalicoNodeGroups {
+		configured[g.Name] = true
+	}
+	var removed []string
+	for _, ds := range groups.Items {
+		if name := ds.Labels[render.CalicoNodeGroupLabel]; !configured[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// calicoNodeDaemonSetNames returns the calico-node DaemonSets that the Installation runs.
+func calicoNodeDaemonSetNames(install *operator.InstallationSpec) []types.NamespacedName {
+	names := []types.NamespacedName{{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace}}
+	for _, g := range install.CalicoNodeGroups {
+		names = append(names, types.NamespacedName{Name: render.CalicoNodeGroupDaemonSetName(g.Name), Namespace: common.CalicoNamespace})
+	}
+	return names
+}
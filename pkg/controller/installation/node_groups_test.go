@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("calico-node node groups", func() {
+	var cli client.Client
+	install := &operator.InstallationSpec{CalicoNodeGroups: []operator.CalicoNodeGroup{{Name: "gpu"}}}
+
+	daemonSet := func(name string, labels map[string]string) client.Object {
+		return &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace, Labels: labels}}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(appsv1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			daemonSet(common.NodeDaemonSetName, nil),
+			daemonSet("calico-node-gpu", map[string]string{render.CalicoNodeGroupLabel: "gpu"}),
+			daemonSet("calico-node-edge", map[string]string{render.CalicoNodeGroupLabel: "edge"}),
+		).Build()
+	})
+
+	It("should list the default and node group DaemonSets", func() {
+		daemonSets, err := calicoNodeDaemonSets(context.Background(), cli)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(daemonSets).To(HaveLen(3))
+		Expect(daemonSets[0].Name).To(Equal(common.NodeDaemonSetName))
+	})
+
+	It("should find the DaemonSets of removed node groups", func() {
+		removed, err := removedCalicoNodeGroups(context.Background(), cli, install)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(Equal([]string{"edge"}))
+
+		Expect(calicoNodeDaemonSetNames(install)).To(Equal([]types.NamespacedName{
+			{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace},
+			{Name: "calico-node-gpu", Namespace: common.CalicoNamespace},
+		}))
+	})
+})
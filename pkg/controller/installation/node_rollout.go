@@ -0,0 +1,363 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/render"
+)
+
+const (
+	defaultNodeRolloutSoakPeriod   = 5 * time.Minute
+	defaultNodeRolloutBatchTimeout = 10 * time.Minute
+)
+
+// nodeRolloutResult is the outcome of a single step of a calico-node rollout.
+type nodeRolloutResult struct {
+	// State is the progress to report in the TigeraStatus, or nil if all calico-node pods are up to date.
+	State *nodeRolloutState
+
+	// Degraded is set when the rollout is paused because updated calico-node pods have not become ready within the
+	// batch timeout.
+	Degraded error
+
+	// RequeueAfter is when the rollout should be checked again.
+	RequeueAfter time.Duration
+}
+
+// nodeRolloutState is the progress of a calico-node rollout.
+type nodeRolloutState struct {
+	// Paused is true when the rollout is waiting for the pods updated so far to become ready.
+	Paused  bool
+	Message string
+}
+
+// reconcileNodeRollout performs the next step of an operator managed calico-node rollout. Each step looks at the
+// calico-node pods that do not yet run the current pod template and, once the pods updated so far are ready and have
+// soaked, deletes the next batch of outdated pods so that the DaemonSet recreates them. The canary nodes are always
+// updated first. The rollout keeps no state of its own, so it picks up where it left off after an operator restart.
+func reconcileNodeRollout(ctx context.Context, cli client.Client, rollout *operator.CalicoNodeRollout, now time.Time, log logr.Logger) (*nodeRolloutResult, error) {
+	daemonSets, err := calicoNodeDaemonSets(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	// The default DaemonSet and the DaemonSets of the node groups are rolled out together. Their pods are told apart by
+	// the node group label, which is empty for the pods of the default DaemonSet.
+	desiredHashes := map[string]string{}
+	groupDaemonSets := map[string]*appsv1.DaemonSet{}
+	desired := 0
+	for i := range daemonSets {
+		ds := &daemonSets[i]
+		hash := ds.Spec.Template.Annotations[render.CalicoNodeTemplateHashAnnotation]
+		if hash == "" {
+			continue
+		}
+		desiredHashes[ds.Labels[render.CalicoNodeGroupLabel]] = hash
+		groupDaemonSets[ds.Labels[render.CalicoNodeGroupLabel]] = ds
+		desired += int(ds.Status.DesiredNumberScheduled)
+	}
+	if len(desiredHashes) == 0 {
+		return &nodeRolloutResult{}, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{"k8s-app": render.CalicoNodeObjectName}); err != nil {
+		return nil, err
+	}
+
+	var outdated []*corev1.Pod
+	var updated, notReady []string
+	var lastReady, pendingSince time.Time
+	terminating := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		desiredHash, ok := desiredHashes[pod.Labels[render.CalicoNodeGroupLabel]]
+		if !ok {
+			continue
+		}
+		if pod.DeletionTimestamp != nil {
+			terminating++
+			if pendingSince.IsZero() || pod.DeletionTimestamp.Time.Before(pendingSince) {
+				pendingSince = pod.DeletionTimestamp.Time
+			}
+			continue
+		}
+		if pod.Annotations[render.CalicoNodeTemplateHashAnnotation] == "" {
+			// Pods created before the rollout was enabled have no hash. Those that run the current template are
+			// up to date, so record the hash on them instead of replacing them.
+			current, err := runsCurrentTemplate(ctx, cli, groupDaemonSets[pod.Labels[render.CalicoNodeGroupLabel]], pod)
+			if err != nil {
+				return nil, err
+			}
+			if current {
+				if err := seedTemplateHash(ctx, cli, pod, desiredHash); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if pod.Annotations[render.CalicoNodeTemplateHashAnnotation] != desiredHash {
+			outdated = append(outdated, pod)
+			continue
+		}
+		updated = append(updated, pod.Spec.NodeName)
+		ready := podReadyCondition(pod)
+		if ready == nil || ready.Status != corev1.ConditionTrue {
+			notReady = append(notReady, pod.Spec.NodeName)
+			if pendingSince.IsZero() || pod.CreationTimestamp.Time.Before(pendingSince) {
+				pendingSince = pod.CreationTimestamp.Time
+			}
+		} else if ready.LastTransitionTime.Time.After(lastReady) {
+			lastReady = ready.LastTransitionTime.Time
+		}
+	}
+
+	// Pods that have been deleted are missing until the DaemonSet has created their replacements.
+	missing := desired - len(updated) - len(outdated)
+	if len(outdated) == 0 && len(notReady) == 0 && terminating == 0 && missing <= 0 {
+		return &nodeRolloutResult{}, nil
+	}
+	progress := fmt.Sprintf("%d out of %d calico-node pods updated", len(updated), desired)
+
+	// Pause while the pods of the current batch are being replaced or are not ready yet.
+	if len(notReady) > 0 || terminating > 0 || missing > 0 {
+		sort.Strings(notReady)
+		msg := fmt.Sprintf("Waiting for calico-node to become ready on the updated nodes (%s)", progress)
+		if len(notReady) > 0 {
+			msg = fmt.Sprintf("Waiting for calico-node to become ready on nodes %s (%s)", strings.Join(notReady, ", "), progress)
+		}
+		result := &nodeRolloutResult{State: &nodeRolloutState{Paused: true, Message: msg}, RequeueAfter: utils.StandardRetry}
+		timeout := defaultNodeRolloutBatchTimeout
+		if rollout.BatchTimeout != nil {
+			timeout = rollout.BatchTimeout.Duration
+		}
+		if !pendingSince.IsZero() && now.Sub(pendingSince) > timeout {
+			result.Degraded = fmt.Errorf("calico-node rollout is paused: updated calico-node pods have not become ready within %s", timeout)
+		}
+		return result, nil
+	}
+
+	soakPeriod := defaultNodeRolloutSoakPeriod
+	if rollout.SoakPeriod != nil {
+		soakPeriod = rollout.SoakPeriod.Duration
+	}
+	if soakEnd := lastReady.Add(soakPeriod); len(updated) > 0 && now.Before(soakEnd) {
+		return &nodeRolloutResult{
+			State:        &nodeRolloutState{Message: fmt.Sprintf("Soaking the updated calico-node pods until %s (%s)", soakEnd.UTC().Format(time.RFC3339), progress)},
+			RequeueAfter: soakEnd.Sub(now),
+		}, nil
+	}
+
+	if inWindow, next := inMaintenanceWindow(rollout.MaintenanceWindows, now); !inWindow {
+		if next.IsZero() {
+			return nil, fmt.Errorf("none of the calico-node maintenance windows are valid")
+		}
+		return &nodeRolloutResult{
+			State:        &nodeRolloutState{Message: fmt.Sprintf("Waiting for the next maintenance window at %s (%s)", next.UTC().Format(time.RFC3339), progress)},
+			RequeueAfter: next.Sub(now),
+		}, nil
+	}
+
+	batch, canary, err := nextNodeRolloutBatch(ctx, cli, rollout, outdated, desired)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []string
+	for _, pod := range batch {
+		log.Info("Deleting calico-node pod to update it", "pod", pod.Name, "node", pod.Spec.NodeName)
+		if err := cli.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		nodes = append(nodes, pod.Spec.NodeName)
+	}
+
+	group := "nodes"
+	if canary {
+		group = "canary nodes"
+	}
+	return &nodeRolloutResult{
+		State:        &nodeRolloutState{Message: fmt.Sprintf("Updating calico-node on %s %s (%s)", group, strings.Join(nodes, ", "), progress)},
+		RequeueAfter: utils.StandardRetry,
+	}, nil
+}
+
+// runsCurrentTemplate returns whether the given calico-node pod, which has no template hash, was created from the
+// current pod template of its DaemonSet. The template that the pod was created from is read from the DaemonSet's
+// ControllerRevision, which holds the template as stored by the API server, and compared with the stored template of
+// the DaemonSet, ignoring the template hash that the DaemonSet gained when the rollout was enabled.
+func runsCurrentTemplate(ctx context.Context, cli client.Client, ds *appsv1.DaemonSet, pod *corev1.Pod) (bool, error) {
+	revisionHash := pod.Labels[appsv1.DefaultDaemonSetUniqueLabelKey]
+	if ds == nil || revisionHash == "" {
+		return false, nil
+	}
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := cli.List(ctx, revisions, client.InNamespace(ds.Namespace), client.MatchingLabels{appsv1.DefaultDaemonSetUniqueLabelKey: revisionHash}); err != nil {
+		return false, err
+	}
+	for _, revision := range revisions.Items {
+		if !metav1.IsControlledBy(&revision, ds) {
+			continue
+		}
+		// The DaemonSet controller stores the template as a patch of the DaemonSet.
+		patch := struct {
+			Spec struct {
+				Template corev1.PodTemplateSpec `json:"template"`
+			} `json:"spec"`
+		}{}
+		if err := json.Unmarshal(revision.Data.Raw, &patch); err != nil {
+			return false, fmt.Errorf("failed to read ControllerRevision %s: %w", revision.Name, err)
+		}
+		podHash, err := render.CalicoNodeTemplateHash(&patch.Spec.Template)
+		if err != nil {
+			return false, err
+		}
+		currentHash, err := render.CalicoNodeTemplateHash(&ds.Spec.Template)
+		if err != nil {
+			return false, err
+		}
+		return podHash == currentHash, nil
+	}
+	return false, nil
+}
+
+// seedTemplateHash records the given template hash on a calico-node pod without restarting it.
+func seedTemplateHash(ctx context.Context, cli client.Client, pod *corev1.Pod, hash string) error {
+	patchFrom := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[render.CalicoNodeTemplateHashAnnotation] = hash
+	return cli.Patch(ctx, pod, patchFrom)
+}
+
+// nextNodeRolloutBatch returns the outdated calico-node pods to update next, and whether they are on canary nodes.
+// The outdated pods on canary nodes are updated first, after which the remaining pods are updated, ordered by node
+// name. Either way, no more pods than the configured batch size are updated at a time.
+func nextNodeRolloutBatch(ctx context.Context, cli client.Client, rollout *operator.CalicoNodeRollout, outdated []*corev1.Pod, desired int) ([]*corev1.Pod, bool, error) {
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].Spec.NodeName < outdated[j].Spec.NodeName })
+
+	batchSize := intstr.FromInt(1)
+	if rollout.BatchSize != nil {
+		batchSize = *rollout.BatchSize
+	}
+	size, err := intstr.GetScaledValueFromIntOrPercent(&batchSize, desired, true)
+	if err != nil {
+		return nil, false, err
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	if rollout.CanaryNodeSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(rollout.CanaryNodeSelector)
+		if err != nil {
+			return nil, false, err
+		}
+		nodes := &corev1.NodeList{}
+		if err := cli.List(ctx, nodes, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, false, err
+		}
+		canaryNodes := map[string]bool{}
+		for _, n := range nodes.Items {
+			canaryNodes[n.Name] = true
+		}
+		var canary []*corev1.Pod
+		for _, pod := range outdated {
+			if canaryNodes[pod.Spec.NodeName] {
+				canary = append(canary, pod)
+			}
+		}
+		if len(canary) > 0 {
+			if size < len(canary) {
+				canary = canary[:size]
+			}
+			return canary, true, nil
+		}
+	}
+
+	if size > len(outdated) {
+		size = len(outdated)
+	}
+	return outdated[:size], false, nil
+}
+
+// inMaintenanceWindow returns whether now is within one of the given maintenance windows and, if not, when the next
+// window starts. Without any windows, updates may start at any time.
+func inMaintenanceWindow(windows []operator.MaintenanceWindow, now time.Time) (bool, time.Time) {
+	if len(windows) == 0 {
+		return true, now
+	}
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var next time.Time
+	for _, w := range windows {
+		start, err := time.Parse("15:04", w.Start)
+		if err != nil {
+			continue
+		}
+		// Look at the windows starting in the past week, which may still be open, and in the coming week.
+		for day := -7; day <= 7; day++ {
+			windowStart := today.AddDate(0, 0, day).Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+			if !maintenanceWindowDay(w.Days, windowStart.Weekday()) {
+				continue
+			}
+			if !windowStart.After(now) && now.Before(windowStart.Add(w.Duration.Duration)) {
+				return true, now
+			}
+			if windowStart.After(now) && (next.IsZero() || windowStart.Before(next)) {
+				next = windowStart
+			}
+		}
+	}
+	return false, next
+}
+
+func maintenanceWindowDay(days []operator.Weekday, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if string(d) == day.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func podReadyCondition(pod *corev1.Pod) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == corev1.PodReady {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
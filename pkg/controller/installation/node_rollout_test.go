@@ -0,0 +1,314 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	"github.com/tigera/operator/pkg/common"
+	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
+	"github.com/tigera/operator/pkg/render"
+)
+
+var _ = Describe("calico-node rollout tests", func() {
+	var c client.Client
+	var ctx context.Context
+	var rollout *operator.CalicoNodeRollout
+	now := time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC) // A Monday.
+	nodeLabels := map[string]string{"k8s-app": "calico-node"}
+
+	createPodWithLabels := func(node, hash string, readySince time.Time, labels map[string]string) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "calico-node-" + node,
+				Namespace:         common.CalicoNamespace,
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+				Labels:            labels,
+				Annotations:       map[string]string{render.CalicoNodeTemplateHashAnnotation: hash},
+			},
+			Spec: corev1.PodSpec{NodeName: node},
+		}
+		if !readySince.IsZero() {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(readySince)}}
+		}
+		Expect(c.Create(ctx, pod)).NotTo(HaveOccurred())
+	}
+
+	createPod := func(node, hash string, readySince time.Time) {
+		createPodWithLabels(node, hash, readySince, nodeLabels)
+	}
+
+	podExists := func(node string) bool {
+		err := c.Get(ctx, client.ObjectKey{Name: "calico-node-" + node, Namespace: common.CalicoNamespace}, &corev1.Pod{})
+		return err == nil
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(appsv1.AddToScheme(scheme)).NotTo(HaveOccurred())
+		c = ctrlrfake.DefaultFakeClientBuilder(scheme).Build()
+		ctx = context.Background()
+
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace},
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: nodeLabels},
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{render.CalicoNodeTemplateHashAnnotation: "new"},
+				}},
+			},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 4},
+		}
+		Expect(c.Create(ctx, ds)).NotTo(HaveOccurred())
+		for _, node := range []string{"canary", "node-a", "node-b", "node-c"} {
+			labels := map[string]string{}
+			if node == "canary" {
+				labels["canary"] = "true"
+			}
+			Expect(c.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: node, Labels: labels}})).NotTo(HaveOccurred())
+		}
+
+		batchSize := intstr.FromInt(2)
+		rollout = &operator.CalicoNodeRollout{
+			CanaryNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+			BatchSize:          &batchSize,
+		}
+	})
+
+	It("updates the canary nodes first and then proceeds in batches", func() {
+		for _, node := range []string{"canary", "node-a", "node-b", "node-c"} {
+			createPod(node, "old", now.Add(-time.Hour))
+		}
+
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Degraded).NotTo(HaveOccurred())
+		Expect(result.State.Message).To(Equal("Updating calico-node on canary nodes canary (0 out of 4 calico-node pods updated)"))
+		Expect(podExists("canary")).To(BeFalse())
+		Expect(podExists("node-a")).To(BeTrue())
+
+		// The canary has been replaced and has been ready for longer than the soak period.
+		createPod("canary", "new", now.Add(-10*time.Minute))
+		result, err = reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Message).To(Equal("Updating calico-node on nodes node-a, node-b (1 out of 4 calico-node pods updated)"))
+		Expect(podExists("node-a")).To(BeFalse())
+		Expect(podExists("node-b")).To(BeFalse())
+		Expect(podExists("node-c")).To(BeTrue())
+	})
+
+	It("waits for the soak period before starting the next batch", func() {
+		createPod("canary", "new", now.Add(-time.Minute))
+		for _, node := range []string{"node-a", "node-b", "node-c"} {
+			createPod(node, "old", now.Add(-time.Hour))
+		}
+
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Paused).To(BeFalse())
+		Expect(result.State.Message).To(HavePrefix("Soaking the updated calico-node pods until 2025-06-02T12:04:00Z"))
+		Expect(result.RequeueAfter).To(Equal(4 * time.Minute))
+		Expect(podExists("node-a")).To(BeTrue())
+	})
+
+	It("pauses while updated pods are not ready and degrades after the batch timeout", func() {
+		createPod("canary", "new", time.Time{})
+		for _, node := range []string{"node-a", "node-b", "node-c"} {
+			createPod(node, "old", now.Add(-time.Hour))
+		}
+
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Paused).To(BeTrue())
+		Expect(result.State.Message).To(Equal("Waiting for calico-node to become ready on nodes canary (1 out of 4 calico-node pods updated)"))
+		Expect(result.Degraded).NotTo(HaveOccurred())
+
+		result, err = reconcileNodeRollout(ctx, c, rollout, now.Add(time.Hour), logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Paused).To(BeTrue())
+		Expect(result.Degraded).To(HaveOccurred())
+		Expect(podExists("node-a")).To(BeTrue())
+	})
+
+	It("pauses while replaced pods are missing", func() {
+		createPod("canary", "new", now.Add(-time.Hour))
+		createPod("node-a", "old", now.Add(-time.Hour))
+		createPod("node-b", "old", now.Add(-time.Hour))
+
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Paused).To(BeTrue())
+		Expect(podExists("node-a")).To(BeTrue())
+	})
+
+	It("only starts batches within a maintenance window", func() {
+		for _, node := range []string{"canary", "node-a", "node-b", "node-c"} {
+			createPod(node, "old", now.Add(-time.Hour))
+		}
+		rollout.MaintenanceWindows = []operator.MaintenanceWindow{{
+			Days:     []operator.Weekday{"Tuesday"},
+			Start:    "02:00",
+			Duration: metav1.Duration{Duration: 2 * time.Hour},
+		}}
+
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Message).To(HavePrefix("Waiting for the next maintenance window at 2025-06-03T02:00:00Z"))
+		Expect(result.RequeueAfter).To(Equal(14 * time.Hour))
+		Expect(podExists("canary")).To(BeTrue())
+
+		_, err = reconcileNodeRollout(ctx, c, rollout, now.Add(15*time.Hour), logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podExists("canary")).To(BeFalse())
+	})
+
+	It("limits the canary nodes to the batch size", func() {
+		node := &corev1.Node{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "node-a"}, node)).NotTo(HaveOccurred())
+		node.Labels = map[string]string{"canary": "true"}
+		Expect(c.Update(ctx, node)).NotTo(HaveOccurred())
+		batchSize := intstr.FromInt(1)
+		rollout.BatchSize = &batchSize
+		for _, node := range []string{"canary", "node-a", "node-b", "node-c"} {
+			createPod(node, "old", now.Add(-time.Hour))
+		}
+
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Message).To(Equal("Updating calico-node on canary nodes canary (0 out of 4 calico-node pods updated)"))
+		Expect(podExists("canary")).To(BeFalse())
+		Expect(podExists("node-a")).To(BeTrue())
+	})
+
+	It("records the hash on pods that were created from the current template before the rollout was enabled", func() {
+		template := func(image string) corev1.PodTemplateSpec {
+			return corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: nodeLabels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "calico-node", Image: image}}},
+			}
+		}
+		ds := &appsv1.DaemonSet{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: common.NodeDaemonSetName, Namespace: common.CalicoNamespace}, ds)).NotTo(HaveOccurred())
+		ds.Spec.Template = template("calico/node:v2")
+		ds.Spec.Template.Annotations = map[string]string{render.CalicoNodeTemplateHashAnnotation: "new"}
+		Expect(c.Update(ctx, ds)).NotTo(HaveOccurred())
+		for revisionHash, image := range map[string]string{"current": "calico/node:v2", "previous": "calico/node:v1"} {
+			raw, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"template": template(image), "$patch": "replace"}})
+			Expect(err).NotTo(HaveOccurred())
+			revision := &appsv1.ControllerRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "calico-node-" + revisionHash,
+					Namespace: common.CalicoNamespace,
+					Labels:    map[string]string{"k8s-app": "calico-node", appsv1.DefaultDaemonSetUniqueLabelKey: revisionHash},
+				},
+				Data: runtime.RawExtension{Raw: raw},
+			}
+			Expect(controllerutil.SetControllerReference(ds, revision, c.Scheme())).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, revision)).NotTo(HaveOccurred())
+		}
+		revisionLabels := func(revisionHash string) map[string]string {
+			return map[string]string{"k8s-app": "calico-node", appsv1.DefaultDaemonSetUniqueLabelKey: revisionHash}
+		}
+		for _, node := range []string{"canary", "node-a", "node-b"} {
+			createPodWithLabels(node, "", now.Add(-time.Hour), revisionLabels("current"))
+		}
+		createPodWithLabels("node-c", "", now.Add(-time.Hour), revisionLabels("previous"))
+
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Message).To(Equal("Updating calico-node on nodes node-c (3 out of 4 calico-node pods updated)"))
+		for _, node := range []string{"canary", "node-a", "node-b"} {
+			pod := &corev1.Pod{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "calico-node-" + node, Namespace: common.CalicoNamespace}, pod)).NotTo(HaveOccurred())
+			Expect(pod.Annotations).To(HaveKeyWithValue(render.CalicoNodeTemplateHashAnnotation, "new"))
+		}
+		Expect(podExists("node-c")).To(BeFalse())
+	})
+
+	It("reports nothing once all pods are up to date", func() {
+		for _, node := range []string{"canary", "node-a", "node-b", "node-c"} {
+			createPod(node, "new", now.Add(-time.Hour))
+		}
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State).To(BeNil())
+	})
+
+	It("compares the pods of node groups with the pod template of their own DaemonSet", func() {
+		groupLabels := map[string]string{"k8s-app": "calico-node", render.CalicoNodeGroupLabel: "gpu"}
+		Expect(c.Create(ctx, &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      render.CalicoNodeGroupDaemonSetName("gpu"),
+				Namespace: common.CalicoNamespace,
+				Labels:    map[string]string{render.CalicoNodeGroupLabel: "gpu"},
+			},
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: groupLabels},
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{render.CalicoNodeTemplateHashAnnotation: "gpu-new"},
+				}},
+			},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 1},
+		})).NotTo(HaveOccurred())
+		for _, node := range []string{"canary", "node-a", "node-b", "node-c"} {
+			createPod(node, "new", now.Add(-time.Hour))
+		}
+		createPodWithLabels("gpu-a", "gpu-new", now.Add(-time.Hour), groupLabels)
+
+		result, err := reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State).To(BeNil())
+		Expect(podExists("gpu-a")).To(BeTrue())
+
+		Expect(c.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "calico-node-gpu-a", Namespace: common.CalicoNamespace}})).NotTo(HaveOccurred())
+		createPodWithLabels("gpu-a", "gpu-old", now.Add(-time.Hour), groupLabels)
+
+		result, err = reconcileNodeRollout(ctx, c, rollout, now, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.State.Message).To(Equal("Updating calico-node on nodes gpu-a (4 out of 5 calico-node pods updated)"))
+		Expect(podExists("gpu-a")).To(BeFalse())
+	})
+
+	It("handles maintenance windows that span midnight", func() {
+		windows := []operator.MaintenanceWindow{{
+			Days:     []operator.Weekday{"Sunday"},
+			Start:    "22:00",
+			Duration: metav1.Duration{Duration: 4 * time.Hour},
+		}}
+		inWindow, _ := inMaintenanceWindow(windows, time.Date(2025, time.June, 2, 1, 0, 0, 0, time.UTC))
+		Expect(inWindow).To(BeTrue())
+		inWindow, next := inMaintenanceWindow(windows, time.Date(2025, time.June, 2, 3, 0, 0, 0, time.UTC))
+		Expect(inWindow).To(BeFalse())
+		Expect(next).To(Equal(time.Date(2025, time.June, 8, 22, 0, 0, 0, time.UTC)))
+	})
+})
@@ -18,7 +18,10 @@ import (
 	"fmt"
 	"net"
 	"path"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"errors"
 
@@ -36,6 +39,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 // validateCustomResource validates that the given custom resource is correct. This
@@ -180,13 +186,16 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 						}
 					}
 				case operatorv1.IPAMPluginHostLocal:
-					// The host-local IPAM plugin doesn't support VXLAN.
+					// The host-local IPAM plugin only supports VXLAN when BGP is disabled, in which case
+					// calico-node routes between the nodes' pod CIDRs over VXLAN.
 					switch pool.Encapsulation {
 					case operatorv1.EncapsulationVXLAN, operatorv1.EncapsulationVXLANCrossSubnet:
-						return fmt.Errorf("%s is invalid for ipPool.encapsulation with %s CNI and %s IPAM",
-							pool.Encapsulation,
-							instance.Spec.CNI.Type,
-							instance.Spec.CNI.IPAM.Type)
+						if instance.Spec.CalicoNetwork.BGP != nil && *instance.Spec.CalicoNetwork.BGP == operatorv1.BGPEnabled {
+							return fmt.Errorf("%s is invalid for ipPool.encapsulation with %s CNI and %s IPAM when BGP is enabled",
+								pool.Encapsulation,
+								instance.Spec.CNI.Type,
+								instance.Spec.CNI.IPAM.Type)
+						}
 					}
 				}
 			} else {
@@ -223,6 +232,28 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 			}
 		}
 
+		if e := instance.Spec.CalicoNetwork.Encryption; e != nil && e.HostEncryption != nil {
+			if *e.HostEncryption == operatorv1.WireGuardEnabled && (e.WireGuardIPv4 == nil || *e.WireGuardIPv4 != operatorv1.WireGuardEnabled) {
+				return fmt.Errorf("spec.calicoNetwork.encryption.hostEncryption requires spec.calicoNetwork.encryption.wireGuardIPv4 to be Enabled")
+			}
+			if *e.HostEncryption == operatorv1.WireGuardDisabled && render.WireGuardHostEncryptionRequired(&instance.Spec) {
+				return fmt.Errorf("spec.calicoNetwork.encryption.hostEncryption cannot be Disabled with the %s CNI on %s, which requires WireGuard host encryption",
+					instance.Spec.CNI.Type, instance.Spec.KubernetesProvider)
+			}
+		}
+
+		if d := instance.Spec.CalicoNetwork.MTUDetection; d != nil && *d != operatorv1.MTUDetectionDisabled {
+			if instance.Spec.CNI.Type != operatorv1.PluginCalico {
+				return fmt.Errorf("spec.calicoNetwork.mtuDetection is only supported with the Calico CNI (configured: %s)", instance.Spec.CNI.Type)
+			}
+		}
+
+		if instance.Spec.CalicoNetwork.BGPConfiguration != nil {
+			if err := validateBGPConfiguration(instance.Spec.CalicoNetwork); err != nil {
+				return err
+			}
+		}
+
 		if instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 != nil {
 			err := validateNodeAddressDetection(instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4)
 			if err != nil {
@@ -306,6 +337,12 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 			instance.Spec.NodeUpdateStrategy.RollingUpdate)
 	}
 
+	if instance.Spec.NodeRollout != nil {
+		if err := validateNodeRollout(instance.Spec.NodeRollout); err != nil {
+			return err
+		}
+	}
+
 	if instance.Spec.ControlPlaneNodeSelector != nil {
 		if v, ok := instance.Spec.ControlPlaneNodeSelector["beta.kubernetes.io/os"]; ok && v != "linux" {
 			return fmt.Errorf("Installation spec.ControlPlaneNodeSelector 'beta.kubernetes.io/os=%s' is not supported", v)
@@ -355,6 +392,10 @@ func validateCustomResource(instance *operatorv1.Installation) error {
 		}
 	}
 
+	if err := validateCalicoNodeGroups(&instance.Spec); err != nil {
+		return err
+	}
+
 	// Verify the CalicoNodeWindowsDaemonSet overrides, if specified, is valid.
 	if ds := instance.Spec.CalicoNodeWindowsDaemonSet; ds != nil {
 		err := validation.ValidateReplicatedPodResourceOverrides(ds, node.ValidateCalicoNodeWindowsDaemonSetContainer, node.ValidateCalicoNodeWindowsDaemonSetInitContainer)
@@ -479,6 +520,271 @@ func validateNodeAddressDetection(ad *operatorv1.NodeAddressAutodetection) error
 	return nil
 }
 
+// validateBGPConfiguration checks the BGP settings and peerings, including that they are consistent with the IP pools.
+func validateBGPConfiguration(cn *operatorv1.CalicoNetworkSpec) error {
+	bgp := cn.BGPConfiguration
+	if cn.BGP == nil || *cn.BGP != operatorv1.BGPEnabled {
+		return fmt.Errorf("spec.calicoNetwork.bgpConfiguration requires that BGP is enabled")
+	}
+
+	var pools []*net.IPNet
+	for _, pool := range cn.IPPools {
+		if _, cidr, err := net.ParseCIDR(pool.CIDR); err == nil {
+			pools = append(pools, cidr)
+		}
+	}
+
+	for _, svc := range []struct {
+		field string
+		cidrs []string
+	}{
+		{"serviceClusterIPs", bgp.ServiceClusterIPs},
+		{"serviceExternalIPs", bgp.ServiceExternalIPs},
+		{"serviceLoadBalancerIPs", bgp.ServiceLoadBalancerIPs},
+	} {
+		for _, c := range svc.cidrs {
+			_, cidr, err := net.ParseCIDR(c)
+			if err != nil {
+				return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.%s contains invalid CIDR %q", svc.field, c)
+			}
+			for _, pool := range pools {
+				if pool.Contains(cidr.IP) || cidr.Contains(pool.IP) {
+					return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.%s CIDR %s overlaps with IP pool %s", svc.field, c, pool)
+				}
+			}
+		}
+	}
+
+	for i, rr := range bgp.RouteReflectors {
+		if ip := net.ParseIP(rr.ClusterID); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.routeReflectors[%d].clusterID %q is not an IPv4 address", i, rr.ClusterID)
+		}
+		if len(rr.NodeSelector.MatchLabels) == 0 && len(rr.NodeSelector.MatchExpressions) == 0 {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.routeReflectors[%d].nodeSelector must not be empty", i)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(&rr.NodeSelector); err != nil {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.routeReflectors[%d].nodeSelector is invalid: %w", i, err)
+		}
+	}
+
+	names := map[string]bool{}
+	for i, peer := range bgp.Peers {
+		if names[peer.Name] {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers contains more than one peer named %q", peer.Name)
+		}
+		names[peer.Name] = true
+
+		for _, sel := range []*metav1.LabelSelector{peer.NodeSelector, peer.PeerSelector} {
+			if _, err := metav1.LabelSelectorAsSelector(sel); err != nil {
+				return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers[%d] has an invalid selector: %w", i, err)
+			}
+		}
+		if (peer.PeerIP == "") == (peer.PeerSelector == nil) {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers[%d] must set exactly one of peerIP and peerSelector", i)
+		}
+		if peer.Password != nil && (peer.Password.Name == "" || peer.Password.Key == "") {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers[%d].password must set the secret name and key", i)
+		}
+		if peer.PeerIP == "" {
+			if peer.ASNumber != nil {
+				// Peerings with cluster nodes use the AS number of the selected nodes.
+				return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers[%d].asNumber is only valid with peerIP", i)
+			}
+			continue
+		}
+
+		if peer.ASNumber == nil {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers[%d].asNumber is required with peerIP", i)
+		}
+		ip, err := parseBGPPeerIP(peer.PeerIP)
+		if err != nil {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers[%d].peerIP %q is invalid: %w", i, peer.PeerIP, err)
+		}
+		sameFamily := len(pools) == 0
+		for _, pool := range pools {
+			if pool.Contains(ip) {
+				return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers[%d].peerIP %s is within IP pool %s", i, ip, pool)
+			}
+			if (pool.IP.To4() == nil) == (ip.To4() == nil) {
+				sameFamily = true
+			}
+		}
+		if !sameFamily {
+			return fmt.Errorf("spec.calicoNetwork.bgpConfiguration.peers[%d].peerIP %s does not match the address family of any IP pool", i, ip)
+		}
+	}
+
+	if bgp.NodeToNodeMesh != nil && *bgp.NodeToNodeMesh == operatorv1.NodeToNodeMeshDisabled && len(bgp.Peers) == 0 {
+		// Without the mesh or any peers, nothing distributes the routes of pools that rely on BGP.
+		for _, pool := range cn.IPPools {
+			switch pool.Encapsulation {
+			case operatorv1.EncapsulationIPIP, operatorv1.EncapsulationIPIPCrossSubnet, operatorv1.EncapsulationNone:
+				return fmt.Errorf("spec.calicoNetwork.bgpConfiguration disables the node-to-node mesh without any peers, but IP pool %s relies on BGP to distribute routes", pool.CIDR)
+			}
+		}
+	}
+	return nil
+}
+
+// validateNodeRollout checks the settings of the operator managed calico-node rollout.
+func validateNodeRollout(rollout *operatorv1.CalicoNodeRollout) error {
+	if rollout.CanaryNodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(rollout.CanaryNodeSelector); err != nil {
+			return fmt.Errorf("Installation spec.nodeRollout.canaryNodeSelector is invalid: %w", err)
+		}
+	}
+	if rollout.BatchSize != nil {
+		// Scaling against 100 pods gives a positive result for any positive number or percentage.
+		if size, err := intstr.GetScaledValueFromIntOrPercent(rollout.BatchSize, 100, true); err != nil || size <= 0 {
+			return fmt.Errorf("Installation spec.nodeRollout.batchSize %q must be a positive number or percentage", rollout.BatchSize.String())
+		}
+	}
+	if rollout.SoakPeriod != nil && rollout.SoakPeriod.Duration < 0 {
+		return fmt.Errorf("Installation spec.nodeRollout.soakPeriod must not be negative")
+	}
+	if rollout.BatchTimeout != nil && rollout.BatchTimeout.Duration <= 0 {
+		return fmt.Errorf("Installation spec.nodeRollout.batchTimeout must be positive")
+	}
+	for i, w := range rollout.MaintenanceWindows {
+		if _, err := time.Parse("15:04", w.Start); err != nil {
+			return fmt.Errorf("Installation spec.nodeRollout.maintenanceWindows[%d].start %q is not a time of day in the format HH:MM", i, w.Start)
+		}
+		if w.Duration.Duration <= 0 {
+			return fmt.Errorf("Installation spec.nodeRollout.maintenanceWindows[%d].duration must be positive", i)
+		}
+	}
+	return nil
+}
+
+// maxCalicoNodeGroupExclusionTerms limits the number of node affinity terms that the default calico-node DaemonSet
+// needs to exclude the nodes of the node groups.
+const maxCalicoNodeGroupExclusionTerms = 64
+
+// validateCalicoNodeGroups checks the calico-node node groups. Each node may only be selected by one group, since
+// only one calico-node pod can run on a node.
+func validateCalicoNodeGroups(spec *operatorv1.InstallationSpec) error {
+	names := map[string]bool{}
+	var required []v1.NodeSelectorTerm
+	if a := rcc.GetAffinity(spec.CalicoNodeDaemonSet); a != nil && a.NodeAffinity != nil && a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		required = a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	}
+	terms := max(1, len(required))
+	for i, g := range spec.CalicoNodeGroups {
+		if errs := k8svalidation.IsDNS1123Label(g.Name); len(errs) > 0 {
+			return fmt.Errorf("Installation spec.calicoNodeGroups[%d].name %q is invalid: %s", i, g.Name, strings.Join(errs, ", "))
+		}
+		if render.CalicoNodeGroupNameReserved(g.Name) {
+			return fmt.Errorf("Installation spec.calicoNodeGroups[%d].name %q is reserved, %s is the name of another calico-node object", i, g.Name, render.CalicoNodeGroupDaemonSetName(g.Name))
+		}
+		if names[g.Name] {
+			return fmt.Errorf("Installation spec.calicoNodeGroups has more than one group named %s", g.Name)
+		}
+		names[g.Name] = true
+
+		if len(g.NodeSelector) == 0 {
+			return fmt.Errorf("Installation spec.calicoNodeGroups[%d].nodeSelector must not be empty", i)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: g.NodeSelector}); err != nil {
+			return fmt.Errorf("Installation spec.calicoNodeGroups[%d].nodeSelector is invalid: %w", i, err)
+		}
+		// The default DaemonSet excludes the nodes of every group with one term for each combination of labels.
+		if terms *= len(g.NodeSelector); terms > maxCalicoNodeGroupExclusionTerms {
+			return fmt.Errorf("Installation spec.calicoNodeGroups excludes too many label combinations from the default calico-node DaemonSet, the product of the number of nodeSelector labels of the groups and the number of required node affinity terms in spec.calicoNodeDaemonSet must not exceed %d", maxCalicoNodeGroupExclusionTerms)
+		}
+		for _, other := range spec.CalicoNodeGroups[:i] {
+			if !disjointNodeSelectors(g.NodeSelector, other.NodeSelector) {
+				return fmt.Errorf("Installation spec.calicoNodeGroups %s and %s can select the same nodes, their nodeSelectors must require different values for at least one label", other.Name, g.Name)
+			}
+		}
+
+		// The default DaemonSet is kept off the nodes of the group by adding NotIn requirements to its required node
+		// affinity. The DaemonSet of the group shares that affinity, so it must still allow the nodes of the group or
+		// they would not run calico-node at all.
+		if len(required) > 0 && !slices.ContainsFunc(required, func(t v1.NodeSelectorTerm) bool { return nodeSelectorTermAllows(t, g.NodeSelector) }) {
+			return fmt.Errorf("Installation spec.calicoNodeDaemonSet required node affinity does not allow the nodes of spec.calicoNodeGroups[%d] %s, which are excluded from the default calico-node DaemonSet", i, g.Name)
+		}
+
+		if ds := g.CalicoNodeDaemonSet; ds != nil && ds.Resources != nil {
+			if err := node.ValidateCalicoNodeDaemonSetContainer(v1.Container{Resources: *ds.Resources}); err != nil {
+				return fmt.Errorf("Installation spec.calicoNodeGroups[%d].calicoNodeDaemonSet.resources is not valid: %w", i, err)
+			}
+		}
+
+		// A group can change how node addresses are detected, but not which address families are used.
+		if g.NodeAddressAutodetectionV4 != nil {
+			if spec.CalicoNetwork == nil || spec.CalicoNetwork.NodeAddressAutodetectionV4 == nil {
+				return fmt.Errorf("Installation spec.calicoNodeGroups[%d].nodeAddressAutodetectionV4 requires spec.calicoNetwork.nodeAddressAutodetectionV4 to be set", i)
+			}
+			if err := validateNodeAddressDetection(g.NodeAddressAutodetectionV4); err != nil {
+				return fmt.Errorf("Installation spec.calicoNodeGroups[%d].nodeAddressAutodetectionV4 is invalid: %w", i, err)
+			}
+		}
+		if g.NodeAddressAutodetectionV6 != nil {
+			if spec.CalicoNetwork == nil || spec.CalicoNetwork.NodeAddressAutodetectionV6 == nil {
+				return fmt.Errorf("Installation spec.calicoNodeGroups[%d].nodeAddressAutodetectionV6 requires spec.calicoNetwork.nodeAddressAutodetectionV6 to be set", i)
+			}
+			if err := validateNodeAddressDetection(g.NodeAddressAutodetectionV6); err != nil {
+				return fmt.Errorf("Installation spec.calicoNodeGroups[%d].nodeAddressAutodetectionV6 is invalid: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// nodeSelectorTermAllows returns true if the node selector term can match a node with the given labels. Requirements
+// on other labels are assumed to be met.
+func nodeSelectorTermAllows(term v1.NodeSelectorTerm, labels map[string]string) bool {
+	for _, r := range term.MatchExpressions {
+		value, ok := labels[r.Key]
+		if !ok {
+			continue
+		}
+		switch r.Operator {
+		case v1.NodeSelectorOpIn:
+			if !slices.Contains(r.Values, value) {
+				return false
+			}
+		case v1.NodeSelectorOpNotIn:
+			if slices.Contains(r.Values, value) {
+				return false
+			}
+		case v1.NodeSelectorOpDoesNotExist:
+			return false
+		}
+	}
+	return true
+}
+
+// disjointNodeSelectors returns true if no node can match both selectors, i.e. both require a different value for the
+// same label.
+func disjointNodeSelectors(a, b map[string]string) bool {
+	for k, v := range a {
+		if other, ok := b[k]; ok && other != v {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBGPPeerIP parses a BGP peer address of the form <IP>, <IPv4>:<port> or [<IPv6>]:<port>.
+func parseBGPPeerIP(peerIP string) (net.IP, error) {
+	if ip := net.ParseIP(peerIP); ip != nil {
+		return ip, nil
+	}
+	host, port, err := net.SplitHostPort(peerIP)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return nil, fmt.Errorf("invalid port %q", port)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", host)
+	}
+	return ip, nil
+}
+
 func validateHostPorts(hp *operatorv1.HostPortsType) error {
 	if hp == nil {
 		return fmt.Errorf("HostPorts must be set, it should be one of %s",
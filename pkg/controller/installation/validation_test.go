@@ -15,7 +15,9 @@
 package installation
 
 import (
+	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/tigera/operator/pkg/render"
 
@@ -26,6 +28,8 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/controller/k8sapi"
@@ -203,6 +207,43 @@ var _ = Describe("Installation validation tests", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should not allow MTU detection with a CNI other than Calico", func() {
+		enforce := operator.MTUDetectionEnforce
+		instance.Spec.CalicoNetwork.MTUDetection = &enforce
+		instance.Spec.CNI.Type = operator.PluginAmazonVPC
+		err := validateCustomResource(instance)
+		Expect(err).To(HaveOccurred())
+		instance.Spec.CNI.Type = operator.PluginCalico
+		err = validateCustomResource(instance)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not allow WireGuard host encryption without IPv4 WireGuard", func() {
+		enabled := operator.WireGuardEnabled
+		instance.Spec.CalicoNetwork.Encryption = &operator.EncryptionSpec{HostEncryption: &enabled}
+		err := validateCustomResource(instance)
+		Expect(err).To(HaveOccurred())
+		instance.Spec.CalicoNetwork.Encryption.WireGuardIPv4 = &enabled
+		err = validateCustomResource(instance)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not allow WireGuard host encryption to be disabled with the AmazonVPC CNI on EKS", func() {
+		enabled := operator.WireGuardEnabled
+		disabled := operator.WireGuardDisabled
+		bgp := operator.BGPDisabled
+		instance.Spec.KubernetesProvider = operator.ProviderEKS
+		instance.Spec.CalicoNetwork.BGP = &bgp
+		instance.Spec.CNI.Type = operator.PluginAmazonVPC
+		instance.Spec.CNI.IPAM.Type = operator.IPAMPluginAmazonVPC
+		instance.Spec.CalicoNetwork.Encryption = &operator.EncryptionSpec{WireGuardIPv4: &enabled, HostEncryption: &disabled}
+		err := validateCustomResource(instance)
+		Expect(err).To(MatchError(ContainSubstring("cannot be Disabled with the AmazonVPC CNI on EKS")))
+		instance.Spec.CalicoNetwork.Encryption.HostEncryption = &enabled
+		err = validateCustomResource(instance)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	It("should not allow VPP to be used if BGP is not enabled", func() {
 		vpp := operator.LinuxDataplaneVPP
 		en := operator.BGPEnabled
@@ -491,6 +532,36 @@ var _ = Describe("Installation validation tests", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 
+			It("with IPPool with Encapsulation VXLAN validates", func() {
+				instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
+					{
+						CIDR:          "192.168.0.0/24",
+						Encapsulation: operator.EncapsulationVXLAN,
+						NATOutgoing:   operator.NATOutgoingEnabled,
+						NodeSelector:  "all()",
+					},
+				}
+				Expect(fillDefaults(instance, nil)).NotTo(HaveOccurred())
+				err := validateCustomResource(instance)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("with IPPool with Encapsulation VXLAN and BGP enabled fails", func() {
+				enable := operator.BGPEnabled
+				instance.Spec.CalicoNetwork.BGP = &enable
+				instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
+					{
+						CIDR:          "192.168.0.0/24",
+						Encapsulation: operator.EncapsulationVXLAN,
+						NATOutgoing:   operator.NATOutgoingEnabled,
+						NodeSelector:  "all()",
+					},
+				}
+				Expect(fillDefaults(instance, nil)).NotTo(HaveOccurred())
+				err := validateCustomResource(instance)
+				Expect(err).To(HaveOccurred())
+			})
+
 			It("With dual-stack enabled", func() {
 				instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
 					{
@@ -1222,4 +1293,200 @@ var _ = Describe("Installation validation tests", func() {
 			Entry("Product: TigeraSecureEnterprise FipsMode: Enabled", operator.TigeraSecureEnterprise, operator.FIPSModeEnabled, true),
 		)
 	})
+
+	Describe("validate BGP configuration", func() {
+		var bgp *operator.CalicoBGPConfiguration
+		asNumber := uint32(64513)
+
+		BeforeEach(func() {
+			enabled := operator.BGPEnabled
+			bgp = &operator.CalicoBGPConfiguration{}
+			instance.Spec.CalicoNetwork.BGP = &enabled
+			instance.Spec.CalicoNetwork.BGPConfiguration = bgp
+			instance.Spec.CalicoNetwork.IPPools = []operator.IPPool{
+				{CIDR: "192.168.0.0/16", Encapsulation: operator.EncapsulationNone, NodeSelector: "all()"},
+			}
+		})
+
+		It("should allow peers and route reflectors", func() {
+			disabled := operator.NodeToNodeMeshDisabled
+			bgp.ASNumber = &asNumber
+			bgp.NodeToNodeMesh = &disabled
+			bgp.ServiceClusterIPs = []string{"10.96.0.0/12"}
+			bgp.RouteReflectors = []operator.BGPRouteReflector{{
+				NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"route-reflector": "true"}},
+				ClusterID:    "244.0.0.1",
+			}}
+			bgp.Peers = []operator.BGPPeer{
+				{
+					Name:         "rack-1-tor",
+					NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "1"}},
+					PeerIP:       "10.0.1.1:179",
+					ASNumber:     &asNumber,
+					Password:     &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "bgp-secrets"}, Key: "rack-1"},
+				},
+				{
+					Name:         "route-reflectors",
+					PeerSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"route-reflector": "true"}},
+				},
+			}
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		It("should require BGP to be enabled", func() {
+			disabled := operator.BGPDisabled
+			instance.Spec.CalicoNetwork.BGP = &disabled
+			instance.Spec.CalicoNetwork.IPPools[0].Encapsulation = operator.EncapsulationVXLAN
+			Expect(validateCustomResource(instance)).To(MatchError(ContainSubstring("requires that BGP is enabled")))
+		})
+
+		DescribeTable("should reject invalid configuration",
+			func(mutate func(*operator.CalicoBGPConfiguration), expected string) {
+				mutate(bgp)
+				Expect(validateCustomResource(instance)).To(MatchError(ContainSubstring(expected)))
+			},
+			Entry("service CIDR overlapping an IP pool", func(b *operator.CalicoBGPConfiguration) {
+				b.ServiceExternalIPs = []string{"192.168.10.0/24"}
+			}, "serviceExternalIPs CIDR 192.168.10.0/24 overlaps with IP pool 192.168.0.0/16"),
+			Entry("invalid service CIDR", func(b *operator.CalicoBGPConfiguration) {
+				b.ServiceClusterIPs = []string{"10.96.0.0"}
+			}, "invalid CIDR"),
+			Entry("IPv6 route reflector cluster ID", func(b *operator.CalicoBGPConfiguration) {
+				b.RouteReflectors = []operator.BGPRouteReflector{{
+					NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"route-reflector": "true"}},
+					ClusterID:    "fd00::1",
+				}}
+			}, "is not an IPv4 address"),
+			Entry("route reflector selecting all nodes", func(b *operator.CalicoBGPConfiguration) {
+				b.RouteReflectors = []operator.BGPRouteReflector{{ClusterID: "244.0.0.1"}}
+			}, "nodeSelector must not be empty"),
+			Entry("peer with both peerIP and peerSelector", func(b *operator.CalicoBGPConfiguration) {
+				b.Peers = []operator.BGPPeer{{Name: "tor", PeerIP: "10.0.1.1", ASNumber: &asNumber, PeerSelector: &metav1.LabelSelector{}}}
+			}, "exactly one of peerIP and peerSelector"),
+			Entry("peer without AS number", func(b *operator.CalicoBGPConfiguration) {
+				b.Peers = []operator.BGPPeer{{Name: "tor", PeerIP: "10.0.1.1"}}
+			}, "asNumber is required with peerIP"),
+			Entry("peer within an IP pool", func(b *operator.CalicoBGPConfiguration) {
+				b.Peers = []operator.BGPPeer{{Name: "tor", PeerIP: "192.168.1.1", ASNumber: &asNumber}}
+			}, "is within IP pool 192.168.0.0/16"),
+			Entry("peer without an IP pool of its family", func(b *operator.CalicoBGPConfiguration) {
+				b.Peers = []operator.BGPPeer{{Name: "tor", PeerIP: "[fd00::1]:179", ASNumber: &asNumber}}
+			}, "does not match the address family of any IP pool"),
+			Entry("duplicate peer names", func(b *operator.CalicoBGPConfiguration) {
+				b.Peers = []operator.BGPPeer{
+					{Name: "tor", PeerIP: "10.0.1.1", ASNumber: &asNumber},
+					{Name: "tor", PeerIP: "10.0.2.1", ASNumber: &asNumber},
+				}
+			}, "more than one peer named"),
+			Entry("mesh disabled without peers", func(b *operator.CalicoBGPConfiguration) {
+				disabled := operator.NodeToNodeMeshDisabled
+				b.NodeToNodeMesh = &disabled
+			}, "relies on BGP to distribute routes"),
+		)
+	})
+
+	Describe("validate calico-node node groups", func() {
+		It("should allow node groups with non-overlapping selectors", func() {
+			firstFound := true
+			instance.Spec.CalicoNetwork.NodeAddressAutodetectionV4 = &operator.NodeAddressAutodetection{FirstFound: &firstFound}
+			instance.Spec.CalicoNodeGroups = []operator.CalicoNodeGroup{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+				{Name: "edge", NodeSelector: map[string]string{"pool": "edge", "zone": "far"}, NodeAddressAutodetectionV4: &operator.NodeAddressAutodetection{Interface: "wg0"}},
+			}
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("should reject invalid node groups", func(groups []operator.CalicoNodeGroup, msg string) {
+			instance.Spec.CalicoNodeGroups = groups
+			Expect(validateCustomResource(instance)).To(MatchError(ContainSubstring(msg)))
+		},
+			Entry("invalid name", []operator.CalicoNodeGroup{{Name: "GPU", NodeSelector: map[string]string{"pool": "gpu"}}}, `name "GPU" is invalid`),
+			Entry("duplicate name", []operator.CalicoNodeGroup{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "edge"}},
+			}, "more than one group named gpu"),
+			Entry("empty selector", []operator.CalicoNodeGroup{{Name: "gpu"}}, "nodeSelector must not be empty"),
+			Entry("overlapping selectors", []operator.CalicoNodeGroup{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+				{Name: "zone-a", NodeSelector: map[string]string{"zone": "a"}},
+			}, "gpu and zone-a can select the same nodes"),
+			Entry("autodetection for a disabled address family", []operator.CalicoNodeGroup{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}, NodeAddressAutodetectionV6: &operator.NodeAddressAutodetection{Interface: "eth0"}},
+			}, "requires spec.calicoNetwork.nodeAddressAutodetectionV6"),
+			Entry("name of the Windows DaemonSet", []operator.CalicoNodeGroup{
+				{Name: "windows", NodeSelector: map[string]string{"pool": "gpu"}},
+			}, `name "windows" is reserved`),
+			Entry("too many label combinations", func() []operator.CalicoNodeGroup {
+				// Each group has two labels, so the default DaemonSet would need 2^7 terms to exclude them.
+				var groups []operator.CalicoNodeGroup
+				for i := 0; i < 7; i++ {
+					groups = append(groups, operator.CalicoNodeGroup{Name: fmt.Sprintf("pool-%d", i), NodeSelector: map[string]string{"pool": fmt.Sprint(i), "zone": "a"}})
+				}
+				return groups
+			}(), "must not exceed 64"),
+			Entry("invalid resources", []operator.CalicoNodeGroup{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}, CalicoNodeDaemonSet: &operator.CalicoNodeGroupDaemonSet{
+					Resources: &v1.ResourceRequirements{
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+					},
+				}},
+			}, "calicoNodeGroups[0].calicoNodeDaemonSet.resources is not valid"),
+		)
+
+		It("should reject a default DaemonSet affinity that excludes the nodes of a group", func() {
+			instance.Spec.CalicoNodeDaemonSet = &operator.CalicoNodeDaemonSet{
+				Spec: &operator.CalicoNodeDaemonSetSpec{
+					Template: &operator.CalicoNodeDaemonSetPodTemplateSpec{
+						Spec: &operator.CalicoNodeDaemonSetPodSpec{
+							Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+								RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+									NodeSelectorTerms: []v1.NodeSelectorTerm{{
+										MatchExpressions: []v1.NodeSelectorRequirement{{Key: "pool", Operator: v1.NodeSelectorOpNotIn, Values: []string{"gpu"}}},
+									}},
+								},
+							}},
+						},
+					},
+				},
+			}
+			instance.Spec.CalicoNodeGroups = []operator.CalicoNodeGroup{
+				{Name: "edge", NodeSelector: map[string]string{"pool": "edge"}},
+			}
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+
+			instance.Spec.CalicoNodeGroups = append(instance.Spec.CalicoNodeGroups, operator.CalicoNodeGroup{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}})
+			Expect(validateCustomResource(instance)).To(MatchError(ContainSubstring("does not allow the nodes of spec.calicoNodeGroups[1] gpu")))
+		})
+	})
+
+	Describe("validate calico-node rollout", func() {
+		It("should allow a canary rollout within maintenance windows", func() {
+			batchSize := intstr.FromString("25%")
+			instance.Spec.NodeRollout = &operator.CalicoNodeRollout{
+				CanaryNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+				BatchSize:          &batchSize,
+				SoakPeriod:         &metav1.Duration{Duration: 10 * time.Minute},
+				MaintenanceWindows: []operator.MaintenanceWindow{{
+					Days:     []operator.Weekday{"Saturday", "Sunday"},
+					Start:    "22:30",
+					Duration: metav1.Duration{Duration: 4 * time.Hour},
+				}},
+			}
+			Expect(validateCustomResource(instance)).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("should reject invalid settings", func(rollout operator.CalicoNodeRollout, msg string) {
+			instance.Spec.NodeRollout = &rollout
+			Expect(validateCustomResource(instance)).To(MatchError(ContainSubstring(msg)))
+		},
+			Entry("zero batch size", operator.CalicoNodeRollout{BatchSize: &intstr.IntOrString{Type: intstr.Int, IntVal: 0}}, "batchSize \"0\" must be a positive"),
+			Entry("invalid batch size percentage", operator.CalicoNodeRollout{BatchSize: &intstr.IntOrString{Type: intstr.String, StrVal: "half"}}, "must be a positive number or percentage"),
+			Entry("zero batch timeout", operator.CalicoNodeRollout{BatchTimeout: &metav1.Duration{}}, "batchTimeout must be positive"),
+			Entry("invalid window start", operator.CalicoNodeRollout{MaintenanceWindows: []operator.MaintenanceWindow{{
+				Start: "25:00", Duration: metav1.Duration{Duration: time.Hour},
+			}}}, "is not a time of day"),
+			Entry("empty window", operator.CalicoNodeRollout{MaintenanceWindows: []operator.MaintenanceWindow{{Start: "02:00"}}}, "duration must be positive"),
+		)
+	})
 })
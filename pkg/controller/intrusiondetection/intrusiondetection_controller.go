@@ -17,6 +17,8 @@ package intrusiondetection
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"time"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 
@@ -32,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
@@ -156,6 +159,17 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 		return fmt.Errorf("intrusiondetection-controller failed to watch the ConfigMap resource: %v", err)
 	}
 
+	if !opts.MultiTenant {
+		// Watch the labelled secrets that hold the values of threat feed headers, and the copies of them that we own.
+		if err = c.WatchObject(&corev1.Secret{}, &handler.EnqueueRequestForObject{}, predicate.NewPredicateFuncs(isThreatFeedSecret)); err != nil {
+			return fmt.Errorf("intrusiondetection-controller failed to watch the Secret resource: %v", err)
+		}
+		// Watch the ConfigMaps that hold custom alert templates.
+		if err = utils.AddLabeledConfigMapWatch(c, render.CustomAlertTemplateConfigMapLabel, eventHandler); err != nil {
+			return fmt.Errorf("intrusiondetection-controller failed to watch the ConfigMap resource: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -216,6 +230,7 @@ func getIntrusionDetection(ctx context.Context, cli client.Client, mt bool, ns s
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	helper := utils.NewNamespaceHelper(r.multiTenant, render.IntrusionDetectionNamespace, request.Namespace)
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "installNS", helper.InstallNamespace(), "truthNS", helper.TruthNamespace())
 	reqLogger.Info("Reconciling IntrusionDetection")
@@ -250,7 +265,7 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 	r.status.OnCRFound()
 	reqLogger.V(2).Info("Loaded config", "config", instance)
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating IntrusionDetection status conditions
 	if request.Name == tigeraStatusName && request.Namespace == "" {
@@ -290,6 +305,11 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, err
 	}
 
+	if err := render.ValidateThreatFeeds(instance.Spec.ThreatFeeds); err != nil {
+		r.status.SetDegraded(operatorv1.ResourceValidationError, "Invalid threat feed configuration", err, reqLogger)
+		return reconcile.Result{}, nil
+	}
+
 	if !isManagedCluster && !r.elasticExternal {
 		// Check if Elasticsearch is ready.
 		elasticsearch, err := utils.GetElasticsearch(ctx, r.client)
@@ -445,6 +465,79 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, err
 	}
 
+	var threatFeedSecrets []*corev1.Secret
+	var threatFeedsDeployed []string
+	var threatFeedSecretsDeployed []string
+	var threatFeedTierDeployed bool
+	var customAlertTemplates []*v3.GlobalAlertTemplate
+	var customAlertTemplatesDeployed []string
+	if !r.multiTenant {
+		if threatFeedSecrets, err = r.getThreatFeedSecrets(ctx, instance); err != nil {
+			if errors.IsNotFound(err) {
+				r.status.SetDegraded(operatorv1.ResourceNotFound, "Threat feed header secret not found", err, reqLogger)
+				return reconcile.Result{}, nil
+			}
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error reading threat feed header secrets", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		for _, s := range threatFeedSecrets {
+			if !isThreatFeedSecret(s) {
+				r.status.SetDegraded(operatorv1.ResourceValidationError, "Threat feed header secret is missing the "+render.ThreatFeedLabel+" label",
+					fmt.Errorf("secret %s must have the label %s=true", s.Name, render.ThreatFeedLabel), reqLogger)
+				return reconcile.Result{}, nil
+			}
+		}
+		feeds := &v3.GlobalThreatFeedList{}
+		if err = r.client.List(ctx, feeds, client.HasLabels{render.ThreatFeedLabel}); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying GlobalThreatFeeds", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		for _, feed := range feeds.Items {
+			threatFeedsDeployed = append(threatFeedsDeployed, feed.Name)
+		}
+		secrets := &corev1.SecretList{}
+		if err = r.client.List(ctx, secrets, client.InNamespace(helper.InstallNamespace()), client.HasLabels{render.ThreatFeedLabel}); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying threat feed header secrets", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		for _, s := range secrets.Items {
+			threatFeedSecretsDeployed = append(threatFeedSecretsDeployed, s.Name)
+		}
+		tier := &v3.Tier{}
+		if err = r.client.Get(ctx, client.ObjectKey{Name: render.ThreatFeedTierName}, tier); err == nil {
+			_, threatFeedTierDeployed = tier.Labels[render.ThreatFeedLabel]
+		} else if !errors.IsNotFound(err) {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying the threat feed tier", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+
+		var names []string
+		for _, src := range instance.Spec.CustomAlertTemplates {
+			names = append(names, src.ConfigMapName)
+		}
+		configMaps, err := utils.GetLabeledConfigMaps(ctx, r.client, render.CustomAlertTemplateConfigMapLabel, names)
+		if err != nil {
+			return reconcile.Result{}, utils.SetLabeledConfigMapDegraded(r.status, err, "custom alert template ConfigMap", reqLogger)
+		}
+		var invalidAlertTemplates []operatorv1.InvalidAlertTemplate
+		customAlertTemplates, invalidAlertTemplates = render.CustomAlertTemplates(configMaps)
+		if !reflect.DeepEqual(instance.Status.InvalidAlertTemplates, invalidAlertTemplates) {
+			instance.Status.InvalidAlertTemplates = invalidAlertTemplates
+			if err = r.client.Status().Update(ctx, instance); err != nil {
+				r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error reporting invalid alert templates", err, reqLogger)
+				return reconcile.Result{}, err
+			}
+		}
+		templates := &v3.GlobalAlertTemplateList{}
+		if err = r.client.List(ctx, templates, client.HasLabels{render.CustomAlertTemplateLabel}); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying GlobalAlertTemplates", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+		for _, t := range templates.Items {
+			customAlertTemplatesDeployed = append(customAlertTemplatesDeployed, t.Name)
+		}
+	}
+
 	reqLogger.V(3).Info("rendering components")
 	// Render the desired objects from the CRD and create or update them.
 	hasNoLicense := !utils.IsFeatureActive(license, common.ThreatDefenseFeature)
@@ -465,6 +558,12 @@ func (r *ReconcileIntrusionDetection) Reconcile(ctx context.Context, request rec
 		Tenant:                       tenant,
 		ExternalElastic:              r.elasticExternal,
 		SyslogForwardingIsEnabled:    syslogForwardingIsEnabled(lc),
+		ThreatFeedSecrets:            threatFeedSecrets,
+		ThreatFeedsDeployed:          threatFeedsDeployed,
+		ThreatFeedSecretsDeployed:    threatFeedSecretsDeployed,
+		ThreatFeedTierDeployed:       threatFeedTierDeployed,
+		CustomAlertTemplates:         customAlertTemplates,
+		CustomAlertTemplatesDeployed: customAlertTemplatesDeployed,
 	}
 	setUp := render.NewSetup(&render.SetUpConfiguration{
 		OpenShift:       r.provider.IsOpenShift(),
@@ -665,3 +764,44 @@ func (r *ReconcileIntrusionDetection) fillDefaults(ctx context.Context, ids *ope
 
 	return nil
 }
+
+// getThreatFeedSecrets returns the secrets in the operator namespace that hold the values of the threat feed headers
+// listed in the IntrusionDetection CR.
+func (r *ReconcileIntrusionDetection) getThreatFeedSecrets(ctx context.Context, ids *operatorv1.IntrusionDetection) ([]*corev1.Secret, error) {
+	var secrets []*corev1.Secret
+	for _, name := range threatFeedSecretNames(ids) {
+		s := &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: common.OperatorNamespace()}, s); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, s)
+	}
+	return secrets, nil
+}
+
+// threatFeedSecretNames returns the names of the secrets that the threat feed headers in the IntrusionDetection CR
+// reference.
+func threatFeedSecretNames(ids *operatorv1.IntrusionDetection) []string {
+	if ids.Spec.ThreatFeeds == nil {
+		return nil
+	}
+	var names []string
+	seen := map[string]bool{}
+	for _, feed := range ids.Spec.ThreatFeeds.Custom {
+		for _, header := range feed.Headers {
+			if header.SecretKeyRef == nil || seen[header.SecretKeyRef.Name] {
+				continue
+			}
+			seen[header.SecretKeyRef.Name] = true
+			names = append(names, header.SecretKeyRef.Name)
+		}
+	}
+	return names
+}
+
+// isThreatFeedSecret returns whether the object is a threat feed header secret in the tigera-operator namespace, or a
+// copy of one in the intrusion detection namespace.
+func isThreatFeedSecret(obj client.Object) bool {
+	ns := obj.GetNamespace()
+	return (ns == common.OperatorNamespace() || ns == render.IntrusionDetectionNamespace) && obj.GetLabels()[render.ThreatFeedLabel] == "true"
+}
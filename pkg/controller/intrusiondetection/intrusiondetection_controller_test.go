@@ -30,6 +30,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -493,6 +494,117 @@ var _ = Describe("IntrusionDetection controller tests", func() {
 			Expect(*ids.Spec.ComponentResources[0].ResourceRequirements.Requests.Memory()).Should(Equal(resource.MustParse(memoryRequest)))
 			Expect(*ids.Spec.ComponentResources[0].ResourceRequirements.Limits.Memory()).Should(Equal(resource.MustParse(memoryLimit)))
 		})
+
+		It("should own the threat feeds listed in the IntrusionDetection CR", func() {
+			ids := &operatorv1.IntrusionDetection{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ids)).NotTo(HaveOccurred())
+			ids.Spec.ThreatFeeds = &operatorv1.ThreatFeeds{
+				BuiltIn: []operatorv1.BuiltInThreatFeed{{Name: operatorv1.BuiltInThreatFeedAlienVaultIPs, Block: true}},
+				Custom: []operatorv1.CustomThreatFeed{{
+					Name: "partner-ips",
+					URL:  "https://feeds.example.com/ips",
+					Headers: []operatorv1.ThreatFeedHTTPHeader{{Name: "Authorization", SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "partner-feed-token"},
+						Key:                  "token",
+					}}},
+				}},
+			}
+			Expect(c.Update(ctx, ids)).NotTo(HaveOccurred())
+
+			By("waiting for the header secret")
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "partner-ips"}, &v3.GlobalThreatFeed{}))).To(BeTrue())
+
+			By("rejecting a header secret without the threat feed label")
+			headerSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "partner-feed-token", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"token": []byte("Bearer abc")},
+			}
+			Expect(c.Create(ctx, headerSecret)).NotTo(HaveOccurred())
+			mockStatus.On("SetDegraded", operatorv1.ResourceValidationError, "Threat feed header secret is missing the "+render.ThreatFeedLabel+" label", mock.Anything, mock.Anything).Return().Once()
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(isThreatFeedSecret(headerSecret)).To(BeFalse())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "partner-ips"}, &v3.GlobalThreatFeed{}))).To(BeTrue())
+
+			By("creating the threat feeds once the header secret is labelled")
+			headerSecret.Labels = map[string]string{render.ThreatFeedLabel: "true"}
+			Expect(c.Update(ctx, headerSecret)).NotTo(HaveOccurred())
+			Expect(isThreatFeedSecret(headerSecret)).To(BeTrue())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			feed := &v3.GlobalThreatFeed{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera.io.threatfeed.alienvault-ips"}, feed)).NotTo(HaveOccurred())
+			Expect(feed.Spec.GlobalNetworkSet).NotTo(BeNil())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "partner-ips"}, feed)).NotTo(HaveOccurred())
+			Expect(feed.Labels).To(HaveKeyWithValue(render.ThreatFeedLabel, "true"))
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera.io.threatfeed.partner-feed-token", Namespace: render.IntrusionDetectionNamespace}, &corev1.Secret{})).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, client.ObjectKey{Name: render.ThreatFeedBlockPolicyName}, &v3.GlobalNetworkPolicy{})).NotTo(HaveOccurred())
+
+			By("removing the threat feeds that are no longer listed")
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ids)).NotTo(HaveOccurred())
+			ids.Spec.ThreatFeeds.BuiltIn = nil
+			ids.Spec.ThreatFeeds.Custom[0].Headers = nil
+			Expect(c.Update(ctx, ids)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "tigera.io.threatfeed.alienvault-ips"}, &v3.GlobalThreatFeed{}))).To(BeTrue())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "partner-ips"}, &v3.GlobalThreatFeed{})).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: render.ThreatFeedBlockPolicyName}, &v3.GlobalNetworkPolicy{}))).To(BeTrue())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: render.ThreatFeedTierName}, &v3.Tier{}))).To(BeTrue())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "tigera.io.threatfeed.partner-feed-token", Namespace: render.IntrusionDetectionNamespace}, &corev1.Secret{}))).To(BeTrue())
+		})
+
+		It("should apply custom alert templates, report invalid ones and remove those that are no longer provided", func() {
+			template := func(name string) string {
+				return "apiVersion: projectcalico.org/v3\nkind: GlobalAlertTemplate\nmetadata:\n  name: " + name +
+					"\nspec:\n  description: Custom detection\n  severity: 80\n  dataSet: flows\n"
+			}
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "soc-templates", Namespace: common.OperatorNamespace()},
+				Data: map[string]string{
+					"egress.yaml":  template("soc.egress"),
+					"invalid.yaml": template("soc.invalid") + "  unknown: true\n",
+				},
+			}
+			Expect(c.Create(ctx, cm)).NotTo(HaveOccurred())
+			ids := &operatorv1.IntrusionDetection{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ids)).NotTo(HaveOccurred())
+			ids.Spec.CustomAlertTemplates = []operatorv1.AlertTemplateSource{{ConfigMapName: "soc-templates"}}
+			Expect(c.Update(ctx, ids)).NotTo(HaveOccurred())
+
+			By("rejecting a ConfigMap without the alert templates label")
+			mockStatus.On("SetDegraded", operatorv1.ResourceValidationError, "Custom alert template ConfigMap is missing the "+render.CustomAlertTemplateConfigMapLabel+" label", mock.Anything, mock.Anything).Return().Once()
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(utils.IsLabeledConfigMap(cm, render.CustomAlertTemplateConfigMapLabel)).To(BeFalse())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "soc.egress"}, &v3.GlobalAlertTemplate{}))).To(BeTrue())
+
+			By("applying the templates once the ConfigMap is labelled")
+			cm.Labels = map[string]string{render.CustomAlertTemplateConfigMapLabel: "true"}
+			Expect(c.Update(ctx, cm)).NotTo(HaveOccurred())
+			Expect(utils.IsLabeledConfigMap(cm, render.CustomAlertTemplateConfigMapLabel)).To(BeTrue())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			t := &v3.GlobalAlertTemplate{}
+			Expect(c.Get(ctx, client.ObjectKey{Name: "soc.egress"}, t)).NotTo(HaveOccurred())
+			Expect(t.Labels).To(HaveKeyWithValue(render.CustomAlertTemplateLabel, "soc-templates"))
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "soc.invalid"}, &v3.GlobalAlertTemplate{}))).To(BeTrue())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ids)).NotTo(HaveOccurred())
+			Expect(ids.Status.InvalidAlertTemplates).To(HaveLen(1))
+			Expect(ids.Status.InvalidAlertTemplates[0].Key).To(Equal("invalid.yaml"))
+
+			cm.Data = map[string]string{"lateral.yaml": template("soc.lateral")}
+			Expect(c.Update(ctx, cm)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "soc.lateral"}, &v3.GlobalAlertTemplate{})).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "soc.egress"}, &v3.GlobalAlertTemplate{}))).To(BeTrue())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "policy.pod"}, &v3.GlobalAlertTemplate{})).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, ids)).NotTo(HaveOccurred())
+			Expect(ids.Status.InvalidAlertTemplates).To(BeEmpty())
+		})
 	})
 
 	Context("Reconcile for Condition status", func() {
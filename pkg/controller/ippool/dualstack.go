@@ -0,0 +1,260 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ippool
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+)
+
+// Adding an IPv6 pool to a cluster that only has IPv4 pools is done in stages, so that workloads are never given
+// an IPv6 address on a node that can't route it:
+//
+//  1. Every node must have an IPv6 address (and an IPv6 pod CIDR when using host-local IPAM) before the pool is created.
+//  2. The pool is created with new allocations disabled. The Installation controller treats it as pending: it enables
+//     IPv6 address autodetection and Felix IPv6 support on calico-node, but leaves IPv6 out of the CNI IPAM config.
+//  3. Once calico-node has rolled out with IPv6 support and has detected an IPv6 address on every node, the pool is
+//     enabled. The Installation controller then adds IPv6 to the CNI IPAM config, and calico-node rolls once more.
+
+const (
+	// calicoIPv6AddressAnnotation is set on a node by calico-node once it has detected the node's IPv6 address.
+	calicoIPv6AddressAnnotation = "projectcalico.org/IPv6Address"
+
+	// maxListedNodes limits the number of node names included in status messages.
+	maxListedNodes = 10
+)
+
+// dualStackTransition tracks an IPv6 pool being added to an existing IPv4 cluster.
+type dualStackTransition struct {
+	// cidr of the IPv6 pool being added.
+	cidr string
+
+	// created is true once the pool exists in the cluster, with new allocations disabled.
+	created bool
+
+	// ready is true once every node is ready for workloads to get IPv6 addresses.
+	ready bool
+
+	// message describes the progress of the transition.
+	message string
+}
+
+// PendingIPv6Pools returns the pools in the cluster that are part of a transition to dual-stack: IPv6 pools that
+// the Installation wants enabled, but that have been created with new allocations disabled until calico-node is
+// ready for IPv6 on every node.
+func PendingIPv6Pools(installation *operator.Installation, currentPools *crdv1.IPPoolList) []crdv1.IPPool {
+	if currentPools == nil || installation.Spec.CalicoNetwork == nil || hasEnabledIPv6Pool(currentPools) {
+		return nil
+	}
+
+	pending := []crdv1.IPPool{}
+	for _, p := range currentPools.Items {
+		if !p.Spec.Disabled || !hasOwnerLabel(&p) || !isIPv6(p.Spec.CIDR) {
+			continue
+		}
+		for _, desired := range installation.Spec.CalicoNetwork.IPPools {
+			if desired.CIDR == p.Spec.CIDR && (desired.DisableNewAllocations == nil || !*desired.DisableNewAllocations) {
+				pending = append(pending, p)
+			}
+		}
+	}
+	return pending
+}
+
+// getDualStackTransition returns the state of the transition to dual-stack, or nil if the Installation is not adding an
+// IPv6 pool to an existing IPv4 cluster.
+func getDualStackTransition(ctx context.Context, cli client.Client, installation *operator.Installation, currentPools *crdv1.IPPoolList) (*dualStackTransition, error) {
+	if len(currentPools.Items) == 0 || hasEnabledIPv6Pool(currentPools) {
+		// Either this is a new cluster, in which case all pools are created together, or the cluster already
+		// supports IPv6.
+		return nil, nil
+	}
+	v6pool := render.GetIPv6Pool(installation.Spec.CalicoNetwork.IPPools)
+	if v6pool == nil || (v6pool.DisableNewAllocations != nil && *v6pool.DisableNewAllocations) {
+		return nil, nil
+	}
+
+	t := &dualStackTransition{cidr: v6pool.CIDR}
+	for _, p := range currentPools.Items {
+		if p.Spec.CIDR == v6pool.CIDR {
+			t.created = true
+		}
+	}
+
+	nodes := corev1.NodeList{}
+	if err := cli.List(ctx, &nodes); err != nil {
+		return nil, err
+	}
+
+	if !t.created {
+		hostLocal := installation.Spec.CNI.IPAM != nil && installation.Spec.CNI.IPAM.Type == operator.IPAMPluginHostLocal
+		if err := validateNodesForIPv6(nodes.Items, hostLocal); err != nil {
+			return nil, err
+		}
+		t.message = fmt.Sprintf("Creating IPv6 pool %s with new allocations disabled", t.cidr)
+		return t, nil
+	}
+
+	notReady, err := nodesNotReadyForIPv6(ctx, cli, nodes.Items)
+	if err != nil {
+		return nil, err
+	}
+	total := len(linuxNodes(nodes.Items))
+	if len(notReady) == 0 {
+		t.ready = true
+		t.message = fmt.Sprintf("Enabling IPv6 pool %s: %d/%d nodes are ready for IPv6", t.cidr, total, total)
+		return t, nil
+	}
+	t.message = fmt.Sprintf("Waiting to enable IPv6 pool %s: %d/%d nodes are ready for IPv6, waiting for %s",
+		t.cidr, total-len(notReady), total, nodeList(notReady))
+	return t, nil
+}
+
+// validateNodesForIPv6 returns an error listing the nodes that can't support IPv6 workloads.
+func validateNodesForIPv6(nodes []corev1.Node, hostLocal bool) error {
+	var noAddress, noPodCIDR []string
+	for _, n := range linuxNodes(nodes) {
+		hasAddress := false
+		for _, a := range n.Status.Addresses {
+			if (a.Type == corev1.NodeInternalIP || a.Type == corev1.NodeExternalIP) && isIPv6Address(a.Address) {
+				hasAddress = true
+			}
+		}
+		if !hasAddress {
+			noAddress = append(noAddress, n.Name)
+		}
+
+		if hostLocal {
+			hasPodCIDR := false
+			for _, c := range n.Spec.PodCIDRs {
+				if isIPv6(c) {
+					hasPodCIDR = true
+				}
+			}
+			if !hasPodCIDR {
+				noPodCIDR = append(noPodCIDR, n.Name)
+			}
+		}
+	}
+
+	var msgs []string
+	if len(noAddress) > 0 {
+		msgs = append(msgs, fmt.Sprintf("nodes without an IPv6 address: %s", nodeList(noAddress)))
+	}
+	if len(noPodCIDR) > 0 {
+		msgs = append(msgs, fmt.Sprintf("nodes without an IPv6 pod CIDR, which host-local IPAM requires: %s", nodeList(noPodCIDR)))
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("cannot add an IPv6 pool to the cluster; %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// nodesNotReadyForIPv6 returns the nodes where calico-node is not yet running with IPv6 support or has not yet
+// detected an IPv6 address.
+func nodesNotReadyForIPv6(ctx context.Context, cli client.Client, nodes []corev1.Node) ([]string, error) {
+	pods := corev1.PodList{}
+	if err := cli.List(ctx, &pods, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{"k8s-app": "calico-node"}); err != nil {
+		return nil, err
+	}
+	podReadyForIPv6 := map[string]bool{}
+	for _, p := range pods.Items {
+		if p.DeletionTimestamp == nil && podReady(&p) && ipv6SupportEnabled(&p) {
+			podReadyForIPv6[p.Spec.NodeName] = true
+		}
+	}
+
+	var notReady []string
+	for _, n := range linuxNodes(nodes) {
+		if !podReadyForIPv6[n.Name] || n.Annotations[calicoIPv6AddressAnnotation] == "" {
+			notReady = append(notReady, n.Name)
+		}
+	}
+	return notReady, nil
+}
+
+func ipv6SupportEnabled(pod *corev1.Pod) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name != render.CalicoNodeObjectName {
+			continue
+		}
+		for _, e := range c.Env {
+			if e.Name == "FELIX_IPV6SUPPORT" {
+				return e.Value == "true"
+			}
+		}
+	}
+	return false
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// linuxNodes returns the nodes that run calico-node. Windows nodes are excluded.
+func linuxNodes(nodes []corev1.Node) []corev1.Node {
+	linux := []corev1.Node{}
+	for _, n := range nodes {
+		if n.Labels["kubernetes.io/os"] == "windows" {
+			continue
+		}
+		linux = append(linux, n)
+	}
+	return linux
+}
+
+func hasEnabledIPv6Pool(pools *crdv1.IPPoolList) bool {
+	for _, p := range pools.Items {
+		if !p.Spec.Disabled && isIPv6(p.Spec.CIDR) {
+			return true
+		}
+	}
+	return false
+}
+
+func isIPv6(cidr string) bool {
+	addr, _, err := net.ParseCIDR(cidr)
+	return err == nil && addr.To4() == nil
+}
+
+func isIPv6Address(address string) bool {
+	addr := net.ParseIP(address)
+	return addr != nil && addr.To4() == nil
+}
+
+// nodeList formats node names for a status message, truncating long lists.
+func nodeList(names []string) string {
+	sort.Strings(names)
+	if len(names) > maxListedNodes {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:maxListedNodes], ", "), len(names)-maxListedNodes)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ippool
+
+import (
+	"context"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Dual-stack transition", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var installation *operator.Installation
+	var v4pool crdv1.IPPool
+
+	newNode := func(name string, addresses ...string) *corev1.Node {
+		n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}}}
+		for _, a := range addresses {
+			n.Status.Addresses = append(n.Status.Addresses, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: a})
+		}
+		return n
+	}
+
+	newNodePod := func(nodeName string, ipv6 bool) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "calico-node-" + nodeName,
+				Namespace: common.CalicoNamespace,
+				Labels:    map[string]string{"k8s-app": "calico-node"},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{{
+					Name: "calico-node",
+					Env:  []corev1.EnvVar{{Name: "FELIX_IPV6SUPPORT", Value: strconv.FormatBool(ipv6)}},
+				}},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	v6pool := func(disabled bool) crdv1.IPPool {
+		return crdv1.IPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-ipv6-ippool", Labels: map[string]string{managedByLabel: managedByValue}},
+			Spec:       crdv1.IPPoolSpec{CIDR: "fd00::/64", Disabled: disabled},
+		}
+	}
+
+	build := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		installation = &operator.Installation{
+			Spec: operator.InstallationSpec{
+				CNI: &operator.CNISpec{
+					Type: operator.PluginCalico,
+					IPAM: &operator.IPAMSpec{Type: operator.IPAMPluginCalico},
+				},
+				CalicoNetwork: &operator.CalicoNetworkSpec{
+					IPPools: []operator.IPPool{
+						{CIDR: "192.168.0.0/16", DisableNewAllocations: ptr.ToPtr(false)},
+						{CIDR: "fd00::/64", DisableNewAllocations: ptr.ToPtr(false)},
+					},
+				},
+			},
+		}
+		v4pool = crdv1.IPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-ipv4-ippool", Labels: map[string]string{managedByLabel: managedByValue}},
+			Spec:       crdv1.IPPoolSpec{CIDR: "192.168.0.0/16"},
+		}
+	})
+
+	It("should not start a transition for a new cluster", func() {
+		t, err := getDualStackTransition(ctx, build(), installation, &crdv1.IPPoolList{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t).To(BeNil())
+	})
+
+	It("should not start a transition if the cluster already has an IPv6 pool", func() {
+		pools := &crdv1.IPPoolList{Items: []crdv1.IPPool{v4pool, v6pool(false)}}
+		t, err := getDualStackTransition(ctx, build(), installation, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t).To(BeNil())
+	})
+
+	It("should refuse to add an IPv6 pool if a node has no IPv6 address", func() {
+		cli := build(newNode("node-a", "10.0.0.1", "fd00:1::1"), newNode("node-b", "10.0.0.2"))
+		pools := &crdv1.IPPoolList{Items: []crdv1.IPPool{v4pool}}
+		_, err := getDualStackTransition(ctx, cli, installation, pools)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("nodes without an IPv6 address: node-b"))
+	})
+
+	It("should require IPv6 pod CIDRs with host-local IPAM", func() {
+		installation.Spec.CNI.IPAM.Type = operator.IPAMPluginHostLocal
+		node := newNode("node-a", "10.0.0.1", "fd00:1::1")
+		node.Spec.PodCIDRs = []string{"192.168.1.0/24"}
+		pools := &crdv1.IPPoolList{Items: []crdv1.IPPool{v4pool}}
+		_, err := getDualStackTransition(ctx, build(node), installation, pools)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("nodes without an IPv6 pod CIDR"))
+	})
+
+	It("should create the IPv6 pool disabled once nodes have IPv6 addresses", func() {
+		windows := newNode("node-win", "10.0.0.9")
+		windows.Labels = map[string]string{"kubernetes.io/os": "windows"}
+		cli := build(newNode("node-a", "10.0.0.1", "fd00:1::1"), windows)
+		pools := &crdv1.IPPoolList{Items: []crdv1.IPPool{v4pool}}
+		t, err := getDualStackTransition(ctx, cli, installation, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t).NotTo(BeNil())
+		Expect(t.cidr).To(Equal("fd00::/64"))
+		Expect(t.created).To(BeFalse())
+		Expect(t.ready).To(BeFalse())
+	})
+
+	It("should report per-node readiness and enable the pool once every node is ready", func() {
+		nodeA := newNode("node-a", "10.0.0.1", "fd00:1::1")
+		nodeA.Annotations[calicoIPv6AddressAnnotation] = "fd00:1::1/64"
+		nodeB := newNode("node-b", "10.0.0.2", "fd00:1::2")
+		pools := &crdv1.IPPoolList{Items: []crdv1.IPPool{v4pool, v6pool(true)}}
+
+		// node-b's calico-node has not rolled out with IPv6 support yet.
+		cli := build(nodeA, nodeB, newNodePod("node-a", true), newNodePod("node-b", false))
+		t, err := getDualStackTransition(ctx, cli, installation, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t.created).To(BeTrue())
+		Expect(t.ready).To(BeFalse())
+		Expect(t.message).To(ContainSubstring("1/2 nodes are ready for IPv6, waiting for node-b"))
+
+		// node-b has rolled and detected its IPv6 address.
+		nodeB.Annotations[calicoIPv6AddressAnnotation] = "fd00:1::2/64"
+		cli = build(nodeA, nodeB, newNodePod("node-a", true), newNodePod("node-b", true))
+		t, err = getDualStackTransition(ctx, cli, installation, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t.ready).To(BeTrue())
+	})
+
+	It("should return the pending IPv6 pools", func() {
+		pools := &crdv1.IPPoolList{Items: []crdv1.IPPool{v4pool, v6pool(true)}}
+		Expect(PendingIPv6Pools(installation, pools)).To(ConsistOf(v6pool(true)))
+
+		// A pool disabled through the Installation is not pending.
+		installation.Spec.CalicoNetwork.IPPools[1].DisableNewAllocations = ptr.ToPtr(true)
+		Expect(PendingIPv6Pools(installation, pools)).To(BeEmpty())
+
+		// Nor is anything once an IPv6 pool is enabled.
+		installation.Spec.CalicoNetwork.IPPools[1].DisableNewAllocations = ptr.ToPtr(false)
+		pools.Items[1].Spec.Disabled = false
+		Expect(PendingIPv6Pools(installation, pools)).To(BeEmpty())
+	})
+})
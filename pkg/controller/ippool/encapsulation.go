@@ -0,0 +1,301 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ippool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operator "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+// Changing the encapsulation of a live IP pool between IPIP, VXLAN and no encapsulation is done in stages, so that
+// there is no gap in connectivity while felix reconfigures each node:
+//
+//  1. Felix is configured to run both the old and the new encapsulation, by setting ipipEnabled and vxlanEnabled
+//     on the default FelixConfiguration. The pool keeps its old encapsulation until felix has had time to settle.
+//  2. The pool is updated to the new encapsulation.
+//  3. Once every node has a tunnel address for the new encapsulation, calico-node is ready on every node and felix
+//     has had time to program its routes, the FelixConfiguration is restored, which disables the old encapsulation.
+//
+// The state of the change is kept in an annotation on the default FelixConfiguration so that it survives an operator
+// restart. Only one pool is changed at a time.
+
+const (
+	// encapsulationChangeAnnotation holds the JSON encoded encapsulationChange in progress.
+	encapsulationChangeAnnotation = "operator.tigera.io/encapsulation-change"
+
+	// encapsulationSettleTime is how long felix is given to reconfigure nodes after each step of the change.
+	encapsulationSettleTime = time.Minute
+
+	encapsulationPhaseBothEnabled = "BothEnabled"
+	encapsulationPhasePoolUpdated = "PoolUpdated"
+)
+
+// Node annotations set by calico-node once it has been allocated a tunnel address.
+var tunnelAddressAnnotations = map[string]string{
+	"IPIP":    "projectcalico.org/IPv4IPIPTunnelAddr",
+	"VXLAN":   "projectcalico.org/IPv4VXLANTunnelAddr",
+	"VXLANv6": "projectcalico.org/IPv6VXLANTunnelAddr",
+}
+
+// encapsulationChange is the state of an encapsulation change in progress.
+type encapsulationChange struct {
+	CIDR  string                     `json:"cidr"`
+	From  operator.EncapsulationType `json:"from"`
+	To    operator.EncapsulationType `json:"to"`
+	Phase string                     `json:"phase"`
+	Since metav1.Time                `json:"since"`
+
+	// The values of the FelixConfiguration fields before the change, restored once it is complete.
+	IPIPEnabled  *bool `json:"ipipEnabled,omitempty"`
+	VXLANEnabled *bool `json:"vxlanEnabled,omitempty"`
+}
+
+// encapsulationProgress is the result of reconciling an encapsulation change.
+type encapsulationProgress struct {
+	// holdCIDR is the CIDR of the pool that must keep its current encapsulation for now.
+	holdCIDR string
+
+	// waiting is true while the change is waiting on felix or the nodes.
+	waiting bool
+
+	message string
+}
+
+// encapsulationFamily returns the tunnel type used by the encapsulation, or "" if there is none.
+func encapsulationFamily(e operator.EncapsulationType) string {
+	switch e {
+	case operator.EncapsulationIPIP, operator.EncapsulationIPIPCrossSubnet:
+		return "IPIP"
+	case operator.EncapsulationVXLAN, operator.EncapsulationVXLANCrossSubnet:
+		return "VXLAN"
+	}
+	return ""
+}
+
+func currentEncapsulation(pool crdv1.IPPool) operator.EncapsulationType {
+	p := operator.IPPool{}
+	FromProjectCalicoV1(&p, pool)
+	if p.Encapsulation == "" {
+		return operator.EncapsulationNone
+	}
+	return p.Encapsulation
+}
+
+// reconcileEncapsulationChange moves any encapsulation change for the operator's IP pools forward by one step, starting
+// one if a pool in the Installation has a different encapsulation than the pool in the cluster. It returns nil if no
+// change is in progress.
+func reconcileEncapsulationChange(ctx context.Context, cli client.Client, installation *operator.Installation, ourPools map[string]crdv1.IPPool, now time.Time) (*encapsulationProgress, error) {
+	fc, err := utils.GetFelixConfiguration(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	var change *encapsulationChange
+	if s, ok := fc.Annotations[encapsulationChangeAnnotation]; ok {
+		change = &encapsulationChange{}
+		if err := json.Unmarshal([]byte(s), change); err != nil {
+			return nil, fmt.Errorf("failed to parse annotation %s: %w", encapsulationChangeAnnotation, err)
+		}
+	}
+
+	var desired *operator.IPPool
+	if change != nil {
+		for i, p := range installation.Spec.CalicoNetwork.IPPools {
+			if p.CIDR == change.CIDR {
+				desired = &installation.Spec.CalicoNetwork.IPPools[i]
+			}
+		}
+	} else {
+		for i, p := range installation.Spec.CalicoNetwork.IPPools {
+			cur, ok := ourPools[p.CIDR]
+			if ok && encapsulationFamily(currentEncapsulation(cur)) != encapsulationFamily(p.Encapsulation) {
+				desired = &installation.Spec.CalicoNetwork.IPPools[i]
+				change = &encapsulationChange{
+					CIDR:         p.CIDR,
+					From:         currentEncapsulation(cur),
+					IPIPEnabled:  fc.Spec.IPIPEnabled,
+					VXLANEnabled: fc.Spec.VXLANEnabled,
+				}
+				break
+			}
+		}
+		if change == nil {
+			return nil, nil
+		}
+	}
+
+	switch {
+	case desired == nil:
+		// The pool was removed. Undo the change to felix.
+		return nil, endEncapsulationChange(ctx, cli, change)
+
+	case change.Phase != encapsulationPhasePoolUpdated && encapsulationFamily(desired.Encapsulation) == encapsulationFamily(change.From):
+		// The pool was changed back before it was updated. Undo the change to felix.
+		return nil, endEncapsulationChange(ctx, cli, change)
+
+	case change.Phase == encapsulationPhasePoolUpdated && encapsulationFamily(desired.Encapsulation) != encapsulationFamily(change.To):
+		// The pool was changed again after it was updated. End this change, and start a new one from the
+		// current encapsulation on the next reconcile.
+		if err := endEncapsulationChange(ctx, cli, change); err != nil {
+			return nil, err
+		}
+		return &encapsulationProgress{
+			holdCIDR: change.CIDR,
+			waiting:  true,
+			message:  fmt.Sprintf("Changing encapsulation of IP pool %s from %s to %s", change.CIDR, change.To, desired.Encapsulation),
+		}, nil
+
+	case change.Phase == "" || (change.Phase == encapsulationPhaseBothEnabled && desired.Encapsulation != change.To):
+		// Start the change, or restart it if the desired encapsulation changed before the pool was updated.
+		change.To = desired.Encapsulation
+		change.Phase = encapsulationPhaseBothEnabled
+		change.Since = metav1.NewTime(now)
+		if err := saveEncapsulationChange(ctx, cli, change); err != nil {
+			return nil, err
+		}
+		return &encapsulationProgress{
+			holdCIDR: change.CIDR,
+			waiting:  true,
+			message:  fmt.Sprintf("Changing encapsulation of IP pool %s from %s to %s: enabling both on felix", change.CIDR, change.From, change.To),
+		}, nil
+
+	case change.Phase == encapsulationPhaseBothEnabled:
+		if now.Sub(change.Since.Time) < encapsulationSettleTime {
+			return &encapsulationProgress{
+				holdCIDR: change.CIDR,
+				waiting:  true,
+				message:  fmt.Sprintf("Changing encapsulation of IP pool %s from %s to %s: waiting for felix to enable both", change.CIDR, change.From, change.To),
+			}, nil
+		}
+		change.Phase = encapsulationPhasePoolUpdated
+		change.Since = metav1.NewTime(now)
+		if err := saveEncapsulationChange(ctx, cli, change); err != nil {
+			return nil, err
+		}
+		return &encapsulationProgress{
+			waiting: true,
+			message: fmt.Sprintf("Changing encapsulation of IP pool %s from %s to %s: updating the pool", change.CIDR, change.From, change.To),
+		}, nil
+	}
+
+	// The pool has been updated. Wait for every node to be ready to use the new encapsulation.
+	allNodes := desired.NodeSelector == "" || desired.NodeSelector == operator.NodeSelectorDefault
+	notReady, total, err := nodesNotReadyForEncapsulation(ctx, cli, change.CIDR, change.To, allNodes)
+	if err != nil {
+		return nil, err
+	}
+	if len(notReady) > 0 {
+		return &encapsulationProgress{
+			waiting: true,
+			message: fmt.Sprintf("Changing encapsulation of IP pool %s from %s to %s: %d/%d nodes are ready, waiting for %s",
+				change.CIDR, change.From, change.To, total-len(notReady), total, nodeList(notReady)),
+		}, nil
+	}
+	if now.Sub(change.Since.Time) < encapsulationSettleTime {
+		return &encapsulationProgress{
+			waiting: true,
+			message: fmt.Sprintf("Changing encapsulation of IP pool %s from %s to %s: waiting for felix to program routes", change.CIDR, change.From, change.To),
+		}, nil
+	}
+	if err := endEncapsulationChange(ctx, cli, change); err != nil {
+		return nil, err
+	}
+	return &encapsulationProgress{
+		message: fmt.Sprintf("Changed encapsulation of IP pool %s from %s to %s", change.CIDR, change.From, change.To),
+	}, nil
+}
+
+// saveEncapsulationChange records the change on the default FelixConfiguration and enables both the old and the new
+// encapsulation on felix.
+func saveEncapsulationChange(ctx context.Context, cli client.Client, change *encapsulationChange) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	_, err = utils.PatchFelixConfiguration(ctx, cli, func(fc *crdv1.FelixConfiguration) (bool, error) {
+		if fc.Annotations == nil {
+			fc.Annotations = map[string]string{}
+		}
+		fc.Annotations[encapsulationChangeAnnotation] = string(data)
+		for _, e := range []operator.EncapsulationType{change.From, change.To} {
+			switch encapsulationFamily(e) {
+			case "IPIP":
+				fc.Spec.IPIPEnabled = ptr.BoolToPtr(true)
+			case "VXLAN":
+				fc.Spec.VXLANEnabled = ptr.BoolToPtr(true)
+			}
+		}
+		return true, nil
+	})
+	return err
+}
+
+// endEncapsulationChange restores the FelixConfiguration to its state before the change.
+func endEncapsulationChange(ctx context.Context, cli client.Client, change *encapsulationChange) error {
+	_, err := utils.PatchFelixConfiguration(ctx, cli, func(fc *crdv1.FelixConfiguration) (bool, error) {
+		delete(fc.Annotations, encapsulationChangeAnnotation)
+		fc.Spec.IPIPEnabled = change.IPIPEnabled
+		fc.Spec.VXLANEnabled = change.VXLANEnabled
+		return true, nil
+	})
+	return err
+}
+
+// nodesNotReadyForEncapsulation returns the nodes where calico-node is not ready or, for a tunnel encapsulation, has
+// not yet been allocated a tunnel address. Tunnel addresses are only checked for pools that select all nodes.
+func nodesNotReadyForEncapsulation(ctx context.Context, cli client.Client, cidr string, encapsulation operator.EncapsulationType, allNodes bool) ([]string, int, error) {
+	nodes := corev1.NodeList{}
+	if err := cli.List(ctx, &nodes); err != nil {
+		return nil, 0, err
+	}
+	pods := corev1.PodList{}
+	if err := cli.List(ctx, &pods, client.InNamespace(common.CalicoNamespace), client.MatchingLabels{"k8s-app": "calico-node"}); err != nil {
+		return nil, 0, err
+	}
+	ready := map[string]bool{}
+	for _, p := range pods.Items {
+		if p.DeletionTimestamp == nil && podReady(&p) {
+			ready[p.Spec.NodeName] = true
+		}
+	}
+
+	annotation := ""
+	if family := encapsulationFamily(encapsulation); family != "" && allNodes {
+		if isIPv6(cidr) {
+			family += "v6"
+		}
+		annotation = tunnelAddressAnnotations[family]
+	}
+
+	linux := linuxNodes(nodes.Items)
+	var notReady []string
+	for _, n := range linux {
+		if !ready[n.Name] || (annotation != "" && n.Annotations[annotation] == "") {
+			notReady = append(notReady, n.Name)
+		}
+	}
+	return notReady, len(linux), nil
+}
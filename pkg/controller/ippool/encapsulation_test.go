@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ippool
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operator "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+	"github.com/tigera/operator/pkg/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Encapsulation change", func() {
+	var ctx context.Context
+	var cli client.Client
+	var installation *operator.Installation
+	var ourPools map[string]crdv1.IPPool
+	var node *corev1.Node
+	var start time.Time
+
+	felixConfig := func() *crdv1.FelixConfiguration {
+		fc, err := utils.GetFelixConfiguration(ctx, cli)
+		Expect(err).NotTo(HaveOccurred())
+		return fc
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(corev1.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+		node = &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Annotations: map[string]string{}}}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "calico-node-a",
+				Namespace: common.CalicoNamespace,
+				Labels:    map[string]string{"k8s-app": "calico-node"},
+			},
+			Spec:   corev1.PodSpec{NodeName: "node-a"},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
+		fc := &crdv1.FelixConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		fc.Spec.VXLANEnabled = ptr.BoolToPtr(false)
+		cli = fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, pod, fc).Build()
+
+		installation = &operator.Installation{
+			Spec: operator.InstallationSpec{
+				CalicoNetwork: &operator.CalicoNetworkSpec{
+					IPPools: []operator.IPPool{{CIDR: "192.168.0.0/16", Encapsulation: operator.EncapsulationVXLAN, NodeSelector: "all()"}},
+				},
+			},
+		}
+		ourPools = map[string]crdv1.IPPool{
+			"192.168.0.0/16": {Spec: crdv1.IPPoolSpec{CIDR: "192.168.0.0/16", IPIPMode: crdv1.IPIPModeAlways, VXLANMode: crdv1.VXLANModeNever}},
+		}
+		start = time.Now()
+	})
+
+	It("should do nothing if the encapsulation is unchanged", func() {
+		installation.Spec.CalicoNetwork.IPPools[0].Encapsulation = operator.EncapsulationIPIPCrossSubnet
+		progress, err := reconcileEncapsulationChange(ctx, cli, installation, ourPools, start)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress).To(BeNil())
+	})
+
+	It("should switch a pool from IPIP to VXLAN in stages", func() {
+		By("enabling both encapsulations on felix while holding the pool")
+		progress, err := reconcileEncapsulationChange(ctx, cli, installation, ourPools, start)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.holdCIDR).To(Equal("192.168.0.0/16"))
+		Expect(progress.message).To(ContainSubstring("from IPIP to VXLAN"))
+		fc := felixConfig()
+		Expect(fc.Spec.IPIPEnabled).To(Equal(ptr.BoolToPtr(true)))
+		Expect(fc.Spec.VXLANEnabled).To(Equal(ptr.BoolToPtr(true)))
+		Expect(fc.Annotations).To(HaveKey(encapsulationChangeAnnotation))
+
+		progress, err = reconcileEncapsulationChange(ctx, cli, installation, ourPools, start.Add(encapsulationSettleTime/2))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.holdCIDR).To(Equal("192.168.0.0/16"))
+
+		By("releasing the pool once felix has settled")
+		progress, err = reconcileEncapsulationChange(ctx, cli, installation, ourPools, start.Add(encapsulationSettleTime))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.holdCIDR).To(BeEmpty())
+		ourPools["192.168.0.0/16"] = crdv1.IPPool{Spec: crdv1.IPPoolSpec{CIDR: "192.168.0.0/16", IPIPMode: crdv1.IPIPModeNever, VXLANMode: crdv1.VXLANModeAlways}}
+
+		By("waiting for every node to get a VXLAN tunnel address")
+		progress, err = reconcileEncapsulationChange(ctx, cli, installation, ourPools, start.Add(3*encapsulationSettleTime))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.waiting).To(BeTrue())
+		Expect(progress.message).To(ContainSubstring("0/1 nodes are ready, waiting for node-a"))
+
+		node.Annotations["projectcalico.org/IPv4VXLANTunnelAddr"] = "192.168.10.1"
+		Expect(cli.Update(ctx, node)).NotTo(HaveOccurred())
+
+		By("restoring the FelixConfiguration")
+		progress, err = reconcileEncapsulationChange(ctx, cli, installation, ourPools, start.Add(3*encapsulationSettleTime))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.waiting).To(BeFalse())
+		fc = felixConfig()
+		Expect(fc.Spec.IPIPEnabled).To(BeNil())
+		Expect(fc.Spec.VXLANEnabled).To(Equal(ptr.BoolToPtr(false)))
+		Expect(fc.Annotations).NotTo(HaveKey(encapsulationChangeAnnotation))
+
+		progress, err = reconcileEncapsulationChange(ctx, cli, installation, ourPools, start.Add(4*encapsulationSettleTime))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress).To(BeNil())
+	})
+
+	It("should undo the change to felix if the pool is changed back before it is updated", func() {
+		_, err := reconcileEncapsulationChange(ctx, cli, installation, ourPools, start)
+		Expect(err).NotTo(HaveOccurred())
+
+		installation.Spec.CalicoNetwork.IPPools[0].Encapsulation = operator.EncapsulationIPIP
+		progress, err := reconcileEncapsulationChange(ctx, cli, installation, ourPools, start.Add(encapsulationSettleTime))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress).To(BeNil())
+		fc := felixConfig()
+		Expect(fc.Spec.IPIPEnabled).To(BeNil())
+		Expect(fc.Spec.VXLANEnabled).To(Equal(ptr.BoolToPtr(false)))
+		Expect(fc.Annotations).NotTo(HaveKey(encapsulationChangeAnnotation))
+	})
+})
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -141,6 +142,7 @@ func hasOwnerLabel(pool *crdv1.IPPool) bool {
 // - Query existing IP pools owned by this controller
 // - Reconcile the differences
 func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.V(1).Info("Reconciling IP pools")
 
@@ -157,7 +159,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return reconcile.Result{}, err
 	}
 	r.status.OnCRFound()
-	defer r.status.SetMetaData(&installation.ObjectMeta)
+	defer r.status.SetMetaData(installation)
 
 	// If the installation is terminating, do nothing.
 	if installation.DeletionTimestamp != nil {
@@ -270,6 +272,47 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	}
 	reqLogger.V(1).Info("Found IP pools owned by us", "count", len(ourPools))
 
+	// An IPv6 pool added to an existing IPv4 cluster is created with new allocations disabled, and only enabled
+	// once calico-node is ready for IPv6 on every node. See dualstack.go for details.
+	transition, err := getDualStackTransition(ctx, r.client, installation, currentPools)
+	if err != nil {
+		r.status.SetDegraded(operatorv1.InvalidConfigurationError, "Unable to add IPv6 pool", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
+	// Changes to the encapsulation of a live pool are orchestrated with felix one step at a time. See encapsulation.go
+	// for details. This is only possible once the API server is available, since it is needed to update the pool.
+	var encapsulation *encapsulationProgress
+	if apiAvailable {
+		encapsulation, err = reconcileEncapsulationChange(ctx, r.client, installation, ourPools, time.Now())
+		if err != nil {
+			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error changing IP pool encapsulation", err, reqLogger)
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Report the progress of any staged change in the status.
+	var progress []string
+	waiting := false
+	if transition != nil {
+		progress = append(progress, transition.message)
+		waiting = transition.created && !transition.ready
+	}
+	if encapsulation != nil {
+		progress = append(progress, encapsulation.message)
+		waiting = waiting || encapsulation.waiting
+	}
+	if len(progress) > 0 {
+		reqLogger.Info(strings.Join(progress, "; "))
+		reason := operatorv1.RolloutProgressing
+		if waiting {
+			reason = operatorv1.RolloutPaused
+		}
+		r.status.SetCondition(operatorv1.ComponentRollingOut, operatorv1.ConditionTrue, reason, strings.Join(progress, "; "))
+	} else {
+		r.status.ClearCondition(operatorv1.ComponentRollingOut)
+	}
+
 	// For each pool that is desired, but doesn't exist, create it.
 	// We will install pools at start-of-day using the CRD API, but otherwise
 	// we require the v3 API to be running. This is so that we properly leverage the v3 API's validation.
@@ -283,6 +326,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 			return reconcile.Result{}, err
 		}
 		v1res.Labels[managedByLabel] = managedByValue
+		if transition != nil && transition.cidr == p.CIDR && !transition.ready {
+			v1res.Spec.Disabled = true
+		}
+		if cur, ok := ourPools[p.CIDR]; ok && encapsulation != nil && encapsulation.holdCIDR == p.CIDR {
+			v1res.Spec.IPIPMode = cur.Spec.IPIPMode
+			v1res.Spec.VXLANMode = cur.Spec.VXLANMode
+		}
 
 		// If there is an existing IP pool in the cluster with the same CIDR, but it is not owned by us, then we cannot
 		// take action on it.
@@ -383,9 +433,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	// We can clear the degraded state now since as far as we know everything is in order.
 	r.status.ClearDegraded()
 
-	if !r.status.IsAvailable() {
+	if !r.status.IsAvailable() || transition != nil || encapsulation != nil {
 		// Schedule a kick to check again in the near future. Hopefully by then
-		// things will be available.
+		// things will be available, and any staged change to the IP pools can move on.
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
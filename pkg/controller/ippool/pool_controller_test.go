@@ -134,6 +134,7 @@ var _ = Describe("IP Pool controller tests", func() {
 		mockStatus.On("SetMetaData", mock.Anything)
 		mockStatus.On("IsAvailable").Return(true)
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("ClearCondition", operator.ComponentRollingOut)
 		mockStatus.On("ClearDegraded")
 
 		_, err := r.Reconcile(ctx, reconcile.Request{})
@@ -187,6 +188,7 @@ var _ = Describe("IP Pool controller tests", func() {
 		mockStatus.On("SetMetaData", mock.Anything)
 		mockStatus.On("IsAvailable").Return(true)
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("ClearCondition", operator.ComponentRollingOut)
 		mockStatus.On("ClearDegraded")
 
 		_, err := r.Reconcile(ctx, reconcile.Request{})
@@ -238,6 +240,7 @@ var _ = Describe("IP Pool controller tests", func() {
 		mockStatus.On("SetMetaData", mock.Anything)
 		mockStatus.On("IsAvailable").Return(true)
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("ClearCondition", operator.ComponentRollingOut)
 		mockStatus.On("ClearDegraded")
 
 		_, err := r.Reconcile(ctx, reconcile.Request{})
@@ -288,6 +291,7 @@ var _ = Describe("IP Pool controller tests", func() {
 		mockStatus.On("SetMetaData", mock.Anything)
 		mockStatus.On("IsAvailable").Return(true)
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("ClearCondition", operator.ComponentRollingOut)
 		mockStatus.On("ClearDegraded")
 
 		_, err := r.Reconcile(ctx, reconcile.Request{})
@@ -334,6 +338,7 @@ var _ = Describe("IP Pool controller tests", func() {
 		mockStatus.On("SetMetaData", mock.Anything)
 		mockStatus.On("IsAvailable").Return(true)
 		mockStatus.On("ReadyToMonitor")
+		mockStatus.On("ClearCondition", operator.ComponentRollingOut)
 		mockStatus.On("ClearDegraded")
 
 		_, err := r.Reconcile(ctx, reconcile.Request{})
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tigera/operator/pkg/dns"
 	corev1 "k8s.io/api/core/v1"
@@ -248,6 +249,7 @@ func fillDefaults(instance *operatorv1.LogCollector) []string {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling LogCollector")
 	// Fetch the LogCollector instance
@@ -267,7 +269,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 	r.status.OnCRFound()
 
 	// SetMetaData in the TigeraStatus such as observedGenerations.
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Changes for updating LogCollector status conditions
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -456,7 +458,7 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 	var s3Credential *render.S3Credential
 	if instance.Spec.AdditionalStores != nil {
 		if instance.Spec.AdditionalStores.S3 != nil {
-			s3Credential, err = getS3Credential(r.client)
+			s3Credential, err = utils.GetS3Credential(r.client)
 			if err != nil {
 				r.status.SetDegraded(operatorv1.ResourceValidationError, "Error with S3 credential secret", err, reqLogger)
 				return reconcile.Result{}, err
@@ -709,37 +711,6 @@ func (r *ReconcileLogCollector) Reconcile(ctx context.Context, request reconcile
 	return reconcile.Result{}, nil
 }
 
-func getS3Credential(client client.Client) (*render.S3Credential, error) {
-	secret := &corev1.Secret{}
-	secretNamespacedName := types.NamespacedName{
-		Name:      render.S3FluentdSecretName,
-		Namespace: common.OperatorNamespace(),
-	}
-	if err := client.Get(context.Background(), secretNamespacedName, secret); err != nil {
-		if errors.IsNotFound(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to read secret %q: %s", render.S3FluentdSecretName, err)
-	}
-
-	var ok bool
-	var kId []byte
-	if kId, ok = secret.Data[render.S3KeyIdName]; !ok || len(kId) == 0 {
-		return nil, fmt.Errorf("expected secret %q to have a field named %q",
-			render.S3FluentdSecretName, render.S3KeyIdName)
-	}
-	var kSecret []byte
-	if kSecret, ok = secret.Data[render.S3KeySecretName]; !ok || len(kSecret) == 0 {
-		return nil, fmt.Errorf("expected secret %q to have a field named %q",
-			render.S3FluentdSecretName, render.S3KeySecretName)
-	}
-
-	return &render.S3Credential{
-		KeyId:     kId,
-		KeySecret: kSecret,
-	}, nil
-}
-
 func getSplunkCredential(client client.Client) (*render.SplunkCredential, error) {
 	tokenSecret := &corev1.Secret{}
 	tokenNamespacedName := types.NamespacedName{
@@ -17,6 +17,7 @@ package elastic
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	operatorv1 "github.com/tigera/operator/api/v1"
@@ -68,6 +69,11 @@ func (m *MockESClient) DeleteUser(ctx context.Context, u *utils.User) error {
 	return ret.Error(0)
 }
 
+func (m *MockESClient) CountFlowLogs(ctx context.Context, since time.Time, policyPattern string) (int64, error) {
+	ret := m.Called(ctx, since, policyPattern)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
 func (m *MockESClient) GetUsers(ctx context.Context) ([]utils.User, error) {
 	ret := m.Called(ctx)
 	return ret.Get(0).([]utils.User), ret.Error(1)
@@ -119,6 +119,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 }
 
 func (r *ESMetricsSubController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling LogStorage - ES Metrics")
 
@@ -17,6 +17,7 @@ package initializer
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -185,6 +186,7 @@ func validateComponentResources(spec *operatorv1.LogStorageSpec) error {
 }
 
 func (r *LogStorageInitializer) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling LogStorage")
 
@@ -285,7 +287,7 @@ func (r *LogStorageInitializer) Reconcile(ctx context.Context, request reconcile
 		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Failed to update LogStorage status", err, reqLogger)
 		return reconcile.Result{}, err
 	}
-	defer r.status.SetMetaData(&ls.ObjectMeta)
+	defer r.status.SetMetaData(ls)
 
 	// Mark the status as available.
 	r.status.ReadyToMonitor()
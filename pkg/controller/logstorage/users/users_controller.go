@@ -17,6 +17,7 @@ package users
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/tigera/operator/pkg/controller/logstorage/initializer"
 
@@ -160,6 +161,7 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 }
 
 func (r *UserController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	helper := utils.NewNamespaceHelper(r.multiTenant, render.ElasticsearchNamespace, request.Namespace)
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "installNS", helper.InstallNamespace(), "truthNS", helper.TruthNamespace())
 	reqLogger.Info("Reconciling LogStorage - Users")
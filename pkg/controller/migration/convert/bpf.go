@@ -40,6 +40,9 @@ func copyK8sServicesEPConfigMap(c *components) error {
 	}
 	host := cm.Data["KUBERNETES_SERVICE_HOST"]
 	port := cm.Data["KUBERNETES_SERVICE_PORT"]
+	if c.readOnly {
+		return nil
+	}
 
 	// Create the config map in tigera-operator namespace
 	cmNamespacedName.Namespace = common.OperatorNamespace()
@@ -36,29 +36,37 @@ type components struct {
 	client client.Client
 
 	cni cni.NetworkComponents
+
+	// flannel is the configuration of a canal or flannel installation, or nil if the cluster runs Calico.
+	flannel *flannelConfig
+
+	// readOnly is set when the installation is only being checked, in which case handlers must not write to the
+	// cluster.
+	readOnly bool
 }
 
 // getComponents loads the main calico components into structs for later parsing.
 func getComponents(ctx context.Context, client client.Client) (*components, error) {
 	var ds = appsv1.DaemonSet{}
 
-	// verify canal isn't present, or block
-	if err := client.Get(ctx, types.NamespacedName{
-		Name:      "canal-node",
-		Namespace: metav1.NamespaceSystem,
-	}, &ds); err == nil {
-		return nil, fmt.Errorf("detected existing canal installation")
-	} else if !errors.IsNotFound(err) {
-		return nil, fmt.Errorf("failed to check for existing canal installation: %v", err)
-	}
-
+	var fc *flannelConfig
 	if err := client.Get(ctx, types.NamespacedName{
 		Name:      "calico-node",
 		Namespace: metav1.NamespaceSystem,
 	}, &ds); err != nil {
-		if errors.IsNotFound(err) {
-			return nil, nil
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get calico-node daemonset: %v", err)
+		}
+
+		// Without a calico-node DaemonSet, look for a canal or flannel installation to convert instead.
+		fds, fdsInfo, err := getFlannelDaemonSet(ctx, client)
+		if err != nil || fds == nil {
+			return nil, err
 		}
+		if fc, err = loadFlannelConfig(ctx, client, fds, fdsInfo); err != nil {
+			return nil, err
+		}
+		ds = *fds
 	}
 
 	var kc = new(appsv1.Deployment)
@@ -87,7 +95,8 @@ func getComponents(ctx context.Context, client client.Client) (*components, erro
 	}
 
 	comps := &components{
-		client: client,
+		client:  client,
+		flannel: fc,
 		node: CheckedDaemonSet{
 			ds,
 			map[string]checkedFields{},
@@ -98,6 +107,12 @@ func getComponents(ctx context.Context, client client.Client) (*components, erro
 
 	// do some upfront processing of CNI by loading it into comps
 	var err error
+	if fc != nil && !fc.canal {
+		if fc.cniConf != "" {
+			comps.cni, err = cni.Parse(fc.cniConf)
+		}
+		return comps, err
+	}
 	comps.cni, err = loadCNI(comps)
 
 	return comps, err
@@ -20,6 +20,7 @@ package convert
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
@@ -56,26 +57,56 @@ func Convert(ctx context.Context, client client.Client) (*operatorv1.Installatio
 	}
 
 	install := &operatorv1.Installation{}
-	for _, hdlr := range handlers {
-		if err := hdlr(comps, install); err != nil {
-			return nil, err
+	if err := runHandlers(comps, install, func(err ErrIncompatibleCluster) error { return err }); err != nil {
+		return nil, err
+	}
+	return install, nil
+}
+
+// runHandlers converts the components into the given Installation. Each ErrIncompatibleCluster found along the
+// way is passed to onIncompatible, and conversion stops if it returns an error. Any other error stops conversion.
+func runHandlers(comps *components, install *operatorv1.Installation, onIncompatible func(ErrIncompatibleCluster) error) error {
+	check := func(err error) error {
+		var incompatible ErrIncompatibleCluster
+		if errors.As(err, &incompatible) {
+			return onIncompatible(incompatible)
+		}
+		return err
+	}
+
+	hdlrs := handlers
+	if comps.flannel != nil {
+		hdlrs = flannelHandlers
+		if comps.flannel.canal {
+			hdlrs = canalHandlers
 		}
 	}
 
+	for _, hdlr := range hdlrs {
+		if err := check(hdlr(comps, install)); err != nil {
+			return err
+		}
+	}
+
+	// A flannel installation has no calico-node whose settings need to be carried forward.
+	if comps.flannel != nil && !comps.flannel.canal {
+		return nil
+	}
+
 	// Handle the remaining FelixVars last because we only want to take env vars which weren't accounted
 	// for by the other handlers
-	if err := handleFelixVars(comps); err != nil {
-		return nil, err
+	if err := check(handleFelixVars(comps)); err != nil {
+		return err
 	}
 
 	// check for unchecked env vars
 	if uncheckedVars := comps.node.uncheckedVars(); len(uncheckedVars) != 0 {
-		return nil, ErrIncompatibleCluster{
+		return check(ErrIncompatibleCluster{
 			err:       fmt.Sprintf("unexpected env vars: %s", uncheckedVars),
 			component: ComponentCalicoNode,
 			fix:       "remove these environment variables from the calico-node daemonest",
-		}
+		})
 	}
 
-	return install, nil
+	return nil
 }
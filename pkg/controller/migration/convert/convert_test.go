@@ -65,7 +65,7 @@ var _ = Describe("Parser", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
-	It("should error if it detects a canal installation", func() {
+	It("should error if it detects a canal installation without its flannel configuration", func() {
 		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(&appsv1.DaemonSet{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      "canal-node",
@@ -120,6 +120,8 @@ func handleCore(c *components, install *operatorv1.Installation) error {
 					err:       "detected 'flexvol-driver-host' volume but no 'flexvol-driver' init container",
 					component: ComponentCalicoNode,
 					fix:       "remove the 'flexvol-driver-host' volume or restore the 'flexvol-driver' init container",
+					// Nothing uses the volume without the init container.
+					autoFixable: true,
 				}
 			}
 			install.Spec.FlexVolumePath = vol.HostPath.Path
@@ -184,7 +186,11 @@ func handleCore(c *components, install *operatorv1.Installation) error {
 			}
 		}
 
-		if err := c.node.assertEnv(ctx, c.client, containerInstallCNI, "CNI_CONF_NAME", "10-calico.conflist"); err != nil {
+		if c.flannel != nil {
+			// canal installs its CNI config as 10-canal.conflist. The operator's 10-calico.conflist sorts before it,
+			// so it takes precedence once calico-node has been migrated.
+			c.node.ignoreEnv(containerInstallCNI, "CNI_CONF_NAME")
+		} else if err := c.node.assertEnv(ctx, c.client, containerInstallCNI, "CNI_CONF_NAME", "10-calico.conflist"); err != nil {
 			return err
 		}
 	}
@@ -25,6 +25,9 @@ type ErrIncompatibleCluster struct {
 	fix string
 	// component identifies which component caused the problem.
 	component string
+	// autoFixable is set when the fix only removes config that has no effect on the cluster, so applying it does not
+	// change how the cluster behaves.
+	autoFixable bool
 }
 
 func (e ErrIncompatibleCluster) Error() string {
@@ -40,6 +43,7 @@ const (
 	ComponentTypha           = "deployment/calico-typha"
 	ComponentCNIConfig       = "cni-config"
 	ComponentIPPools         = "ippools"
+	ComponentFlannel         = "flannel"
 )
 
 func ErrMissingHostPathVolume(component, volume, hostPath string) ErrIncompatibleCluster {
@@ -52,8 +56,9 @@ func ErrMissingHostPathVolume(component, volume, hostPath string) ErrIncompatibl
 
 func ErrIncompatibleAnnotation(annotations map[string]string, component string) error {
 	return ErrIncompatibleCluster{
-		err:       fmt.Sprintf("unexpected annotation '%v'", annotations),
-		component: component,
-		fix:       "remove the annotation from the component",
+		err:         fmt.Sprintf("unexpected annotation '%v'", annotations),
+		component:   component,
+		fix:         "remove the annotation from the component",
+		autoFixable: true,
 	}
 }
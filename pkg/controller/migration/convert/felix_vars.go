@@ -102,6 +102,9 @@ func handleFelixVars(c *components) error {
 
 	}
 
+	if c.readOnly {
+		return nil
+	}
 	return c.client.Patch(ctx, &crdv1.FelixConfiguration{
 		ObjectMeta: metav1.ObjectMeta{Name: "default"},
 	}, p)
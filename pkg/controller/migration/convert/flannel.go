@@ -0,0 +1,328 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+const (
+	containerFlannel = "kube-flannel"
+
+	// flannelConfigVolume is the name of the volume that mounts the flannel ConfigMap into the flannel container.
+	flannelConfigVolume = "flannel-cfg"
+	flannelNetConfKey   = "net-conf.json"
+	flannelCNIConfKey   = "cni-conf.json"
+
+	// Defaults used by flannel's VXLAN backend when the VNI and port are not configured.
+	defaultFlannelVXLANVNI  = 1
+	defaultFlannelVXLANPort = 8472
+)
+
+// flannelDaemonSet identifies a DaemonSet that runs flannel, either on its own or as part of canal.
+type flannelDaemonSet struct {
+	namespace string
+	name      string
+	canal     bool
+	// configMap is the ConfigMap holding the flannel network configuration, used if the DaemonSet does not mount one.
+	configMap string
+}
+
+// flannelDaemonSets are the DaemonSets installed by the canal and flannel manifests, in the order they are looked up.
+var flannelDaemonSets = []flannelDaemonSet{
+	{namespace: "kube-system", name: "canal", canal: true, configMap: "canal-config"},
+	{namespace: "kube-system", name: "canal-node", canal: true, configMap: "canal-config"},
+	{namespace: "kube-flannel", name: "kube-flannel-ds", configMap: "kube-flannel-cfg"},
+	{namespace: "kube-system", name: "kube-flannel-ds", configMap: "kube-flannel-cfg"},
+}
+
+// flannelConfig is the configuration of an existing canal or flannel installation.
+type flannelConfig struct {
+	// canal is true if flannel runs alongside calico-node for network policy.
+	canal bool
+
+	// netConf is the flannel network configuration.
+	netConf flannelNetConf
+
+	// cniConf is the CNI config installed by flannel. canal installs its CNI config from the install-cni container
+	// instead.
+	cniConf string
+}
+
+// flannelNetConf is the network configuration that flannel reads from the net-conf.json key of its ConfigMap.
+type flannelNetConf struct {
+	Network     string         `json:"Network"`
+	IPv6Network string         `json:"IPv6Network"`
+	EnableIPv6  bool           `json:"EnableIPv6"`
+	Backend     flannelBackend `json:"Backend"`
+}
+
+type flannelBackend struct {
+	Type          string `json:"Type"`
+	VNI           int    `json:"VNI"`
+	Port          int    `json:"Port"`
+	DirectRouting bool   `json:"DirectRouting"`
+}
+
+// getFlannelDaemonSet returns the canal or flannel DaemonSet, if there is one.
+func getFlannelDaemonSet(ctx context.Context, cli client.Client) (*appsv1.DaemonSet, *flannelDaemonSet, error) {
+	for i := range flannelDaemonSets {
+		fds := &flannelDaemonSets[i]
+		ds := &appsv1.DaemonSet{}
+		if err := cli.Get(ctx, types.NamespacedName{Name: fds.name, Namespace: fds.namespace}, ds); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to check for existing %s/%s daemonset: %v", fds.namespace, fds.name, err)
+		}
+		return ds, fds, nil
+	}
+	return nil, nil, nil
+}
+
+// loadFlannelConfig reads the flannel network configuration from the ConfigMap mounted into the flannel container.
+func loadFlannelConfig(ctx context.Context, cli client.Client, ds *appsv1.DaemonSet, fds *flannelDaemonSet) (*flannelConfig, error) {
+	cmName := fds.configMap
+	if vol := getVolume(ds.Spec.Template.Spec, flannelConfigVolume); vol != nil && vol.ConfigMap != nil {
+		cmName = vol.ConfigMap.Name
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: cmName, Namespace: ds.Namespace}, cm); err != nil {
+		return nil, fmt.Errorf("failed to read flannel configuration from configmap %s/%s: %v", ds.Namespace, cmName, err)
+	}
+	data, ok := cm.Data[flannelNetConfKey]
+	if !ok {
+		return nil, ErrIncompatibleCluster{
+			err:       fmt.Sprintf("configmap %s/%s has no %s key", ds.Namespace, cmName, flannelNetConfKey),
+			component: ComponentFlannel,
+		}
+	}
+
+	fc := &flannelConfig{canal: fds.canal, cniConf: cm.Data[flannelCNIConfKey]}
+	if err := json.Unmarshal([]byte(data), &fc.netConf); err != nil {
+		return nil, ErrIncompatibleCluster{
+			err:       fmt.Sprintf("failed to parse %s in configmap %s/%s: %v", flannelNetConfKey, ds.Namespace, cmName, err),
+			component: ComponentFlannel,
+		}
+	}
+	return fc, nil
+}
+
+// handleCanal is a migration handler which checks the calico-node container of a canal installation. canal runs
+// calico-node for network policy only, leaving pod networking to flannel.
+func handleCanal(c *components, _ *operatorv1.Installation) error {
+	if c.flannel == nil || !c.flannel.canal {
+		return nil
+	}
+	if err := c.node.assertEnv(ctx, c.client, containerCalicoNode, "IP", ""); err != nil {
+		return err
+	}
+	if err := c.node.assertEnv(ctx, c.client, containerCalicoNode, "CALICO_NETWORKING_BACKEND", "none"); err != nil {
+		return err
+	}
+	if err := c.node.assertEnv(ctx, c.client, containerCalicoNode, "FELIX_INTERFACEPREFIX", "cali"); err != nil {
+		return err
+	}
+	if err := c.node.assertEnv(ctx, c.client, containerCalicoNode, "FELIX_IPV6SUPPORT", "false"); err != nil {
+		return err
+	}
+	c.node.ignoreEnv(containerCalicoNode, "CALICO_IPV4POOL_CIDR")
+	c.node.ignoreEnv(containerCalicoNode, "USE_POD_CIDR")
+	return nil
+}
+
+// handleFlannel is a migration handler which converts the flannel network configuration of a canal or flannel
+// installation. Pods keep getting their addresses from the node's pod CIDR through host-local IPAM so that existing
+// pods are unaffected while the nodes are migrated one at a time. The flannel backend maps to Calico networking as
+// follows:
+//   - vxlan uses a VXLAN IP pool with BGP disabled. spec.felixOverrides configures Felix with flannel's VNI and port.
+//   - host-gw uses an unencapsulated IP pool with BGP enabled.
+func handleFlannel(c *components, install *operatorv1.Installation) error {
+	if c.flannel == nil {
+		return nil
+	}
+	netConf := c.flannel.netConf
+
+	if netConf.EnableIPv6 || netConf.IPv6Network != "" {
+		return ErrIncompatibleCluster{
+			err:       "IPv6 flannel networks are not supported",
+			component: ComponentFlannel,
+			fix:       fmt.Sprintf("remove IPv6Network and EnableIPv6 from %s", flannelNetConfKey),
+		}
+	}
+	if _, _, err := net.ParseCIDR(netConf.Network); err != nil {
+		return ErrIncompatibleCluster{
+			err:       fmt.Sprintf("invalid flannel Network '%s'", netConf.Network),
+			component: ComponentFlannel,
+			fix:       fmt.Sprintf("set Network in %s to the cluster's pod CIDR", flannelNetConfKey),
+		}
+	}
+
+	if install.Spec.CNI == nil {
+		install.Spec.CNI = &operatorv1.CNISpec{}
+	}
+	install.Spec.CNI.Type = operatorv1.PluginCalico
+	install.Spec.CNI.IPAM = &operatorv1.IPAMSpec{Type: operatorv1.IPAMPluginHostLocal}
+
+	if install.Spec.CalicoNetwork == nil {
+		install.Spec.CalicoNetwork = &operatorv1.CalicoNetworkSpec{}
+	}
+
+	pool := operatorv1.IPPool{
+		CIDR:         netConf.Network,
+		NATOutgoing:  operatorv1.NATOutgoingDisabled,
+		NodeSelector: "all()",
+	}
+
+	switch strings.ToLower(netConf.Backend.Type) {
+	case "vxlan":
+		install.Spec.CalicoNetwork.BGP = operatorv1.BGPOptionPtr(operatorv1.BGPDisabled)
+		pool.Encapsulation = operatorv1.EncapsulationVXLAN
+		if netConf.Backend.DirectRouting {
+			pool.Encapsulation = operatorv1.EncapsulationVXLANCrossSubnet
+		}
+	case "host-gw":
+		install.Spec.CalicoNetwork.BGP = operatorv1.BGPOptionPtr(operatorv1.BGPEnabled)
+		pool.Encapsulation = operatorv1.EncapsulationNone
+	default:
+		return ErrIncompatibleCluster{
+			err:       fmt.Sprintf("flannel backend '%s' is not supported", netConf.Backend.Type),
+			component: ComponentFlannel,
+			fix:       "switch the flannel backend to 'vxlan' or 'host-gw'",
+		}
+	}
+
+	masq, iface, err := getFlannelArgs(c)
+	if err != nil {
+		return err
+	}
+	if masq {
+		pool.NATOutgoing = operatorv1.NATOutgoingEnabled
+	}
+	if iface != "" {
+		if ip := net.ParseIP(iface); ip != nil {
+			install.Spec.CalicoNetwork.NodeAddressAutodetectionV4 = &operatorv1.NodeAddressAutodetection{CIDRS: []string{iface + "/32"}}
+		} else {
+			install.Spec.CalicoNetwork.NodeAddressAutodetectionV4 = &operatorv1.NodeAddressAutodetection{Interface: iface}
+		}
+	}
+
+	if install.Spec.CalicoNetwork.IPPools == nil {
+		install.Spec.CalicoNetwork.IPPools = []operatorv1.IPPool{pool}
+	}
+
+	// CNI portmap plugin
+	hp := operatorv1.HostPortsDisabled
+	if _, ok := c.cni.Plugins["portmap"]; ok {
+		hp = operatorv1.HostPortsEnabled
+	}
+	install.Spec.CalicoNetwork.HostPorts = &hp
+
+	if pool.Encapsulation != operatorv1.EncapsulationNone {
+		setFlannelVXLANOverrides(install, netConf.Backend)
+	}
+	return nil
+}
+
+// getFlannelArgs returns whether flannel masquerades traffic leaving the pod network and the interface it uses for
+// traffic between nodes. These are read from the flanneld arguments and the equivalent FLANNELD_* env vars.
+func getFlannelArgs(c *components) (bool, string, error) {
+	container := getContainer(c.node.Spec.Template.Spec, containerFlannel)
+	if container == nil {
+		return false, "", ErrIncompatibleCluster{
+			err:       fmt.Sprintf("couldn't find the %s container", containerFlannel),
+			component: ComponentFlannel,
+			fix:       fmt.Sprintf("restore the %s container if you've renamed or removed it", containerFlannel),
+		}
+	}
+
+	var masq bool
+	var iface string
+	for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+		switch {
+		case !strings.HasPrefix(arg, "--"):
+			// The flanneld binary.
+		case arg == "--ip-masq" || arg == "--ip-masq=true":
+			masq = true
+		case arg == "--ip-masq=false", arg == "--kube-subnet-mgr", arg == "--kube-subnet-mgr=true":
+		case strings.HasPrefix(arg, "--iface="):
+			iface = strings.TrimPrefix(arg, "--iface=")
+		default:
+			return false, "", ErrIncompatibleCluster{
+				err:       fmt.Sprintf("unexpected flanneld argument '%s'", arg),
+				component: ComponentFlannel,
+				fix:       "remove the argument from the flannel container",
+			}
+		}
+	}
+
+	ipMasq, err := c.node.getEnv(ctx, c.client, containerFlannel, "FLANNELD_IP_MASQ")
+	if err != nil {
+		return false, "", err
+	}
+	if ipMasq != nil {
+		masq = strings.ToLower(*ipMasq) == "true"
+	}
+	ifaceEnv, err := c.node.getEnv(ctx, c.client, containerFlannel, "FLANNELD_IFACE")
+	if err != nil {
+		return false, "", err
+	}
+	if ifaceEnv != nil && *ifaceEnv != "" {
+		iface = *ifaceEnv
+	}
+
+	// These are only used by flannel itself.
+	c.node.ignoreEnv(containerFlannel, "POD_NAME")
+	c.node.ignoreEnv(containerFlannel, "POD_NAMESPACE")
+	c.node.ignoreEnv(containerFlannel, "EVENT_QUEUE_DEPTH")
+	c.node.ignoreEnv(containerFlannel, "CONT_WHEN_CACHE_NOT_READY")
+
+	return masq, iface, nil
+}
+
+// setFlannelVXLANOverrides sets Felix's VXLAN VNI and port to those of flannel's VXLAN network in the Installation, so
+// that the VXLAN traffic of migrated and unmigrated nodes stays compatible. They are written to the FelixConfiguration
+// by the core controller once the Calico CRDs are installed.
+func setFlannelVXLANOverrides(install *operatorv1.Installation, backend flannelBackend) {
+	vni, port := defaultFlannelVXLANVNI, defaultFlannelVXLANPort
+	if backend.VNI != 0 {
+		vni = backend.VNI
+	}
+	if backend.Port != 0 {
+		port = backend.Port
+	}
+	if install.Spec.FelixOverrides == nil {
+		install.Spec.FelixOverrides = &operatorv1.FelixOverrides{}
+	}
+	if install.Spec.FelixOverrides.Settings == nil {
+		install.Spec.FelixOverrides.Settings = map[string]string{}
+	}
+	install.Spec.FelixOverrides.Settings["vxlanVNI"] = strconv.Itoa(vni)
+	install.Spec.FelixOverrides.Settings["vxlanPort"] = strconv.Itoa(port)
+}
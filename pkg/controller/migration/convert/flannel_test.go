@@ -0,0 +1,300 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
+)
+
+const canalCNIConfig = `{
+  "name": "k8s-pod-network",
+  "cniVersion": "0.3.1",
+  "plugins": [
+    {
+      "type": "calico",
+      "log_level": "info",
+      "datastore_type": "kubernetes",
+      "nodename": "__KUBERNETES_NODE_NAME__",
+      "mtu": __CNI_MTU__,
+      "ipam": {"type": "host-local", "subnet": "usePodCidr"},
+      "policy": {"type": "k8s"},
+      "kubernetes": {"kubeconfig": "__KUBECONFIG_FILEPATH__"}
+    },
+    {"type": "portmap", "snat": true, "capabilities": {"portMappings": true}},
+    {"type": "bandwidth", "capabilities": {"bandwidth": true}}
+  ]
+}`
+
+const flannelCNIConfig = `{
+  "name": "cbr0",
+  "cniVersion": "0.3.1",
+  "plugins": [
+    {"type": "flannel", "delegate": {"hairpinMode": true, "isDefaultGateway": true}},
+    {"type": "portmap", "capabilities": {"portMappings": true}}
+  ]
+}`
+
+func canalDaemonSet() *appsv1.DaemonSet {
+	ds := emptyNodeSpec()
+	ds.Name = "canal"
+	ds.Spec.Template.Spec.InitContainers[0].Env = []corev1.EnvVar{
+		{Name: "CNI_CONF_NAME", Value: "10-canal.conflist"},
+		{Name: "CNI_NETWORK_CONFIG", ValueFrom: configMapRef("canal-config", "cni_network_config")},
+		{Name: "CNI_MTU", ValueFrom: configMapRef("canal-config", "veth_mtu")},
+		{Name: "SLEEP", Value: "false"},
+	}
+	ds.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: "DATASTORE_TYPE", Value: "kubernetes"},
+		{Name: "WAIT_FOR_DATASTORE", Value: "true"},
+		{Name: "CALICO_NETWORKING_BACKEND", Value: "none"},
+		{Name: "CLUSTER_TYPE", Value: "k8s,canal"},
+		{Name: "FELIX_IPTABLESREFRESHINTERVAL", Value: "60"},
+		{Name: "IP", Value: ""},
+		{Name: "CALICO_DISABLE_FILE_LOGGING", Value: "true"},
+		{Name: "FELIX_DEFAULTENDPOINTTOHOSTACTION", Value: "ACCEPT"},
+		{Name: "FELIX_IPV6SUPPORT", Value: "false"},
+		{Name: "FELIX_HEALTHENABLED", Value: "true"},
+	}
+	ds.Spec.Template.Spec.Containers = append(ds.Spec.Template.Spec.Containers, corev1.Container{
+		Name:    "kube-flannel",
+		Command: []string{"/opt/bin/flanneld", "--ip-masq", "--kube-subnet-mgr"},
+		Env: []corev1.EnvVar{
+			{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			{Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+			{Name: "FLANNELD_IFACE", ValueFrom: configMapRef("canal-config", "canal_iface")},
+			{Name: "FLANNELD_IP_MASQ", ValueFrom: configMapRef("canal-config", "masquerade")},
+		},
+	})
+	ds.Spec.Template.Spec.Volumes = append(ds.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "flannel-cfg",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "canal-config"}},
+		},
+	})
+	return ds
+}
+
+func canalConfigMap(netConf string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: "canal-config", Namespace: "kube-system"},
+		Data: map[string]string{
+			"canal_iface":        "",
+			"masquerade":         "true",
+			"veth_mtu":           "1450",
+			"cni_network_config": canalCNIConfig,
+			"net-conf.json":      netConf,
+		},
+	}
+}
+
+func flannelDS() *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: v1.ObjectMeta{Name: "kube-flannel-ds", Namespace: "kube-flannel"},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "kube-flannel",
+						Command: []string{"/opt/bin/flanneld"},
+						Args:    []string{"--ip-masq", "--kube-subnet-mgr", "--iface=eth1"},
+						Env: []corev1.EnvVar{
+							{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+							{Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+							{Name: "EVENT_QUEUE_DEPTH", Value: "5000"},
+						},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "flannel-cfg",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "kube-flannel-cfg"}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func flannelConfigMap(netConf string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: "kube-flannel-cfg", Namespace: "kube-flannel"},
+		Data: map[string]string{
+			"cni-conf.json": flannelCNIConfig,
+			"net-conf.json": netConf,
+		},
+	}
+}
+
+func configMapRef(name, key string) *corev1.EnvVarSource {
+	return &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}}
+}
+
+var _ = Describe("canal and flannel conversion", func() {
+	var ctx = context.Background()
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = kscheme.Scheme
+		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
+	})
+
+	getFelixConfig := func(c client.Client) *crdv1.FelixConfiguration {
+		fc := &crdv1.FelixConfiguration{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, fc)).NotTo(HaveOccurred())
+		return fc
+	}
+
+	It("should convert a canal installation using the vxlan backend", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(
+			canalDaemonSet(),
+			canalConfigMap(`{"Network": "10.244.0.0/16", "Backend": {"Type": "vxlan"}}`),
+			emptyFelixConfig(),
+		).Build()
+
+		Expect(NeedsConversion(ctx, c)).To(BeTrue())
+		install, err := Convert(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(install.Spec.CNI.Type).To(Equal(operatorv1.PluginCalico))
+		Expect(install.Spec.CNI.IPAM.Type).To(Equal(operatorv1.IPAMPluginHostLocal))
+		Expect(*install.Spec.CalicoNetwork.BGP).To(Equal(operatorv1.BGPDisabled))
+		Expect(*install.Spec.CalicoNetwork.MTU).To(BeEquivalentTo(1450))
+		Expect(*install.Spec.CalicoNetwork.HostPorts).To(Equal(operatorv1.HostPortsEnabled))
+		Expect(install.Spec.CalicoNetwork.NodeAddressAutodetectionV4).To(BeNil())
+		Expect(install.Spec.CalicoNetwork.IPPools).To(Equal([]operatorv1.IPPool{{
+			CIDR:          "10.244.0.0/16",
+			Encapsulation: operatorv1.EncapsulationVXLAN,
+			NATOutgoing:   operatorv1.NATOutgoingEnabled,
+			NodeSelector:  "all()",
+		}}))
+
+		Expect(install.Spec.FelixOverrides.Settings).To(Equal(map[string]string{"vxlanVNI": "1", "vxlanPort": "8472"}))
+
+		fc := getFelixConfig(c)
+		Expect(fc.Spec.VXLANVNI).To(BeNil())
+		Expect(fc.Spec.VXLANPort).To(BeNil())
+		Expect(fc.Spec.IptablesRefreshInterval).NotTo(BeNil())
+	})
+
+	It("should reject canal settings that are not supported", func() {
+		ds := canalDaemonSet()
+		ds.Spec.Template.Spec.Containers[0].Env = append(ds.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "FOO", Value: "bar"})
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(
+			ds,
+			canalConfigMap(`{"Network": "10.244.0.0/16", "Backend": {"Type": "vxlan"}}`),
+			emptyFelixConfig(),
+		).Build()
+
+		_, err := Convert(ctx, c)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("calico-node/FOO"))
+	})
+
+	It("should error if the canal flannel configuration is missing", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(canalDaemonSet(), emptyFelixConfig()).Build()
+		_, err := Convert(ctx, c)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should convert a flannel installation using the host-gw backend", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(
+			flannelDS(),
+			flannelConfigMap(`{"Network": "10.244.0.0/16", "Backend": {"Type": "host-gw"}}`),
+		).Build()
+
+		install, err := Convert(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(install.Spec.CNI.IPAM.Type).To(Equal(operatorv1.IPAMPluginHostLocal))
+		Expect(*install.Spec.CalicoNetwork.BGP).To(Equal(operatorv1.BGPEnabled))
+		Expect(*install.Spec.CalicoNetwork.HostPorts).To(Equal(operatorv1.HostPortsEnabled))
+		Expect(install.Spec.CalicoNetwork.NodeAddressAutodetectionV4).To(Equal(&operatorv1.NodeAddressAutodetection{Interface: "eth1"}))
+		Expect(install.Spec.CalicoNetwork.IPPools).To(Equal([]operatorv1.IPPool{{
+			CIDR:          "10.244.0.0/16",
+			Encapsulation: operatorv1.EncapsulationNone,
+			NATOutgoing:   operatorv1.NATOutgoingEnabled,
+			NodeSelector:  "all()",
+		}}))
+	})
+
+	It("should carry the flannel VXLAN VNI and port forward", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(
+			flannelDS(),
+			flannelConfigMap(`{"Network": "10.244.0.0/16", "Backend": {"Type": "vxlan", "VNI": 4096, "Port": 4789, "DirectRouting": true}}`),
+		).Build()
+
+		install, err := Convert(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(install.Spec.CalicoNetwork.IPPools[0].Encapsulation).To(Equal(operatorv1.EncapsulationVXLANCrossSubnet))
+		Expect(install.Spec.FelixOverrides.Settings).To(Equal(map[string]string{"vxlanVNI": "4096", "vxlanPort": "4789"}))
+
+		// Conversion must not write to the cluster, which may not have the Calico CRDs yet.
+		err = c.Get(ctx, types.NamespacedName{Name: "default"}, &crdv1.FelixConfiguration{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should reject unsupported flannel backends", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(
+			flannelDS(),
+			flannelConfigMap(`{"Network": "10.244.0.0/16", "Backend": {"Type": "udp"}}`),
+		).Build()
+
+		_, err := Convert(ctx, c)
+		Expect(err).To(BeAssignableToTypeOf(ErrIncompatibleCluster{}))
+	})
+
+	It("should reject IPv6 flannel networks", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(
+			flannelDS(),
+			flannelConfigMap(`{"Network": "10.244.0.0/16", "EnableIPv6": true, "IPv6Network": "fd00::/56", "Backend": {"Type": "vxlan"}}`),
+		).Build()
+
+		_, err := Convert(ctx, c)
+		Expect(err).To(BeAssignableToTypeOf(ErrIncompatibleCluster{}))
+	})
+
+	It("should reject unexpected flanneld arguments", func() {
+		ds := flannelDS()
+		ds.Spec.Template.Spec.Containers[0].Args = append(ds.Spec.Template.Spec.Containers[0].Args, "--public-ip=10.0.0.1")
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(
+			ds,
+			flannelConfigMap(`{"Network": "10.244.0.0/16", "Backend": {"Type": "vxlan"}}`),
+		).Build()
+
+		_, err := Convert(ctx, c)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("--public-ip"))
+	})
+})
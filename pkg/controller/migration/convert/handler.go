@@ -41,3 +41,27 @@ var handlers = []handler{
 	handleBPF,
 	handleNftables,
 }
+
+// canalHandlers convert a canal installation. calico-node is checked the same way as for Calico, while the pod
+// network is converted from the flannel configuration instead of the Calico CNI config and IP pools.
+var canalHandlers = []handler{
+	checkTypha,
+	handleAddonManager,
+	handleNetwork,
+	handleCore,
+	handleCanal,
+	handleAnnotations,
+	handleNodeSelectors,
+	handleFelixNodeMetrics,
+	handleTyphaMetrics,
+	handleFlannel,
+	handleMTU,
+	handleBPF,
+	handleNftables,
+}
+
+// flannelHandlers convert a flannel installation, which has no calico-node to check.
+var flannelHandlers = []handler{
+	handleAddonManager,
+	handleFlannel,
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReportConfigMapName is the name of the ConfigMap in the operator namespace that holds the result of the
+	// last migration preflight.
+	ReportConfigMapName = "calico-migration-report"
+	// ReportKey is the ConfigMap key holding the JSON encoded Report.
+	ReportKey = "report.json"
+)
+
+// Report lists every incompatibility found in an existing Calico installation.
+type Report struct {
+	// Compatible is true if the existing installation can be converted as is.
+	Compatible bool `json:"compatible"`
+
+	// Incompatibilities found in the existing installation, in the order they were detected.
+	Incompatibilities []Incompatibility `json:"incompatibilities,omitempty"`
+}
+
+// Incompatibility is a single config option in the existing installation which prevents migration.
+type Incompatibility struct {
+	// Component which has the incompatible config.
+	Component string `json:"component"`
+
+	// Error describes the incompatible config.
+	Error string `json:"error"`
+
+	// Fix explains what the user can do, if anything, to continue the migration.
+	Fix string `json:"fix,omitempty"`
+
+	// AutoFixable is true if the fix only removes config that has no effect on the cluster, such as annotations or
+	// unused volumes, so that it can be applied without changing how the cluster behaves.
+	AutoFixable bool `json:"autoFixable"`
+}
+
+// String returns a human readable summary of the report.
+func (r *Report) String() string {
+	if r.Compatible {
+		return "The existing Calico installation can be migrated."
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Found %d incompatibilities in the existing Calico installation:\n", len(r.Incompatibilities))
+	for _, i := range r.Incompatibilities {
+		fmt.Fprintf(&sb, "- %s: %s", i.Component, i.Error)
+		if i.AutoFixable {
+			sb.WriteString(" (auto-fixable)")
+		}
+		sb.WriteString("\n")
+		if i.Fix != "" {
+			fmt.Fprintf(&sb, "  To fix it, %s\n", i.Fix)
+		}
+	}
+	return sb.String()
+}
+
+// Preflight checks an existing Calico install (i.e. one that is not managed by operator) the same way Convert does,
+// but keeps going after an incompatibility is found so that all of them are reported at once. Unlike Convert, it does
+// not write anything to the cluster. A nil Report is returned if there is no existing install. Errors other than an ErrIncompatibleCluster are returned as is.
+func Preflight(ctx context.Context, client client.Client) (*Report, error) {
+	comps, err := getComponents(ctx, client)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if comps == nil {
+		return nil, nil
+	}
+	comps.readOnly = true
+
+	// Handlers build on the config set by earlier handlers, which may have stopped part way through.
+	install := &operatorv1.Installation{
+		Spec: operatorv1.InstallationSpec{
+			CNI:           &operatorv1.CNISpec{IPAM: &operatorv1.IPAMSpec{}},
+			CalicoNetwork: &operatorv1.CalicoNetworkSpec{},
+		},
+	}
+
+	report := &Report{}
+	err = runHandlers(comps, install, func(err ErrIncompatibleCluster) error {
+		report.Incompatibilities = append(report.Incompatibilities, Incompatibility{
+			Component:   err.component,
+			Error:       err.err,
+			Fix:         err.fix,
+			AutoFixable: err.autoFixable,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Compatible = len(report.Incompatibilities) == 0
+	return report, nil
+}
+
+// PublishReport writes the report to the ReportConfigMapName ConfigMap in the operator namespace.
+func PublishReport(ctx context.Context, cli client.Client, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = cli.Get(ctx, types.NamespacedName{Name: ReportConfigMapName, Namespace: common.OperatorNamespace()}, cm)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ReportConfigMapName, Namespace: common.OperatorNamespace()},
+			Data:       map[string]string{ReportKey: string(data)},
+		}
+		return cli.Create(ctx, cm)
+	}
+	cm.Data = map[string]string{ReportKey: string(data)}
+	return cli.Update(ctx, cm)
+}
+
+// DeleteReport removes the report ConfigMap once it no longer applies.
+func DeleteReport(ctx context.Context, cli client.Client) error {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ReportConfigMapName, Namespace: common.OperatorNamespace()}}
+	if err := cli.Delete(ctx, cm); err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("Preflight", func() {
+	var ctx = context.Background()
+	var pool *crdv1.IPPool
+	var scheme *runtime.Scheme
+	BeforeEach(func() {
+		scheme = kscheme.Scheme
+		err := apis.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		pool = crdv1.NewIPPool()
+		pool.Spec = crdv1.IPPoolSpec{
+			CIDR:        "192.168.4.0/24",
+			IPIPMode:    crdv1.IPIPModeAlways,
+			NATOutgoing: true,
+		}
+	})
+
+	It("should return no report if there is no existing installation", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).Build()
+		report, err := Preflight(ctx, c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report).To(BeNil())
+	})
+
+	It("should report a valid installation as compatible", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(emptyNodeSpec(), emptyKubeControllerSpec(), pool, emptyFelixConfig()).Build()
+		report, err := Preflight(ctx, c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Compatible).To(BeTrue())
+		Expect(report.Incompatibilities).To(BeEmpty())
+	})
+
+	It("should report every incompatibility instead of stopping at the first", func() {
+		node := emptyNodeSpec()
+		node.Annotations = map[string]string{"foo": "bar"}
+		node.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+			{Name: "FELIX_DEFAULTENDPOINTTOHOSTACTION", Value: "drop"},
+			{Name: "FOO", Value: "bar"},
+		}
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(node, emptyKubeControllerSpec(), pool, emptyFelixConfig()).Build()
+
+		_, err := Convert(ctx, c)
+		Expect(err).To(HaveOccurred())
+
+		report, err := Preflight(ctx, c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Compatible).To(BeFalse())
+		Expect(report.Incompatibilities).To(HaveLen(3))
+
+		Expect(report.Incompatibilities[0].Component).To(Equal(ComponentCalicoNode))
+		Expect(report.Incompatibilities[0].Error).To(ContainSubstring("FELIX_DEFAULTENDPOINTTOHOSTACTION"))
+		Expect(report.Incompatibilities[0].AutoFixable).To(BeFalse())
+
+		Expect(report.Incompatibilities[1].Error).To(ContainSubstring("unexpected annotation"))
+		Expect(report.Incompatibilities[1].Fix).ToNot(BeEmpty())
+		Expect(report.Incompatibilities[1].AutoFixable).To(BeTrue())
+
+		Expect(report.Incompatibilities[2].Error).To(ContainSubstring("FOO"))
+		Expect(report.Incompatibilities[2].AutoFixable).To(BeFalse())
+	})
+
+	It("should not write to the cluster", func() {
+		node := emptyNodeSpec()
+		node.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+			{Name: "FELIX_BPFENABLED", Value: "true"},
+			{Name: "FELIX_IPTABLESREFRESHINTERVAL", Value: "60"},
+		}
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(node, emptyKubeControllerSpec(), pool, emptyFelixConfig(), endPointCM).Build()
+
+		report, err := Preflight(ctx, c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Compatible).To(BeTrue())
+
+		fc := &crdv1.FelixConfiguration{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: "default"}, fc)).To(Succeed())
+		Expect(fc.Spec.IptablesRefreshInterval).To(BeNil())
+		cm := &corev1.ConfigMap{}
+		err = c.Get(ctx, types.NamespacedName{Name: cmName, Namespace: common.OperatorNamespace()}, cm)
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should report an unused flexvol volume as auto-fixable", func() {
+		node := emptyNodeSpec()
+		node.Spec.Template.Spec.Volumes = append(node.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name:         "flexvol-driver-host",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/usr/libexec/kubernetes/kubelet-plugins/volume/exec/nodeagent~uds"}},
+		})
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).WithObjects(node, emptyKubeControllerSpec(), pool, emptyFelixConfig()).Build()
+
+		report, err := Preflight(ctx, c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Incompatibilities).To(HaveLen(1))
+		Expect(report.Incompatibilities[0].Error).To(ContainSubstring("no 'flexvol-driver' init container"))
+		Expect(report.Incompatibilities[0].AutoFixable).To(BeTrue())
+	})
+
+	It("should publish and delete the report", func() {
+		c := ctrlrfake.DefaultFakeClientBuilder(scheme).Build()
+		report := &Report{Incompatibilities: []Incompatibility{{Component: ComponentCalicoNode, Error: "unexpected annotation", AutoFixable: true}}}
+		key := types.NamespacedName{Name: ReportConfigMapName, Namespace: common.OperatorNamespace()}
+
+		Expect(PublishReport(ctx, c, report)).To(Succeed())
+		report.Compatible = true
+		report.Incompatibilities = nil
+		Expect(PublishReport(ctx, c, report)).To(Succeed())
+
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, key, cm)).To(Succeed())
+		published := &Report{}
+		Expect(json.Unmarshal([]byte(cm.Data[ReportKey]), published)).To(Succeed())
+		Expect(published).To(Equal(report))
+
+		Expect(DeleteReport(ctx, c)).To(Succeed())
+		Expect(kerrors.IsNotFound(c.Get(ctx, key, cm))).To(BeTrue())
+		Expect(DeleteReport(ctx, c)).To(Succeed())
+	})
+})
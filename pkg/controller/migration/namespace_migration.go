@@ -61,12 +61,37 @@ const (
 	k8sServicesEndpointConfigMap = "kubernetes-services-endpoint"
 
 	defaultMaxUnavailable int32 = 1
+
+	// nodeMigrationTimeout is how long calico-node may take to become ready on a node after the node has been
+	// migrated before the node is rolled back.
+	nodeMigrationTimeout = 5 * time.Minute
 )
 
 var (
 	migratedNodeLabel = map[string]string{nodeSelectorKey: nodeSelectorValuePost}
 )
 
+// legacyNodeDaemonSet identifies a DaemonSet that networks the nodes which have not been migrated to the operator
+// managed calico-node yet.
+type legacyNodeDaemonSet struct {
+	namespace string
+	name      string
+
+	// flannel is set for DaemonSets that run flannel, whose VXLAN device must be removed from a node before
+	// calico-node can take over flannel's VXLAN network on it.
+	flannel bool
+}
+
+// legacyNodeDaemonSets are the DaemonSets of the manifest based installations that can be migrated: kube-system
+// Calico, canal and flannel.
+var legacyNodeDaemonSets = []legacyNodeDaemonSet{
+	{namespace: kubeSystem, name: nodeDaemonSetName},
+	{namespace: kubeSystem, name: "canal", flannel: true},
+	{namespace: kubeSystem, name: "canal-node", flannel: true},
+	{namespace: "kube-flannel", name: "kube-flannel-ds", flannel: true},
+	{namespace: kubeSystem, name: "kube-flannel-ds", flannel: true},
+}
+
 type NamespaceMigration interface {
 	NeedsCoreNamespaceMigration(ctx context.Context) (bool, error)
 	Run(ctx context.Context, log logr.Logger) error
@@ -83,20 +108,21 @@ type CoreNamespaceMigration struct {
 }
 
 // NeedsCoreNamespaceMigration returns true if any components still exist in
-// the kube-system namespace.
+// the kube-system namespace, or if the cluster still runs canal or flannel.
 // It checks the following in the kube-system namespace:
 // calico-kube-controllers deployment, typha deployment, or calico-node deployment
+// and the canal and flannel DaemonSets.
 func (m *CoreNamespaceMigration) NeedsCoreNamespaceMigration(ctx context.Context) (bool, error) {
 	if m.migrationComplete {
 		return false, nil
 	}
 
-	nds, err := m.client.AppsV1().DaemonSets(kubeSystem).Get(ctx, nodeDaemonSetName, metav1.GetOptions{})
-	if err == nil && nds != nil && nds.DeletionTimestamp == nil {
-		return true, nil
+	legacy, err := m.getLegacyNodeDaemonSets(ctx)
+	if err != nil {
+		return false, err
 	}
-	if !apierrs.IsNotFound(err) {
-		return false, fmt.Errorf("failed to get daemonset %s in kube-system: %s", nodeDaemonSetName, err)
+	if len(legacy) > 0 {
+		return true, nil
 	}
 
 	kcdeploy, err := m.client.AppsV1().Deployments(kubeSystem).Get(ctx, kubeControllerDeploymentName, metav1.GetOptions{})
@@ -275,10 +301,12 @@ func (m *CoreNamespaceMigration) Run(ctx context.Context, log logr.Logger) error
 		return fmt.Errorf("failed to label unmigrated nodes: %s", err.Error())
 	}
 	log.V(1).Info("All unmigrated nodes labeled")
-	if err := m.ensureKubeSysNodeDaemonSetHasNodeSelectorAndIsReady(ctx, log); err != nil {
-		return fmt.Errorf("the kube-system node DaemonSet is not ready with the updated nodeSelector: %s", err.Error())
+	for _, l := range legacyNodeDaemonSets {
+		if err := m.ensureLegacyNodeDaemonSetHasNodeSelectorAndIsReady(ctx, l, log); err != nil {
+			return fmt.Errorf("the %s/%s DaemonSet is not ready with the updated nodeSelector: %s", l.namespace, l.name, err.Error())
+		}
 	}
-	log.V(1).Info("Node selector added to kube-system node DaemonSet")
+	log.V(1).Info("Node selector added to the previous node DaemonSets")
 	if err := m.ensureTyphaRoom(ctx, log); err != nil {
 		return fmt.Errorf("unable to ensure room for enough typhas: %s", err.Error())
 	}
@@ -296,10 +324,10 @@ func (m *CoreNamespaceMigration) Run(ctx context.Context, log logr.Logger) error
 		return fmt.Errorf("failed to wait for calico-node daemonset to be ready: %s", err.Error())
 	}
 	log.V(1).Info("calico-system/calico-node daemonset has been rolled out successfully")
-	if err := m.deleteKubeSystemCalicoNode(ctx); err != nil {
-		return fmt.Errorf("failed to delete kube-system node DaemonSet: %s", err.Error())
+	if err := m.deleteLegacyNodeDaemonSets(ctx); err != nil {
+		return fmt.Errorf("failed to delete the previous node DaemonSets: %s", err.Error())
 	}
-	log.V(1).Info("kube-system node DaemonSet deleted")
+	log.V(1).Info("Previous node DaemonSets deleted")
 	if err := m.deleteKubeSystemTypha(ctx); err != nil {
 		return fmt.Errorf("failed to delete kube-system typha Deployment: %s", err.Error())
 	}
@@ -455,16 +483,35 @@ func (m *CoreNamespaceMigration) deleteKubeSystemTypha(ctx context.Context) erro
 	return nil
 }
 
-// deleteKubeSystemCalicoNode deletes the calico-node daemonset
-// in the kube-system namespace
-func (m *CoreNamespaceMigration) deleteKubeSystemCalicoNode(ctx context.Context) error {
-	err := m.client.AppsV1().DaemonSets(kubeSystem).Delete(ctx, nodeDaemonSetName, metav1.DeleteOptions{})
-	if err != nil && !apierrs.IsNotFound(err) {
-		return err
+// deleteLegacyNodeDaemonSets deletes the kube-system calico-node, canal and flannel daemonsets.
+func (m *CoreNamespaceMigration) deleteLegacyNodeDaemonSets(ctx context.Context) error {
+	for _, l := range legacyNodeDaemonSets {
+		err := m.client.AppsV1().DaemonSets(l.namespace).Delete(ctx, l.name, metav1.DeleteOptions{})
+		if err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
 	}
 	return nil
 }
 
+// getLegacyNodeDaemonSets returns the legacy node DaemonSets that exist in the cluster and are not being deleted.
+func (m *CoreNamespaceMigration) getLegacyNodeDaemonSets(ctx context.Context) ([]legacyNodeDaemonSet, error) {
+	var found []legacyNodeDaemonSet
+	for _, l := range legacyNodeDaemonSets {
+		ds, err := m.client.AppsV1().DaemonSets(l.namespace).Get(ctx, l.name, metav1.GetOptions{})
+		if err != nil {
+			if apierrs.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get daemonset %s in %s: %s", l.name, l.namespace, err)
+		}
+		if ds.DeletionTimestamp == nil {
+			found = append(found, l)
+		}
+	}
+	return found, nil
+}
+
 // waitForOperatorTyphaDeploymentReady waits until the 'new' typha deployment in
 // the calico-system namespace is ready before continuing, it will wait up to
 // 10 minutes before returning with an error.
@@ -518,20 +565,23 @@ func (m *CoreNamespaceMigration) removeNodeMigrationLabelFromNodes(ctx context.C
 	return nil
 }
 
-// ensureKubeSysNodeDaemonSetHasNodeSelectorAndIsReady updates the calico-node DaemonSet in the
-// kube-system namespace with a node selector that will prevent it from being
-// deployed to nodes that have been migrated and waits for the daemonset to update.
-func (m *CoreNamespaceMigration) ensureKubeSysNodeDaemonSetHasNodeSelectorAndIsReady(ctx context.Context, log logr.Logger) error {
+// ensureLegacyNodeDaemonSetHasNodeSelectorAndIsReady updates the given legacy node DaemonSet with a node selector
+// that will prevent it from being deployed to nodes that have been migrated and waits for the daemonset to update.
+// DaemonSets that do not exist are skipped.
+func (m *CoreNamespaceMigration) ensureLegacyNodeDaemonSetHasNodeSelectorAndIsReady(ctx context.Context, l legacyNodeDaemonSet, log logr.Logger) error {
 	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 10*time.Minute, true, func(ctx context.Context) (bool, error) {
-		ds, err := m.client.AppsV1().DaemonSets(kubeSystem).Get(ctx, nodeDaemonSetName, metav1.GetOptions{})
+		ds, err := m.client.AppsV1().DaemonSets(l.namespace).Get(ctx, l.name, metav1.GetOptions{})
 		if err != nil {
+			if apierrs.IsNotFound(err) {
+				return true, nil
+			}
 			return false, err
 		}
 		if ds.Spec.Template.Spec.NodeSelector == nil {
 			ds.Spec.Template.Spec.NodeSelector = make(map[string]string)
 		}
 
-		err = m.addNodeSelectorToDaemonSet(ctx, ds, kubeSystem, nodeSelectorKey, nodeSelectorValuePre, log)
+		err = m.addNodeSelectorToDaemonSet(ctx, ds, l.namespace, nodeSelectorKey, nodeSelectorValuePre, log)
 		if err != nil {
 			if apierrs.IsConflict(err) {
 				// Retry on update conflicts.
@@ -540,8 +590,8 @@ func (m *CoreNamespaceMigration) ensureKubeSysNodeDaemonSetHasNodeSelectorAndIsR
 			return false, err
 		}
 
-		// Get latest kube-system node ds.
-		ds, err = m.client.AppsV1().DaemonSets(kubeSystem).Get(ctx, nodeDaemonSetName, metav1.GetOptions{})
+		// Get latest legacy node ds.
+		ds, err = m.client.AppsV1().DaemonSets(l.namespace).Get(ctx, l.name, metav1.GetOptions{})
 		if err != nil {
 			return false, err
 		}
@@ -550,10 +600,10 @@ func (m *CoreNamespaceMigration) ensureKubeSysNodeDaemonSetHasNodeSelectorAndIsR
 			return false, nil
 		}
 		if ds.Status.DesiredNumberScheduled != ds.Status.NumberReady {
-			log.Info(fmt.Sprintf("waiting for kube-system/calico-node to have %d replicas, currently at %d", ds.Status.DesiredNumberScheduled, ds.Status.NumberReady))
+			log.Info(fmt.Sprintf("waiting for %s/%s to have %d replicas, currently at %d", l.namespace, l.name, ds.Status.DesiredNumberScheduled, ds.Status.NumberReady))
 			return false, nil
 		}
-		log.Info("All kube-system calico/node pods are now ready after nodeSelector update")
+		log.Info(fmt.Sprintf("All %s/%s pods are now ready after nodeSelector update", l.namespace, l.name))
 
 		// Successful update
 		return true, nil
@@ -587,7 +637,7 @@ func (m *CoreNamespaceMigration) addNodeSelectorToDaemonSet(ctx context.Context,
 		}
 		log.Info(fmt.Sprintf("Patch NodeSelector with: %s", string(patchBytes)))
 
-		_, err := m.client.AppsV1().DaemonSets(kubeSystem).Patch(ctx, ds.Name, types.JSONPatchType, patchBytes, metav1.PatchOptions{})
+		_, err := m.client.AppsV1().DaemonSets(namespace).Patch(ctx, ds.Name, types.JSONPatchType, patchBytes, metav1.PatchOptions{})
 		if err != nil {
 			return err
 		}
@@ -604,6 +654,11 @@ func (m *CoreNamespaceMigration) migrateEachNode(ctx context.Context, log logr.L
 	for len(nodes) > 0 {
 		log.WithValues("count", len(nodes)).Info("nodes to migrate")
 		for i, node := range nodes {
+			// Copy the flannel VTEPs before each node, since flannel publishes them for nodes that join during the
+			// migration too.
+			if err := m.copyFlannelVTEPs(ctx, log); err != nil {
+				return err
+			}
 			// This is to ensure that our new pods are becoming healthy before continuing on.
 			// We only wait up to 3 minutes after switching a node to allow the new pod
 			// to come up. Also if the operator crashed we don't want to continue
@@ -618,6 +673,14 @@ func (m *CoreNamespaceMigration) migrateEachNode(ctx context.Context, log logr.L
 				}
 				// Pause for a little bit to give a chance for the label changes to propagate.
 				time.Sleep(1 * time.Second)
+
+				if err := m.waitForNodeMigrated(ctx, node.Name, log); err != nil {
+					log.WithValues("node.Name", node.Name, "reason", err).Info("calico-node did not become ready, rolling back the node")
+					if rerr := m.rollbackNode(ctx, node.Name, log); rerr != nil {
+						return fmt.Errorf("calico-node did not become ready on node %s (%s) and rolling back the node failed: %s", node.Name, err, rerr)
+					}
+					return fmt.Errorf("calico-node did not become ready on node %s, the node has been rolled back: %s", node.Name, err)
+				}
 			} else {
 				log.WithValues("reason", err).V(1).Info("Failed to check for new healthy pods")
 				time.Sleep(10 * time.Second)
@@ -653,10 +716,17 @@ func (m *CoreNamespaceMigration) getNodesToMigrate() []*v1.Node {
 // daemonsets to make sure we don't simultaneously migrate more pods than allowed.
 func (m *CoreNamespaceMigration) waitUntilNodeCanBeMigrated(ctx context.Context, log logr.Logger) error {
 	return wait.PollUntilContextTimeout(ctx, 1*time.Second, 1*time.Minute, true, func(ctx context.Context) (bool, error) {
-		// num node desired schedule, num node ready, node max unavailable in kube-system
-		ksD, ksR, _, err := m.getNumPodsDesiredAndReady(ctx, kubeSystem, nodeDaemonSetName)
-		if err != nil {
-			return false, err
+		// num node desired schedule, num node ready in the legacy node daemonsets
+		var ksD, ksR int32
+		for _, l := range legacyNodeDaemonSets {
+			d, r, _, err := m.getNumPodsDesiredAndReady(ctx, l.namespace, l.name)
+			if err != nil {
+				if apierrs.IsNotFound(err) {
+					continue
+				}
+				return false, err
+			}
+			ksD, ksR = ksD+d, ksR+r
 		}
 
 		// num node desired schedule, num node ready, node max unavailable in calico-system
@@ -709,9 +779,15 @@ func (m *CoreNamespaceMigration) getNumPodsDesiredAndReady(ctx context.Context,
 		return 0, 0, nil, err
 	}
 
+	// The OnDelete update strategy has no maxUnavailable.
+	var maxUnavailable *intstr.IntOrString
+	if ds.Spec.UpdateStrategy.RollingUpdate != nil {
+		maxUnavailable = ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable
+	}
+
 	return ds.Status.DesiredNumberScheduled,
 		ds.Status.NumberReady,
-		ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable,
+		maxUnavailable,
 		nil
 }
 
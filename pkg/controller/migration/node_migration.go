@@ -0,0 +1,278 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+const (
+	// VXLAN devices created by flannel and calico-node. Both use the same VNI and port after a migration from
+	// flannel, so only one of them can exist on a node at a time.
+	flannelVXLANDevice = "flannel.1"
+	calicoVXLANDevice  = "vxlan.calico"
+
+	removeLinkTimeout = 2 * time.Minute
+
+	// Node annotations through which flannel publishes the VTEP of each node.
+	flannelBackendTypeAnnotation = "flannel.alpha.coreos.com/backend-type"
+	flannelBackendDataAnnotation = "flannel.alpha.coreos.com/backend-data"
+	flannelPublicIPAnnotation    = "flannel.alpha.coreos.com/public-ip"
+
+	// Node annotations from which Calico reads the address and VTEP of each node.
+	calicoIPv4AddressAnnotation     = "projectcalico.org/IPv4Address"
+	calicoVXLANTunnelAddrAnnotation = "projectcalico.org/IPv4VXLANTunnelAddr"
+	calicoVXLANTunnelMACAnnotation  = "projectcalico.org/VXLANTunnelMACAddr"
+)
+
+// waitForNodeMigrated waits for the legacy node pod to leave a node that has just been labeled as migrated and for the
+// operator managed calico-node to become ready on it.
+func (m *CoreNamespaceMigration) waitForNodeMigrated(ctx context.Context, nodeName string, log logr.Logger) error {
+	flannel, err := m.hasLegacyFlannel(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := m.waitForLegacyNodePodsRemoved(ctx, nodeName); err != nil {
+		return fmt.Errorf("the previous node pod was not removed: %s", err)
+	}
+	if flannel {
+		if err := m.removeLinkOnNode(ctx, nodeName, flannelVXLANDevice, log); err != nil {
+			return err
+		}
+	}
+
+	return wait.PollUntilContextTimeout(ctx, 5*time.Second, nodeMigrationTimeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := m.client.CoreV1().Pods(common.CalicoNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "k8s-app=calico-node",
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp == nil && podReady(&pod) {
+				return true, nil
+			}
+		}
+		log.V(1).Info("waiting for calico-node to become ready", "node.Name", nodeName)
+		return false, nil
+	})
+}
+
+// rollbackNode returns a node whose calico-node did not become ready to the legacy node DaemonSet.
+func (m *CoreNamespaceMigration) rollbackNode(ctx context.Context, nodeName string, log logr.Logger) error {
+	if err := m.addNodeLabel(ctx, nodeName, nodeSelectorKey, nodeSelectorValuePre); err != nil {
+		return fmt.Errorf("setting label on node %s failed; %s", nodeName, err)
+	}
+
+	flannel, err := m.hasLegacyFlannel(ctx)
+	if err != nil || !flannel {
+		return err
+	}
+
+	// flannel can only recreate its VXLAN device once calico-node has left the node and its device is removed.
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, nodeMigrationTimeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := m.client.CoreV1().Pods(common.CalicoNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "k8s-app=calico-node",
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+		})
+		if err != nil {
+			return false, err
+		}
+		return len(pods.Items) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("calico-node was not removed from node %s: %s", nodeName, err)
+	}
+	return m.removeLinkOnNode(ctx, nodeName, calicoVXLANDevice, log)
+}
+
+// hasLegacyFlannel returns whether one of the remaining legacy node DaemonSets runs flannel.
+func (m *CoreNamespaceMigration) hasLegacyFlannel(ctx context.Context) (bool, error) {
+	legacy, err := m.getLegacyNodeDaemonSets(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, l := range legacy {
+		if l.flannel {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// copyFlannelVTEPs copies the VTEP that flannel published for each node to the annotations that Calico reads, unless
+// Calico already has them. Felix on the migrated nodes then programs routes to the pods of the nodes that still run
+// flannel, and calico-node takes over flannel's VTEP address and MAC on each node as it is migrated so that flannel
+// on the remaining nodes can still reach it.
+func (m *CoreNamespaceMigration) copyFlannelVTEPs(ctx context.Context, log logr.Logger) error {
+	for _, obj := range m.indexer.List() {
+		node, ok := obj.(*v1.Node)
+		if !ok {
+			return fmt.Errorf("never expected index to have anything other than a Node object: %v", obj)
+		}
+		if node.Annotations[flannelBackendTypeAnnotation] != "vxlan" {
+			continue
+		}
+
+		annotations := map[string]string{}
+		if node.Annotations[calicoVXLANTunnelMACAnnotation] == "" {
+			var data struct {
+				VtepMAC string `json:"VtepMAC"`
+			}
+			if err := json.Unmarshal([]byte(node.Annotations[flannelBackendDataAnnotation]), &data); err != nil {
+				log.WithValues("node.Name", node.Name, "reason", err).Info("Unable to parse the flannel backend data of the node")
+			} else if data.VtepMAC != "" {
+				annotations[calicoVXLANTunnelMACAnnotation] = data.VtepMAC
+			}
+		}
+		// flannel uses the network address of the node's pod CIDR as the address of its VTEP.
+		if node.Annotations[calicoVXLANTunnelAddrAnnotation] == "" && node.Spec.PodCIDR != "" {
+			if _, podCIDR, err := net.ParseCIDR(node.Spec.PodCIDR); err == nil && podCIDR.IP.To4() != nil {
+				annotations[calicoVXLANTunnelAddrAnnotation] = podCIDR.IP.String()
+			}
+		}
+		if node.Annotations[calicoIPv4AddressAnnotation] == "" {
+			if ip := net.ParseIP(node.Annotations[flannelPublicIPAnnotation]); ip != nil && ip.To4() != nil {
+				annotations[calicoIPv4AddressAnnotation] = ip.String() + "/32"
+			}
+		}
+		if len(annotations) == 0 {
+			continue
+		}
+
+		patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+		if err != nil {
+			return err
+		}
+		if _, err := m.client.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to copy the flannel VTEP of node %s: %s", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// waitForLegacyNodePodsRemoved waits until none of the legacy node DaemonSets have a pod on the given node.
+func (m *CoreNamespaceMigration) waitForLegacyNodePodsRemoved(ctx context.Context, nodeName string) error {
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, nodeMigrationTimeout, true, func(ctx context.Context) (bool, error) {
+		for _, l := range legacyNodeDaemonSets {
+			pods, err := m.client.CoreV1().Pods(l.namespace).List(ctx, metav1.ListOptions{
+				FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+			})
+			if err != nil {
+				return false, err
+			}
+			for _, pod := range pods.Items {
+				for _, ref := range pod.OwnerReferences {
+					if ref.Kind == "DaemonSet" && ref.Name == l.name {
+						return false, nil
+					}
+				}
+			}
+		}
+		return true, nil
+	})
+}
+
+// removeLinkOnNode deletes a network device from a node, if it exists, by running a short-lived pod on the node.
+// The pod uses the calico-node image of the operator managed calico-node DaemonSet.
+func (m *CoreNamespaceMigration) removeLinkOnNode(ctx context.Context, nodeName, link string, log logr.Logger) error {
+	ds, err := m.client.AppsV1().DaemonSets(common.CalicoNamespace).Get(ctx, nodeDaemonSetName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	var image string
+	for _, c := range ds.Spec.Template.Spec.Containers {
+		if c.Name == nodeDaemonSetName {
+			image = c.Image
+		}
+	}
+	if image == "" {
+		return fmt.Errorf("failed to find the calico-node image")
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: calicoNodeMigrationName + "-",
+			Namespace:    common.CalicoNamespace,
+			Labels:       map[string]string{"k8s-app": calicoNodeMigrationName},
+		},
+		Spec: v1.PodSpec{
+			NodeName:                     nodeName,
+			HostNetwork:                  true,
+			RestartPolicy:                v1.RestartPolicyNever,
+			AutomountServiceAccountToken: ptr.BoolToPtr(false),
+			Tolerations:                  []v1.Toleration{{Operator: v1.TolerationOpExists}},
+			ImagePullSecrets:             ds.Spec.Template.Spec.ImagePullSecrets,
+			Containers: []v1.Container{{
+				Name:    "remove-link",
+				Image:   image,
+				Command: []string{"/bin/sh", "-c", fmt.Sprintf("if ip link show %[1]s >/dev/null 2>&1; then ip link delete %[1]s; fi", link)},
+				SecurityContext: &v1.SecurityContext{
+					Capabilities: &v1.Capabilities{Add: []v1.Capability{"NET_ADMIN"}},
+				},
+			}},
+		},
+	}
+	pod, err = m.client.CoreV1().Pods(common.CalicoNamespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create pod to remove %s on node %s: %s", link, nodeName, err)
+	}
+	defer func() {
+		if err := m.client.CoreV1().Pods(common.CalicoNamespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+			log.Error(err, "Failed to delete pod", "pod", pod.Name)
+		}
+	}()
+
+	log.Info(fmt.Sprintf("Removing %s from node %s", link, nodeName))
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, removeLinkTimeout, true, func(ctx context.Context) (bool, error) {
+		p, err := m.client.CoreV1().Pods(common.CalicoNamespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch p.Status.Phase {
+		case v1.PodSucceeded:
+			return true, nil
+		case v1.PodFailed:
+			return false, fmt.Errorf("failed to remove %s on node %s", link, nodeName)
+		}
+		return false, nil
+	})
+}
+
+func podReady(pod *v1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nonclusterhost
+
+import (
+	"time"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/render/nonclusterhost"
+)
+
+const defaultEnrollmentTokenTTL = 24 * time.Hour
+
+// hostInventory returns the inventory of the hosts listed in the spec, based on the previous inventory, the
+// HostEndpoints, and the certificate signing requests made by the hosts. It also returns the time until the next
+// pending enrollment token expires, or zero if no token is pending.
+func hostInventory(
+	spec operatorv1.NonClusterHostSpec,
+	previous []operatorv1.NonClusterHostInventory,
+	heps []v3.HostEndpoint,
+	csrs []certificatesv1.CertificateSigningRequest,
+	now time.Time,
+) ([]operatorv1.NonClusterHostInventory, time.Duration) {
+	ttl := defaultEnrollmentTokenTTL
+	if spec.EnrollmentTokenTTL != nil {
+		ttl = spec.EnrollmentTokenTTL.Duration
+	}
+	prev := map[string]operatorv1.NonClusterHostInventory{}
+	for _, h := range previous {
+		prev[h.Hostname] = h
+	}
+
+	var inventory []operatorv1.NonClusterHostInventory
+	var nextExpiry time.Duration
+	for _, host := range spec.Hosts {
+		h, found := prev[host.Hostname]
+		if !found {
+			h = operatorv1.NonClusterHostInventory{Hostname: host.Hostname, State: operatorv1.NonClusterHostPending}
+		}
+
+		h.HostEndpoint, h.Addresses = "", nil
+		for _, hep := range heps {
+			if hep.Spec.Node == host.Hostname {
+				h.HostEndpoint = hep.Name
+				h.Addresses = hep.Spec.ExpectedIPs
+				break
+			}
+		}
+
+		for _, csr := range csrs {
+			if csr.Labels[nonclusterhost.HostnameLabel] != host.Hostname {
+				continue
+			}
+			if !csr.CreationTimestamp.IsZero() && (h.LastSeen == nil || h.LastSeen.Before(&csr.CreationTimestamp)) {
+				seen := csr.CreationTimestamp
+				h.LastSeen = &seen
+			}
+			if h.State == operatorv1.NonClusterHostPending && csr.Spec.Username == nonclusterhost.EnrollmentUsername(host.Hostname) {
+				if approvedAt := approvalTime(&csr); approvedAt != nil {
+					h.State = operatorv1.NonClusterHostEnrolled
+					h.EnrolledAt = approvedAt
+				}
+			}
+		}
+
+		switch {
+		case host.Revoked:
+			h.State = operatorv1.NonClusterHostRevoked
+		case h.State == operatorv1.NonClusterHostRevoked:
+			// The host is no longer revoked. It must enroll again with a new token.
+			h.State = operatorv1.NonClusterHostPending
+			h.TokenExpiry = nil
+		}
+
+		if h.State == operatorv1.NonClusterHostPending {
+			if h.TokenExpiry == nil {
+				expiry := metav1.NewTime(now.Add(ttl))
+				h.TokenExpiry = &expiry
+			}
+			if !now.Before(h.TokenExpiry.Time) {
+				h.State = operatorv1.NonClusterHostExpired
+			} else if remaining := h.TokenExpiry.Sub(now); nextExpiry == 0 || remaining < nextExpiry {
+				nextExpiry = remaining
+			}
+		}
+		if h.State == operatorv1.NonClusterHostPending {
+			h.TokenSecretName = nonclusterhost.EnrollmentName(host.Hostname)
+		} else {
+			h.TokenSecretName = ""
+		}
+		inventory = append(inventory, h)
+	}
+	return inventory, nextExpiry
+}
+
+// approvalTime returns the time at which the CSR was approved, or nil if it has not been approved.
+func approvalTime(csr *certificatesv1.CertificateSigningRequest) *metav1.Time {
+	for _, c := range csr.Status.Conditions {
+		if c.Type == certificatesv1.CertificateApproved && c.Status == corev1.ConditionTrue {
+			t := c.LastUpdateTime
+			if t.IsZero() {
+				t = csr.CreationTimestamp
+			}
+			return &t
+		}
+	}
+	return nil
+}
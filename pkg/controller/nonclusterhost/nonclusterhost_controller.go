@@ -18,7 +18,16 @@ import (
 	"context"
 	"fmt"
 	"net"
-
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -28,10 +37,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/options"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
 	"github.com/tigera/operator/pkg/ctrlruntime"
+	"github.com/tigera/operator/pkg/render"
 	"github.com/tigera/operator/pkg/render/nonclusterhost"
 	"github.com/tigera/operator/pkg/url"
 )
@@ -72,6 +83,14 @@ func add(mgr manager.Manager, c ctrlruntime.Controller) error {
 		return fmt.Errorf("%s failed to watch resource: %w", controllerName, err)
 	}
 
+	// Watch the certificate signing requests of non-cluster hosts, to track enrollment and when the hosts were last seen.
+	if err := utils.AddCSRWatchWithRelevancyFn(c, func(csr *certificatesv1.CertificateSigningRequest) bool {
+		_, ok := csr.Labels[nonclusterhost.HostnameLabel]
+		return ok
+	}); err != nil {
+		return fmt.Errorf("%s failed to watch certificate signing requests: %w", controllerName, err)
+	}
+
 	return nil
 }
 
@@ -84,6 +103,7 @@ type ReconcileNonClusterHost struct {
 }
 
 func (r *ReconcileNonClusterHost) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	logc := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	logc.Info("Reconciling NonClusterHost")
 
@@ -98,7 +118,7 @@ func (r *ReconcileNonClusterHost) Reconcile(ctx context.Context, request reconci
 
 	logc.V(2).Info("Loaded config", "config", instance)
 	r.status.OnCRFound()
-	defer r.status.SetMetaData(&instance.ObjectMeta)
+	defer r.status.SetMetaData(instance)
 
 	// Validate endpoint fields
 	_, _, _, err = url.ParseEndpoint(instance.Spec.Endpoint)
@@ -116,6 +136,11 @@ func (r *ReconcileNonClusterHost) Reconcile(ctx context.Context, request reconci
 	config := &nonclusterhost.Config{
 		NonClusterHost: instance.Spec,
 	}
+	inventory, nextExpiry, err := r.enrollment(ctx, instance, config)
+	if err != nil {
+		r.status.SetDegraded(operatorv1.ResourceReadError, "Failed to query the enrollment state of non-cluster hosts", err, logc)
+		return reconcile.Result{}, err
+	}
 	component := nonclusterhost.NonClusterHost(config)
 
 	ch := utils.NewComponentHandler(logc, r.client, r.scheme, instance)
@@ -124,12 +149,84 @@ func (r *ReconcileNonClusterHost) Reconcile(ctx context.Context, request reconci
 		return reconcile.Result{}, err
 	}
 
+	if !reflect.DeepEqual(instance.Status.Hosts, inventory) {
+		instance.Status.Hosts = inventory
+		if err = r.client.Status().Update(ctx, instance); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Failed to update NonClusterHost status", err, logc)
+			return reconcile.Result{}, err
+		}
+	}
+
 	r.status.ReadyToMonitor()
+
+	// Typha refuses the connections of revoked hosts based on their HostEndpoint addresses. A revoked host without
+	// addresses can keep connecting until its certificate expires, so report it until it has some.
+	if unrefused := revokedHostsWithoutNets(inventory); len(unrefused) > 0 {
+		err = fmt.Errorf("revoked non-cluster hosts %s have no HostEndpoint addresses", strings.Join(unrefused, ", "))
+		r.status.SetDegraded(operatorv1.ResourceValidationError, "Connections from revoked non-cluster hosts cannot be refused", err, logc)
+		return reconcile.Result{RequeueAfter: utils.StandardRetry}, nil
+	}
 	r.status.ClearDegraded()
 
 	if !r.status.IsAvailable() {
 		return reconcile.Result{RequeueAfter: utils.StandardRetry}, nil
 	}
 
-	return reconcile.Result{}, nil
+	// Reconcile again when the next enrollment token expires, so that it can be removed.
+	return reconcile.Result{RequeueAfter: nextExpiry}, nil
+}
+
+// revokedHostsWithoutNets returns the revoked hosts that have no HostEndpoint addresses for Typha to refuse.
+func revokedHostsWithoutNets(inventory []operatorv1.NonClusterHostInventory) []string {
+	var hosts []string
+	for _, h := range inventory {
+		if h.State == operatorv1.NonClusterHostRevoked && len(render.NonClusterHostNets(h)) == 0 {
+			hosts = append(hosts, h.Hostname)
+		}
+	}
+	return hosts
+}
+
+// enrollment works out the inventory of the non-cluster hosts, and fills in the enrollment tokens that need to be
+// created and removed in the config. It returns the inventory and the time until the next enrollment token expires.
+func (r *ReconcileNonClusterHost) enrollment(ctx context.Context, instance *operatorv1.NonClusterHost, config *nonclusterhost.Config) ([]operatorv1.NonClusterHostInventory, time.Duration, error) {
+	var inventory []operatorv1.NonClusterHostInventory
+	var nextExpiry time.Duration
+	if len(instance.Spec.Hosts) > 0 {
+		heps := &v3.HostEndpointList{}
+		if err := r.client.List(ctx, heps); err != nil {
+			return nil, 0, err
+		}
+		csrs := &certificatesv1.CertificateSigningRequestList{}
+		if err := r.client.List(ctx, csrs, client.HasLabels{nonclusterhost.HostnameLabel}); err != nil {
+			return nil, 0, err
+		}
+		inventory, nextExpiry = hostInventory(instance.Spec, instance.Status.Hosts, heps.Items, csrs.Items, time.Now())
+	}
+
+	for _, h := range inventory {
+		if h.State == operatorv1.NonClusterHostPending {
+			config.PendingHosts = append(config.PendingHosts, h.Hostname)
+		}
+	}
+
+	// Remove the enrollment tokens of hosts that have enrolled, expired, been revoked, or are no longer listed. A
+	// token can only be used once, enrolled hosts renew their certificates with the non-cluster host token.
+	sas := &corev1.ServiceAccountList{}
+	if err := r.client.List(ctx, sas, client.InNamespace(common.CalicoNamespace), client.HasLabels{nonclusterhost.EnrollmentLabel}); err != nil {
+		return nil, 0, err
+	}
+	for _, sa := range sas.Items {
+		host := sa.Labels[nonclusterhost.EnrollmentLabel]
+		if !slices.Contains(config.PendingHosts, host) {
+			config.StaleEnrollments = append(config.StaleEnrollments, host)
+		}
+	}
+
+	err := r.client.Get(ctx, client.ObjectKey{Name: nonclusterhost.EnrollmentObjectName}, &rbacv1.ClusterRole{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, 0, err
+	}
+	config.EnrollmentRBACDeployed = err == nil
+	return inventory, nextExpiry, nil
 }
@@ -16,14 +16,18 @@ package nonclusterhost
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -33,6 +37,7 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/controller/utils"
 	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
 )
 
@@ -51,6 +56,7 @@ var _ = Describe("NonClusterHost controller tests", func() {
 		Expect(apis.AddToScheme(scheme)).NotTo(HaveOccurred())
 		Expect(appsv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
 		Expect(rbacv1.SchemeBuilder.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(certificatesv1.AddToScheme(scheme)).NotTo(HaveOccurred())
 
 		ctx = context.Background()
 		cli = ctrlrfake.DefaultFakeClientBuilder(scheme).Build()
@@ -108,6 +114,90 @@ var _ = Describe("NonClusterHost controller tests", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should issue single-use enrollment tokens and keep an inventory of the hosts", func() {
+			nonclusterhost.Spec.Hosts = []operatorv1.NonClusterHostEnrollment{{Hostname: "host-a"}, {Hostname: "host-b"}}
+			Expect(cli.Create(ctx, nonclusterhost)).NotTo(HaveOccurred())
+			Expect(cli.Create(ctx, &v3.HostEndpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: "host-a-eth0"},
+				Spec:       v3.HostEndpointSpec{Node: "host-a", ExpectedIPs: []string{"10.0.0.1"}},
+			})).NotTo(HaveOccurred())
+
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 24*time.Hour, time.Minute))
+
+			for _, host := range []string{"host-a", "host-b"} {
+				name := "tigera-noncluster-host-enrollment-" + host
+				Expect(cli.Get(ctx, client.ObjectKey{Name: name, Namespace: "calico-system"}, &corev1.ServiceAccount{})).NotTo(HaveOccurred())
+				Expect(cli.Get(ctx, client.ObjectKey{Name: name, Namespace: "calico-system"}, &corev1.Secret{})).NotTo(HaveOccurred())
+			}
+			crb := &rbacv1.ClusterRoleBinding{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-noncluster-host-enrollment"}, crb)).NotTo(HaveOccurred())
+			Expect(crb.Subjects).To(HaveLen(2))
+
+			instance := &operatorv1.NonClusterHost{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).NotTo(HaveOccurred())
+			Expect(instance.Status.Hosts).To(HaveLen(2))
+			Expect(instance.Status.Hosts[0].State).To(Equal(operatorv1.NonClusterHostPending))
+			Expect(instance.Status.Hosts[0].TokenSecretName).To(Equal("tigera-noncluster-host-enrollment-host-a"))
+			Expect(instance.Status.Hosts[0].HostEndpoint).To(Equal("host-a-eth0"))
+			Expect(instance.Status.Hosts[0].Addresses).To(Equal([]string{"10.0.0.1"}))
+
+			By("enrolling host-a with its token")
+			Expect(cli.Create(ctx, &certificatesv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "node-certs-noncluster-host:host-a",
+					Labels:            map[string]string{"nonclusterhost.tigera.io/hostname": "host-a"},
+					CreationTimestamp: metav1.Now(),
+				},
+				Spec: certificatesv1.CertificateSigningRequestSpec{Username: "system:serviceaccount:calico-system:tigera-noncluster-host-enrollment-host-a"},
+				Status: certificatesv1.CertificateSigningRequestStatus{
+					Conditions: []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}},
+				},
+			})).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).NotTo(HaveOccurred())
+			Expect(instance.Status.Hosts[0].State).To(Equal(operatorv1.NonClusterHostEnrolled))
+			Expect(instance.Status.Hosts[0].EnrolledAt).NotTo(BeNil())
+			Expect(instance.Status.Hosts[0].LastSeen).NotTo(BeNil())
+			// The token can only be used once, so it is removed as soon as the host has enrolled.
+			Expect(instance.Status.Hosts[0].TokenSecretName).To(BeEmpty())
+			err = cli.Get(ctx, client.ObjectKey{Name: "tigera-noncluster-host-enrollment-host-a", Namespace: "calico-system"}, &corev1.ServiceAccount{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = cli.Get(ctx, client.ObjectKey{Name: "tigera-noncluster-host-enrollment-host-a", Namespace: "calico-system"}, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-noncluster-host-enrollment"}, crb)).NotTo(HaveOccurred())
+			Expect(crb.Subjects).To(HaveLen(1))
+
+			By("revoking host-b, which has no HostEndpoint addresses for Typha to refuse")
+			mockStatus.On("SetDegraded", operatorv1.ResourceValidationError, "Connections from revoked non-cluster hosts cannot be refused", mock.Anything, mock.Anything).Return()
+			instance.Spec.Hosts[1].Revoked = true
+			Expect(cli.Update(ctx, instance)).NotTo(HaveOccurred())
+			result, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(utils.StandardRetry))
+			mockStatus.AssertCalled(GinkgoT(), "SetDegraded", operatorv1.ResourceValidationError, "Connections from revoked non-cluster hosts cannot be refused", mock.Anything, mock.Anything)
+
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).NotTo(HaveOccurred())
+			Expect(instance.Status.Hosts[1].State).To(Equal(operatorv1.NonClusterHostRevoked))
+			err = cli.Get(ctx, client.ObjectKey{Name: "tigera-noncluster-host-enrollment-host-b", Namespace: "calico-system"}, &corev1.ServiceAccount{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = cli.Get(ctx, client.ObjectKey{Name: "tigera-noncluster-host-enrollment"}, crb)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("revoking the enrolled host-a, so that it can no longer renew its certificate")
+			instance.Spec.Hosts[0].Revoked = true
+			Expect(cli.Update(ctx, instance)).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, instance)).NotTo(HaveOccurred())
+			Expect(instance.Status.Hosts[0].State).To(Equal(operatorv1.NonClusterHostRevoked))
+			Expect(instance.Status.Hosts[0].TokenSecretName).To(BeEmpty())
+		})
+
 		It("should set degraded status if endpoint is invalid", func() {
 			mockStatus.On("SetDegraded", operatorv1.ResourceValidationError, "Invalid endpoint", mock.Anything, mock.Anything).Return()
 
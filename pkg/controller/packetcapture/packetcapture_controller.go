@@ -16,7 +16,10 @@ package packetcapture
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +34,7 @@ import (
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/controller/certificatemanager"
 	"github.com/tigera/operator/pkg/controller/options"
@@ -48,6 +52,13 @@ import (
 const (
 	PacketCaptureControllerName = "packet-capture-controller"
 	ResourceName                = "packet-capture"
+
+	// defaultCaptureMaxFiles is the number of rotated capture files that Felix keeps by default.
+	defaultCaptureMaxFiles = 2
+
+	// captureStorageAnnotation records the capture settings that the operator set on the default FelixConfiguration,
+	// so that they can be reverted once they are no longer configured.
+	captureStorageAnnotation = "operator.tigera.io/packet-capture-storage"
 )
 
 var log = logf.Log.WithName("controller_packet_capture")
@@ -134,6 +145,7 @@ type ReconcilePacketCapture struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcilePacketCapture) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling PacketCapture")
 
@@ -151,7 +163,7 @@ func (r *ReconcilePacketCapture) Reconcile(ctx context.Context, request reconcil
 	r.status.OnCRFound()
 	reqLogger.V(2).Info("Loaded config", "config", packetcaptureapi)
 
-	defer r.status.SetMetaData(&packetcaptureapi.ObjectMeta)
+	defer r.status.SetMetaData(packetcaptureapi)
 
 	// Changes for updating PacketCapture status conditions.
 	if request.Name == ResourceName && request.Namespace == "" {
@@ -269,6 +281,11 @@ func (r *ReconcilePacketCapture) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, err
 	}
 
+	if err = r.patchFelixCaptureStorage(ctx, packetcaptureapi.Spec.Storage); err != nil {
+		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error updating the packet capture storage in FelixConfiguration", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	trustedBundle := certificateManager.CreateTrustedBundle(certificates...)
 	packetCaptureApiCfg := &render.PacketCaptureApiConfiguration{
 		PullSecrets:                 pullSecrets,
@@ -326,3 +343,75 @@ func (r *ReconcilePacketCapture) Reconcile(ctx context.Context, request reconcil
 	return reconcile.Result{}, nil
 
 }
+
+// captureStorageSettings are the FelixConfiguration capture settings that are derived from the PacketCaptureAPI storage.
+type captureStorageSettings struct {
+	CaptureMaxSizeBytes    *int `json:"captureMaxSizeBytes,omitempty"`
+	CaptureRotationSeconds *int `json:"captureRotationSeconds,omitempty"`
+}
+
+// patchFelixCaptureStorage configures Felix so that the files of a single capture do not exceed the configured size
+// and age on any node, and reverts the settings that the operator made once they are no longer configured.
+func (r *ReconcilePacketCapture) patchFelixCaptureStorage(ctx context.Context, storage *operatorv1.PacketCaptureStorage) error {
+	_, err := utils.PatchFelixConfiguration(ctx, r.client, func(fc *crdv1.FelixConfiguration) (bool, error) {
+		applied := captureStorageSettings{}
+		if a := fc.Annotations[captureStorageAnnotation]; a != "" {
+			if err := json.Unmarshal([]byte(a), &applied); err != nil {
+				return false, fmt.Errorf("failed to parse the %s annotation: %w", captureStorageAnnotation, err)
+			}
+		}
+
+		// Felix keeps CaptureMaxFiles rotated files in addition to the file that is being written.
+		files := defaultCaptureMaxFiles
+		if fc.Spec.CaptureMaxFiles != nil {
+			files = *fc.Spec.CaptureMaxFiles
+		}
+		files++
+
+		desired := captureStorageSettings{}
+		if storage != nil && storage.MaxCaptureSizePerNode != nil {
+			size := int(storage.MaxCaptureSizePerNode.Value() / int64(files))
+			if size < 1 {
+				return false, fmt.Errorf("maxCaptureSizePerNode must be at least %d bytes", files)
+			}
+			desired.CaptureMaxSizeBytes = &size
+		}
+		if storage != nil && storage.Retention != nil {
+			seconds := int(storage.Retention.Duration.Seconds()) / files
+			if seconds < 1 {
+				return false, fmt.Errorf("retention must be at least %ds", files)
+			}
+			desired.CaptureRotationSeconds = &seconds
+		}
+
+		before := fc.DeepCopy()
+		fc.Spec.CaptureMaxSizeBytes = captureSetting(fc.Spec.CaptureMaxSizeBytes, applied.CaptureMaxSizeBytes, desired.CaptureMaxSizeBytes)
+		fc.Spec.CaptureRotationSeconds = captureSetting(fc.Spec.CaptureRotationSeconds, applied.CaptureRotationSeconds, desired.CaptureRotationSeconds)
+		if desired == (captureStorageSettings{}) {
+			delete(fc.Annotations, captureStorageAnnotation)
+		} else {
+			annotation, err := json.Marshal(desired)
+			if err != nil {
+				return false, err
+			}
+			if fc.Annotations == nil {
+				fc.Annotations = map[string]string{}
+			}
+			fc.Annotations[captureStorageAnnotation] = string(annotation)
+		}
+		return !reflect.DeepEqual(before, fc), nil
+	})
+	return err
+}
+
+// captureSetting returns the desired value of a capture setting. Without a desired value, the value that the operator
+// set before is removed and any other value is left to the user.
+func captureSetting(current, applied, desired *int) *int {
+	if desired != nil {
+		return desired
+	}
+	if current != nil && applied != nil && *current == *applied {
+		return nil
+	}
+	return current
+}
@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -36,6 +37,7 @@ import (
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/controller/certificatemanager"
@@ -300,6 +302,74 @@ var _ = Describe("packet capture controller tests", func() {
 		})
 	})
 
+	Context("storage", func() {
+		setStorage := func(storage *operatorv1.PacketCaptureStorage) {
+			pc := &operatorv1.PacketCaptureAPI{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, pc)).NotTo(HaveOccurred())
+			pc.Spec.Storage = storage
+			Expect(cli.Update(ctx, pc)).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			Expect(cli.Create(ctx, installation)).To(BeNil())
+		})
+
+		It("should configure Felix and revert its changes when the storage configuration is removed", func() {
+			maxFiles := 4
+			Expect(cli.Create(ctx, &crdv1.FelixConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec:       crdv1.FelixConfigurationSpec{CaptureMaxFiles: &maxFiles},
+			})).NotTo(HaveOccurred())
+			maxSize := resource.MustParse("50M")
+			setStorage(&operatorv1.PacketCaptureStorage{
+				MaxCaptureSizePerNode: &maxSize,
+				Retention:             &metav1.Duration{Duration: 5 * time.Hour},
+			})
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fc := &crdv1.FelixConfiguration{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, fc)).NotTo(HaveOccurred())
+			Expect(*fc.Spec.CaptureMaxSizeBytes).To(Equal(10000000))
+			Expect(*fc.Spec.CaptureRotationSeconds).To(Equal(3600))
+
+			By("removing the storage configuration")
+			setStorage(nil)
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fc = &crdv1.FelixConfiguration{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, fc)).NotTo(HaveOccurred())
+			Expect(fc.Spec.CaptureMaxSizeBytes).To(BeNil())
+			Expect(fc.Spec.CaptureRotationSeconds).To(BeNil())
+			Expect(*fc.Spec.CaptureMaxFiles).To(Equal(4))
+			Expect(fc.Annotations).NotTo(HaveKey("operator.tigera.io/packet-capture-storage"))
+		})
+
+		It("should not revert capture settings that were changed by the user", func() {
+			maxSize := resource.MustParse("30M")
+			setStorage(&operatorv1.PacketCaptureStorage{MaxCaptureSizePerNode: &maxSize})
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fc := &crdv1.FelixConfiguration{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, fc)).NotTo(HaveOccurred())
+			Expect(*fc.Spec.CaptureMaxSizeBytes).To(Equal(10000000))
+			userSize := 1000
+			fc.Spec.CaptureMaxSizeBytes = &userSize
+			Expect(cli.Update(ctx, fc)).NotTo(HaveOccurred())
+
+			setStorage(nil)
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fc = &crdv1.FelixConfiguration{}
+			Expect(cli.Get(ctx, client.ObjectKey{Name: "default"}, fc)).NotTo(HaveOccurred())
+			Expect(*fc.Spec.CaptureMaxSizeBytes).To(Equal(1000))
+		})
+	})
+
 	Context("allow-tigera reconciliation", func() {
 		var readyFlag *utils.ReadyFlag
 
@@ -17,6 +17,7 @@ package policyrecommendation
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -51,6 +52,19 @@ import (
 const (
 	PolicyRecommendationControllerName = "policy-recommendation-controller"
 	ResourceName                       = "policy-recommendation"
+
+	// recommendationScopeLabel is set by the recommendation engine on the StagedNetworkPolicies it generates.
+	recommendationScopeLabel = "policyrecommendation.tigera.io/scope"
+
+	// recommendationStatusAnnotation holds the recommendation engine's view of a recommended policy's lifecycle.
+	recommendationStatusAnnotation = "policyrecommendation.tigera.io/status"
+
+	// recommendationStagedByLabel marks the recommended policies that the operator has taken over from the
+	// recommendation engine and staged in AutoStage mode.
+	recommendationStagedByLabel = "policyrecommendation.tigera.io/staged-by"
+
+	// defaultRecommendationInterval matches the recommendation engine's default PolicyRecommendationScope interval.
+	defaultRecommendationInterval = 150 * time.Second
 )
 
 var log = logf.Log.WithName("controller_policy_recommendation")
@@ -207,6 +221,7 @@ func GetPolicyRecommendation(ctx context.Context, cli client.Client, mt bool, ns
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcilePolicyRecommendation) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	helper := utils.NewNamespaceHelper(r.multiTenant, render.PolicyRecommendationNamespace, request.Namespace)
 	logc := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "installNS", helper.InstallNamespace(), "truthNS", helper.TruthNamespace())
 	logc.Info("Reconciling PolicyRecommendation")
@@ -245,7 +260,12 @@ func (r *ReconcilePolicyRecommendation) Reconcile(ctx context.Context, request r
 	logc.V(2).Info("Loaded config", "config", policyRecommendation)
 
 	// SetMetaData in the TigeraStatus such as observedGenerations
-	defer r.status.SetMetaData(&policyRecommendation.ObjectMeta)
+	defer r.status.SetMetaData(policyRecommendation)
+
+	if r.multiTenant && policyRecommendation.Spec.Scope != nil {
+		r.status.SetDegraded(operatorv1.ResourceValidationError, "PolicyRecommendation scope is not supported in multi-tenant management clusters", nil, logc)
+		return reconcile.Result{}, nil
+	}
 
 	if !utils.IsAPIServerReady(r.client, logc) {
 		r.status.SetDegraded(operatorv1.ResourceNotReady, "Waiting for Tigera API server to be ready", nil, logc)
@@ -490,15 +510,38 @@ func (r *ReconcilePolicyRecommendation) Reconcile(ctx context.Context, request r
 		return reconcile.Result{RequeueAfter: utils.StandardRetry}, nil
 	}
 
+	// When the operator owns the PolicyRecommendationScope it also manages the lifecycle of the recommendations.
+	scope := policyRecommendation.Spec.Scope
+	var counts *operatorv1.PolicyRecommendationCounts
+	if scope != nil {
+		if counts, err = r.reconcileRecommendations(ctx, scope); err != nil {
+			r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error reconciling recommended policies", err, logc)
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Everything is available - update the CRD status.
 	policyRecommendation.Status.State = operatorv1.TigeraStatusReady
+	policyRecommendation.Status.Recommendations = counts
 	if err = r.client.Status().Update(ctx, policyRecommendation); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	if scope != nil {
+		if scope.Mode == nil || *scope.Mode == operatorv1.PolicyRecommendationModeDisabled {
+			return reconcile.Result{}, nil
+		}
+		// Recommendations change every time the engine runs, so check again after the next run.
+		interval := defaultRecommendationInterval
+		if scope.Interval != nil {
+			interval = scope.Interval.Duration
+		}
+		return reconcile.Result{RequeueAfter: interval}, nil
+	}
+
 	// Fetch any existing PolicyRecommendationScope object
 	policyRecommendationScope := &v3.PolicyRecommendationScope{}
-	err = r.client.Get(ctx, types.NamespacedName{Name: "default"}, policyRecommendationScope)
+	err = r.client.Get(ctx, types.NamespacedName{Name: render.PolicyRecommendationScopeName}, policyRecommendationScope)
 	if err != nil {
 		if !errors.IsNotFound(err) {
 			r.status.SetDegraded(operatorv1.ResourceReadError, "Unable to read policyRecommendationScope", err, logc)
@@ -513,6 +556,56 @@ func (r *ReconcilePolicyRecommendation) Reconcile(ctx context.Context, request r
 	return reconcile.Result{}, nil
 }
 
+// reconcileRecommendations counts the recommended policies generated by the recommendation engine in each state and,
+// in AutoStage mode, stages the stable ones.
+//
+// The recommendation engine owns the policies that carry its scope label and rewrites them on every run, so a stable
+// recommendation is handed over before it is staged: the scope label is replaced by recommendationStagedByLabel in
+// the same update that sets the staged action. The update is rejected if the engine changed the policy in the
+// meantime, and the policy is tried again on the next reconcile.
+func (r *ReconcilePolicyRecommendation) reconcileRecommendations(ctx context.Context, scope *operatorv1.PolicyRecommendationScopeConfig) (*operatorv1.PolicyRecommendationCounts, error) {
+	autoStage := scope.Mode != nil && *scope.Mode == operatorv1.PolicyRecommendationModeAutoStage
+
+	staged := &v3.StagedNetworkPolicyList{}
+	if err := r.client.List(ctx, staged, client.HasLabels{recommendationStagedByLabel}); err != nil {
+		return nil, err
+	}
+	snps := &v3.StagedNetworkPolicyList{}
+	if err := r.client.List(ctx, snps, client.HasLabels{recommendationScopeLabel}); err != nil {
+		return nil, err
+	}
+
+	counts := &operatorv1.PolicyRecommendationCounts{Staged: int32(len(staged.Items))}
+	for i := range snps.Items {
+		snp := &snps.Items[i]
+		if snp.Spec.StagedAction == v3.StagedActionSet {
+			counts.Staged++
+			continue
+		}
+		if snp.Spec.StagedAction != v3.StagedActionLearn {
+			continue
+		}
+
+		switch snp.Annotations[recommendationStatusAnnotation] {
+		case "Learning", "Stabilizing":
+			counts.Learning++
+		case "Stable":
+			if !autoStage {
+				counts.Stable++
+				continue
+			}
+			delete(snp.Labels, recommendationScopeLabel)
+			snp.Labels[recommendationStagedByLabel] = common.OperatorName()
+			snp.Spec.StagedAction = v3.StagedActionSet
+			if err := r.client.Update(ctx, snp); err != nil {
+				return nil, fmt.Errorf("failed to stage recommended policy %s/%s: %w", snp.Namespace, snp.Name, err)
+			}
+			counts.Staged++
+		}
+	}
+	return counts, nil
+}
+
 // createDefaultPolicyRecommendationScope will create a new default version of the
 // PolicyRecommendationScope resource.
 func (r *ReconcilePolicyRecommendation) createDefaultPolicyRecommendationScope(ctx context.Context, prs *v3.PolicyRecommendationScope, log logr.Logger) error {
@@ -520,12 +613,9 @@ func (r *ReconcilePolicyRecommendation) createDefaultPolicyRecommendationScope(c
 		prs = &v3.PolicyRecommendationScope{}
 	}
 
-	prs.ObjectMeta.Name = "default"
-	prs.Spec.NamespaceSpec.RecStatus = "Disabled"
-	prs.Spec.NamespaceSpec.Selector = "!(projectcalico.org/name starts with 'tigera-') && !(projectcalico.org/name starts with 'calico-') && !(projectcalico.org/name starts with 'kube-')"
-	if r.provider.IsOpenShift() {
-		prs.Spec.NamespaceSpec.Selector += " && !(projectcalico.org/name starts with 'openshift-')"
-	}
+	prs.ObjectMeta.Name = render.PolicyRecommendationScopeName
+	prs.Spec.NamespaceSpec.RecStatus = v3.PolicyRecommendationScopeDisabled
+	prs.Spec.NamespaceSpec.Selector = render.PolicyRecommendationNamespaceSelector(r.provider.IsOpenShift(), nil, nil)
 
 	if err := r.client.Create(ctx, prs); err != nil {
 		r.status.SetDegraded(operatorv1.ResourceCreateError, "Unable to Create default PolicyRecommendationScope", err, log)
@@ -317,6 +317,126 @@ var _ = Describe("PolicyRecommendation controller tests", func() {
 			Expect(test.GetResource(c, &prs)).To(BeNil())
 		})
 
+		Context("operator owned PolicyRecommendationScope", func() {
+			recommendation := func(name string, action v3.StagedAction, status string) *v3.StagedNetworkPolicy {
+				return &v3.StagedNetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name,
+						Namespace:   "app",
+						Labels:      map[string]string{"policyrecommendation.tigera.io/scope": "namespace"},
+						Annotations: map[string]string{"policyrecommendation.tigera.io/status": status},
+					},
+					Spec: v3.StagedNetworkPolicySpec{StagedAction: action},
+				}
+			}
+
+			BeforeEach(func() {
+				for _, snp := range []*v3.StagedNetworkPolicy{
+					recommendation("learning", v3.StagedActionLearn, "Learning"),
+					recommendation("stabilizing", v3.StagedActionLearn, "Stabilizing"),
+					recommendation("stable", v3.StagedActionLearn, "Stable"),
+					recommendation("staged", v3.StagedActionSet, "Stable"),
+				} {
+					Expect(c.Create(ctx, snp)).NotTo(HaveOccurred())
+				}
+				Expect(c.Create(ctx, &v3.StagedNetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "user-policy", Namespace: "app"},
+					Spec:       v3.StagedNetworkPolicySpec{StagedAction: v3.StagedActionLearn},
+				})).NotTo(HaveOccurred())
+			})
+
+			setScope := func(scope *operatorv1.PolicyRecommendationScopeConfig) {
+				pr := &operatorv1.PolicyRecommendation{}
+				Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, pr)).NotTo(HaveOccurred())
+				pr.Spec.Scope = scope
+				Expect(c.Update(ctx, pr)).NotTo(HaveOccurred())
+			}
+
+			It("should render the scope and report recommendation counts in Learn mode", func() {
+				mode := operatorv1.PolicyRecommendationModeLearn
+				setScope(&operatorv1.PolicyRecommendationScopeConfig{Mode: &mode, IncludeNamespaces: []string{"app"}})
+
+				result, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(150 * time.Second))
+
+				scope := &v3.PolicyRecommendationScope{}
+				Expect(c.Get(ctx, client.ObjectKey{Name: "default"}, scope)).NotTo(HaveOccurred())
+				Expect(scope.Spec.NamespaceSpec.RecStatus).To(Equal(v3.PolicyRecommendationScopeEnabled))
+				Expect(scope.Spec.NamespaceSpec.Selector).To(HavePrefix("projectcalico.org/name in {'app'}"))
+
+				stable := &v3.StagedNetworkPolicy{}
+				Expect(c.Get(ctx, client.ObjectKey{Name: "stable", Namespace: "app"}, stable)).NotTo(HaveOccurred())
+				Expect(stable.Spec.StagedAction).To(Equal(v3.StagedActionLearn))
+
+				pr := &operatorv1.PolicyRecommendation{}
+				Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, pr)).NotTo(HaveOccurred())
+				Expect(pr.Status.Recommendations).To(Equal(&operatorv1.PolicyRecommendationCounts{Learning: 2, Stable: 1, Staged: 1}))
+			})
+
+			It("should requeue after the configured interval without changing the recommendations", func() {
+				mode := operatorv1.PolicyRecommendationModeLearn
+				setScope(&operatorv1.PolicyRecommendationScopeConfig{Mode: &mode, Interval: &metav1.Duration{Duration: 5 * time.Minute}})
+
+				result, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+
+				for _, name := range []string{"learning", "stabilizing", "stable", "user-policy"} {
+					snp := &v3.StagedNetworkPolicy{}
+					Expect(c.Get(ctx, client.ObjectKey{Name: name, Namespace: "app"}, snp)).NotTo(HaveOccurred())
+					Expect(snp.Spec.StagedAction).To(Equal(v3.StagedActionLearn))
+				}
+			})
+
+			It("should take over and stage stable recommendations in AutoStage mode", func() {
+				mode := operatorv1.PolicyRecommendationModeAutoStage
+				setScope(&operatorv1.PolicyRecommendationScopeConfig{Mode: &mode})
+
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).NotTo(HaveOccurred())
+
+				stable := &v3.StagedNetworkPolicy{}
+				Expect(c.Get(ctx, client.ObjectKey{Name: "stable", Namespace: "app"}, stable)).NotTo(HaveOccurred())
+				Expect(stable.Spec.StagedAction).To(Equal(v3.StagedActionSet))
+				Expect(stable.Labels).NotTo(HaveKey("policyrecommendation.tigera.io/scope"))
+				Expect(stable.Labels).To(HaveKeyWithValue("policyrecommendation.tigera.io/staged-by", "tigera-operator"))
+
+				for _, name := range []string{"learning", "stabilizing", "user-policy"} {
+					snp := &v3.StagedNetworkPolicy{}
+					Expect(c.Get(ctx, client.ObjectKey{Name: name, Namespace: "app"}, snp)).NotTo(HaveOccurred())
+					Expect(snp.Spec.StagedAction).To(Equal(v3.StagedActionLearn))
+				}
+
+				pr := &operatorv1.PolicyRecommendation{}
+				Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, pr)).NotTo(HaveOccurred())
+				Expect(pr.Status.Recommendations).To(Equal(&operatorv1.PolicyRecommendationCounts{Learning: 2, Stable: 0, Staged: 2}))
+
+				// The policy that was taken over is still counted on the next reconcile.
+				_, err = r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c.Get(ctx, client.ObjectKey{Name: "tigera-secure"}, pr)).NotTo(HaveOccurred())
+				Expect(pr.Status.Recommendations).To(Equal(&operatorv1.PolicyRecommendationCounts{Learning: 2, Stable: 0, Staged: 2}))
+			})
+
+			It("should overwrite changes made directly to the scope", func() {
+				setScope(&operatorv1.PolicyRecommendationScopeConfig{})
+				_, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).NotTo(HaveOccurred())
+
+				scope := &v3.PolicyRecommendationScope{}
+				Expect(c.Get(ctx, client.ObjectKey{Name: "default"}, scope)).NotTo(HaveOccurred())
+				scope.Spec.NamespaceSpec.RecStatus = v3.PolicyRecommendationScopeEnabled
+				Expect(c.Update(ctx, scope)).NotTo(HaveOccurred())
+
+				result, err := r.Reconcile(ctx, reconcile.Request{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(0 * time.Second))
+				Expect(c.Get(ctx, client.ObjectKey{Name: "default"}, scope)).NotTo(HaveOccurred())
+				Expect(scope.Spec.NamespaceSpec.RecStatus).To(Equal(v3.PolicyRecommendationScopeDisabled))
+			})
+		})
+
 		Context("Multi-tenant/namespaced reconciliation", func() {
 			tenantANamespace := "tenant-a"
 			tenantBNamespace := "tenant-b"
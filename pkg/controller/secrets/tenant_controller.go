@@ -17,6 +17,7 @@ package secrets
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	operatorv1 "github.com/tigera/operator/api/v1"
@@ -101,6 +102,7 @@ func AddTenantController(mgr manager.Manager, opts options.AddOptions) error {
 }
 
 func (r *TenantController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	logc := r.log.WithValues("Request.Namespace", request.Namespace)
 	if request.Namespace == "" {
 		// Tenant resources are always within a namespace.
@@ -15,15 +15,15 @@ package status
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 
 	operator "github.com/tigera/operator/api/v1"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
 	"github.com/stretchr/testify/mock"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // TODO use mockery to generate mock
@@ -63,6 +63,22 @@ func (m *MockStatus) AddCronJobs(cjs []types.NamespacedName) {
 	m.Called(cjs)
 }
 
+func (m *MockStatus) AddStagedPolicies(policies []types.NamespacedName) {
+	m.Called(policies)
+}
+
+func (m *MockStatus) AddPolicyExceptions(exceptions []PolicyExceptionState) {
+	m.Called(exceptions)
+}
+
+func (m *MockStatus) SetCondition(conditionType operator.StatusConditionType, status operator.ConditionStatus, reason operator.TigeraStatusReason, msg string) {
+	m.Called(conditionType, status, reason, msg)
+}
+
+func (m *MockStatus) ClearCondition(conditionType operator.StatusConditionType) {
+	m.Called(conditionType)
+}
+
 func (m *MockStatus) AddCertificateSigningRequests(name string, labels map[string]string) {
 	m.Called(name)
 }
@@ -123,6 +139,9 @@ func (m *MockStatus) WasCalled(method string, arguments ...interface{}) bool {
 	return false
 }
 
-func (m *MockStatus) SetMetaData(meta *metav1.ObjectMeta) {
-	m.Called(meta)
+func (m *MockStatus) SetMetaData(owner client.Object) {
+	m.Called(owner)
 }
+
+// ObserveReconcile is not recorded, as none of the controller tests are interested in reconcile timings.
+func (m *MockStatus) ObserveReconcile(start time.Time) {}
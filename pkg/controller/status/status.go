@@ -18,11 +18,13 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 
 	operator "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
@@ -36,12 +38,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/set"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 var log = logf.Log.WithName("status_manager")
 
+// maxHistory is the number of history entries kept in each TigeraStatus.
+const maxHistory = 20
+
 // StatusManager manages the status for a single controller and component, and reports the status via
 // a TigeraStatus API object. The status manager uses the following conditions/states to represent the
 // component's current status:
@@ -69,6 +76,10 @@ type StatusManager interface {
 	RemoveDeployments(dps ...types.NamespacedName)
 	RemoveStatefulSets(sss ...types.NamespacedName)
 	RemoveCronJobs(cjs ...types.NamespacedName)
+	AddStagedPolicies(policies []types.NamespacedName)
+	AddPolicyExceptions(exceptions []PolicyExceptionState)
+	SetCondition(conditionType operator.StatusConditionType, status operator.ConditionStatus, reason operator.TigeraStatusReason, msg string)
+	ClearCondition(conditionType operator.StatusConditionType)
 	RemoveCertificateSigningRequests(name string)
 	SetDegraded(reason operator.TigeraStatusReason, msg string, err error, log logr.Logger)
 	ClearDegraded()
@@ -76,7 +87,16 @@ type StatusManager interface {
 	IsProgressing() bool
 	IsDegraded() bool
 	ReadyToMonitor()
-	SetMetaData(meta *metav1.ObjectMeta)
+	SetMetaData(owner client.Object)
+	ObserveReconcile(start time.Time)
+}
+
+// PolicyExceptionState describes a PolicyException that was merged into an allow-tigera policy, or that was rejected
+// because it is invalid.
+type PolicyExceptionState struct {
+	Name   string
+	Policy types.NamespacedName
+	Error  error
 }
 
 type statusManager struct {
@@ -86,6 +106,8 @@ type statusManager struct {
 	deployments               map[string]types.NamespacedName
 	statefulsets              map[string]types.NamespacedName
 	cronjobs                  map[string]types.NamespacedName
+	stagedPolicies            map[string]types.NamespacedName
+	policyExceptions          map[string]PolicyExceptionState
 	certificatestatusrequests map[string]map[string]string
 	lock                      sync.Mutex
 	enabled                   *bool
@@ -99,6 +121,30 @@ type statusManager struct {
 	// Keep track of currently calculated status.
 	progressing []string
 	failing     []string
+	notReady    []string
+
+	// staged holds a message for each allow-tigera policy that is still staged.
+	staged []string
+
+	// appliedExceptions and invalidExceptions hold a message for each PolicyException that has been merged into, or
+	// rejected for, the policies of this component.
+	appliedExceptions []string
+	invalidExceptions []string
+
+	// conditions holds the conditions other than Available, Progressing and Degraded that apply to the component,
+	// and reportedConditions the types of those that have been written to the TigeraStatus, so that a condition
+	// that no longer applies can be cleared.
+	conditions         map[operator.StatusConditionType]operator.TigeraStatusCondition
+	reportedConditions map[operator.StatusConditionType]bool
+
+	// History entries that have not yet been written to the TigeraStatus, and the duration of the most
+	// recent reconcile to attach to new entries.
+	pendingHistory    []operator.TigeraStatusEvent
+	reconcileDuration time.Duration
+
+	// lastEvents holds the status and reason of the last Event emitted for each condition type, so that an Event
+	// is only emitted when one of them changes.
+	lastEvents map[operator.StatusConditionType]string
 
 	// readyToMonitor tells the status manager that it's ready to monitor the resources that it's been told to monitor,
 	// if there are any, and report statuses based on the state of those resources.
@@ -111,6 +157,10 @@ type statusManager struct {
 	crExists bool
 
 	observedGeneration int64
+
+	// owner references the CR that owns the component. Events are emitted against it, or against the
+	// TigeraStatus if the controller has not reported its CR.
+	owner *corev1.ObjectReference
 }
 
 func New(client client.Client, component string, kubernetesVersion *common.VersionInfo) StatusManager {
@@ -132,7 +182,12 @@ func New(client client.Client, component string, kubernetesVersion *common.Versi
 		deployments:               make(map[string]types.NamespacedName),
 		statefulsets:              make(map[string]types.NamespacedName),
 		cronjobs:                  make(map[string]types.NamespacedName),
+		stagedPolicies:            make(map[string]types.NamespacedName),
+		policyExceptions:          make(map[string]PolicyExceptionState),
 		certificatestatusrequests: make(map[string]map[string]string),
+		conditions:                make(map[operator.StatusConditionType]operator.TigeraStatusCondition),
+		reportedConditions:        make(map[operator.StatusConditionType]bool),
+		lastEvents:                make(map[operator.StatusConditionType]string),
 		kubernetesVersion:         kubernetesVersion,
 		crExists:                  crExists,
 	}
@@ -186,6 +241,20 @@ func (m *statusManager) updateStatus() {
 				m.clearDegraded()
 			}
 		}
+
+		if msg := m.stagedMessage(); msg != "" {
+			m.SetCondition(operator.ComponentPoliciesStaged, operator.ConditionTrue, operator.StagedPoliciesPending, msg)
+		} else {
+			m.ClearCondition(operator.ComponentPoliciesStaged)
+		}
+
+		if msg := m.appliedExceptionsMessage(); msg != "" {
+			m.SetCondition(operator.ComponentPolicyExceptionsApplied, operator.ConditionTrue, operator.PolicyExceptionsApplied, msg)
+		} else {
+			m.ClearCondition(operator.ComponentPolicyExceptionsApplied)
+		}
+
+		m.setConditions()
 	} else {
 		log.V(2).WithName(m.component).Info("Status manager is not ready to report component statuses.")
 
@@ -259,10 +328,19 @@ func (m *statusManager) OnCRNotFound() {
 	m.enabled = &f
 	m.progressing = []string{}
 	m.failing = []string{}
+	m.notReady = nil
+	m.pendingHistory = nil
 	m.daemonsets = make(map[string]types.NamespacedName)
 	m.deployments = make(map[string]types.NamespacedName)
 	m.statefulsets = make(map[string]types.NamespacedName)
 	m.cronjobs = make(map[string]types.NamespacedName)
+	m.stagedPolicies = make(map[string]types.NamespacedName)
+	m.staged = nil
+	m.policyExceptions = make(map[string]PolicyExceptionState)
+	m.appliedExceptions = nil
+	m.invalidExceptions = nil
+	m.conditions = make(map[operator.StatusConditionType]operator.TigeraStatusCondition)
+	m.reportedConditions = make(map[operator.StatusConditionType]bool)
 }
 
 // AddDaemonsets tells the status manager to monitor the health of the given daemonsets.
@@ -301,6 +379,42 @@ func (m *statusManager) AddCronJobs(cjs []types.NamespacedName) {
 	}
 }
 
+// AddStagedPolicies tells the status manager to report on the given allow-tigera StagedNetworkPolicies until they
+// have been promoted to enforced policies.
+func (m *statusManager) AddStagedPolicies(policies []types.NamespacedName) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, p := range policies {
+		m.stagedPolicies[p.String()] = p
+	}
+}
+
+// AddPolicyExceptions tells the status manager to report on the given PolicyExceptions, which have been merged into
+// or rejected for the allow-tigera policies of this component.
+func (m *statusManager) AddPolicyExceptions(exceptions []PolicyExceptionState) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, e := range exceptions {
+		m.policyExceptions[e.Name] = e
+	}
+}
+
+// SetCondition tells the status manager to report a condition other than Available, Progressing and Degraded, such
+// as RollingOut, until it is changed or cleared.
+func (m *statusManager) SetCondition(conditionType operator.StatusConditionType, status operator.ConditionStatus, reason operator.TigeraStatusReason, msg string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.conditions[conditionType] = operator.TigeraStatusCondition{Type: conditionType, Status: status, Reason: string(reason), Message: msg}
+}
+
+// ClearCondition tells the status manager that a condition set with SetCondition no longer applies. If it has been
+// reported, it is set to False.
+func (m *statusManager) ClearCondition(conditionType operator.StatusConditionType) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.conditions, conditionType)
+}
+
 // AddCertificateSigningRequests tells the status manager to monitor the health of the given CertificateSigningRequests.
 func (m *statusManager) AddCertificateSigningRequests(name string, labels map[string]string) {
 	m.lock.Lock()
@@ -360,9 +474,19 @@ func (m *statusManager) SetDegraded(reason operator.TigeraStatusReason, msg stri
 	}
 	m.lock.Lock()
 	defer m.lock.Unlock()
+	msg = fmt.Sprintf("%s: %s", msg, errormsg)
+	if !m.degraded || m.explicitDegradedReason != reason || m.explicitDegradedMsg != msg {
+		// Record the new degraded reason even if it clears before the next status update.
+		m.pendingHistory = append(m.pendingHistory, m.newHistoryEntry(operator.TigeraStatusCondition{
+			Type: operator.ComponentDegraded, Status: operator.ConditionTrue, Reason: string(reason), Message: msg,
+		}))
+		if len(m.pendingHistory) > maxHistory {
+			m.pendingHistory = m.pendingHistory[len(m.pendingHistory)-maxHistory:]
+		}
+	}
 	m.degraded = true
 	m.explicitDegradedReason = reason
-	m.explicitDegradedMsg = fmt.Sprintf("%s: %s", msg, errormsg)
+	m.explicitDegradedMsg = msg
 }
 
 // ClearDegraded clears degraded state.
@@ -424,8 +548,8 @@ func (m *statusManager) IsDegraded() bool {
 		return false
 	}
 
-	// We may be degraded due to failing pods.
-	return len(m.failing) != 0
+	// We may be degraded due to failing pods or invalid policy exceptions.
+	return len(m.failing) != 0 || len(m.invalidExceptions) != 0
 }
 
 // syncState syncs the internal state of the k8s resources that the status manager has been told to monitor with that of
@@ -435,6 +559,15 @@ func (m *statusManager) syncState() {
 	defer m.lock.Unlock()
 	progressing := []string{}
 	failing := []string{}
+	notReady := set.New[string]()
+	progress := func(obj, msg string) {
+		progressing = append(progressing, msg)
+		notReady.Insert(obj)
+	}
+	fail := func(obj, msg string) {
+		failing = append(failing, msg)
+		notReady.Insert(obj)
+	}
 
 	// For each daemonset, check its rollout status.
 	for _, dsnn := range m.daemonsets {
@@ -445,13 +578,13 @@ func (m *statusManager) syncState() {
 			continue
 		}
 		if ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
-			progressing = append(progressing, fmt.Sprintf("DaemonSet %q update is rolling out (%d out of %d updated)", dsnn.String(), ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled))
+			progress("DaemonSet "+dsnn.String(), fmt.Sprintf("DaemonSet %q update is rolling out (%d out of %d updated)", dsnn.String(), ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled))
 		} else if ds.Status.NumberUnavailable > 0 {
-			progressing = append(progressing, fmt.Sprintf("DaemonSet %q is not available (awaiting %d nodes)", dsnn.String(), ds.Status.NumberUnavailable))
+			progress("DaemonSet "+dsnn.String(), fmt.Sprintf("DaemonSet %q is not available (awaiting %d nodes)", dsnn.String(), ds.Status.NumberUnavailable))
 		} else if ds.Status.NumberAvailable == 0 && ds.Status.DesiredNumberScheduled != 0 {
-			progressing = append(progressing, fmt.Sprintf("DaemonSet %q is not yet scheduled on any nodes", dsnn.String()))
+			progress("DaemonSet "+dsnn.String(), fmt.Sprintf("DaemonSet %q is not yet scheduled on any nodes", dsnn.String()))
 		} else if ds.Generation > ds.Status.ObservedGeneration {
-			progressing = append(progressing, fmt.Sprintf("DaemonSet %q update is being processed (generation %d, observed generation %d)", dsnn.String(), ds.Generation, ds.Status.ObservedGeneration))
+			progress("DaemonSet "+dsnn.String(), fmt.Sprintf("DaemonSet %q update is being processed (generation %d, observed generation %d)", dsnn.String(), ds.Generation, ds.Status.ObservedGeneration))
 		}
 
 		// If all these are true then all expected pods are present and healthy
@@ -467,7 +600,7 @@ func (m *statusManager) syncState() {
 		// Check if any pods within the daemonset are failing.
 		if f, err := m.podsFailing(ds.Spec.Selector, ds.Namespace); err == nil {
 			if f != "" {
-				failing = append(failing, f)
+				fail("DaemonSet "+dsnn.String(), f)
 			}
 		} else {
 			log.WithValues("reason", err, "daemonset", dsnn).Info("Failed to check for failing pods")
@@ -483,11 +616,11 @@ func (m *statusManager) syncState() {
 			continue
 		}
 		if dep.Status.UnavailableReplicas > 0 {
-			progressing = append(progressing, fmt.Sprintf("Deployment %q is not available (awaiting %d replicas)", depnn.String(), dep.Status.UnavailableReplicas))
+			progress("Deployment "+depnn.String(), fmt.Sprintf("Deployment %q is not available (awaiting %d replicas)", depnn.String(), dep.Status.UnavailableReplicas))
 		} else if dep.Status.AvailableReplicas == 0 {
-			progressing = append(progressing, fmt.Sprintf("Deployment %q is not yet scheduled on any nodes", depnn.String()))
+			progress("Deployment "+depnn.String(), fmt.Sprintf("Deployment %q is not yet scheduled on any nodes", depnn.String()))
 		} else if dep.Status.ObservedGeneration < dep.Generation {
-			progressing = append(progressing, fmt.Sprintf("Deployment %q update is being processed (generation %d, observed generation %d)", depnn.String(), dep.Generation, dep.Status.ObservedGeneration))
+			progress("Deployment "+depnn.String(), fmt.Sprintf("Deployment %q update is being processed (generation %d, observed generation %d)", depnn.String(), dep.Generation, dep.Status.ObservedGeneration))
 		}
 
 		replicas := int32(1)
@@ -507,7 +640,7 @@ func (m *statusManager) syncState() {
 		// Check if any pods within the deployment are failing.
 		if f, err := m.podsFailing(dep.Spec.Selector, dep.Namespace); err == nil {
 			if f != "" {
-				failing = append(failing, f)
+				fail("Deployment "+depnn.String(), f)
 			}
 		} else {
 			log.WithValues("reason", err, "deployment", depnn).Info("Failed to check for failing pods")
@@ -523,9 +656,9 @@ func (m *statusManager) syncState() {
 			continue
 		}
 		if *ss.Spec.Replicas != ss.Status.CurrentReplicas {
-			progressing = append(progressing, fmt.Sprintf("Statefulset %q is not available (awaiting %d replicas)", depnn.String(), ss.Status.CurrentReplicas-*ss.Spec.Replicas))
+			progress("StatefulSet "+depnn.String(), fmt.Sprintf("Statefulset %q is not available (awaiting %d replicas)", depnn.String(), ss.Status.CurrentReplicas-*ss.Spec.Replicas))
 		} else if ss.Status.ObservedGeneration < ss.Generation {
-			progressing = append(progressing, fmt.Sprintf("Statefulset %q update is being processed (generation %d, observed generation %d)", ss.String(), ss.Generation, ss.Status.ObservedGeneration))
+			progress("StatefulSet "+depnn.String(), fmt.Sprintf("Statefulset %q update is being processed (generation %d, observed generation %d)", ss.String(), ss.Generation, ss.Status.ObservedGeneration))
 		}
 
 		replicas := int32(1)
@@ -545,7 +678,7 @@ func (m *statusManager) syncState() {
 		// Check if any pods within the deployment are failing.
 		if f, err := m.podsFailing(ss.Spec.Selector, ss.Namespace); err == nil {
 			if f != "" {
-				failing = append(failing, f)
+				fail("StatefulSet "+depnn.String(), f)
 			}
 		} else {
 			log.WithValues("reason", err, "statefuleset", depnn).Info("Failed to check for failing pods")
@@ -574,10 +707,60 @@ func (m *statusManager) syncState() {
 		}
 
 		if numFailed > 0 {
-			failing = append(failing, "cronjob/"+cj.Name+" failed in ns '"+cj.Namespace+"'")
+			fail("CronJob "+depnn.String(), "cronjob/"+cj.Name+" failed in ns '"+cj.Namespace+"'")
 		}
 	}
 
+	staged := []string{}
+	for key, pnn := range m.stagedPolicies {
+		snp := &v3.StagedNetworkPolicy{}
+		if err := m.client.Get(context.TODO(), pnn, snp); err != nil {
+			if errors.IsNotFound(err) {
+				// The policy has been promoted, or is no longer rendered.
+				delete(m.stagedPolicies, key)
+			} else {
+				log.WithValues("reason", err).Info("Failed to query staged network policy")
+			}
+			continue
+		}
+		msg := fmt.Sprintf("StagedNetworkPolicy %q is waiting to be promoted", pnn.String())
+		if denied := snp.Annotations[common.StagedRolloutDeniedFlowsAnnotation]; denied != "" && denied != "0" {
+			msg = fmt.Sprintf("StagedNetworkPolicy %q would have denied %s flows", pnn.String(), denied)
+		} else if reason := snp.Annotations[common.StagedRolloutUnverifiedAnnotation]; reason != "" {
+			msg = fmt.Sprintf("StagedNetworkPolicy %q cannot be verified: %s", pnn.String(), reason)
+		} else if since := snp.Annotations[common.StagedRolloutSinceAnnotation]; since != "" {
+			msg = fmt.Sprintf("StagedNetworkPolicy %q is waiting to be promoted (staged since %s)", pnn.String(), since)
+		}
+		staged = append(staged, msg)
+	}
+	sort.Strings(staged)
+
+	applied := []string{}
+	invalid := []string{}
+	for name, state := range m.policyExceptions {
+		exception := &operator.PolicyException{}
+		if err := m.client.Get(context.TODO(), types.NamespacedName{Name: name}, exception); err != nil {
+			if errors.IsNotFound(err) {
+				delete(m.policyExceptions, name)
+			} else {
+				log.WithValues("reason", err).Info("Failed to query policy exception")
+			}
+			continue
+		}
+		if exception.Spec.Policy != state.Policy.Name || exception.Spec.Namespace != state.Policy.Namespace {
+			// The exception no longer targets a policy of this component.
+			delete(m.policyExceptions, name)
+			continue
+		}
+		if state.Error != nil {
+			invalid = append(invalid, fmt.Sprintf("PolicyException %q for policy %q is invalid: %s", name, state.Policy.String(), state.Error))
+		} else {
+			applied = append(applied, fmt.Sprintf("PolicyException %q is applied to policy %q", name, state.Policy.String()))
+		}
+	}
+	sort.Strings(applied)
+	sort.Strings(invalid)
+
 	for _, labels := range m.certificatestatusrequests {
 		pending, err := hasPendingCSR(context.TODO(), m, labels)
 		if err != nil {
@@ -589,6 +772,10 @@ func (m *statusManager) syncState() {
 
 	m.progressing = progressing
 	m.failing = failing
+	m.notReady = notReady.SortedList()
+	m.staged = staged
+	m.appliedExceptions = applied
+	m.invalidExceptions = invalid
 	m.hasSynced = true
 }
 
@@ -688,6 +875,10 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 	// Make a copy for comparing later.
 	old := ts.DeepCopy()
 
+	// Start with any history that was recorded since the last update, and add to it any transitions
+	// caused by the new conditions.
+	history := append([]operator.TigeraStatusEvent{}, m.pendingHistory...)
+
 	// Go through each new condition. If we have an existing condition of the same type, then simply
 	// update it. Otherwise add a new one.
 	for _, condition := range conditions {
@@ -703,6 +894,9 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 				condition.LastTransitionTime = c.LastTransitionTime
 				if c.Status != condition.Status {
 					condition.LastTransitionTime = metav1.NewTime(time.Now())
+					history = m.appendHistory(history, condition)
+				} else if condition.Type == operator.ComponentDegraded && condition.Status == operator.ConditionTrue && c.Reason != condition.Reason {
+					history = m.appendHistory(history, condition)
 				}
 				ts.Status.Conditions[i] = condition
 				found = true
@@ -711,11 +905,18 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 		if !found {
 			condition.LastTransitionTime = metav1.NewTime(time.Now())
 			ts.Status.Conditions = append(ts.Status.Conditions, condition)
+			if condition.Status == operator.ConditionTrue {
+				history = m.appendHistory(history, condition)
+			}
 		}
 	}
+	ts.Status.History = append(ts.Status.History, history...)
+	if len(ts.Status.History) > maxHistory {
+		ts.Status.History = ts.Status.History[len(ts.Status.History)-maxHistory:]
+	}
 
 	// If nothing has changed, we don't need to update in the API.
-	if reflect.DeepEqual(ts.Status.Conditions, old.Status.Conditions) {
+	if reflect.DeepEqual(ts.Status, old.Status) {
 		return
 	}
 
@@ -736,6 +937,96 @@ func (m *statusManager) set(retry bool, conditions ...operator.TigeraStatusCondi
 		}
 	}
 	m.crExists = true
+	if err == nil {
+		m.pendingHistory = nil
+		m.recordEvents(&ts, history)
+	}
+}
+
+// appendHistory adds an entry for the given condition to the history. Degraded reasons that were set explicitly are
+// skipped, as SetDegraded records those itself.
+func (m *statusManager) appendHistory(history []operator.TigeraStatusEvent, condition operator.TigeraStatusCondition) []operator.TigeraStatusEvent {
+	if condition.Type == operator.ComponentDegraded && condition.Status == operator.ConditionTrue &&
+		m.explicitDegradedReason != "" && condition.Reason == string(m.explicitDegradedReason) {
+		return history
+	}
+	return append(history, m.newHistoryEntry(condition))
+}
+
+// newHistoryEntry returns a history entry for the given condition, with the objects that are not ready and the
+// duration of the most recent reconcile.
+func (m *statusManager) newHistoryEntry(condition operator.TigeraStatusCondition) operator.TigeraStatusEvent {
+	entry := operator.TigeraStatusEvent{
+		Time:    metav1.NewTime(time.Now()),
+		Type:    condition.Type,
+		Status:  condition.Status,
+		Reason:  condition.Reason,
+		Message: condition.Message,
+	}
+	if condition.Status == operator.ConditionTrue && condition.Type != operator.ComponentAvailable && len(m.notReady) > 0 {
+		entry.Objects = append([]string{}, m.notReady...)
+	}
+	if m.reconcileDuration > 0 {
+		entry.ReconcileDuration = &metav1.Duration{Duration: m.reconcileDuration}
+	}
+	return entry
+}
+
+// recordEvents emits a Kubernetes Event on the owning CR, or on the TigeraStatus if it is not known, for each of the
+// given history entries whose status or reason differs from the last Event emitted for the same condition type.
+// Failures are only logged, as the history is already recorded in the TigeraStatus itself.
+func (m *statusManager) recordEvents(ts *operator.TigeraStatus, history []operator.TigeraStatusEvent) {
+	involvedObject := corev1.ObjectReference{
+		APIVersion: operator.GroupVersion.String(),
+		Kind:       "TigeraStatus",
+		Name:       ts.Name,
+		UID:        ts.UID,
+	}
+	if m.owner != nil {
+		involvedObject = *m.owner
+	}
+	// Events for cluster scoped objects go in the default namespace.
+	namespace := involvedObject.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	for _, h := range history {
+		eventType := corev1.EventTypeNormal
+		if h.Type == operator.ComponentDegraded && h.Status == operator.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		reason := h.Reason
+		if reason == "" || reason == string(operator.Unknown) {
+			reason = string(h.Type)
+		}
+		key := fmt.Sprintf("%s/%s", h.Status, reason)
+		if m.lastEvents[h.Type] == key {
+			continue
+		}
+		m.lastEvents[h.Type] = key
+		msg := fmt.Sprintf("%s=%s", h.Type, h.Status)
+		if h.Message != "" {
+			msg = fmt.Sprintf("%s: %s", msg, h.Message)
+		}
+		ev := &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: fmt.Sprintf("%s.", involvedObject.Name),
+				Namespace:    namespace,
+			},
+			InvolvedObject: involvedObject,
+			Reason:         reason,
+			Message:        msg,
+			Type:           eventType,
+			FirstTimestamp: h.Time,
+			LastTimestamp:  h.Time,
+			Count:          1,
+			Source:         corev1.EventSource{Component: "tigera-operator"},
+		}
+		if err := m.client.Create(context.TODO(), ev); err != nil {
+			log.WithValues("reason", err).V(1).Info("Failed to record event", "component", m.component)
+		}
+	}
 }
 
 func (m *statusManager) setAvailable(reason operator.TigeraStatusReason, msg string) {
@@ -798,6 +1089,42 @@ func (m *statusManager) clearAvailable() {
 	m.set(true, conditions...)
 }
 
+// setConditions writes the conditions set with SetCondition, and clears those that have been reported but no longer
+// apply.
+func (m *statusManager) setConditions() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	conditions := []operator.TigeraStatusCondition{}
+	for conditionType, condition := range m.conditions {
+		conditions = append(conditions, condition)
+		m.reportedConditions[conditionType] = true
+	}
+	for conditionType := range m.reportedConditions {
+		if _, ok := m.conditions[conditionType]; !ok {
+			conditions = append(conditions, operator.TigeraStatusCondition{Type: conditionType, Status: operator.ConditionFalse, Reason: string(operator.Unknown), Message: ""})
+			delete(m.reportedConditions, conditionType)
+		}
+	}
+	if len(conditions) == 0 {
+		return
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].Type < conditions[j].Type })
+	m.set(true, conditions...)
+}
+
+func (m *statusManager) appliedExceptionsMessage() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return strings.Join(m.appliedExceptions, "\n")
+}
+
+func (m *statusManager) stagedMessage() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return strings.Join(m.staged, "\n")
+}
+
 func (m *statusManager) progressingMessage() string {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -812,6 +1139,7 @@ func (m *statusManager) degradedMessage() string {
 		msgs = append(msgs, m.explicitDegradedMsg)
 	}
 	msgs = append(msgs, m.failing...)
+	msgs = append(msgs, m.invalidExceptions...)
 	return strings.Join(msgs, "\n")
 }
 
@@ -831,6 +1159,9 @@ func (m *statusManager) degradedReason() operator.TigeraStatusReason {
 	if len(m.failing) != 0 {
 		return operator.PodFailure
 	}
+	if len(m.invalidExceptions) != 0 {
+		return operator.ResourceValidationError
+	}
 	return operator.Unknown
 }
 
@@ -854,10 +1185,33 @@ func (m *statusManager) clearProgressingWithReason(reason operator.TigeraStatusR
 	m.set(true, conditions...)
 }
 
-func (m *statusManager) SetMetaData(meta *metav1.ObjectMeta) {
+// ObserveReconcile records the duration of a reconcile of this component that started at the given time. It is
+// intended to be deferred at the start of Reconcile: defer r.status.ObserveReconcile(time.Now()).
+func (m *statusManager) ObserveReconcile(start time.Time) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	m.observedGeneration = meta.Generation
+	m.reconcileDuration = time.Since(start)
+}
+
+// SetMetaData records the generation of the CR that owns the component, and the CR itself so that events can be
+// emitted against it.
+func (m *statusManager) SetMetaData(owner client.Object) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.observedGeneration = owner.GetGeneration()
+
+	gvk, err := apiutil.GVKForObject(owner, m.client.Scheme())
+	if err != nil {
+		log.WithValues("reason", err).V(1).Info("Failed to determine the kind of the owning CR", "component", m.component)
+		return
+	}
+	m.owner = &corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  owner.GetNamespace(),
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}
 }
 
 func hasPendingCSR(ctx context.Context, m *statusManager, labelMap map[string]string) (bool, error) {
@@ -962,9 +1316,7 @@ func UpdateStatusCondition(statuscondition []metav1.Condition, conditions []oper
 		}
 
 		for i, c := range statuscondition {
-			if condition.Type == operator.ComponentAvailable && c.Type == string(operator.ComponentReady) ||
-				condition.Type == operator.ComponentDegraded && c.Type == string(operator.ComponentDegraded) ||
-				condition.Type == operator.ComponentProgressing && c.Type == string(operator.ComponentProgressing) {
+			if c.Type == ctype {
 				if !reflect.DeepEqual(c.Status, condition.Status) {
 					ic.LastTransitionTime = metav1.NewTime(time.Now())
 				}
@@ -16,11 +16,15 @@ package status
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+
 	appsv1 "k8s.io/api/apps/v1"
 	certV1 "k8s.io/api/certificates/v1"
 	certV1beta1 "k8s.io/api/certificates/v1beta1"
@@ -459,6 +463,316 @@ var _ = Describe("Status reporting tests", func() {
 			Expect(sm.degradedMessage()).To(Equal("Controller set us degraded: \nThis pod has died"))
 		})
 
+		Context("history", func() {
+			getStatus := func() *operator.TigeraStatus {
+				ts := &operator.TigeraStatus{}
+				Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+				return ts
+			}
+
+			It("should record a degraded reason that cleared before the next update", func() {
+				sm.ReadyToMonitor()
+				sm.updateStatus()
+				Expect(getStatus().Status.History).To(HaveLen(1))
+				Expect(getStatus().Status.History[0].Type).To(Equal(operator.ComponentAvailable))
+
+				sm.ObserveReconcile(time.Now().Add(-2 * time.Second))
+				sm.SetDegraded(operator.ResourceReadError, "Failed to read", fmt.Errorf("timeout"), log)
+				// Setting the same reason again does not add another entry.
+				sm.SetDegraded(operator.ResourceReadError, "Failed to read", fmt.Errorf("timeout"), log)
+				sm.ClearDegraded()
+				sm.updateStatus()
+
+				history := getStatus().Status.History
+				Expect(history).To(HaveLen(2))
+				Expect(history[1].Type).To(Equal(operator.ComponentDegraded))
+				Expect(history[1].Status).To(Equal(operator.ConditionTrue))
+				Expect(history[1].Reason).To(Equal(string(operator.ResourceReadError)))
+				Expect(history[1].Message).To(Equal("Failed to read: timeout"))
+				Expect(history[1].ReconcileDuration.Duration).To(BeNumerically(">=", 2*time.Second))
+
+				events := &corev1.EventList{}
+				Expect(client.List(ctx, events)).NotTo(HaveOccurred())
+				Expect(events.Items).To(HaveLen(2))
+				Expect(events.Items).To(ContainElement(And(
+					HaveField("Type", corev1.EventTypeWarning),
+					HaveField("Reason", string(operator.ResourceReadError)),
+					HaveField("Message", "Degraded=True: Failed to read: timeout"),
+					HaveField("InvolvedObject.Name", "test-component"),
+				)))
+			})
+
+			It("should record a degraded transition once", func() {
+				sm.ReadyToMonitor()
+				sm.updateStatus()
+
+				sm.SetDegraded(operator.ResourceReadError, "Failed to read", fmt.Errorf("timeout"), log)
+				sm.updateStatus()
+
+				var degraded []operator.TigeraStatusEvent
+				for _, h := range getStatus().Status.History {
+					if h.Type == operator.ComponentDegraded {
+						degraded = append(degraded, h)
+					}
+				}
+				Expect(degraded).To(HaveLen(1))
+				Expect(degraded[0].Status).To(Equal(operator.ConditionTrue))
+				Expect(degraded[0].Reason).To(Equal(string(operator.ResourceReadError)))
+
+				events := &corev1.EventList{}
+				Expect(client.List(ctx, events)).NotTo(HaveOccurred())
+				var warnings []corev1.Event
+				for _, ev := range events.Items {
+					if ev.Type == corev1.EventTypeWarning {
+						warnings = append(warnings, ev)
+					}
+				}
+				Expect(warnings).To(HaveLen(1))
+			})
+
+			It("should not emit another event when only the message of a degraded reason changes", func() {
+				sm.ReadyToMonitor()
+				sm.updateStatus()
+
+				sm.SetDegraded(operator.ResourceReadError, "Failed to read", fmt.Errorf("timeout"), log)
+				sm.updateStatus()
+				sm.SetDegraded(operator.ResourceReadError, "Failed to read", fmt.Errorf("connection refused"), log)
+				sm.updateStatus()
+
+				var degraded []operator.TigeraStatusEvent
+				for _, h := range getStatus().Status.History {
+					if h.Type == operator.ComponentDegraded {
+						degraded = append(degraded, h)
+					}
+				}
+				Expect(degraded).To(HaveLen(2))
+
+				events := &corev1.EventList{}
+				Expect(client.List(ctx, events)).NotTo(HaveOccurred())
+				var warnings []corev1.Event
+				for _, ev := range events.Items {
+					if ev.Type == corev1.EventTypeWarning {
+						warnings = append(warnings, ev)
+					}
+				}
+				Expect(warnings).To(HaveLen(1))
+				Expect(warnings[0].Message).To(Equal("Degraded=True: Failed to read: timeout"))
+
+				By("emitting an event for a new reason")
+				sm.SetDegraded(operator.ResourceNotFound, "Missing", nil, log)
+				sm.updateStatus()
+				Expect(client.List(ctx, events)).NotTo(HaveOccurred())
+				Expect(events.Items).To(ContainElement(And(
+					HaveField("Type", corev1.EventTypeWarning),
+					HaveField("Reason", string(operator.ResourceNotFound)),
+				)))
+			})
+
+			It("should emit events against the owning CR", func() {
+				sm.SetMetaData(&operator.Installation{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "installation-uid"}})
+				sm.ReadyToMonitor()
+				sm.SetDegraded(operator.ResourceReadError, "Failed to read", fmt.Errorf("timeout"), log)
+				sm.updateStatus()
+
+				events := &corev1.EventList{}
+				Expect(client.List(ctx, events)).NotTo(HaveOccurred())
+				Expect(events.Items).NotTo(BeEmpty())
+				for _, ev := range events.Items {
+					Expect(ev.Namespace).To(Equal(metav1.NamespaceDefault))
+					Expect(ev.InvolvedObject.Kind).To(Equal("Installation"))
+					Expect(ev.InvolvedObject.APIVersion).To(Equal(operator.GroupVersion.String()))
+					Expect(ev.InvolvedObject.Name).To(Equal("default"))
+					Expect(ev.InvolvedObject.UID).To(BeEquivalentTo("installation-uid"))
+				}
+			})
+
+			It("should record condition transitions with the objects that are not ready", func() {
+				sm.ReadyToMonitor()
+				sm.AddDeployments([]types.NamespacedName{{Namespace: "ns", Name: "dep"}})
+				replicas := int32(1)
+				dep := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dep"},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "dep"}},
+						Replicas: &replicas,
+					},
+					Status: appsv1.DeploymentStatus{UnavailableReplicas: 1},
+				}
+				Expect(client.Create(ctx, dep)).NotTo(HaveOccurred())
+				sm.updateStatus()
+
+				dep.Status = appsv1.DeploymentStatus{AvailableReplicas: 1, ReadyReplicas: 1}
+				Expect(client.Status().Update(ctx, dep)).NotTo(HaveOccurred())
+				sm.updateStatus()
+				// Nothing has changed so no more entries are added.
+				sm.updateStatus()
+
+				history := getStatus().Status.History
+				Expect(history).To(HaveLen(3))
+				Expect(history[0].Type).To(Equal(operator.ComponentProgressing))
+				Expect(history[0].Status).To(Equal(operator.ConditionTrue))
+				Expect(history[0].Objects).To(Equal([]string{"Deployment ns/dep"}))
+				Expect(history[1].Type).To(Equal(operator.ComponentAvailable))
+				Expect(history[1].Status).To(Equal(operator.ConditionTrue))
+				Expect(history[2].Type).To(Equal(operator.ComponentProgressing))
+				Expect(history[2].Status).To(Equal(operator.ConditionFalse))
+				Expect(history[2].Objects).To(BeEmpty())
+			})
+
+			It("should keep a bounded history", func() {
+				sm.ReadyToMonitor()
+				for i := 0; i < maxHistory+5; i++ {
+					sm.SetDegraded(operator.ResourceReadError, "Failed to read", fmt.Errorf("attempt %d", i), log)
+					sm.updateStatus()
+				}
+				history := getStatus().Status.History
+				Expect(history).To(HaveLen(maxHistory))
+				Expect(history[maxHistory-1].Message).To(Equal(fmt.Sprintf("Failed to read: attempt %d", maxHistory+4)))
+			})
+		})
+
+		It("should report staged policies until they are promoted", func() {
+			sm.ReadyToMonitor()
+			snp := &v3.StagedNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns",
+					Name:        "allow-tigera.component",
+					Annotations: map[string]string{common.StagedRolloutDeniedFlowsAnnotation: "3"},
+				},
+			}
+			Expect(client.Create(ctx, snp)).NotTo(HaveOccurred())
+			sm.AddStagedPolicies([]types.NamespacedName{{Namespace: "ns", Name: "allow-tigera.component"}})
+			sm.updateStatus()
+
+			ts := &operator.TigeraStatus{}
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", operator.ComponentPoliciesStaged),
+				HaveField("Status", operator.ConditionTrue),
+				HaveField("Reason", string(operator.StagedPoliciesPending)),
+				HaveField("Message", `StagedNetworkPolicy "ns/allow-tigera.component" would have denied 3 flows`),
+			)))
+			Expect(sm.IsAvailable()).To(BeTrue())
+
+			// A policy that could not be checked against flow logs reports why.
+			snp.Annotations = map[string]string{common.StagedRolloutUnverifiedAnnotation: "flow logs are not available"}
+			Expect(client.Update(ctx, snp)).NotTo(HaveOccurred())
+			sm.updateStatus()
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).To(ContainElement(
+				HaveField("Message", `StagedNetworkPolicy "ns/allow-tigera.component" cannot be verified: flow logs are not available`),
+			))
+
+			// Once promoted, the staged policy is deleted and the condition is cleared.
+			Expect(client.Delete(ctx, snp)).NotTo(HaveOccurred())
+			sm.updateStatus()
+			Expect(sm.stagedPolicies).To(BeEmpty())
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", operator.ComponentPoliciesStaged),
+				HaveField("Status", operator.ConditionFalse),
+			)))
+		})
+
+		It("should report applied and invalid policy exceptions", func() {
+			sm.ReadyToMonitor()
+			policy := types.NamespacedName{Namespace: "ns", Name: "allow-tigera.component"}
+			for _, name := range []string{"allow-proxy", "bad-cidr"} {
+				Expect(client.Create(ctx, &operator.PolicyException{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec:       operator.PolicyExceptionSpec{Policy: policy.Name, Namespace: policy.Namespace},
+				})).NotTo(HaveOccurred())
+			}
+			sm.AddPolicyExceptions([]PolicyExceptionState{
+				{Name: "allow-proxy", Policy: policy},
+				{Name: "bad-cidr", Policy: policy, Error: fmt.Errorf(`egress rule 0: invalid CIDR "10.0.0.0/33"`)},
+			})
+			sm.updateStatus()
+
+			Expect(sm.IsDegraded()).To(BeTrue())
+			Expect(sm.degradedReason()).To(Equal(operator.ResourceValidationError))
+			Expect(sm.degradedMessage()).To(Equal(`PolicyException "bad-cidr" for policy "ns/allow-tigera.component" is invalid: egress rule 0: invalid CIDR "10.0.0.0/33"`))
+			ts := &operator.TigeraStatus{}
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", operator.ComponentPolicyExceptionsApplied),
+				HaveField("Status", operator.ConditionTrue),
+				HaveField("Reason", string(operator.PolicyExceptionsApplied)),
+				HaveField("Message", `PolicyException "allow-proxy" is applied to policy "ns/allow-tigera.component"`),
+			)))
+
+			// Deleting the exceptions clears the degraded state and the condition.
+			for _, name := range []string{"allow-proxy", "bad-cidr"} {
+				Expect(client.Delete(ctx, &operator.PolicyException{ObjectMeta: metav1.ObjectMeta{Name: name}})).NotTo(HaveOccurred())
+			}
+			sm.updateStatus()
+			Expect(sm.policyExceptions).To(BeEmpty())
+			Expect(sm.IsDegraded()).To(BeFalse())
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", operator.ComponentPolicyExceptionsApplied),
+				HaveField("Status", operator.ConditionFalse),
+			)))
+		})
+
+		It("should report a condition until it is cleared", func() {
+			sm.ReadyToMonitor()
+			sm.SetCondition(operator.ComponentRollingOut, operator.ConditionTrue, operator.RolloutPaused, "Waiting for calico-node to become ready on nodes node-a")
+			sm.updateStatus()
+
+			ts := &operator.TigeraStatus{}
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", operator.ComponentRollingOut),
+				HaveField("Status", operator.ConditionTrue),
+				HaveField("Reason", string(operator.RolloutPaused)),
+				HaveField("Message", "Waiting for calico-node to become ready on nodes node-a"),
+			)))
+
+			// A condition keeps being reported with the values it was last set to.
+			sm.SetCondition(operator.ComponentRollingOut, operator.ConditionTrue, operator.RolloutProgressing, "2 out of 3 calico-node pods updated")
+			sm.updateStatus()
+			sm.updateStatus()
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", operator.ComponentRollingOut),
+				HaveField("Status", operator.ConditionTrue),
+				HaveField("Reason", string(operator.RolloutProgressing)),
+				HaveField("Message", "2 out of 3 calico-node pods updated"),
+			)))
+
+			sm.ClearCondition(operator.ComponentRollingOut)
+			sm.updateStatus()
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", operator.ComponentRollingOut),
+				HaveField("Status", operator.ConditionFalse),
+				HaveField("Reason", string(operator.Unknown)),
+				HaveField("Message", ""),
+			)))
+		})
+
+		It("should not report a condition that is cleared before it is set", func() {
+			sm.ReadyToMonitor()
+			sm.ClearCondition(operator.ComponentEncrypted)
+			sm.updateStatus()
+
+			ts := &operator.TigeraStatus{}
+			Expect(client.Get(ctx, types.NamespacedName{Name: "test-component"}, ts)).NotTo(HaveOccurred())
+			Expect(ts.Status.Conditions).NotTo(ContainElement(HaveField("Type", operator.ComponentEncrypted)))
+		})
+
+		It("should forget reported conditions when the CR is not found", func() {
+			sm.ReadyToMonitor()
+			sm.SetCondition(operator.ComponentMTUMismatch, operator.ConditionTrue, operator.MTUMismatchDetected, "MTU 1500 is larger than the expected pod MTU 1450")
+			sm.updateStatus()
+			Expect(sm.reportedConditions).To(HaveKey(operator.ComponentMTUMismatch))
+
+			sm.OnCRNotFound()
+			Expect(sm.conditions).To(BeEmpty())
+			Expect(sm.reportedConditions).To(BeEmpty())
+		})
+
 		It("should contain all the NamespacesNames for all the resources added by multiple calls to Set<Resources>", func() {
 			sm.AddStatefulSets([]types.NamespacedName{{Namespace: "NS1", Name: "SS1"}})
 			sm.AddStatefulSets([]types.NamespacedName{{Namespace: "NS1", Name: "SS2"}})
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/utils"
+	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
+)
+
+const (
+	// stagedPolicyRecheckInterval is how often staged allow-tigera policies are checked for promotion.
+	stagedPolicyRecheckInterval = 5 * time.Minute
+	defaultPolicySoakPeriod     = 24 * time.Hour
+)
+
+// reconcileStagedPolicies promotes the allow-tigera StagedNetworkPolicies created by the component handler once they
+// have soaked for the configured period without any would-be denied flows. Policies that cannot be checked against flow
+// logs stay staged, unless the rollout allows unverified policies to be promoted. If staged rollout is not enabled, any
+// remaining staged policies are promoted immediately. It returns whether the staged policies need to be checked again.
+func (r *ReconcileTiers) reconcileStagedPolicies(ctx context.Context, rollout *operatorv1.ComponentPolicyRollout, reqLogger logr.Logger) (bool, error) {
+	stagedPolicies := &v3.StagedNetworkPolicyList{}
+	if err := r.client.List(ctx, stagedPolicies, client.HasLabels{common.StagedRolloutLabel}); err != nil {
+		return false, err
+	}
+
+	soakPeriod := defaultPolicySoakPeriod
+	if rollout != nil && rollout.SoakPeriod != nil {
+		soakPeriod = rollout.SoakPeriod.Duration
+	}
+	promoteUnverified := rollout != nil && rollout.PromoteUnverified != nil && *rollout.PromoteUnverified
+
+	var esClient utils.ElasticClient
+	var esClientErr error
+	esClientChecked := false
+	// Staged policies belonging to a component are reported through that component's status. Those that are shared
+	// across components are reconciled by this controller and so are reported here.
+	var pending, unowned []types.NamespacedName
+	keep := func(staged *v3.StagedNetworkPolicy) {
+		pending = append(pending, client.ObjectKeyFromObject(staged))
+		if len(staged.OwnerReferences) == 0 {
+			unowned = append(unowned, client.ObjectKeyFromObject(staged))
+		}
+	}
+	for i := range stagedPolicies.Items {
+		staged := &stagedPolicies.Items[i]
+		key := client.ObjectKeyFromObject(staged)
+
+		if rollout.StagedRolloutEnabled() {
+			since, err := time.Parse(time.RFC3339, staged.Annotations[common.StagedRolloutSinceAnnotation])
+			if err != nil {
+				since = staged.CreationTimestamp.Time
+			}
+			if time.Since(since) < soakPeriod {
+				keep(staged)
+				continue
+			}
+
+			if !esClientChecked {
+				esClientChecked = true
+				esClient, esClientErr = r.flowLogsClient(ctx)
+			}
+
+			// Errors querying flow logs are reported on the policy they prevented from being verified, so that they
+			// do not hold up the rest of the tiers.
+			var denied int64
+			unverified := ""
+			switch {
+			case esClientErr != nil:
+				reqLogger.Error(esClientErr, "Failed to create a client for flow logs", "policy", key)
+				unverified = fmt.Sprintf("failed to query flow logs: %v", esClientErr)
+			case esClient == nil && promoteUnverified:
+				reqLogger.Info("Flow logs are not available, promoting staged policy after its soak period", "policy", key)
+			case esClient == nil:
+				unverified = "flow logs are not available"
+			default:
+				if denied, err = esClient.CountFlowLogs(ctx, since, deniedFlowsPattern(staged)); err != nil {
+					reqLogger.Error(err, "Failed to query flow logs for staged policy", "policy", key)
+					unverified = fmt.Sprintf("failed to query flow logs: %v", err)
+				}
+			}
+
+			if unverified != "" || denied > 0 {
+				annotations := map[string]string{common.StagedRolloutUnverifiedAnnotation: unverified}
+				if denied > 0 {
+					annotations[common.StagedRolloutDeniedFlowsAnnotation] = strconv.FormatInt(denied, 10)
+				}
+				if err = r.setStagedPolicyAnnotations(ctx, staged, annotations); err != nil {
+					return false, err
+				}
+				if denied > 0 {
+					reqLogger.Info("Staged policy would deny flows, not promoting", "policy", key, "deniedFlows", denied)
+				} else {
+					reqLogger.Info("Staged policy cannot be verified, not promoting", "policy", key, "reason", unverified)
+				}
+				keep(staged)
+				continue
+			}
+		}
+
+		reqLogger.Info("Promoting staged policy", "policy", key)
+		if err := utils.PromoteStagedPolicy(ctx, r.client, staged); err != nil {
+			return false, err
+		}
+	}
+
+	if len(unowned) > 0 {
+		r.status.AddStagedPolicies(unowned)
+	}
+
+	return rollout.StagedRolloutEnabled() || len(pending) > 0, nil
+}
+
+// setStagedPolicyAnnotations sets the given annotations on the staged policy, removing those with an empty value, and
+// updates the policy if they changed.
+func (r *ReconcileTiers) setStagedPolicyAnnotations(ctx context.Context, staged *v3.StagedNetworkPolicy, annotations map[string]string) error {
+	changed := false
+	for k, v := range annotations {
+		if current, ok := staged.Annotations[k]; v == "" && ok {
+			delete(staged.Annotations, k)
+			changed = true
+		} else if v != "" && current != v {
+			if staged.Annotations == nil {
+				staged.Annotations = map[string]string{}
+			}
+			staged.Annotations[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.client.Update(ctx, staged)
+}
+
+// flowLogsClient returns a client for querying flow logs, or nil if flow logs are not stored in this cluster.
+func (r *ReconcileTiers) flowLogsClient(ctx context.Context) (utils.ElasticClient, error) {
+	if r.multiTenant {
+		return nil, nil
+	}
+	managementClusterConnection, err := utils.GetManagementClusterConnection(ctx, r.client)
+	if err != nil {
+		return nil, err
+	}
+	if managementClusterConnection != nil {
+		// Flow logs for managed clusters are stored in the management cluster.
+		return nil, nil
+	}
+	if exists, err := utils.LogStorageExists(ctx, r.client); err != nil || !exists {
+		return nil, err
+	}
+	return r.esCliCreator(r.client, ctx, relasticsearch.ECKElasticEndpoint(), r.elasticExternal)
+}
+
+// deniedFlowsPattern returns a pattern matching the flow log policy trace entries for flows the staged policy would deny.
+// Staged policies are recorded as <index>|<tier>|<namespace>/<tier>.staged:<name>|<action>|<rule>.
+func deniedFlowsPattern(staged *v3.StagedNetworkPolicy) string {
+	name := strings.TrimPrefix(staged.Name, staged.Spec.Tier+".")
+	return fmt.Sprintf("*|%s|%s/%s.staged:%s|deny|*", staged.Spec.Tier, staged.Namespace, staged.Spec.Tier, name)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -87,11 +88,13 @@ func Add(mgr manager.Manager, opts options.AddOptions) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, opts options.AddOptions) reconcile.Reconciler {
 	r := &ReconcileTiers{
-		client:      mgr.GetClient(),
-		scheme:      mgr.GetScheme(),
-		provider:    opts.DetectedProvider,
-		status:      status.New(mgr.GetClient(), "tiers", opts.KubernetesVersion),
-		multiTenant: opts.MultiTenant,
+		client:          mgr.GetClient(),
+		scheme:          mgr.GetScheme(),
+		provider:        opts.DetectedProvider,
+		status:          status.New(mgr.GetClient(), "tiers", opts.KubernetesVersion),
+		multiTenant:     opts.MultiTenant,
+		elasticExternal: opts.ElasticExternal,
+		esCliCreator:    utils.NewElasticClient,
 	}
 	r.status.Run(opts.ShutdownContext)
 	return r
@@ -107,6 +110,8 @@ type ReconcileTiers struct {
 	tierWatchReady     *utils.ReadyFlag
 	policyWatchesReady *utils.ReadyFlag
 	multiTenant        bool
+	elasticExternal    bool
+	esCliCreator       utils.ElasticsearchClientCreator
 }
 
 // add adds watches for resources that are available at startup.
@@ -127,6 +132,7 @@ func add(mgr manager.Manager, c ctrlruntime.Controller) error {
 }
 
 func (r *ReconcileTiers) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer r.status.ObserveReconcile(time.Now())
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling Tiers")
 
@@ -167,8 +173,26 @@ func (r *ReconcileTiers) Reconcile(ctx context.Context, request reconcile.Reques
 		return reconcile.Result{}, err
 	}
 
+	_, installation, err := utils.GetInstallation(ctx, r.client)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.status.SetDegraded(operatorv1.ResourceReadError, "Error querying installation", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+	var rollout *operatorv1.ComponentPolicyRollout
+	if installation != nil {
+		rollout = installation.ComponentPolicyRollout
+	}
+	recheck, err := r.reconcileStagedPolicies(ctx, rollout, reqLogger)
+	if err != nil {
+		r.status.SetDegraded(operatorv1.ResourceUpdateError, "Error promoting staged allow-tigera policies", err, reqLogger)
+		return reconcile.Result{}, err
+	}
+
 	r.status.ReadyToMonitor()
 	r.status.ClearDegraded()
+	if recheck {
+		return reconcile.Result{RequeueAfter: stagedPolicyRecheckInterval}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -16,6 +16,8 @@ package tiers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -24,6 +26,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -32,9 +35,11 @@ import (
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
 	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/logstorage/elastic"
 	"github.com/tigera/operator/pkg/controller/status"
 	"github.com/tigera/operator/pkg/controller/utils"
 	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 )
 
 var _ = Describe("tier controller tests", func() {
@@ -193,4 +198,163 @@ var _ = Describe("tier controller tests", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 		mockStatus.AssertExpectations(GinkgoT())
 	})
+
+	Context("staged policy rollout", func() {
+		var esClient *elastic.MockESClient
+		key := client.ObjectKey{Name: "allow-tigera.test-policy", Namespace: "tigera-namespace"}
+
+		createStagedPolicy := func(since time.Time) {
+			Expect(c.Create(ctx, &v3.StagedNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+					Labels:    map[string]string{common.StagedRolloutLabel: "true"},
+					Annotations: map[string]string{
+						common.StagedRolloutHashAnnotation:  "hash",
+						common.StagedRolloutSinceAnnotation: since.UTC().Format(time.RFC3339),
+					},
+				},
+				Spec: v3.StagedNetworkPolicySpec{
+					StagedAction: v3.StagedActionSet,
+					Tier:         networkpolicy.TigeraComponentTierName,
+					Selector:     "k8s-app == 'tigera-component'",
+				},
+			})).NotTo(HaveOccurred())
+		}
+
+		// The shared policies rendered by this controller are staged as well, so only check for the test policy.
+		reportsPolicy := func(policies []types.NamespacedName) bool {
+			for _, p := range policies {
+				if p == key {
+					return true
+				}
+			}
+			return false
+		}
+
+		setRollout := func(mode operatorv1.ComponentPolicyRolloutMode) {
+			installation := &operatorv1.Installation{}
+			Expect(c.Get(ctx, utils.DefaultInstanceKey, installation)).NotTo(HaveOccurred())
+			installation.Spec.ComponentPolicyRollout = &operatorv1.ComponentPolicyRollout{
+				Mode:       &mode,
+				SoakPeriod: &metav1.Duration{Duration: time.Hour},
+			}
+			Expect(c.Update(ctx, installation)).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			mockStatus.On("ReadyToMonitor")
+			mockStatus.On("ClearDegraded")
+
+			esClient = &elastic.MockESClient{}
+			ctx = context.WithValue(ctx, elastic.MockESClientKey("mockESClient"), esClient)
+			r.esCliCreator = elastic.MockESCLICreator
+			Expect(c.Create(ctx, &operatorv1.LogStorage{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})).NotTo(HaveOccurred())
+		})
+
+		It("promotes staged policies immediately when staged rollout is not enabled", func() {
+			createStagedPolicy(time.Now())
+
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			Expect(c.Get(ctx, key, &v3.StagedNetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+			np := &v3.NetworkPolicy{}
+			Expect(c.Get(ctx, key, np)).NotTo(HaveOccurred())
+			Expect(np.Spec.Selector).To(Equal("k8s-app == 'tigera-component'"))
+			Expect(np.Annotations).To(HaveKeyWithValue(common.StagedRolloutHashAnnotation, "hash"))
+			esClient.AssertExpectations(GinkgoT())
+		})
+
+		It("keeps policies staged during the soak period", func() {
+			setRollout(operatorv1.ComponentPolicyRolloutStaged)
+			createStagedPolicy(time.Now())
+			mockStatus.On("AddStagedPolicies", mock.MatchedBy(reportsPolicy))
+
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(stagedPolicyRecheckInterval))
+			Expect(c.Get(ctx, key, &v3.StagedNetworkPolicy{})).NotTo(HaveOccurred())
+			esClient.AssertExpectations(GinkgoT())
+			mockStatus.AssertExpectations(GinkgoT())
+		})
+
+		It("promotes staged policies that would not have denied any flows", func() {
+			setRollout(operatorv1.ComponentPolicyRolloutStaged)
+			createStagedPolicy(time.Now().Add(-2 * time.Hour))
+			esClient.On("CountFlowLogs", mock.Anything, mock.Anything,
+				"*|allow-tigera|tigera-namespace/allow-tigera.staged:test-policy|deny|*").Return(int64(0), nil)
+			mockStatus.On("AddStagedPolicies", mock.Anything)
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.Get(ctx, key, &v3.StagedNetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+			Expect(c.Get(ctx, key, &v3.NetworkPolicy{})).NotTo(HaveOccurred())
+			esClient.AssertExpectations(GinkgoT())
+		})
+
+		It("keeps policies staged when they would have denied flows", func() {
+			setRollout(operatorv1.ComponentPolicyRolloutStaged)
+			createStagedPolicy(time.Now().Add(-2 * time.Hour))
+			esClient.On("CountFlowLogs", mock.Anything, mock.Anything, mock.Anything).Return(int64(5), nil)
+			mockStatus.On("AddStagedPolicies", mock.MatchedBy(reportsPolicy))
+
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			snp := &v3.StagedNetworkPolicy{}
+			Expect(c.Get(ctx, key, snp)).NotTo(HaveOccurred())
+			Expect(snp.Annotations).To(HaveKeyWithValue(common.StagedRolloutDeniedFlowsAnnotation, "5"))
+			Expect(c.Get(ctx, key, &v3.NetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+			esClient.AssertExpectations(GinkgoT())
+			mockStatus.AssertExpectations(GinkgoT())
+		})
+
+		It("keeps policies staged when flow logs are not available to verify them", func() {
+			setRollout(operatorv1.ComponentPolicyRolloutStaged)
+			createStagedPolicy(time.Now().Add(-2 * time.Hour))
+			Expect(c.Delete(ctx, &operatorv1.LogStorage{ObjectMeta: metav1.ObjectMeta{Name: "tigera-secure"}})).NotTo(HaveOccurred())
+			mockStatus.On("AddStagedPolicies", mock.MatchedBy(reportsPolicy))
+
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(stagedPolicyRecheckInterval))
+			snp := &v3.StagedNetworkPolicy{}
+			Expect(c.Get(ctx, key, snp)).NotTo(HaveOccurred())
+			Expect(snp.Annotations).To(HaveKeyWithValue(common.StagedRolloutUnverifiedAnnotation, "flow logs are not available"))
+			Expect(c.Get(ctx, key, &v3.NetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+
+			By("allowing unverified policies to be promoted")
+			installation := &operatorv1.Installation{}
+			Expect(c.Get(ctx, utils.DefaultInstanceKey, installation)).NotTo(HaveOccurred())
+			promoteUnverified := true
+			installation.Spec.ComponentPolicyRollout.PromoteUnverified = &promoteUnverified
+			Expect(c.Update(ctx, installation)).NotTo(HaveOccurred())
+			mockStatus.On("AddStagedPolicies", mock.Anything)
+
+			_, err = r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(c.Get(ctx, key, &v3.StagedNetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+			Expect(c.Get(ctx, key, &v3.NetworkPolicy{})).NotTo(HaveOccurred())
+			esClient.AssertExpectations(GinkgoT())
+		})
+
+		It("reports errors querying flow logs on the staged policy without degrading", func() {
+			setRollout(operatorv1.ComponentPolicyRolloutStaged)
+			createStagedPolicy(time.Now().Add(-2 * time.Hour))
+			esClient.On("CountFlowLogs", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), fmt.Errorf("connection refused"))
+			mockStatus.On("AddStagedPolicies", mock.MatchedBy(reportsPolicy))
+
+			result, err := r.Reconcile(ctx, reconcile.Request{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(stagedPolicyRecheckInterval))
+			snp := &v3.StagedNetworkPolicy{}
+			Expect(c.Get(ctx, key, snp)).NotTo(HaveOccurred())
+			Expect(snp.Annotations).To(HaveKeyWithValue(common.StagedRolloutUnverifiedAnnotation, "failed to query flow logs: connection refused"))
+			Expect(c.Get(ctx, key, &v3.NetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+			mockStatus.AssertNotCalled(GinkgoT(), "SetDegraded", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			esClient.AssertExpectations(GinkgoT())
+			mockStatus.AssertExpectations(GinkgoT())
+		})
+	})
 })
@@ -436,15 +436,53 @@ func (c *componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 	var deployments []types.NamespacedName
 	var statefulsets []types.NamespacedName
 	var cronJobs []types.NamespacedName
+	var stagedPolicies []types.NamespacedName
+	var policyExceptionStates []policyExceptionState
 
 	objsToCreate, objsToDelete := component.Objects()
 	osType := component.SupportedOSType()
 
 	var alreadyExistsErr error = nil
 
+	// When requested by the Installation, allow-tigera policies are staged before they are enforced. The tiers
+	// controller promotes them once they have soaked.
+	stagePolicies, err := stagedPolicyRolloutEnabled(ctx, c.client, objsToCreate, objsToDelete)
+	if err != nil {
+		cmpLog.Error(err, "Failed to determine the policy rollout mode")
+		return err
+	}
+
+	// Users can extend allow-tigera policies with PolicyExceptions, which are merged in before the policies are applied.
+	policyExceptions, err := listPolicyExceptions(ctx, c.client, objsToCreate)
+	if err != nil {
+		cmpLog.Error(err, "Failed to list policy exceptions")
+		return err
+	}
+
 	for _, obj := range objsToCreate {
 		key := client.ObjectKeyFromObject(obj)
 
+		if len(policyExceptions) > 0 && isTigeraComponentPolicy(obj) {
+			np := obj.(*v3.NetworkPolicy).DeepCopy()
+			policyExceptionStates = append(policyExceptionStates, applyPolicyExceptions(np, policyExceptions)...)
+			obj = np
+		}
+
+		if stagePolicies && isTigeraComponentPolicy(obj) {
+			desired, staged, err := stagePolicy(ctx, c.client, obj.(*v3.NetworkPolicy))
+			if err != nil {
+				cmpLog.Error(err, "Failed to stage policy", "key", key)
+				return err
+			}
+			if staged {
+				stagedPolicies = append(stagedPolicies, key)
+			} else if err = c.delete(ctx, stagedCounterpart(obj)); err != nil && !errors.IsNotFound(err) {
+				cmpLog.Error(err, "Failed to delete staged policy", "key", key)
+				return err
+			}
+			obj = desired
+		}
+
 		// Pass in a DeepCopy so any modifications made by createOrUpdateObject won't be included
 		// if we need to retry the function
 		alreadyRetriedConflict := false
@@ -495,6 +533,12 @@ func (c *componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 		if len(cronJobs) > 0 {
 			status.AddCronJobs(cronJobs)
 		}
+		if len(stagedPolicies) > 0 {
+			status.AddStagedPolicies(stagedPolicies)
+		}
+		if len(policyExceptionStates) > 0 {
+			status.AddPolicyExceptions(policyExceptionStates)
+		}
 	}
 
 	for _, obj := range objsToDelete {
@@ -504,6 +548,14 @@ func (c *componentHandler) CreateOrUpdateOrDelete(ctx context.Context, component
 			logCtx.Error(err, fmt.Sprintf("Error deleting object %v", obj))
 			return err
 		}
+		if stagePolicies && isTigeraComponentPolicy(obj) {
+			// The policy may still be staged rather than enforced.
+			if err = c.delete(ctx, stagedCounterpart(obj)); err != nil && !errors.IsNotFound(err) {
+				logCtx := ContextLoggerForResource(c.log, obj)
+				logCtx.Error(err, "Error deleting staged policy")
+				return err
+			}
+		}
 
 		key := client.ObjectKeyFromObject(obj)
 		if status != nil {
@@ -41,6 +41,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"github.com/tigera/api/pkg/lib/numorstring"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/apis"
@@ -49,6 +50,7 @@ import (
 	ctrlrfake "github.com/tigera/operator/pkg/ctrlruntime/client/fake"
 	"github.com/tigera/operator/pkg/render"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
 )
 
 const (
@@ -526,6 +528,152 @@ var _ = Describe("Component handler tests", func() {
 		Expect(ns.GetLabels()).To(Equal(expectedLabels))
 	})
 
+	Context("staged policy rollout", func() {
+		var np *v3.NetworkPolicy
+		var fc *fakeComponent
+		key := client.ObjectKey{Name: "allow-tigera.test-policy", Namespace: "tigera-namespace"}
+
+		BeforeEach(func() {
+			staged := operatorv1.ComponentPolicyRolloutStaged
+			Expect(c.Create(ctx, &operatorv1.Installation{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec: operatorv1.InstallationSpec{
+					ComponentPolicyRollout: &operatorv1.ComponentPolicyRollout{Mode: &staged},
+				},
+			})).NotTo(HaveOccurred())
+
+			np = &v3.NetworkPolicy{
+				TypeMeta:   metav1.TypeMeta{Kind: "NetworkPolicy", APIVersion: "projectcalico.org/v3"},
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: v3.NetworkPolicySpec{
+					Tier:     networkpolicy.TigeraComponentTierName,
+					Selector: "k8s-app == 'tigera-component'",
+					Egress:   []v3.Rule{{Action: v3.Allow}},
+					Types:    []v3.PolicyType{v3.PolicyTypeEgress},
+				},
+			}
+			fc = &fakeComponent{supportedOSType: rmeta.OSTypeLinux, objs: []client.Object{np}}
+		})
+
+		It("stages new policies instead of enforcing them", func() {
+			Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+			Expect(c.Get(ctx, key, &v3.NetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+			snp := &v3.StagedNetworkPolicy{}
+			Expect(c.Get(ctx, key, snp)).NotTo(HaveOccurred())
+			Expect(snp.Spec.StagedAction).To(Equal(v3.StagedActionSet))
+			Expect(snp.Spec.Selector).To(Equal(np.Spec.Selector))
+			Expect(snp.Labels).To(HaveKeyWithValue(common.StagedRolloutLabel, "true"))
+			Expect(snp.Annotations).To(HaveKeyWithValue(common.StagedRolloutHashAnnotation, rmeta.AnnotationHash(np.Spec)))
+			Expect(snp.Annotations).To(HaveKey(common.StagedRolloutSinceAnnotation))
+			Expect(snp.OwnerReferences).To(HaveLen(1))
+		})
+
+		It("leaves the enforced policy in place while a change is staged", func() {
+			enforced := np.DeepCopy()
+			enforced.Spec.Selector = "k8s-app == 'old-selector'"
+			Expect(c.Create(ctx, enforced)).NotTo(HaveOccurred())
+
+			Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+			current := &v3.NetworkPolicy{}
+			Expect(c.Get(ctx, key, current)).NotTo(HaveOccurred())
+			Expect(current.Spec.Selector).To(Equal("k8s-app == 'old-selector'"))
+			snp := &v3.StagedNetworkPolicy{}
+			Expect(c.Get(ctx, key, snp)).NotTo(HaveOccurred())
+			Expect(snp.Spec.Selector).To(Equal(np.Spec.Selector))
+		})
+
+		It("keeps promoted policies enforced", func() {
+			Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+			snp := &v3.StagedNetworkPolicy{}
+			Expect(c.Get(ctx, key, snp)).NotTo(HaveOccurred())
+
+			Expect(PromoteStagedPolicy(ctx, c, snp)).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, key, &v3.StagedNetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+			current := &v3.NetworkPolicy{}
+			Expect(c.Get(ctx, key, current)).NotTo(HaveOccurred())
+			Expect(current.Spec.Selector).To(Equal(np.Spec.Selector))
+			Expect(current.Labels).NotTo(HaveKey(common.StagedRolloutLabel))
+			Expect(current.OwnerReferences).To(HaveLen(1))
+
+			// Reconciling the same policy again does not stage it again.
+			Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+			Expect(c.Get(ctx, key, &v3.StagedNetworkPolicy{})).To(MatchError(ContainSubstring("not found")))
+		})
+	})
+
+	Context("policy exceptions", func() {
+		var fc *fakeComponent
+		key := client.ObjectKey{Name: "allow-tigera.test-policy", Namespace: "tigera-namespace"}
+		tcp := operatorv1.PolicyExceptionProtocolTCP
+
+		BeforeEach(func() {
+			np := &v3.NetworkPolicy{
+				TypeMeta:   metav1.TypeMeta{Kind: "NetworkPolicy", APIVersion: "projectcalico.org/v3"},
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: v3.NetworkPolicySpec{
+					Tier:     networkpolicy.TigeraComponentTierName,
+					Selector: "k8s-app == 'tigera-component'",
+					Egress:   []v3.Rule{{Action: v3.Allow, Destination: v3.EntityRule{Nets: []string{"10.0.0.0/8"}}}, {Action: v3.Pass}},
+					Types:    []v3.PolicyType{v3.PolicyTypeEgress},
+				},
+			}
+			fc = &fakeComponent{supportedOSType: rmeta.OSTypeLinux, objs: []client.Object{np}}
+		})
+
+		It("merges the exception rules into the targeted policy", func() {
+			Expect(c.Create(ctx, &operatorv1.PolicyException{
+				ObjectMeta: metav1.ObjectMeta{Name: "allow-proxy"},
+				Spec: operatorv1.PolicyExceptionSpec{
+					Policy:    key.Name,
+					Namespace: key.Namespace,
+					Egress: []operatorv1.PolicyExceptionRule{{
+						Nets:     []string{"192.168.1.10/32"},
+						Protocol: &tcp,
+						Ports:    []string{"3128"},
+					}},
+				},
+			})).NotTo(HaveOccurred())
+
+			Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+			current := &v3.NetworkPolicy{}
+			Expect(c.Get(ctx, key, current)).NotTo(HaveOccurred())
+			Expect(current.Spec.Egress).To(HaveLen(3))
+			Expect(current.Spec.Egress[1].Action).To(Equal(v3.Allow))
+			Expect(current.Spec.Egress[1].Destination.Nets).To(Equal([]string{"192.168.1.10/32"}))
+			Expect(current.Spec.Egress[1].Destination.Ports).To(Equal([]numorstring.Port{numorstring.SinglePort(3128)}))
+			Expect(current.Spec.Egress[2].Action).To(BeEquivalentTo(v3.Pass))
+		})
+
+		It("does not merge invalid exceptions", func() {
+			Expect(c.Create(ctx, &operatorv1.PolicyException{
+				ObjectMeta: metav1.ObjectMeta{Name: "ingress-on-egress-policy"},
+				Spec: operatorv1.PolicyExceptionSpec{
+					Policy:    key.Name,
+					Namespace: key.Namespace,
+					Ingress:   []operatorv1.PolicyExceptionRule{{Nets: []string{"192.168.1.0/24"}}},
+				},
+			})).NotTo(HaveOccurred())
+			Expect(c.Create(ctx, &operatorv1.PolicyException{
+				ObjectMeta: metav1.ObjectMeta{Name: "bad-cidr"},
+				Spec: operatorv1.PolicyExceptionSpec{
+					Policy:    key.Name,
+					Namespace: key.Namespace,
+					Egress:    []operatorv1.PolicyExceptionRule{{Nets: []string{"192.168.1.0/33"}}},
+				},
+			})).NotTo(HaveOccurred())
+
+			Expect(handler.CreateOrUpdateOrDelete(ctx, fc, sm)).NotTo(HaveOccurred())
+
+			current := &v3.NetworkPolicy{}
+			Expect(c.Get(ctx, key, current)).NotTo(HaveOccurred())
+			Expect(current.Spec.Ingress).To(BeEmpty())
+			Expect(current.Spec.Egress).To(HaveLen(2))
+		})
+	})
+
 	Context("ensureTLSCiphers", func() {
 		cipher1 := operatorv1.TLS_AES_128_GCM_SHA256
 		cipher2 := operatorv1.TLS_AES_256_GCM_SHA384
@@ -2150,8 +2298,16 @@ var _ = Describe("Mocked client Component handler tests", func() {
 			supportedOSType: rmeta.OSTypeLinux,
 			objs:            []client.Object{baseNP},
 		}
+		// The handler looks up the Installation to determine whether allow-tigera policies should be staged, and
+		// the PolicyExceptions to merge into them.
+		installationNotFound := mockReturn{
+			Method: "Get",
+			Return: errors.NewNotFound(schema.GroupResource{}, "default"),
+		}
+		noPolicyExceptions := mockReturn{Method: "List"}
 
 		It("NetworkPolicy updates are omitted if there is no change", func() {
+			mc.Info = append(mc.Info, installationNotFound, noPolicyExceptions)
 			mc.Info = append(mc.Info, mockReturn{
 				Method:       "Get",
 				Return:       nil,
@@ -2160,7 +2316,7 @@ var _ = Describe("Mocked client Component handler tests", func() {
 
 			err := handler.CreateOrUpdateOrDelete(ctx, fc, nil)
 			Expect(err).To(BeNil())
-			Expect(mc.Index).To(Equal(1))
+			Expect(mc.Index).To(Equal(3))
 		})
 
 		It("NetworkPolicy updates are applied if there is a change", func() {
@@ -2172,6 +2328,7 @@ var _ = Describe("Mocked client Component handler tests", func() {
 				}
 			}
 
+			mc.Info = append(mc.Info, installationNotFound, noPolicyExceptions)
 			mc.Info = append(mc.Info, mockReturn{
 				Method:       "Get",
 				Return:       nil,
@@ -2186,7 +2343,7 @@ var _ = Describe("Mocked client Component handler tests", func() {
 
 			err := handler.CreateOrUpdateOrDelete(ctx, fc, nil)
 			Expect(err).To(BeNil())
-			Expect(mc.Index).To(Equal(2))
+			Expect(mc.Index).To(Equal(4))
 		})
 	})
 
@@ -2314,7 +2471,24 @@ func (mc *mockClient) Get(ctx context.Context, key client.ObjectKey, obj client.
 }
 
 func (mc *mockClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
-	panic("List not implemented in mockClient")
+	defer func() { mc.Index++ }()
+	funcName := "List"
+	if len(mc.Info) <= mc.Index {
+		panic(fmt.Sprintf("mockClient Info doesn't have enough entries for %s", funcName))
+	}
+	if mc.Info[mc.Index].Method != funcName {
+		panic(fmt.Sprintf("mockClient current (%d) call is for %v, not %s", mc.Index, mc.Info[mc.Index].Method, funcName))
+	}
+	if mc.Info[mc.Index].Return == nil {
+		return nil
+	}
+
+	v, ok := mc.Info[mc.Index].Return.(error)
+	if !ok {
+		panic(fmt.Sprintf("mockClient Info didn't have right type for entry %d for %s", mc.Index, funcName))
+	}
+
+	return v
 }
 
 func (mc *mockClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
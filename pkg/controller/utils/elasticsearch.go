@@ -44,6 +44,8 @@ const (
 	DefaultMaxIndexSizeGi        = 30
 	ElasticConnRetries           = 10
 	ElasticConnRetryInterval     = "500ms"
+
+	flowLogsIndexPattern = "tigera_secure_ee_flows*"
 )
 
 type Policy struct {
@@ -117,6 +119,7 @@ type ElasticClient interface {
 	CreateUser(context.Context, *User) error
 	DeleteUser(context.Context, *User) error
 	GetUsers(ctx context.Context) ([]User, error)
+	CountFlowLogs(ctx context.Context, since time.Time, policyPattern string) (int64, error)
 }
 
 type esClient struct {
@@ -423,6 +426,16 @@ func (es *esClient) GetUsers(ctx context.Context) ([]User, error) {
 	return users, nil
 }
 
+// CountFlowLogs returns the number of flow logs that ended after the given time and whose policy trace matches the
+// given wildcard pattern.
+func (es *esClient) CountFlowLogs(ctx context.Context, since time.Time, policyPattern string) (int64, error) {
+	query := elastic.NewBoolQuery().Filter(
+		elastic.NewRangeQuery("end_time").Gte(since.Unix()),
+		elastic.NewWildcardQuery("policies.all_policies", policyPattern),
+	)
+	return es.client.Count(flowLogsIndexPattern).Query(query).Do(ctx)
+}
+
 // SetILMPolicies creates ILM policies for each timeseries based index using the retention period and storage size in LogStorage
 func (es *esClient) SetILMPolicies(ctx context.Context, ls *operatorv1.LogStorage) error {
 	policyList := es.listILMPolicies(ls)
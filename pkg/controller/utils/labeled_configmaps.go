@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/ctrlruntime"
+)
+
+// Some CRs reference ConfigMaps in the operator namespace by name, for example WAF rule packs or custom report types.
+// Their names are chosen by the user, so instead of watching every ConfigMap the controllers only watch the ConfigMaps
+// that have a label set to "true", and the referenced ConfigMaps are required to carry that label. A referenced
+// ConfigMap without the label is reported rather than used, since changes to it would go unnoticed.
+
+// MissingLabelError is returned by GetLabeledConfigMaps for a referenced ConfigMap that does not have the label.
+type MissingLabelError struct {
+	Name  string
+	Label string
+}
+
+func (e *MissingLabelError) Error() string {
+	return fmt.Sprintf("ConfigMap %s must have the label %s=true", e.Name, e.Label)
+}
+
+// IsLabeledConfigMap returns whether the object is in the operator namespace and has the given label set to "true".
+func IsLabeledConfigMap(obj client.Object, label string) bool {
+	return obj.GetNamespace() == common.OperatorNamespace() && obj.GetLabels()[label] == "true"
+}
+
+// AddLabeledConfigMapWatch watches the ConfigMaps in the operator namespace that have the given label set to "true".
+func AddLabeledConfigMapWatch(c ctrlruntime.Controller, label string, h handler.EventHandler) error {
+	return c.WatchObject(&corev1.ConfigMap{}, h, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return IsLabeledConfigMap(obj, label)
+	}))
+}
+
+// GetLabeledConfigMaps returns the named ConfigMaps from the operator namespace, in the given order. It returns a
+// *MissingLabelError if one of them does not have the given label set to "true".
+func GetLabeledConfigMaps(ctx context.Context, cli client.Client, label string, names []string) ([]*corev1.ConfigMap, error) {
+	var configMaps []*corev1.ConfigMap
+	for _, name := range names {
+		cm := &corev1.ConfigMap{}
+		if err := cli.Get(ctx, types.NamespacedName{Name: name, Namespace: common.OperatorNamespace()}, cm); err != nil {
+			return nil, err
+		}
+		if !IsLabeledConfigMap(cm, label) {
+			return nil, &MissingLabelError{Name: name, Label: label}
+		}
+		configMaps = append(configMaps, cm)
+	}
+	return configMaps, nil
+}
+
+// SetLabeledConfigMapDegraded marks the component degraded for an error returned by GetLabeledConfigMaps, where desc
+// describes the ConfigMaps, for example "custom report type ConfigMap". It returns the error to return from Reconcile.
+// Missing and unlabelled ConfigMaps have to be fixed by the user, and the watch picks up the fix, so they are not
+// retried.
+func SetLabeledConfigMapDegraded(s status.StatusManager, err error, desc string, log logr.Logger) error {
+	sentence := strings.ToUpper(desc[:1]) + desc[1:]
+	var missingLabel *MissingLabelError
+	switch {
+	case apierrors.IsNotFound(err):
+		s.SetDegraded(operatorv1.ResourceNotFound, sentence+" not found", err, log)
+		return nil
+	case errors.As(err, &missingLabel):
+		s.SetDegraded(operatorv1.ResourceValidationError, fmt.Sprintf("%s is missing the %s label", sentence, missingLabel.Label), err, log)
+		return nil
+	default:
+		s.SetDegraded(operatorv1.ResourceReadError, "Error reading "+desc, err, log)
+		return err
+	}
+}
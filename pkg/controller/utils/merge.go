@@ -123,6 +123,16 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 		override.NodeUpdateStrategy.DeepCopyInto(&inst.NodeUpdateStrategy)
 	}
 
+	switch compareFields(inst.NodeRollout, override.NodeRollout) {
+	case BOnlySet, Different:
+		inst.NodeRollout = override.NodeRollout.DeepCopy()
+	}
+
+	switch compareFields(inst.FelixOverrides, override.FelixOverrides) {
+	case BOnlySet, Different:
+		inst.FelixOverrides = override.FelixOverrides.DeepCopy()
+	}
+
 	switch compareFields(inst.ComponentResources, override.ComponentResources) {
 	case BOnlySet, Different:
 		inst.ComponentResources = make([]operatorv1.ComponentResource, len(override.ComponentResources))
@@ -157,6 +167,15 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 	case Different:
 		inst.CalicoNodeDaemonSet = mergeCalicoNodeDaemonSet(inst.CalicoNodeDaemonSet, override.CalicoNodeDaemonSet)
 	}
+
+	switch compareFields(inst.CalicoNodeGroups, override.CalicoNodeGroups) {
+	case BOnlySet, Different:
+		inst.CalicoNodeGroups = make([]operatorv1.CalicoNodeGroup, len(override.CalicoNodeGroups))
+		for i := range override.CalicoNodeGroups {
+			override.CalicoNodeGroups[i].DeepCopyInto(&inst.CalicoNodeGroups[i])
+		}
+	}
+
 	switch compareFields(inst.CSINodeDriverDaemonSet, override.CSINodeDriverDaemonSet) {
 	case BOnlySet:
 		inst.CSINodeDriverDaemonSet = override.CSINodeDriverDaemonSet.DeepCopy()
@@ -224,6 +243,11 @@ func OverrideInstallationSpec(cfg, override operatorv1.InstallationSpec) operato
 		inst.Proxy = override.Proxy
 	}
 
+	switch compareFields(inst.ComponentPolicyRollout, override.ComponentPolicyRollout) {
+	case BOnlySet, Different:
+		inst.ComponentPolicyRollout = override.ComponentPolicyRollout
+	}
+
 	return inst
 }
 
@@ -280,6 +304,11 @@ func mergeCalicoNetwork(cfg, override *operatorv1.CalicoNetworkSpec) *operatorv1
 		out.BGP = override.BGP
 	}
 
+	switch compareFields(out.BGPConfiguration, override.BGPConfiguration) {
+	case BOnlySet, Different:
+		out.BGPConfiguration = override.BGPConfiguration.DeepCopy()
+	}
+
 	switch compareFields(out.IPPools, override.IPPools) {
 	case BOnlySet, Different:
 		out.IPPools = make([]operatorv1.IPPool, len(override.IPPools))
@@ -293,6 +322,16 @@ func mergeCalicoNetwork(cfg, override *operatorv1.CalicoNetworkSpec) *operatorv1
 		out.MTU = override.MTU
 	}
 
+	switch compareFields(out.MTUDetection, override.MTUDetection) {
+	case BOnlySet, Different:
+		out.MTUDetection = override.MTUDetection
+	}
+
+	switch compareFields(out.Encryption, override.Encryption) {
+	case BOnlySet, Different:
+		out.Encryption = override.Encryption
+	}
+
 	switch compareFields(out.LinuxPolicySetupTimeoutSeconds, override.LinuxPolicySetupTimeoutSeconds) {
 	case BOnlySet, Different:
 		out.LinuxPolicySetupTimeoutSeconds = override.LinuxPolicySetupTimeoutSeconds
@@ -17,6 +17,7 @@ package utils
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -26,6 +27,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	opv1 "github.com/tigera/operator/api/v1"
@@ -222,6 +224,8 @@ var _ = Describe("Installation merge tests", func() {
 			Entry("Both set not matching", []opv1.IPPool{{CIDR: "10.0.0.0/24"}}, []opv1.IPPool{{CIDR: "172.16.0.0/8"}}, []opv1.IPPool{{CIDR: "172.16.0.0/8"}}),
 		)
 
+		mtuReport := opv1.MTUDetectionReport
+		mtuEnforce := opv1.MTUDetectionEnforce
 		DescribeTable("merge MTU", func(main, second, expect *opv1.CalicoNetworkSpec) {
 			m := opv1.InstallationSpec{}
 			s := opv1.InstallationSpec{}
@@ -263,6 +267,10 @@ var _ = Describe("Installation merge tests", func() {
 				&opv1.CalicoNetworkSpec{},
 				&opv1.CalicoNetworkSpec{MTU: intPtr(8980)},
 				&opv1.CalicoNetworkSpec{MTU: intPtr(8980)}),
+			Entry("MTU detection overridden",
+				&opv1.CalicoNetworkSpec{MTU: intPtr(1500), MTUDetection: &mtuReport},
+				&opv1.CalicoNetworkSpec{MTUDetection: &mtuEnforce},
+				&opv1.CalicoNetworkSpec{MTU: intPtr(1500), MTUDetection: &mtuEnforce}),
 		)
 
 		_true := true
@@ -594,6 +602,70 @@ var _ = Describe("Installation merge tests", func() {
 		Entry("Both set not matching", &_roll1, &_roll2, &_roll2),
 	)
 
+	_rolloutCanary := &opv1.CalicoNodeRollout{CanaryNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}}
+	_rolloutSoak := &opv1.CalicoNodeRollout{SoakPeriod: &metav1.Duration{Duration: time.Hour}}
+	DescribeTable("merge NodeRollout", func(main, second, expect *opv1.CalicoNodeRollout) {
+		m := opv1.InstallationSpec{NodeRollout: main}
+		s := opv1.InstallationSpec{NodeRollout: second}
+		inst := OverrideInstallationSpec(m, s)
+		if expect == nil {
+			Expect(inst.NodeRollout).To(BeNil())
+		} else {
+			Expect(*inst.NodeRollout).To(Equal(*expect))
+		}
+	},
+		Entry("Both unset", nil, nil, nil),
+		Entry("Main only set", _rolloutCanary, nil, _rolloutCanary),
+		Entry("Second only set", nil, _rolloutSoak, _rolloutSoak),
+		Entry("Both set equal", _rolloutCanary, _rolloutCanary, _rolloutCanary),
+		Entry("Both set not matching", _rolloutCanary, _rolloutSoak, _rolloutSoak),
+	)
+
+	_felixDebug := &opv1.FelixOverrides{Settings: map[string]string{"logSeverityScreen": "Debug"}}
+	_felixNodes := &opv1.FelixOverrides{NodeOverrides: []opv1.FelixNodeOverride{{
+		NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+		Settings:     map[string]string{"bpfLogLevel": "Debug"},
+	}}}
+	DescribeTable("merge FelixOverrides", func(main, second, expect *opv1.FelixOverrides) {
+		m := opv1.InstallationSpec{FelixOverrides: main}
+		s := opv1.InstallationSpec{FelixOverrides: second}
+		inst := OverrideInstallationSpec(m, s)
+		if expect == nil {
+			Expect(inst.FelixOverrides).To(BeNil())
+		} else {
+			Expect(*inst.FelixOverrides).To(Equal(*expect))
+		}
+	},
+		Entry("Both unset", nil, nil, nil),
+		Entry("Main only set", _felixDebug, nil, _felixDebug),
+		Entry("Second only set", nil, _felixNodes, _felixNodes),
+		Entry("Both set equal", _felixDebug, _felixDebug, _felixDebug),
+		Entry("Both set not matching", _felixDebug, _felixNodes, _felixNodes),
+	)
+
+	_gpuGroup := opv1.CalicoNodeGroup{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}}
+	_edgeGroup := opv1.CalicoNodeGroup{
+		Name:                       "edge",
+		NodeSelector:               map[string]string{"pool": "edge"},
+		NodeAddressAutodetectionV4: &opv1.NodeAddressAutodetection{Interface: "wg0"},
+	}
+	DescribeTable("merge CalicoNodeGroups", func(main, second, expect []opv1.CalicoNodeGroup) {
+		m := opv1.InstallationSpec{CalicoNodeGroups: main}
+		s := opv1.InstallationSpec{CalicoNodeGroups: second}
+		inst := OverrideInstallationSpec(m, s)
+		if expect == nil {
+			Expect(inst.CalicoNodeGroups).To(HaveLen(0))
+		} else {
+			Expect(inst.CalicoNodeGroups).To(Equal(expect))
+		}
+	},
+		Entry("Both unset", nil, nil, nil),
+		Entry("Main only set", []opv1.CalicoNodeGroup{_gpuGroup}, nil, []opv1.CalicoNodeGroup{_gpuGroup}),
+		Entry("Second only set", nil, []opv1.CalicoNodeGroup{_edgeGroup}, []opv1.CalicoNodeGroup{_edgeGroup}),
+		Entry("Both set equal", []opv1.CalicoNodeGroup{_gpuGroup}, []opv1.CalicoNodeGroup{_gpuGroup}, []opv1.CalicoNodeGroup{_gpuGroup}),
+		Entry("Both set not matching", []opv1.CalicoNodeGroup{_gpuGroup}, []opv1.CalicoNodeGroup{_gpuGroup, _edgeGroup}, []opv1.CalicoNodeGroup{_gpuGroup, _edgeGroup}),
+	)
+
 	_nodeComp := opv1.ComponentResource{
 		ComponentName: opv1.ComponentNameNode,
 		ResourceRequirements: &v1.ResourceRequirements{
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/controller/status"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
+)
+
+// policyExceptionState allows the state to be declared where the status package is shadowed by a StatusManager.
+type policyExceptionState = status.PolicyExceptionState
+
+// listPolicyExceptions returns the PolicyExceptions in the cluster, if any of the given objects is an allow-tigera
+// policy that they could apply to.
+func listPolicyExceptions(ctx context.Context, cli client.Client, objs []client.Object) ([]operatorv1.PolicyException, error) {
+	found := false
+	for _, obj := range objs {
+		if isTigeraComponentPolicy(obj) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	exceptions := &operatorv1.PolicyExceptionList{}
+	if err := cli.List(ctx, exceptions); err != nil {
+		return nil, err
+	}
+	return exceptions.Items, nil
+}
+
+// applyPolicyExceptions merges the PolicyExceptions that target the given policy into it, and returns the state of
+// each of them for reporting in the status of the component.
+func applyPolicyExceptions(np *v3.NetworkPolicy, exceptions []operatorv1.PolicyException) []status.PolicyExceptionState {
+	invalid := networkpolicy.ApplyPolicyExceptions(np, exceptions)
+
+	var states []status.PolicyExceptionState
+	for _, exception := range exceptions {
+		if exception.Spec.Policy == np.Name && exception.Spec.Namespace == np.Namespace {
+			states = append(states, status.PolicyExceptionState{
+				Name:   exception.Name,
+				Policy: client.ObjectKeyFromObject(np),
+				Error:  invalid[exception.Name],
+			})
+		}
+	}
+	return states
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tigera/operator/pkg/common"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/networkpolicy"
+)
+
+// isTigeraComponentPolicy returns true if the given object is a NetworkPolicy in the allow-tigera tier.
+func isTigeraComponentPolicy(obj client.Object) bool {
+	np, ok := obj.(*v3.NetworkPolicy)
+	if !ok {
+		return false
+	}
+	if np.Spec.Tier != "" {
+		return np.Spec.Tier == networkpolicy.TigeraComponentTierName
+	}
+	// Policies passed for deletion do not usually include a spec, so fall back to the name.
+	return strings.HasPrefix(np.Name, networkpolicy.TigeraComponentPolicyPrefix)
+}
+
+// stagedPolicyRolloutEnabled returns true if any of the given objects is an allow-tigera policy and the Installation
+// asks for allow-tigera policies to be staged before they are enforced.
+func stagedPolicyRolloutEnabled(ctx context.Context, cli client.Client, objsToCreate, objsToDelete []client.Object) (bool, error) {
+	found := false
+	for _, obj := range append(append([]client.Object{}, objsToCreate...), objsToDelete...) {
+		if isTigeraComponentPolicy(obj) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false, nil
+	}
+
+	_, installation, err := GetInstallation(ctx, cli)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return installation.ComponentPolicyRollout.StagedRolloutEnabled(), nil
+}
+
+// stagePolicy decides whether the given allow-tigera policy can be applied as-is or needs to be staged first. It returns
+// the object to create or update: either the policy itself, when the enforced policy already matches the desired spec,
+// or a StagedNetworkPolicy with the desired spec. The currently enforced policy is left untouched while staged.
+func stagePolicy(ctx context.Context, cli client.Client, np *v3.NetworkPolicy) (client.Object, bool, error) {
+	hash := rmeta.AnnotationHash(np.Spec)
+	key := client.ObjectKeyFromObject(np)
+
+	current := &v3.NetworkPolicy{}
+	if err := cli.Get(ctx, key, current); err != nil && !errors.IsNotFound(err) {
+		return nil, false, err
+	} else if err == nil && (current.Annotations[common.StagedRolloutHashAnnotation] == hash || reflect.DeepEqual(current.Spec, np.Spec)) {
+		// The desired policy is already enforced.
+		enforced := np.DeepCopy()
+		setAnnotation(enforced, common.StagedRolloutHashAnnotation, hash)
+		return enforced, false, nil
+	}
+
+	staged := &v3.StagedNetworkPolicy{
+		TypeMeta: metav1.TypeMeta{Kind: v3.KindStagedNetworkPolicy, APIVersion: v3.GroupVersionCurrent},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        np.Name,
+			Namespace:   np.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: v3.StagedNetworkPolicySpec{
+			StagedAction:           v3.StagedActionSet,
+			Tier:                   np.Spec.Tier,
+			Order:                  np.Spec.Order,
+			Ingress:                np.Spec.Ingress,
+			Egress:                 np.Spec.Egress,
+			Selector:               np.Spec.Selector,
+			Types:                  np.Spec.Types,
+			ServiceAccountSelector: np.Spec.ServiceAccountSelector,
+		},
+	}
+	for k, v := range np.Labels {
+		staged.Labels[k] = v
+	}
+	for k, v := range np.Annotations {
+		staged.Annotations[k] = v
+	}
+	staged.Labels[common.StagedRolloutLabel] = "true"
+	staged.Annotations[common.StagedRolloutHashAnnotation] = hash
+	staged.Annotations[common.StagedRolloutSinceAnnotation] = time.Now().UTC().Format(time.RFC3339)
+
+	// Keep the soak progress if the same spec is already staged.
+	existing := &v3.StagedNetworkPolicy{}
+	if err := cli.Get(ctx, key, existing); err != nil && !errors.IsNotFound(err) {
+		return nil, false, err
+	} else if err == nil && existing.Annotations[common.StagedRolloutHashAnnotation] == hash {
+		for _, a := range []string{common.StagedRolloutSinceAnnotation, common.StagedRolloutDeniedFlowsAnnotation, common.StagedRolloutUnverifiedAnnotation} {
+			if v, ok := existing.Annotations[a]; ok {
+				staged.Annotations[a] = v
+			}
+		}
+	}
+	return staged, true, nil
+}
+
+// stagedCounterpart returns a StagedNetworkPolicy with the same name and namespace as the given policy, for deletion.
+func stagedCounterpart(obj client.Object) *v3.StagedNetworkPolicy {
+	return &v3.StagedNetworkPolicy{
+		TypeMeta:   metav1.TypeMeta{Kind: v3.KindStagedNetworkPolicy, APIVersion: v3.GroupVersionCurrent},
+		ObjectMeta: metav1.ObjectMeta{Name: obj.GetName(), Namespace: obj.GetNamespace()},
+	}
+}
+
+// PromoteStagedPolicy replaces an allow-tigera StagedNetworkPolicy that was staged by the operator with the equivalent
+// enforced NetworkPolicy.
+func PromoteStagedPolicy(ctx context.Context, cli client.Client, staged *v3.StagedNetworkPolicy) error {
+	np := &v3.NetworkPolicy{}
+	err := cli.Get(ctx, client.ObjectKeyFromObject(staged), np)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if !exists {
+		np = &v3.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: staged.Name, Namespace: staged.Namespace}}
+	}
+
+	np.Labels = map[string]string{}
+	for k, v := range staged.Labels {
+		if k != common.StagedRolloutLabel {
+			np.Labels[k] = v
+		}
+	}
+	if np.Annotations == nil {
+		np.Annotations = map[string]string{}
+	}
+	np.Annotations[common.StagedRolloutHashAnnotation] = staged.Annotations[common.StagedRolloutHashAnnotation]
+	np.OwnerReferences = staged.OwnerReferences
+	np.Spec = v3.NetworkPolicySpec{
+		Tier:                   staged.Spec.Tier,
+		Order:                  staged.Spec.Order,
+		Ingress:                staged.Spec.Ingress,
+		Egress:                 staged.Spec.Egress,
+		Selector:               staged.Spec.Selector,
+		Types:                  staged.Spec.Types,
+		ServiceAccountSelector: staged.Spec.ServiceAccountSelector,
+	}
+
+	if exists {
+		err = cli.Update(ctx, np)
+	} else {
+		err = cli.Create(ctx, np)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = cli.Delete(ctx, staged); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func setAnnotation(obj client.Object, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
@@ -259,6 +259,12 @@ func WaitToAddNetworkPolicyWatches(controller ctrlruntime.Controller, c kubernet
 			ObjectMeta: metav1.ObjectMeta{Name: policy.Name, Namespace: policy.Namespace},
 		})
 	}
+	if len(objs) > 0 {
+		// PolicyExceptions add rules to the watched policies, so changes to any of them need to be reconciled.
+		objs = append(objs, &operatorv1.PolicyException{
+			TypeMeta: metav1.TypeMeta{Kind: "PolicyException", APIVersion: "operator.tigera.io/v1"},
+		})
+	}
 
 	// The success of a NetworkPolicy watch is not a dependency for resources to be installed or function correctly.
 	// Therefore, no ready flag is accepted or created for the watch.
@@ -335,6 +341,39 @@ func GetLogCollector(ctx context.Context, cli client.Client) (*operatorv1.LogCol
 	return logCollector, nil
 }
 
+// GetS3Credential returns the S3 credentials from the log-collector-s3-credentials secret in the operator namespace,
+// or nil if the secret does not exist.
+func GetS3Credential(client client.Client) (*render.S3Credential, error) {
+	secret := &corev1.Secret{}
+	secretNamespacedName := types.NamespacedName{
+		Name:      render.S3FluentdSecretName,
+		Namespace: common.OperatorNamespace(),
+	}
+	if err := client.Get(context.Background(), secretNamespacedName, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read secret %q: %s", render.S3FluentdSecretName, err)
+	}
+
+	var ok bool
+	var kId []byte
+	if kId, ok = secret.Data[render.S3KeyIdName]; !ok || len(kId) == 0 {
+		return nil, fmt.Errorf("expected secret %q to have a field named %q",
+			render.S3FluentdSecretName, render.S3KeyIdName)
+	}
+	var kSecret []byte
+	if kSecret, ok = secret.Data[render.S3KeySecretName]; !ok || len(kSecret) == 0 {
+		return nil, fmt.Errorf("expected secret %q to have a field named %q",
+			render.S3FluentdSecretName, render.S3KeySecretName)
+	}
+
+	return &render.S3Credential{
+		KeyId:     kId,
+		KeySecret: kSecret,
+	}, nil
+}
+
 // FetchLicenseKey returns the license if it has been installed. It's useful
 // to prevent rollout of TSEE components that might require it.
 // It will return an error if the license is not installed/cannot be read
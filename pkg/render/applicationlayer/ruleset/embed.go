@@ -18,8 +18,10 @@ import (
 	_ "embed"
 	"fmt"
 	"io/fs"
+	"strings"
 
 	coreruleset "github.com/corazawaf/coraza-coreruleset/v4"
+	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/render/applicationlayer"
 	corev1 "k8s.io/api/core/v1"
@@ -68,7 +70,9 @@ func GetWAFRulesetConfig() (*corev1.ConfigMap, error) {
 	return asConfigMap(applicationlayer.WAFRulesetConfigMapName, common.OperatorNamespace(), data), nil
 }
 
-func ValidateWAFRulesetConfig(cm *corev1.ConfigMap) error {
+// ValidateWAFRulesetConfig checks that the ruleset config contains the files required by dikastes, and that the
+// optional settings and rule packs can be merged into it with ApplyWAFSettings.
+func ValidateWAFRulesetConfig(cm *corev1.ConfigMap, settings *operatorv1.WAFSettings, rulePacks []*corev1.ConfigMap) error {
 	requiredFiles := []string{
 		"tigera.conf",
 		"coraza.conf",
@@ -81,6 +85,20 @@ func ValidateWAFRulesetConfig(cm *corev1.ConfigMap) error {
 		}
 	}
 
+	// Included files must be shipped with the config, except for the core rule set which is mounted separately.
+	for _, m := range includeDirective.FindAllStringSubmatch(cm.Data["tigera.conf"], -1) {
+		if strings.ContainsAny(m[1], "*/") {
+			continue
+		}
+		if _, ok := cm.Data[m[1]]; !ok {
+			return fmt.Errorf("file included from tigera.conf must be present with ruleset files: %s", m[1])
+		}
+	}
+
+	if settings != nil {
+		return validateWAFSettings(cm, settings, rulePacks)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,379 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ruleset
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// BeforeCRSFile holds the directives that must be evaluated before the core rule set is loaded.
+	BeforeCRSFile = "tigera-before-crs.conf"
+	// AfterCRSFile holds the directives that must be evaluated after the core rule set is loaded.
+	AfterCRSFile = "tigera-after-crs.conf"
+
+	// The first ID used for rules generated from RuleExclusions. IDs from 1 to 99999 are reserved for local use,
+	// so generated rules start at 10000 to stay clear of the low IDs used by hand-written rules in rule packs.
+	// Validation rejects rule packs that use any of the generated IDs.
+	exclusionRuleIDBase = 10000
+	// The first ID used for rules generated from NamespaceModes.
+	namespaceModeRuleIDBase = 20000
+
+	// RulePackLabel must be set on the ConfigMaps referenced as rule packs, so that the operator only watches
+	// ConfigMaps that are meant to be rule packs.
+	RulePackLabel = "operator.tigera.io/waf-rule-pack"
+)
+
+var (
+	includeDirective = regexp.MustCompile(`(?m)^\s*Include\s+(\S+)\s*$`)
+	crsSetupInclude  = regexp.MustCompile(`(?m)^\s*Include\s+crs-setup\.conf\s*$`)
+	configMapKey     = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+	ruleIDAction     = regexp.MustCompile(`\bid\s*:\s*'?([0-9]+)`)
+)
+
+// ApplyWAFSettings returns a copy of the given WAF ruleset config with the settings and rule packs merged in.
+// The settings are rendered into BeforeCRSFile and AfterCRSFile, which are included from tigera.conf around
+// the core rule set. Files from the rule packs are copied in with the ConfigMap name as a prefix, so that
+// packs cannot overwrite each other or the base config. The inputs are expected to have passed
+// ValidateWAFRulesetConfig.
+func ApplyWAFSettings(cm *corev1.ConfigMap, settings *operatorv1.WAFSettings, rulePacks []*corev1.ConfigMap) *corev1.ConfigMap {
+	out := cm.DeepCopy()
+	if settings == nil {
+		return out
+	}
+
+	if out.Data == nil {
+		out.Data = map[string]string{}
+	}
+
+	before := beforeCRSDirectives(settings)
+	after := afterCRSDirectives(settings)
+	for _, pack := range rulePacks {
+		for _, key := range ruleFiles(pack) {
+			name := rulePackFileName(pack.Name, key)
+			out.Data[name] = pack.Data[key]
+			after = append(after, fmt.Sprintf("Include %s", name))
+		}
+	}
+
+	tigeraConf := out.Data["tigera.conf"]
+	if len(before) > 0 {
+		out.Data[BeforeCRSFile] = strings.Join(before, "\n") + "\n"
+		loc := crsSetupInclude.FindStringIndex(tigeraConf)
+		tigeraConf = tigeraConf[:loc[1]] + "\nInclude " + BeforeCRSFile + tigeraConf[loc[1]:]
+	}
+	if len(after) > 0 {
+		out.Data[AfterCRSFile] = strings.Join(after, "\n") + "\n"
+		tigeraConf = strings.TrimRight(tigeraConf, "\n") + "\n\nInclude " + AfterCRSFile + "\n"
+	}
+	out.Data["tigera.conf"] = tigeraConf
+
+	return out
+}
+
+// beforeCRSDirectives returns the CRS setup variables and the conditional rule exclusions. Both must be
+// declared before the CRS rules so that they are in effect when the rules are evaluated.
+func beforeCRSDirectives(settings *operatorv1.WAFSettings) []string {
+	var directives []string
+
+	if settings.ParanoiaLevel != nil {
+		directives = append(directives, secAction(900000,
+			fmt.Sprintf("setvar:tx.blocking_paranoia_level=%d", *settings.ParanoiaLevel),
+		))
+	}
+
+	var thresholds []string
+	if settings.InboundAnomalyScoreThreshold != nil {
+		thresholds = append(thresholds, fmt.Sprintf("setvar:tx.inbound_anomaly_score_threshold=%d", *settings.InboundAnomalyScoreThreshold))
+	}
+	if settings.OutboundAnomalyScoreThreshold != nil {
+		thresholds = append(thresholds, fmt.Sprintf("setvar:tx.outbound_anomaly_score_threshold=%d", *settings.OutboundAnomalyScoreThreshold))
+	}
+	if len(thresholds) > 0 {
+		directives = append(directives, secAction(900110, thresholds...))
+	}
+
+	for i, exclusion := range settings.RuleExclusions {
+		if len(exclusion.Hosts) == 0 && len(exclusion.PathPrefixes) == 0 {
+			continue
+		}
+		directives = append(directives, exclusionRule(exclusionRuleIDBase+i, exclusion))
+	}
+
+	mode := defaultMode(settings)
+	for i, nm := range settings.NamespaceModes {
+		if nm.Mode == mode {
+			continue
+		}
+		directives = append(directives, namespaceModeRule(namespaceModeRuleIDBase+i, nm))
+	}
+
+	return directives
+}
+
+// afterCRSDirectives returns the unconditional rule exclusions and the rule engine mode. SecRuleRemoveById only
+// affects rules that have already been declared, so these must follow the CRS rules.
+func afterCRSDirectives(settings *operatorv1.WAFSettings) []string {
+	var directives []string
+
+	for _, exclusion := range settings.RuleExclusions {
+		if len(exclusion.Hosts) > 0 || len(exclusion.PathPrefixes) > 0 {
+			continue
+		}
+		for _, id := range exclusion.RuleIDs {
+			directives = append(directives, fmt.Sprintf("SecRuleRemoveById %d", id))
+		}
+	}
+
+	// The mode is only written when it is configured, otherwise the ruleset's own SecRuleEngine applies.
+	if settings.Mode != nil || len(settings.NamespaceModes) > 0 {
+		directives = append(directives, "SecRuleEngine "+ruleEngine(defaultMode(settings)))
+	}
+
+	return directives
+}
+
+// defaultMode returns the mode for requests that do not match any of the NamespaceModes.
+func defaultMode(settings *operatorv1.WAFSettings) operatorv1.WAFMode {
+	if settings.Mode != nil {
+		return *settings.Mode
+	}
+	return operatorv1.WAFModeDetectionOnly
+}
+
+// ruleEngine returns the SecRuleEngine value for the given mode.
+func ruleEngine(mode operatorv1.WAFMode) string {
+	if mode == operatorv1.WAFModeBlocking {
+		return "On"
+	}
+	return "DetectionOnly"
+}
+
+func secAction(id int, actions ...string) string {
+	lines := []string{
+		fmt.Sprintf("SecAction \\\n    \"id:%d,\\", id),
+		"    phase:1,\\",
+		"    nolog,\\",
+		"    pass,\\",
+		"    t:none,\\",
+	}
+	for _, a := range actions[:len(actions)-1] {
+		lines = append(lines, fmt.Sprintf("    %s,\\", a))
+	}
+	lines = append(lines, fmt.Sprintf("    %s\"", actions[len(actions)-1]))
+	return strings.Join(lines, "\n")
+}
+
+// exclusionRule renders a runtime rule exclusion that removes the given rules for matching requests. When both
+// hosts and paths are given the request must match both.
+func exclusionRule(id int, exclusion operatorv1.WAFRuleExclusion) string {
+	type match struct {
+		variable string
+		pattern  string
+	}
+	var matches []match
+	if len(exclusion.Hosts) > 0 {
+		matches = append(matches, match{"REQUEST_HEADERS:Host", fmt.Sprintf("^(?:%s)(?::[0-9]+)?$", quoteAll(exclusion.Hosts))})
+	}
+	if len(exclusion.PathPrefixes) > 0 {
+		matches = append(matches, match{"REQUEST_FILENAME", fmt.Sprintf("^(?:%s)", quoteAll(exclusion.PathPrefixes))})
+	}
+
+	var ctls []string
+	for _, ruleID := range exclusion.RuleIDs {
+		ctls = append(ctls, fmt.Sprintf("ctl:ruleRemoveById=%d", ruleID))
+	}
+
+	var b strings.Builder
+	first := matches[0]
+	fmt.Fprintf(&b, "SecRule %s \"@rx %s\" \\\n    \"id:%d,\\\n    phase:1,\\\n    pass,\\\n    nolog,\\\n    t:none,\\\n", first.variable, first.pattern, id)
+	if len(matches) == 1 {
+		fmt.Fprintf(&b, "    %s\"", strings.Join(ctls, ",\\\n    "))
+		return b.String()
+	}
+	second := matches[1]
+	fmt.Fprintf(&b, "    chain\"\n    SecRule %s \"@rx %s\" \\\n        \"t:none,\\\n        %s\"", second.variable, second.pattern, strings.Join(ctls, ",\\\n        "))
+	return b.String()
+}
+
+// namespaceModeRule renders a rule that switches the rule engine to the mode of the namespace for requests to its
+// services. The namespace is taken from the Host header, which holds the service's DNS name for in-cluster requests:
+// <service>.<namespace>, optionally followed by .svc and the cluster domain. The client controls the Host header, so
+// validation only allows namespace modes that are stricter than the default mode; a forged Host header can then only
+// turn blocking on, never off.
+func namespaceModeRule(id int, nm operatorv1.WAFNamespaceMode) string {
+	pattern := fmt.Sprintf(`^[^.:]+\.%s(?:\.svc(?:\.[^:]+)?)?\.?(?::[0-9]+)?$`, regexp.QuoteMeta(nm.Namespace))
+	return fmt.Sprintf("SecRule REQUEST_HEADERS:Host \"@rx %s\" \\\n    \"id:%d,\\\n    phase:1,\\\n    pass,\\\n    nolog,\\\n    t:none,\\\n    ctl:ruleEngine=%s\"",
+		pattern, id, ruleEngine(nm.Mode))
+}
+
+// generatedRuleIDs returns the IDs of the rules generated from the settings by beforeCRSDirectives.
+func generatedRuleIDs(settings *operatorv1.WAFSettings) map[string]bool {
+	ids := map[string]bool{}
+	for _, directive := range beforeCRSDirectives(settings) {
+		if m := ruleIDAction.FindStringSubmatch(directive); m != nil {
+			ids[m[1]] = true
+		}
+	}
+	return ids
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = regexp.QuoteMeta(v)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// ruleFiles returns the keys of the rule pack that hold ruleset files, in a stable order.
+func ruleFiles(pack *corev1.ConfigMap) []string {
+	var keys []string
+	for k := range pack.Data {
+		if strings.HasSuffix(k, ".conf") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func rulePackFileName(pack, key string) string {
+	return fmt.Sprintf("%s-%s", pack, key)
+}
+
+// RulePackNames returns the names of the rule pack ConfigMaps referenced by the WAF settings, in the order they are
+// listed.
+func RulePackNames(settings *operatorv1.WAFSettings) []string {
+	if settings == nil {
+		return nil
+	}
+	var names []string
+	for _, rp := range settings.RulePacks {
+		names = append(names, rp.ConfigMapName)
+	}
+	return names
+}
+
+// validateRulePackLabels checks that every rule pack has the RulePackLabel. Rule packs without the label are not
+// watched, so changes to them would go unnoticed.
+func validateRulePackLabels(rulePacks []*corev1.ConfigMap) error {
+	for _, pack := range rulePacks {
+		if pack.Labels[RulePackLabel] != "true" {
+			return fmt.Errorf("rule pack %s must have the label %s=true", pack.Name, RulePackLabel)
+		}
+	}
+	return nil
+}
+
+// validateWAFSettings checks that the settings can be applied to the given ruleset config.
+func validateWAFSettings(cm *corev1.ConfigMap, settings *operatorv1.WAFSettings, rulePacks []*corev1.ConfigMap) error {
+	if settings.ParanoiaLevel != nil && (*settings.ParanoiaLevel < 1 || *settings.ParanoiaLevel > 4) {
+		return fmt.Errorf("paranoiaLevel must be between 1 and 4, got %d", *settings.ParanoiaLevel)
+	}
+	if settings.InboundAnomalyScoreThreshold != nil && *settings.InboundAnomalyScoreThreshold < 1 {
+		return fmt.Errorf("inboundAnomalyScoreThreshold must be at least 1")
+	}
+	if settings.OutboundAnomalyScoreThreshold != nil && *settings.OutboundAnomalyScoreThreshold < 1 {
+		return fmt.Errorf("outboundAnomalyScoreThreshold must be at least 1")
+	}
+
+	if len(beforeCRSDirectives(settings)) > 0 && !crsSetupInclude.MatchString(cm.Data["tigera.conf"]) {
+		return fmt.Errorf("tigera.conf must contain \"Include crs-setup.conf\" to apply paranoiaLevel, anomaly score thresholds, host and path rule exclusions or namespaceModes")
+	}
+
+	for i, exclusion := range settings.RuleExclusions {
+		if len(exclusion.RuleIDs) == 0 {
+			return fmt.Errorf("ruleExclusions[%d] must list at least one rule ID", i)
+		}
+		for _, id := range exclusion.RuleIDs {
+			if id <= 0 {
+				return fmt.Errorf("ruleExclusions[%d] contains invalid rule ID %d", i, id)
+			}
+		}
+		for _, host := range exclusion.Hosts {
+			if host == "" || strings.ContainsAny(host, " /\"") {
+				return fmt.Errorf("ruleExclusions[%d] contains invalid host %q", i, host)
+			}
+		}
+		for _, path := range exclusion.PathPrefixes {
+			if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \"") {
+				return fmt.Errorf("ruleExclusions[%d] contains invalid path prefix %q, it must start with /", i, path)
+			}
+		}
+	}
+
+	namespaces := map[string]bool{}
+	for _, nm := range settings.NamespaceModes {
+		if namespaces[nm.Namespace] {
+			return fmt.Errorf("namespace %s is listed more than once in namespaceModes", nm.Namespace)
+		}
+		namespaces[nm.Namespace] = true
+		if nm.Mode != operatorv1.WAFModeBlocking && nm.Mode != operatorv1.WAFModeDetectionOnly {
+			return fmt.Errorf("namespace %s has invalid mode %q", nm.Namespace, nm.Mode)
+		}
+		if nm.Mode == operatorv1.WAFModeDetectionOnly && defaultMode(settings) == operatorv1.WAFModeBlocking {
+			return fmt.Errorf("namespace %s cannot use mode DetectionOnly while mode is Blocking, namespaceModes can only make the mode stricter", nm.Namespace)
+		}
+	}
+
+	if err := validateRulePackLabels(rulePacks); err != nil {
+		return err
+	}
+	if len(rulePacks) != len(settings.RulePacks) {
+		return fmt.Errorf("expected %d rule pack ConfigMaps, got %d", len(settings.RulePacks), len(rulePacks))
+	}
+	generated := generatedRuleIDs(settings)
+	packs := map[string]bool{}
+	// File names are the pack name and key joined by a dash, so files of different packs can end up with the same
+	// name, for example pack a with key b-c.conf and pack a-b with key c.conf.
+	fileOwners := map[string]string{}
+	for _, pack := range rulePacks {
+		if packs[pack.Name] {
+			return fmt.Errorf("rule pack %s is listed more than once", pack.Name)
+		}
+		packs[pack.Name] = true
+		files := ruleFiles(pack)
+		if len(files) == 0 {
+			return fmt.Errorf("rule pack %s does not contain any .conf files", pack.Name)
+		}
+		for _, key := range files {
+			name := rulePackFileName(pack.Name, key)
+			if !configMapKey.MatchString(name) {
+				return fmt.Errorf("rule pack %s contains invalid file name %s", pack.Name, key)
+			}
+			if _, ok := cm.Data[name]; ok {
+				return fmt.Errorf("rule pack %s file %s conflicts with an existing ruleset file", pack.Name, key)
+			}
+			if owner, ok := fileOwners[name]; ok {
+				return fmt.Errorf("rule pack %s file %s conflicts with a file of rule pack %s, both are stored as %s", pack.Name, key, owner, name)
+			}
+			fileOwners[name] = pack.Name
+			for _, m := range ruleIDAction.FindAllStringSubmatch(pack.Data[key], -1) {
+				if generated[m[1]] {
+					return fmt.Errorf("rule pack %s file %s uses rule ID %s, which is generated from the WAF settings", pack.Name, key, m[1])
+				}
+			}
+		}
+	}
+
+	return nil
+}
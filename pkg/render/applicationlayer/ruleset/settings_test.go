@@ -0,0 +1,214 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ruleset
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/ptr"
+)
+
+func rulePack(name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{RulePackLabel: "true"}},
+		Data:       data,
+	}
+}
+
+func TestApplyWAFSettingsWithoutSettings(t *testing.T) {
+	cm, err := GetWAFRulesetConfig()
+	require.NoError(t, err)
+	require.NoError(t, ValidateWAFRulesetConfig(cm, nil, nil))
+
+	out := ApplyWAFSettings(cm, nil, nil)
+	require.Equal(t, cm.Data, out.Data)
+}
+
+func TestApplyWAFSettings(t *testing.T) {
+	cm, err := GetWAFRulesetConfig()
+	require.NoError(t, err)
+
+	blocking := operatorv1.WAFModeBlocking
+	settings := &operatorv1.WAFSettings{
+		Mode:                          &blocking,
+		ParanoiaLevel:                 ptr.Int32ToPtr(3),
+		InboundAnomalyScoreThreshold:  ptr.Int32ToPtr(10),
+		OutboundAnomalyScoreThreshold: ptr.Int32ToPtr(8),
+		RulePacks:                     []operatorv1.WAFRulePack{{ConfigMapName: "pack-a"}},
+		RuleExclusions: []operatorv1.WAFRuleExclusion{
+			{RuleIDs: []int32{920100}},
+			{RuleIDs: []int32{942100, 942200}, Hosts: []string{"api.example.com"}, PathPrefixes: []string{"/upload"}},
+		},
+	}
+	packs := []*corev1.ConfigMap{rulePack("pack-a", map[string]string{"b.conf": "# b", "a.conf": "# a", "README": "ignored"})}
+	require.NoError(t, ValidateWAFRulesetConfig(cm, settings, packs))
+
+	out := ApplyWAFSettings(cm, settings, packs)
+
+	// The input must not be modified.
+	require.NotContains(t, cm.Data, BeforeCRSFile)
+
+	tigeraConf := out.Data["tigera.conf"]
+	setup := strings.Index(tigeraConf, "Include crs-setup.conf")
+	before := strings.Index(tigeraConf, "Include "+BeforeCRSFile)
+	crs := strings.Index(tigeraConf, "Include coreruleset/*.conf")
+	after := strings.Index(tigeraConf, "Include "+AfterCRSFile)
+	require.True(t, setup < before && before < crs && crs < after, "unexpected include order:\n%s", tigeraConf)
+
+	require.Contains(t, out.Data[BeforeCRSFile], "setvar:tx.blocking_paranoia_level=3")
+	require.Contains(t, out.Data[BeforeCRSFile], "setvar:tx.inbound_anomaly_score_threshold=10")
+	require.Contains(t, out.Data[BeforeCRSFile], "setvar:tx.outbound_anomaly_score_threshold=8")
+	require.Contains(t, out.Data[BeforeCRSFile], `SecRule REQUEST_HEADERS:Host "@rx ^(?:api\.example\.com)(?::[0-9]+)?$"`)
+	require.Contains(t, out.Data[BeforeCRSFile], `SecRule REQUEST_FILENAME "@rx ^(?:/upload)"`)
+	require.Contains(t, out.Data[BeforeCRSFile], "ctl:ruleRemoveById=942200")
+
+	require.Equal(t, "SecRuleRemoveById 920100\nSecRuleEngine On\nInclude pack-a-a.conf\nInclude pack-a-b.conf\n", out.Data[AfterCRSFile])
+	require.Equal(t, "# a", out.Data["pack-a-a.conf"])
+	require.NotContains(t, out.Data, "pack-a-README")
+}
+
+func TestApplyWAFSettingsBlockingNamespace(t *testing.T) {
+	cm, err := GetWAFRulesetConfig()
+	require.NoError(t, err)
+
+	settings := &operatorv1.WAFSettings{
+		NamespaceModes: []operatorv1.WAFNamespaceMode{
+			{Namespace: "shop", Mode: operatorv1.WAFModeBlocking},
+			{Namespace: "docs", Mode: operatorv1.WAFModeDetectionOnly},
+		},
+	}
+	require.NoError(t, ValidateWAFRulesetConfig(cm, settings, nil))
+
+	out := ApplyWAFSettings(cm, settings, nil)
+	require.Equal(t, "SecRuleEngine DetectionOnly\n", out.Data[AfterCRSFile])
+	require.Contains(t, out.Data[BeforeCRSFile], `SecRule REQUEST_HEADERS:Host "@rx ^[^.:]+\.shop(?:\.svc(?:\.[^:]+)?)?\.?(?::[0-9]+)?$"`)
+	require.Contains(t, out.Data[BeforeCRSFile], "ctl:ruleEngine=On")
+	require.NotContains(t, out.Data[BeforeCRSFile], "docs")
+}
+
+func TestValidateWAFRulesetConfig(t *testing.T) {
+	blocking := operatorv1.WAFModeBlocking
+	for _, tc := range []struct {
+		name     string
+		mutate   func(cm *corev1.ConfigMap)
+		settings *operatorv1.WAFSettings
+		packs    []*corev1.ConfigMap
+		err      string
+	}{
+		{
+			name:   "missing required file",
+			mutate: func(cm *corev1.ConfigMap) { delete(cm.Data, "coraza.conf") },
+			err:    "file must be present with ruleset files: coraza.conf",
+		},
+		{
+			name:   "missing included file",
+			mutate: func(cm *corev1.ConfigMap) { cm.Data["tigera.conf"] += "\nInclude extra.conf\n" },
+			err:    "file included from tigera.conf must be present with ruleset files: extra.conf",
+		},
+		{
+			name:     "paranoia level out of range",
+			settings: &operatorv1.WAFSettings{ParanoiaLevel: ptr.Int32ToPtr(5)},
+			err:      "paranoiaLevel must be between 1 and 4",
+		},
+		{
+			name:     "setup variables without crs-setup.conf include",
+			mutate:   func(cm *corev1.ConfigMap) { cm.Data["tigera.conf"] = "Include coraza.conf\n" },
+			settings: &operatorv1.WAFSettings{ParanoiaLevel: ptr.Int32ToPtr(2)},
+			err:      "tigera.conf must contain \"Include crs-setup.conf\"",
+		},
+		{
+			name:     "exclusion without rule IDs",
+			settings: &operatorv1.WAFSettings{RuleExclusions: []operatorv1.WAFRuleExclusion{{Hosts: []string{"a"}}}},
+			err:      "ruleExclusions[0] must list at least one rule ID",
+		},
+		{
+			name:     "relative path prefix",
+			settings: &operatorv1.WAFSettings{RuleExclusions: []operatorv1.WAFRuleExclusion{{RuleIDs: []int32{1}, PathPrefixes: []string{"api"}}}},
+			err:      "ruleExclusions[0] contains invalid path prefix",
+		},
+		{
+			name: "duplicate namespace",
+			settings: &operatorv1.WAFSettings{NamespaceModes: []operatorv1.WAFNamespaceMode{
+				{Namespace: "a", Mode: operatorv1.WAFModeBlocking},
+				{Namespace: "a", Mode: operatorv1.WAFModeDetectionOnly},
+			}},
+			err: "namespace a is listed more than once",
+		},
+		{
+			name:     "rule pack without label",
+			settings: &operatorv1.WAFSettings{RulePacks: []operatorv1.WAFRulePack{{ConfigMapName: "unlabelled"}}},
+			packs: []*corev1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Name: "unlabelled"},
+				Data:       map[string]string{"custom.conf": ""},
+			}},
+			err: "rule pack unlabelled must have the label operator.tigera.io/waf-rule-pack=true",
+		},
+		{
+			name:     "namespace modes without crs-setup.conf include",
+			mutate:   func(cm *corev1.ConfigMap) { cm.Data["tigera.conf"] = "Include coraza.conf\n" },
+			settings: &operatorv1.WAFSettings{NamespaceModes: []operatorv1.WAFNamespaceMode{{Namespace: "a", Mode: operatorv1.WAFModeBlocking}}},
+			err:      "tigera.conf must contain \"Include crs-setup.conf\"",
+		},
+		{
+			name: "namespace mode weaker than the default mode",
+			settings: &operatorv1.WAFSettings{
+				Mode:           &blocking,
+				NamespaceModes: []operatorv1.WAFNamespaceMode{{Namespace: "a", Mode: operatorv1.WAFModeDetectionOnly}},
+			},
+			err: "namespace a cannot use mode DetectionOnly while mode is Blocking",
+		},
+		{
+			name: "rule pack using a generated rule ID",
+			settings: &operatorv1.WAFSettings{
+				RulePacks:      []operatorv1.WAFRulePack{{ConfigMapName: "pack"}},
+				RuleExclusions: []operatorv1.WAFRuleExclusion{{RuleIDs: []int32{1}, Hosts: []string{"a"}}},
+			},
+			packs: []*corev1.ConfigMap{rulePack("pack", map[string]string{"custom.conf": `SecRule REQUEST_URI "@streq /a" "id:10000,phase:1,deny"`})},
+			err:   "rule pack pack file custom.conf uses rule ID 10000, which is generated from the WAF settings",
+		},
+		{
+			name:     "rule pack without ruleset files",
+			settings: &operatorv1.WAFSettings{RulePacks: []operatorv1.WAFRulePack{{ConfigMapName: "empty"}}},
+			packs:    []*corev1.ConfigMap{rulePack("empty", map[string]string{"notes.txt": ""})},
+			err:      "rule pack empty does not contain any .conf files",
+		},
+		{
+			name:     "rule packs with colliding file names",
+			settings: &operatorv1.WAFSettings{RulePacks: []operatorv1.WAFRulePack{{ConfigMapName: "a"}, {ConfigMapName: "a-b"}}},
+			packs: []*corev1.ConfigMap{
+				rulePack("a", map[string]string{"b-c.conf": "# a"}),
+				rulePack("a-b", map[string]string{"c.conf": "# a-b"}),
+			},
+			err: "rule pack a-b file c.conf conflicts with a file of rule pack a, both are stored as a-b-c.conf",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cm, err := GetWAFRulesetConfig()
+			require.NoError(t, err)
+			if tc.mutate != nil {
+				tc.mutate(cm)
+			}
+			err = ValidateWAFRulesetConfig(cm, tc.settings, tc.packs)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
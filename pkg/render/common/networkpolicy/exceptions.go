@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"net"
+
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
+	"github.com/tigera/api/pkg/lib/numorstring"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+)
+
+// ApplyPolicyExceptions merges the rules of the given PolicyExceptions into the policy. The rules are inserted after the
+// leading Allow rules of the policy, so that they take effect before any of its Pass or Deny rules. Exceptions that
+// target a different policy are ignored. Invalid exceptions are not applied; the returned map holds the validation
+// error of each, keyed by the name of the exception.
+func ApplyPolicyExceptions(np *v3.NetworkPolicy, exceptions []operatorv1.PolicyException) map[string]error {
+	invalid := map[string]error{}
+	for _, exception := range exceptions {
+		if exception.Spec.Policy != np.Name || exception.Spec.Namespace != np.Namespace {
+			continue
+		}
+
+		ingress, egress, err := policyExceptionRules(np, exception.Spec)
+		if err != nil {
+			invalid[exception.Name] = err
+			continue
+		}
+		np.Spec.Ingress = insertAfterAllowRules(np.Spec.Ingress, ingress)
+		np.Spec.Egress = insertAfterAllowRules(np.Spec.Egress, egress)
+	}
+	return invalid
+}
+
+// policyExceptionRules validates the exception against the policy and converts it into Calico rules.
+func policyExceptionRules(np *v3.NetworkPolicy, spec operatorv1.PolicyExceptionSpec) ([]v3.Rule, []v3.Rule, error) {
+	if len(spec.Ingress) > 0 && !hasPolicyType(np, v3.PolicyTypeIngress) {
+		return nil, nil, fmt.Errorf("policy %s/%s does not apply to ingress traffic", np.Namespace, np.Name)
+	}
+	if len(spec.Egress) > 0 && !hasPolicyType(np, v3.PolicyTypeEgress) {
+		return nil, nil, fmt.Errorf("policy %s/%s does not apply to egress traffic", np.Namespace, np.Name)
+	}
+
+	var ingress, egress []v3.Rule
+	for i, r := range spec.Ingress {
+		rule, err := policyExceptionRule(r, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ingress rule %d: %w", i, err)
+		}
+		ingress = append(ingress, rule)
+	}
+	for i, r := range spec.Egress {
+		rule, err := policyExceptionRule(r, true)
+		if err != nil {
+			return nil, nil, fmt.Errorf("egress rule %d: %w", i, err)
+		}
+		egress = append(egress, rule)
+	}
+	return ingress, egress, nil
+}
+
+func policyExceptionRule(r operatorv1.PolicyExceptionRule, egress bool) (v3.Rule, error) {
+	if len(r.Nets) == 0 && len(r.Domains) == 0 && r.Selector == "" && r.NamespaceSelector == "" && len(r.Ports) == 0 {
+		return v3.Rule{}, fmt.Errorf("at least one of nets, domains, selector, namespaceSelector or ports must be set")
+	}
+	if len(r.Domains) > 0 && !egress {
+		return v3.Rule{}, fmt.Errorf("domains are only valid for egress rules")
+	}
+	if len(r.Ports) > 0 && r.Protocol == nil {
+		return v3.Rule{}, fmt.Errorf("protocol must be set when ports are set")
+	}
+	for _, n := range r.Nets {
+		if _, _, err := net.ParseCIDR(n); err != nil {
+			return v3.Rule{}, fmt.Errorf("invalid CIDR %q", n)
+		}
+	}
+
+	rule := v3.Rule{Action: v3.Allow}
+	if r.Protocol != nil {
+		protocol := numorstring.ProtocolFromString(string(*r.Protocol))
+		rule.Protocol = &protocol
+	}
+
+	peer := v3.EntityRule{
+		Nets:              r.Nets,
+		Selector:          r.Selector,
+		NamespaceSelector: r.NamespaceSelector,
+	}
+	var ports []numorstring.Port
+	for _, p := range r.Ports {
+		port, err := numorstring.PortFromString(p)
+		if err != nil || port.PortName != "" {
+			return v3.Rule{}, fmt.Errorf("invalid port %q", p)
+		}
+		ports = append(ports, port)
+	}
+
+	if egress {
+		peer.Domains = r.Domains
+		peer.Ports = ports
+		rule.Destination = peer
+	} else {
+		rule.Source = peer
+		rule.Destination = v3.EntityRule{Ports: ports}
+	}
+	return rule, nil
+}
+
+func hasPolicyType(np *v3.NetworkPolicy, t v3.PolicyType) bool {
+	for _, pt := range np.Spec.Types {
+		if pt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// insertAfterAllowRules inserts the extra rules after the leading Allow rules of the given rules.
+func insertAfterAllowRules(rules, extra []v3.Rule) []v3.Rule {
+	if len(extra) == 0 {
+		return rules
+	}
+	i := 0
+	for i < len(rules) && rules[i].Action == v3.Allow {
+		i++
+	}
+	merged := make([]v3.Rule, 0, len(rules)+len(extra))
+	merged = append(merged, rules[:i]...)
+	merged = append(merged, extra...)
+	return append(merged, rules[i:]...)
+}
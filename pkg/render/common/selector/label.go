@@ -14,7 +14,13 @@
 
 package selector
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 const (
 	OpenShiftDNSDaemonsetLabel = "dns.operator.openshift.io/daemonset-dns"
@@ -29,3 +35,46 @@ func PodLabelSelector(name string) *metav1.LabelSelector {
 		},
 	}
 }
+
+// CalicoSelector converts a Kubernetes label selector into the equivalent Calico selector expression. A nil selector
+// is converted to an empty expression, and a selector without requirements to all().
+func CalicoSelector(ls *metav1.LabelSelector) string {
+	if ls == nil {
+		return ""
+	}
+
+	var terms []string
+	keys := make([]string, 0, len(ls.MatchLabels))
+	for k := range ls.MatchLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		terms = append(terms, fmt.Sprintf("%s == '%s'", k, ls.MatchLabels[k]))
+	}
+	for _, req := range ls.MatchExpressions {
+		switch req.Operator {
+		case metav1.LabelSelectorOpIn:
+			terms = append(terms, fmt.Sprintf("%s in {%s}", req.Key, quotedValues(req.Values)))
+		case metav1.LabelSelectorOpNotIn:
+			terms = append(terms, fmt.Sprintf("%s not in {%s}", req.Key, quotedValues(req.Values)))
+		case metav1.LabelSelectorOpExists:
+			terms = append(terms, fmt.Sprintf("has(%s)", req.Key))
+		case metav1.LabelSelectorOpDoesNotExist:
+			terms = append(terms, fmt.Sprintf("!has(%s)", req.Key))
+		}
+	}
+
+	if len(terms) == 0 {
+		return "all()"
+	}
+	return strings.Join(terms, " && ")
+}
+
+func quotedValues(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("'%s'", v)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -18,6 +18,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -27,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 
@@ -57,6 +59,17 @@ const (
 	ComplianceServerPolicyName                                = networkpolicy.TigeraComponentPolicyPrefix + ComplianceServerName
 	MultiTenantComplianceManagedClustersAccessRoleBindingName = "compliance-server-managed-cluster-access"
 
+	// CustomReportTypeLabel is set on the GlobalReportTypes that are read from the ConfigMaps listed in the Compliance
+	// CR. Its value is the name of the ConfigMap.
+	CustomReportTypeLabel = "operator.tigera.io/custom-report-type"
+
+	// CustomReportTypeConfigMapLabel must be set to "true" on the ConfigMaps listed in the Compliance CR that hold
+	// custom report types.
+	CustomReportTypeConfigMapLabel = "operator.tigera.io/compliance-report-types"
+
+	// ComplianceReportLabel is set on the GlobalReports that are created from the reports listed in the Compliance CR.
+	ComplianceReportLabel = "operator.tigera.io/compliance-report"
+
 	// ServiceAccount names.
 	ComplianceServerServiceAccount      = "tigera-compliance-server"
 	ComplianceSnapshotterServiceAccount = "tigera-compliance-snapshotter"
@@ -84,8 +97,18 @@ func init() {
 }
 
 func Compliance(cfg *ComplianceConfiguration) (Component, error) {
+	customReportTypes, err := customReportTypes(cfg.CustomReportTypeConfigMaps)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Compliance != nil {
+		if err := validateComplianceReports(cfg.Compliance.Spec.Reports, customReportTypes); err != nil {
+			return nil, err
+		}
+	}
 	return &complianceComponent{
-		cfg: cfg,
+		cfg:               cfg,
+		customReportTypes: customReportTypes,
 	}, nil
 }
 
@@ -117,6 +140,15 @@ type ComplianceConfiguration struct {
 	Tenant          *operatorv1.Tenant
 	ExternalElastic bool
 	Compliance      *operatorv1.Compliance
+
+	// The ConfigMaps listed in the CustomReportTypes of the Compliance CR, in the order they are listed.
+	CustomReportTypeConfigMaps []*corev1.ConfigMap
+	// The names of the custom GlobalReportTypes that currently exist, so that those that are no longer
+	// provided by a ConfigMap can be deleted.
+	CustomReportTypesDeployed []string
+	// The names of the GlobalReports created from the Compliance CR that currently exist, so that those that are
+	// no longer listed can be deleted.
+	ReportsDeployed []string
 }
 
 type complianceComponent struct {
@@ -126,6 +158,8 @@ type complianceComponent struct {
 	serverImage      string
 	controllerImage  string
 	reporterImage    string
+
+	customReportTypes []*v3.GlobalReportType
 }
 
 func (c *complianceComponent) ResolveImages(is *operatorv1.ImageSet) error {
@@ -223,6 +257,8 @@ func (c *complianceComponent) Objects() ([]client.Object, []client.Object) {
 			c.complianceGlobalReportNetworkAccess(),
 			c.complianceGlobalReportPolicyAudit(),
 			c.complianceGlobalReportCISBenchmark(),
+			c.complianceGlobalReportPCIDSSNetworkSegmentation(),
+			c.complianceGlobalReportNSAHardening(),
 		)
 	}
 
@@ -232,6 +268,36 @@ func (c *complianceComponent) Objects() ([]client.Object, []client.Object) {
 	}
 
 	var objsToDelete []client.Object
+	if !c.cfg.Tenant.MultiTenant() {
+		rendered := map[string]bool{}
+		for _, rt := range c.customReportTypes {
+			complianceObjs = append(complianceObjs, rt)
+			rendered[rt.Name] = true
+		}
+		for _, name := range c.cfg.CustomReportTypesDeployed {
+			if !rendered[name] {
+				objsToDelete = append(objsToDelete, &v3.GlobalReportType{
+					TypeMeta:   metav1.TypeMeta{Kind: "GlobalReportType", APIVersion: "projectcalico.org/v3"},
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+				})
+			}
+		}
+
+		renderedReports := map[string]bool{}
+		for _, r := range c.globalReports() {
+			complianceObjs = append(complianceObjs, r)
+			renderedReports[r.Name] = true
+		}
+		for _, name := range c.cfg.ReportsDeployed {
+			if !renderedReports[name] {
+				objsToDelete = append(objsToDelete, &v3.GlobalReport{
+					TypeMeta:   metav1.TypeMeta{Kind: "GlobalReport", APIVersion: "projectcalico.org/v3"},
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+				})
+			}
+		}
+	}
+
 	if c.cfg.ManagementClusterConnection == nil {
 		complianceObjs = append(complianceObjs,
 			c.complianceServerAllowTigeraNetworkPolicy(),
@@ -1623,6 +1689,231 @@ func (c *complianceComponent) getCISDownloadReportTemplates() []v3.ReportTemplat
 	}
 }
 
+func (c *complianceComponent) complianceGlobalReportPCIDSSNetworkSegmentation() *v3.GlobalReportType {
+	return &v3.GlobalReportType{
+		TypeMeta: metav1.TypeMeta{Kind: "GlobalReportType", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pci-dss-network-segmentation",
+			Labels: map[string]string{
+				"global-report-type": "pci-dss-network-segmentation",
+			},
+		},
+		Spec: v3.ReportTypeSpec{
+			DownloadTemplates: []v3.ReportTemplate{
+				{
+					Name: "requirements.csv",
+					Template: `requirement,description,status,endpointsFailing,endpointsInScope
+1.3.1,Inbound traffic to the CDE is restricted,{{ if eq .EndpointsSummary.NumIngressProtected .EndpointsSummary.NumTotal }}PASS{{ else }}FAIL{{ end }},{{ sub .EndpointsSummary.NumTotal .EndpointsSummary.NumIngressProtected }},{{ .EndpointsSummary.NumTotal }}
+1.3.2,Outbound traffic from the CDE is restricted,{{ if eq .EndpointsSummary.NumEgressProtected .EndpointsSummary.NumTotal }}PASS{{ else }}FAIL{{ end }},{{ sub .EndpointsSummary.NumTotal .EndpointsSummary.NumEgressProtected }},{{ .EndpointsSummary.NumTotal }}
+1.3.2,Outbound traffic from the CDE to the internet is restricted,{{ if eq .EndpointsSummary.NumEgressToInternet 0 }}PASS{{ else }}FAIL{{ end }},{{ .EndpointsSummary.NumEgressToInternet }},{{ .EndpointsSummary.NumTotal }}
+1.4.2,Inbound traffic from untrusted networks to the CDE is restricted,{{ if eq .EndpointsSummary.NumIngressFromInternet 0 }}PASS{{ else }}FAIL{{ end }},{{ .EndpointsSummary.NumIngressFromInternet }},{{ .EndpointsSummary.NumTotal }}
+11.4.5,The CDE is segmented from workloads in other namespaces,{{ if eq .EndpointsSummary.NumIngressFromOtherNamespace 0 }}PASS{{ else }}FAIL{{ end }},{{ .EndpointsSummary.NumIngressFromOtherNamespace }},{{ .EndpointsSummary.NumTotal }}
+`,
+				},
+				{
+					Name: "endpoints.csv",
+					Template: `
+      {{ $c := csv }}
+      {{- $c := $c.AddColumn "endpoint"                  "{{ .Endpoint }}" }}
+      {{- $c := $c.AddColumn "ingressProtected"          "{{ .IngressProtected }}" }}
+      {{- $c := $c.AddColumn "egressProtected"           "{{ .EgressProtected }}" }}
+      {{- $c := $c.AddColumn "ingressFromInternet"       "{{ .IngressFromInternet }}" }}
+      {{- $c := $c.AddColumn "egressToInternet"          "{{ .EgressToInternet }}" }}
+      {{- $c := $c.AddColumn "ingressFromOtherNamespace" "{{ .IngressFromOtherNamespace }}" }}
+      {{- $c := $c.AddColumn "appliedPolicies"           "{{ join \";\" .AppliedPolicies }}" }}
+      {{- $c := $c.AddColumn "failedRequirements"        "{{ if not .IngressProtected }}1.3.1 {{ end }}{{ if or (not .EgressProtected) .EgressToInternet }}1.3.2 {{ end }}{{ if .IngressFromInternet }}1.4.2 {{ end }}{{ if .IngressFromOtherNamespace }}11.4.5{{ end }}" }}
+      {{- $c := $c.AddColumn "trafficAggregationPrefix"  "{{ flowsPrefix . }}" }}
+      {{- $c := $c.AddColumn "endpointsGeneratingTrafficToThisEndpoint"  "{{ join \";\" (flowsIngress .) }}" }}
+      {{- $c.Render .Endpoints }}
+`,
+				},
+			},
+			IncludeEndpointData:        true,
+			IncludeEndpointFlowLogData: true,
+			UISummaryTemplate: v3.ReportTemplate{
+				Name: "ui-summary.json",
+				Template: `
+    {"heading":"PCI DSS Network Segmentation of the CDE","type":"panel","widgets":[{"data":[{"label":"Inbound restricted (1.3.1)","value":{{ .EndpointsSummary.NumIngressProtected }}}],"heading":"Endpoints","summary":{"label":"Total","total":{{ .EndpointsSummary.NumTotal }}},"type":"radialbarchart"},{"data":[{"label":"Outbound restricted (1.3.2)","value":{{ .EndpointsSummary.NumEgressProtected }}}],"heading":"Endpoints","summary":{"label":"Total","total":{{ .EndpointsSummary.NumTotal }}},"type":"radialbarchart"},{"data":[{"label":"Not reachable from the internet (1.4.2)","value":{{ sub .EndpointsSummary.NumTotal .EndpointsSummary.NumIngressFromInternet }}}],"heading":"Endpoints","summary":{"label":"Total","total":{{ .EndpointsSummary.NumTotal }}},"type":"radialbarchart"},{"data":[{"label":"Segmented from other namespaces (11.4.5)","value":{{ sub .EndpointsSummary.NumTotal .EndpointsSummary.NumIngressFromOtherNamespace }}}],"heading":"Endpoints","summary":{"label":"Total","total":{{ .EndpointsSummary.NumTotal }}},"type":"radialbarchart"}]}
+`,
+			},
+		},
+	}
+}
+
+func (c *complianceComponent) complianceGlobalReportNSAHardening() *v3.GlobalReportType {
+	downloadTemplates := []v3.ReportTemplate{
+		{
+			Name: "checks.csv",
+			Template: `section,check,status,failing,total
+Network separation,Network policies restrict ingress to every pod,{{ if eq .EndpointsSummary.NumIngressProtected .EndpointsSummary.NumTotal }}PASS{{ else }}FAIL{{ end }},{{ sub .EndpointsSummary.NumTotal .EndpointsSummary.NumIngressProtected }},{{ .EndpointsSummary.NumTotal }}
+Network separation,Network policies restrict egress from every pod,{{ if eq .EndpointsSummary.NumEgressProtected .EndpointsSummary.NumTotal }}PASS{{ else }}FAIL{{ end }},{{ sub .EndpointsSummary.NumTotal .EndpointsSummary.NumEgressProtected }},{{ .EndpointsSummary.NumTotal }}
+Network separation,Every namespace denies ingress by default,{{ if eq .NamespacesSummary.NumIngressProtected .NamespacesSummary.NumTotal }}PASS{{ else }}FAIL{{ end }},{{ sub .NamespacesSummary.NumTotal .NamespacesSummary.NumIngressProtected }},{{ .NamespacesSummary.NumTotal }}
+Network separation,Every namespace denies egress by default,{{ if eq .NamespacesSummary.NumEgressProtected .NamespacesSummary.NumTotal }}PASS{{ else }}FAIL{{ end }},{{ sub .NamespacesSummary.NumTotal .NamespacesSummary.NumEgressProtected }},{{ .NamespacesSummary.NumTotal }}
+Network separation,Pods are not reachable from the internet,{{ if eq .EndpointsSummary.NumIngressFromInternet 0 }}PASS{{ else }}FAIL{{ end }},{{ .EndpointsSummary.NumIngressFromInternet }},{{ .EndpointsSummary.NumTotal }}
+{{ $n := len .CISBenchmark -}}
+Node hardening,Nodes pass the CIS Kubernetes Benchmark,{{ if eq .CISBenchmarkSummary.HighCount $n }}PASS{{ else }}FAIL{{ end }},{{ sub $n .CISBenchmarkSummary.HighCount }},{{ $n }}
+`,
+		},
+		{
+			Name: "endpoints.csv",
+			Template: `
+      {{ $c := csv }}
+      {{- $c := $c.AddColumn "endpoint"            "{{ .Endpoint }}" }}
+      {{- $c := $c.AddColumn "ingressProtected"    "{{ .IngressProtected }}" }}
+      {{- $c := $c.AddColumn "egressProtected"     "{{ .EgressProtected }}" }}
+      {{- $c := $c.AddColumn "ingressFromInternet" "{{ .IngressFromInternet }}" }}
+      {{- $c := $c.AddColumn "appliedPolicies"     "{{ join \";\" .AppliedPolicies }}" }}
+      {{- $c.Render .Endpoints }}
+`,
+		},
+	}
+	// The node hardening checks are detailed in the same way as in the CIS benchmark report.
+	for _, t := range c.getCISDownloadReportTemplates() {
+		if t.Name == "failed-tests.csv" || t.Name == "node-summary.csv" {
+			downloadTemplates = append(downloadTemplates, t)
+		}
+	}
+
+	return &v3.GlobalReportType{
+		TypeMeta: metav1.TypeMeta{Kind: "GlobalReportType", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "nsa-cisa-kubernetes-hardening",
+			Labels: map[string]string{
+				"global-report-type": "nsa-cisa-kubernetes-hardening",
+			},
+		},
+		Spec: v3.ReportTypeSpec{
+			DownloadTemplates:       downloadTemplates,
+			IncludeEndpointData:     true,
+			IncludeCISBenchmarkData: true,
+			UISummaryTemplate: v3.ReportTemplate{
+				Name: "ui-summary.json",
+				Template: `
+    {"heading":"NSA/CISA Kubernetes Hardening","type":"panel","widgets":[{"data":[{"label":"Ingress restricted","value":{{ .EndpointsSummary.NumIngressProtected }}}],"heading":"Endpoints","summary":{"label":"Total","total":{{ .EndpointsSummary.NumTotal }}},"type":"radialbarchart"},{"data":[{"label":"Egress restricted","value":{{ .EndpointsSummary.NumEgressProtected }}}],"heading":"Endpoints","summary":{"label":"Total","total":{{ .EndpointsSummary.NumTotal }}},"type":"radialbarchart"},{"data":[{"label":"Default deny ingress","value":{{ .NamespacesSummary.NumIngressProtected }}}],"heading":"Namespaces","summary":{"label":"Total","total":{{ .NamespacesSummary.NumTotal }}},"type":"radialbarchart"},{"data":[{"label":"Passing the CIS benchmark","value":{{ .CISBenchmarkSummary.HighCount }}}],"heading":"Nodes","summary":{"label":"Total","total":{{ len .CISBenchmark }}},"type":"radialbarchart"}]}
+`,
+			},
+		},
+	}
+}
+
+// builtInReportTypes are the names of the GlobalReportTypes that are installed by the operator.
+var builtInReportTypes = map[string]bool{
+	"inventory":                     true,
+	"network-access":                true,
+	"policy-audit":                  true,
+	"cis-benchmark":                 true,
+	"pci-dss-network-segmentation":  true,
+	"nsa-cisa-kubernetes-hardening": true,
+}
+
+// customReportTypes returns the GlobalReportTypes held by the given ConfigMaps. Every key ending in ".yaml" must hold
+// a GlobalReportType manifest.
+func customReportTypes(configMaps []*corev1.ConfigMap) ([]*v3.GlobalReportType, error) {
+	var reportTypes []*v3.GlobalReportType
+	seen := map[string]string{}
+	for _, cm := range configMaps {
+		keys := make([]string, 0, len(cm.Data))
+		for k := range cm.Data {
+			if strings.HasSuffix(k, ".yaml") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			rt := &v3.GlobalReportType{}
+			if err := yaml.UnmarshalStrict([]byte(cm.Data[k]), rt); err != nil {
+				return nil, fmt.Errorf("failed to parse %s in ConfigMap %s: %w", k, cm.Name, err)
+			}
+			if rt.Kind != "GlobalReportType" {
+				return nil, fmt.Errorf("%s in ConfigMap %s is a %q, expected a GlobalReportType", k, cm.Name, rt.Kind)
+			}
+			if rt.Name == "" {
+				return nil, fmt.Errorf("the GlobalReportType in %s in ConfigMap %s has no name", k, cm.Name)
+			}
+			if builtInReportTypes[rt.Name] {
+				return nil, fmt.Errorf("the GlobalReportType in %s in ConfigMap %s cannot replace the %s report type", k, cm.Name, rt.Name)
+			}
+			if other, ok := seen[rt.Name]; ok {
+				return nil, fmt.Errorf("the GlobalReportType %s is provided by both ConfigMap %s and %s", rt.Name, other, cm.Name)
+			}
+			seen[rt.Name] = cm.Name
+
+			labels := map[string]string{}
+			for lk, lv := range rt.Labels {
+				labels[lk] = lv
+			}
+			labels["global-report-type"] = rt.Name
+			labels[CustomReportTypeLabel] = cm.Name
+			reportTypes = append(reportTypes, &v3.GlobalReportType{
+				TypeMeta: metav1.TypeMeta{Kind: "GlobalReportType", APIVersion: "projectcalico.org/v3"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        rt.Name,
+					Labels:      labels,
+					Annotations: rt.Annotations,
+				},
+				Spec: rt.Spec,
+			})
+		}
+	}
+	return reportTypes, nil
+}
+
+// validateComplianceReports checks that the reports listed in the Compliance CR have unique names and use a report type
+// that is installed by the operator.
+func validateComplianceReports(reports []operatorv1.ComplianceReport, customReportTypes []*v3.GlobalReportType) error {
+	reportTypes := map[string]bool{}
+	for name := range builtInReportTypes {
+		reportTypes[name] = true
+	}
+	for _, rt := range customReportTypes {
+		reportTypes[rt.Name] = true
+	}
+	seen := map[string]bool{}
+	for _, r := range reports {
+		if seen[r.Name] {
+			return fmt.Errorf("the report %s is listed more than once", r.Name)
+		}
+		seen[r.Name] = true
+		if !reportTypes[r.ReportType] {
+			return fmt.Errorf("the report %s uses the unknown report type %s", r.Name, r.ReportType)
+		}
+	}
+	return nil
+}
+
+// globalReports returns the GlobalReports for the reports listed in the Compliance CR.
+func (c *complianceComponent) globalReports() []*v3.GlobalReport {
+	if c.cfg.Compliance == nil {
+		return nil
+	}
+	var reports []*v3.GlobalReport
+	for _, r := range c.cfg.Compliance.Spec.Reports {
+		report := &v3.GlobalReport{
+			TypeMeta: metav1.TypeMeta{Kind: "GlobalReport", APIVersion: "projectcalico.org/v3"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   r.Name,
+				Labels: map[string]string{ComplianceReportLabel: "true"},
+			},
+			Spec: v3.ReportSpec{
+				ReportType: r.ReportType,
+				Schedule:   r.Schedule,
+			},
+		}
+		if r.EndpointSelector != "" || len(r.Namespaces) > 0 || r.NamespaceSelector != "" {
+			report.Spec.Endpoints = &v3.EndpointsSelection{Selector: r.EndpointSelector}
+			if len(r.Namespaces) > 0 || r.NamespaceSelector != "" {
+				report.Spec.Endpoints.Namespaces = &v3.NamesAndLabelsMatch{
+					Names:    r.Namespaces,
+					Selector: r.NamespaceSelector,
+				}
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
 // Allow internal communication from compliance-benchmarker, compliance-controller, compliance-snapshotter, compliance-reporter
 // to apiserver, coredns, linseed, and elasticsearch.
 func (c *complianceComponent) complianceAccessAllowTigeraNetworkPolicy() *v3.NetworkPolicy {
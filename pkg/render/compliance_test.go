@@ -16,6 +16,7 @@ package render_test
 
 import (
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -380,6 +381,8 @@ var _ = Describe("compliance rendering tests", func() {
 				{"network-access", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"policy-audit", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"cis-benchmark", "", "projectcalico.org", "v3", "GlobalReportType"},
+				{"pci-dss-network-segmentation", "", "projectcalico.org", "v3", "GlobalReportType"},
+				{"nsa-cisa-kubernetes-hardening", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"allow-tigera.compliance-server", ns, "projectcalico.org", "v3", "NetworkPolicy"},
 				{"tigera-compliance-server", "", rbac, "v1", "ClusterRole"},
 				{"compliance", ns, "", "v1", "Service"},
@@ -398,6 +401,8 @@ var _ = Describe("compliance rendering tests", func() {
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "network-access", "", "projectcalico.org", "v3", "GlobalReportType"), "network-access")
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "policy-audit", "", "projectcalico.org", "v3", "GlobalReportType"), "policy-audit")
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "cis-benchmark", "", "projectcalico.org", "v3", "GlobalReportType"), "cis-benchmark")
+			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "pci-dss-network-segmentation", "", "projectcalico.org", "v3", "GlobalReportType"), "pci-dss-network-segmentation")
+			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "nsa-cisa-kubernetes-hardening", "", "projectcalico.org", "v3", "GlobalReportType"), "nsa-cisa-kubernetes-hardening")
 
 			clusterRole := rtest.GetResource(resources, "tigera-compliance-server", "", rbac, "v1", "ClusterRole").(*rbacv1.ClusterRole)
 			Expect(clusterRole.Rules).To(ConsistOf([]rbacv1.PolicyRule{
@@ -488,6 +493,137 @@ var _ = Describe("compliance rendering tests", func() {
 		})
 	})
 
+	Context("custom report types", func() {
+		customReportType := func(name string) string {
+			return `apiVersion: projectcalico.org/v3
+kind: GlobalReportType
+metadata:
+  name: ` + name + `
+  labels:
+    team: security
+spec:
+  includeEndpointData: true
+  uiSummaryTemplate:
+    name: ui-summary.json
+    template: '{"heading":"Custom"}'
+  downloadTemplates:
+  - name: summary.csv
+    template: '{{ .EndpointsSummary.NumTotal }}'
+`
+		}
+
+		It("should render the report types held by the listed ConfigMaps", func() {
+			cfg.CustomReportTypeConfigMaps = []*corev1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Name: "custom-reports", Namespace: common.OperatorNamespace()},
+				Data: map[string]string{
+					"soc2.yaml":  customReportType("soc2"),
+					"README.md":  "ignored",
+					"hipaa.yaml": customReportType("hipaa"),
+				},
+			}}
+			cfg.CustomReportTypesDeployed = []string{"soc2", "iso-27001"}
+			component, err := render.Compliance(cfg)
+			Expect(err).ShouldNot(HaveOccurred())
+			resources, toDelete := component.Objects()
+
+			for _, name := range []string{"hipaa", "soc2"} {
+				rt := rtest.GetResource(resources, name, "", "projectcalico.org", "v3", "GlobalReportType").(*v3.GlobalReportType)
+				rtest.ExpectGlobalReportType(rt, name)
+				Expect(rt.Labels).To(Equal(map[string]string{
+					"team":                       "security",
+					"global-report-type":         name,
+					render.CustomReportTypeLabel: "custom-reports",
+				}))
+				Expect(rt.Spec.IncludeEndpointData).To(BeTrue())
+			}
+			Expect(rtest.GetResource(toDelete, "iso-27001", "", "projectcalico.org", "v3", "GlobalReportType")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, "soc2", "", "projectcalico.org", "v3", "GlobalReportType")).To(BeNil())
+		})
+
+		DescribeTable("should reject invalid report types", func(data string) {
+			cfg.CustomReportTypeConfigMaps = []*corev1.ConfigMap{
+				{ObjectMeta: metav1.ObjectMeta{Name: "custom-reports"}, Data: map[string]string{"soc2.yaml": customReportType("soc2")}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "more-reports"}, Data: map[string]string{"report.yaml": data}},
+			}
+			_, err := render.Compliance(cfg)
+			Expect(err).To(HaveOccurred())
+		},
+			Entry("not a GlobalReportType", strings.Replace(customReportType("hipaa"), "GlobalReportType", "GlobalReport", 1)),
+			Entry("no name", customReportType("")),
+			Entry("a built-in report type", customReportType("cis-benchmark")),
+			Entry("a duplicate name", customReportType("soc2")),
+			Entry("an unknown field", customReportType("hipaa")+"  unknown: true\n"),
+		)
+	})
+
+	Context("scheduled reports", func() {
+		BeforeEach(func() {
+			cfg.Compliance = &operatorv1.Compliance{Spec: operatorv1.ComplianceSpec{
+				Reports: []operatorv1.ComplianceReport{
+					{Name: "daily-inventory", ReportType: "inventory", Schedule: "0 0 * * *"},
+					{
+						Name:              "weekly-pci",
+						ReportType:        "pci-dss-network-segmentation",
+						Schedule:          "0 0 * * 0",
+						EndpointSelector:  "pci == 'true'",
+						Namespaces:        []string{"payments"},
+						NamespaceSelector: "env == 'prod'",
+					},
+				},
+			}}
+		})
+
+		It("should render the listed reports and delete those that are no longer listed", func() {
+			cfg.ReportsDeployed = []string{"daily-inventory", "hourly-audit"}
+			component, err := render.Compliance(cfg)
+			Expect(err).ShouldNot(HaveOccurred())
+			resources, toDelete := component.Objects()
+
+			inventory := rtest.GetResource(resources, "daily-inventory", "", "projectcalico.org", "v3", "GlobalReport").(*v3.GlobalReport)
+			Expect(inventory.Labels).To(Equal(map[string]string{render.ComplianceReportLabel: "true"}))
+			Expect(inventory.Spec).To(Equal(v3.ReportSpec{ReportType: "inventory", Schedule: "0 0 * * *"}))
+
+			pci := rtest.GetResource(resources, "weekly-pci", "", "projectcalico.org", "v3", "GlobalReport").(*v3.GlobalReport)
+			Expect(pci.Spec).To(Equal(v3.ReportSpec{
+				ReportType: "pci-dss-network-segmentation",
+				Schedule:   "0 0 * * 0",
+				Endpoints: &v3.EndpointsSelection{
+					Selector:   "pci == 'true'",
+					Namespaces: &v3.NamesAndLabelsMatch{Names: []string{"payments"}, Selector: "env == 'prod'"},
+				},
+			}))
+
+			Expect(rtest.GetResource(toDelete, "hourly-audit", "", "projectcalico.org", "v3", "GlobalReport")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, "daily-inventory", "", "projectcalico.org", "v3", "GlobalReport")).To(BeNil())
+		})
+
+		It("should allow reports of custom report types", func() {
+			cfg.Compliance.Spec.Reports[0].ReportType = "soc2"
+			cfg.CustomReportTypeConfigMaps = []*corev1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Name: "custom-reports"},
+				Data: map[string]string{"soc2.yaml": `apiVersion: projectcalico.org/v3
+kind: GlobalReportType
+metadata:
+  name: soc2
+`},
+			}}
+			_, err := render.Compliance(cfg)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should reject reports of unknown report types", func() {
+			cfg.Compliance.Spec.Reports[0].ReportType = "soc2"
+			_, err := render.Compliance(cfg)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject duplicate report names", func() {
+			cfg.Compliance.Spec.Reports[1].Name = "daily-inventory"
+			_, err := render.Compliance(cfg)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Context("Management cluster", func() {
 		It("should render all resources for a default configuration", func() {
 			cfg.ManagementCluster = &operatorv1.ManagementCluster{}
@@ -526,6 +662,8 @@ var _ = Describe("compliance rendering tests", func() {
 				{"network-access", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"policy-audit", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"cis-benchmark", "", "projectcalico.org", "v3", "GlobalReportType"},
+				{"pci-dss-network-segmentation", "", "projectcalico.org", "v3", "GlobalReportType"},
+				{"nsa-cisa-kubernetes-hardening", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"allow-tigera.compliance-server", ns, "projectcalico.org", "v3", "NetworkPolicy"},
 				{"tigera-compliance-server", "", rbac, "v1", "ClusterRole"},
 				{"compliance", ns, "", "v1", "Service"},
@@ -544,6 +682,8 @@ var _ = Describe("compliance rendering tests", func() {
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "network-access", "", "projectcalico.org", "v3", "GlobalReportType"), "network-access")
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "policy-audit", "", "projectcalico.org", "v3", "GlobalReportType"), "policy-audit")
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "cis-benchmark", "", "projectcalico.org", "v3", "GlobalReportType"), "cis-benchmark")
+			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "pci-dss-network-segmentation", "", "projectcalico.org", "v3", "GlobalReportType"), "pci-dss-network-segmentation")
+			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "nsa-cisa-kubernetes-hardening", "", "projectcalico.org", "v3", "GlobalReportType"), "nsa-cisa-kubernetes-hardening")
 
 			dpComplianceServer := rtest.GetResource(resources, "compliance-server", ns, "apps", "v1", "Deployment").(*appsv1.Deployment)
 			complianceController := rtest.GetResource(resources, "compliance-controller", ns, "apps", "v1", "Deployment").(*appsv1.Deployment)
@@ -634,6 +774,8 @@ var _ = Describe("compliance rendering tests", func() {
 				{"network-access", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"policy-audit", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"cis-benchmark", "", "projectcalico.org", "v3", "GlobalReportType"},
+				{"pci-dss-network-segmentation", "", "projectcalico.org", "v3", "GlobalReportType"},
+				{"nsa-cisa-kubernetes-hardening", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"tigera-linseed", ns, rbac, "v1", "RoleBinding"},
 			}
 
@@ -647,6 +789,8 @@ var _ = Describe("compliance rendering tests", func() {
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "network-access", "", "projectcalico.org", "v3", "GlobalReportType"), "network-access")
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "policy-audit", "", "projectcalico.org", "v3", "GlobalReportType"), "policy-audit")
 			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "cis-benchmark", "", "projectcalico.org", "v3", "GlobalReportType"), "cis-benchmark")
+			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "pci-dss-network-segmentation", "", "projectcalico.org", "v3", "GlobalReportType"), "pci-dss-network-segmentation")
+			rtest.ExpectGlobalReportType(rtest.GetResource(resources, "nsa-cisa-kubernetes-hardening", "", "projectcalico.org", "v3", "GlobalReportType"), "nsa-cisa-kubernetes-hardening")
 		})
 	})
 
@@ -786,6 +930,8 @@ var _ = Describe("compliance rendering tests", func() {
 				{"network-access", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"policy-audit", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"cis-benchmark", "", "projectcalico.org", "v3", "GlobalReportType"},
+				{"pci-dss-network-segmentation", "", "projectcalico.org", "v3", "GlobalReportType"},
+				{"nsa-cisa-kubernetes-hardening", "", "projectcalico.org", "v3", "GlobalReportType"},
 				{"allow-tigera.compliance-server", ns, "projectcalico.org", "v3", "NetworkPolicy"},
 				{"tigera-compliance-server", "", rbac, "v1", "ClusterRole"},
 				{"compliance", ns, "", "v1", "Service"},
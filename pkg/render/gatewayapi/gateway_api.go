@@ -18,19 +18,25 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
 	"strings"
 	"sync"
 
 	envoyapi "github.com/envoyproxy/gateway/api/v1alpha1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/ptr"
 	"github.com/tigera/operator/pkg/render"
+	"github.com/tigera/operator/pkg/render/applicationlayer"
+	"github.com/tigera/operator/pkg/render/applicationlayer/ruleset"
 	rcomp "github.com/tigera/operator/pkg/render/common/components"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	"github.com/tigera/operator/pkg/render/common/secret"
 	"github.com/tigera/operator/pkg/render/common/securitycontext"
+	"github.com/tigera/operator/pkg/render/monitor"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -38,10 +44,13 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextenv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/set"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	gapi "sigs.k8s.io/gateway-api/apis/v1"
+	gapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	"sigs.k8s.io/yaml" // gopkg.in/yaml.v2 didn't parse all the fields but this package did
 )
 
@@ -91,6 +100,18 @@ const (
 	EnvoyGatewayDeploymentContainerName = "envoy-gateway"
 	EnvoyGatewayJobContainerName        = "envoy-gateway-certgen"
 	wafFilterName                       = "waf-http-filter"
+
+	// GatewayClassLabel is added to gateway pods to identify their GatewayClass, when their
+	// metrics are scraped.
+	GatewayClassLabel = "gateway.tigera.io/gateway-class"
+
+	// The port on which Envoy Gateway configures the proxies to serve /stats/prometheus.
+	envoyProxyMetricsPort     = 19001
+	envoyProxyMetricsPortName = "metrics"
+	defaultScrapeInterval     = "30s"
+
+	// The socket on which the WAF HTTP filter serves the external processing API.
+	wafSocketPath = "/var/run/waf-http-filter/extproc.sock"
 )
 
 var (
@@ -381,6 +402,34 @@ type GatewayAPIImplementationConfig struct {
 	CustomEnvoyGateway    *envoyapi.EnvoyGateway
 	CustomEnvoyProxies    map[string]*envoyapi.EnvoyProxy
 	CurrentGatewayClasses set.Set[string]
+
+	// MonitorEnabled is true when the Monitor CR exists, in which case ServiceMonitors are
+	// rendered for the gateways of each GatewayClass.
+	MonitorEnabled bool
+
+	// ServiceMonitorCRDExists is true when the ServiceMonitor CRD is installed, which is the case whenever the
+	// enterprise CRDs are. Stale metrics Services and ServiceMonitors are only cleaned up when it is.
+	ServiceMonitorCRDExists bool
+
+	// The WAF ruleset, which must be set when the Web Application Firewall is enabled for any
+	// GatewayClass.
+	WAFRulesetConfigMap         *corev1.ConfigMap
+	DefaultCoreRulesetConfigMap *corev1.ConfigMap
+
+	// The WAF settings and rule packs of the ApplicationLayer, which are merged into the WAF
+	// ruleset.  The modes are taken from the GatewayClasses instead.
+	WAFSettings  *operatorv1.WAFSettings
+	WAFRulePacks []*corev1.ConfigMap
+
+	// Whether the WAF ruleset currently exists in the tigera-gateway namespace, so that it can
+	// be cleaned up when no GatewayClass needs it anymore.
+	WAFRulesetDeployed bool
+
+	// The Gateways of the provisioned GatewayClasses, and the WAF EnvoyExtensionPolicies that
+	// were previously rendered for them (as "namespace/name"), so that the policies of Gateways
+	// that no longer need them can be cleaned up.
+	Gateways           []*gapi.Gateway
+	CurrentWAFPolicies set.Set[string]
 }
 
 type gatewayAPIImplementationComponent struct {
@@ -609,6 +658,20 @@ func (pr *gatewayAPIImplementationComponent) Objects() ([]client.Object, []clien
 
 	objs = append(objs, certgenJob)
 
+	objsToDelete := []client.Object(nil)
+
+	// The WAF ruleset, shared by all GatewayClasses.
+	if pr.wafEnabled() {
+		objs = append(objs, pr.wafRulesetConfigMaps()...)
+	} else if pr.cfg.WAFRulesetDeployed {
+		for _, cm := range pr.wafRulesetConfigMaps() {
+			objsToDelete = append(objsToDelete, &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: cm.GetName(), Namespace: cm.GetNamespace()},
+			})
+		}
+	}
+
 	// Provision GatewayClasses.
 	for i := range pr.cfg.GatewayAPI.Spec.GatewayClasses {
 		className := pr.cfg.GatewayAPI.Spec.GatewayClasses[i].Name
@@ -620,12 +683,19 @@ func (pr *gatewayAPIImplementationComponent) Objects() ([]client.Object, []clien
 		// The GatewayClass using that EnvoyProxy config.
 		objs = append(objs, pr.gatewayClass(className, envoyGatewayConfig.Gateway.ControllerName, proxyConfig))
 
+		// The Service and ServiceMonitor for scraping the gateways' metrics. They are removed again when the
+		// Monitor CR is deleted or the ServiceMonitor is disabled for the class.
+		if pr.cfg.MonitorEnabled && serviceMonitorEnabled(&pr.cfg.GatewayAPI.Spec.GatewayClasses[i]) {
+			objs = append(objs, pr.metricsService(className), pr.serviceMonitor(&pr.cfg.GatewayAPI.Spec.GatewayClasses[i]))
+		} else {
+			objsToDelete = append(objsToDelete, pr.metricsObjects(className)...)
+		}
+
 		if pr.cfg.CurrentGatewayClasses.Has(className) {
 			pr.cfg.CurrentGatewayClasses.Delete(className)
 		}
 	}
 
-	objsToDelete := []client.Object(nil)
 	for _, gcName := range pr.cfg.CurrentGatewayClasses.UnsortedList() {
 		log.V(1).Info("Will delete GatewayClass and EnvoyProxy", "name", gcName)
 		objsToDelete = append(objsToDelete,
@@ -649,8 +719,14 @@ func (pr *gatewayAPIImplementationComponent) Objects() ([]client.Object, []clien
 				},
 			},
 		)
+		objsToDelete = append(objsToDelete, pr.metricsObjects(gcName)...)
 	}
 
+	// Attach the WAF to the Gateways of the classes that have it enabled.
+	wafObjs, wafObjsToDelete := pr.wafExtensionPolicies()
+	objs = append(objs, wafObjs...)
+	objsToDelete = append(objsToDelete, wafObjsToDelete...)
+
 	log.V(1).Info("GatewayAPI rendering", "num_current", len(objs), "num_delete", len(objsToDelete))
 	return objs, objsToDelete
 }
@@ -734,6 +810,21 @@ func (pr *gatewayAPIImplementationComponent) envoyProxyConfig(className string,
 	}
 	applyEnvoyProxyServiceOverrides(envoyProxy, classSpec.GatewayService)
 
+	// Label the gateway pods so that the metrics Service can select them.
+	if pr.cfg.MonitorEnabled && serviceMonitorEnabled(classSpec) {
+		var pod *envoyapi.KubernetesPodSpec
+		if envoyProxy.Spec.Provider.Kubernetes.EnvoyDaemonSet != nil {
+			pod = envoyProxy.Spec.Provider.Kubernetes.EnvoyDaemonSet.Pod
+		} else {
+			pod = envoyProxy.Spec.Provider.Kubernetes.EnvoyDeployment.Pod
+		}
+		pod.Labels = common.MapExistsOrInitialize(pod.Labels)
+		pod.Labels[GatewayClassLabel] = className
+	}
+
+	// Whether the access logs are set up for collection by the L7 log collector.
+	l7LogCollection := false
+
 	// Setup WAF HTTP Filter and l7 Log collector on Enterprise.
 	if pr.cfg.Installation.Variant == operatorv1.TigeraSecureEnterprise {
 		// The WAF HTTP filter is not supported when the envoy proxy is deployed as a DaemonSet
@@ -749,7 +840,7 @@ func (pr *gatewayAPIImplementationComponent) envoyProxyConfig(className string,
 					"-logFileName",
 					"waf.log",
 					"-socketPath",
-					"/var/run/waf-http-filter/extproc.sock",
+					wafSocketPath,
 				},
 				RestartPolicy: ptr.ToPtr[corev1.ContainerRestartPolicy](corev1.ContainerRestartPolicyAlways),
 				VolumeMounts: []corev1.VolumeMount{
@@ -768,6 +859,32 @@ func (pr *gatewayAPIImplementationComponent) envoyProxyConfig(className string,
 				},
 				SecurityContext: securitycontext.NewRootContext(true),
 			}
+			if pr.wafEnabledForClass(classSpec) {
+				mode := operatorv1.WAFModeDetectionOnly
+				if classSpec.WebApplicationFirewall.Mode != nil {
+					mode = *classSpec.WebApplicationFirewall.Mode
+				}
+				wafHTTPFilter.Args = append(wafHTTPFilter.Args,
+					"-rulesetRootDirectory",
+					applicationlayer.WAFConfigVolumePath,
+					"-rulesetFile",
+					"tigera.conf",
+					"-mode",
+					string(mode),
+				)
+				wafHTTPFilter.VolumeMounts = append(wafHTTPFilter.VolumeMounts,
+					corev1.VolumeMount{
+						Name:      applicationlayer.WAFConfigVolumeName,
+						MountPath: applicationlayer.WAFConfigVolumePath,
+						ReadOnly:  true,
+					},
+					corev1.VolumeMount{
+						Name:      applicationlayer.DefaultCoreRulesetVolumeName,
+						MountPath: applicationlayer.DefaultCoreRulesetVolumePath,
+						ReadOnly:  true,
+					},
+				)
+			}
 			// need to make changes to the envoy container to mount the socket
 			l7LogCollector := corev1.Container{
 				Name:  "l7-log-collector",
@@ -806,7 +923,7 @@ func (pr *gatewayAPIImplementationComponent) envoyProxyConfig(className string,
 				if initContainer.Name == wafHTTPFilter.Name {
 					hasWAFHTTPFilter = true
 					// Handle update
-					if initContainer.Image != wafHTTPFilter.Image {
+					if initContainer.Image != wafHTTPFilter.Image || !reflect.DeepEqual(initContainer.Args, wafHTTPFilter.Args) {
 						envoyProxy.Spec.Provider.Kubernetes.EnvoyDeployment.InitContainers[i] = wafHTTPFilter
 					}
 				}
@@ -938,6 +1055,31 @@ func (pr *gatewayAPIImplementationComponent) envoyProxyConfig(className string,
 				envoyProxy.Spec.Provider.Kubernetes.EnvoyDeployment.Pod.Volumes = append(envoyProxy.Spec.Provider.Kubernetes.EnvoyDeployment.Pod.Volumes, AccessLogsVolume...)
 			}
 
+			// Mount the WAF ruleset, and restart the gateways when it changes.
+			if pr.wafEnabledForClass(classSpec) {
+				pod := envoyProxy.Spec.Provider.Kubernetes.EnvoyDeployment.Pod
+				pod.Volumes = append(pod.Volumes,
+					corev1.Volume{
+						Name: applicationlayer.WAFConfigVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: applicationlayer.WAFRulesetConfigMapName},
+							},
+						},
+					},
+					corev1.Volume{
+						Name: applicationlayer.DefaultCoreRulesetVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: applicationlayer.DefaultCoreRuleset},
+							},
+						},
+					},
+				)
+				pod.Annotations = common.MapExistsOrInitialize(pod.Annotations)
+				pod.Annotations[applicationlayer.WAFConfigHashAnnotation] = rmeta.AnnotationHash(pr.wafRulesetConfigMaps()[0].(*corev1.ConfigMap).Data)
+			}
+
 			// Configure service account for WAF HTTP Filter license client
 			// Use EnvoyProxy patch mechanism to set serviceAccountName and automountServiceAccountToken
 			serviceAccountPatch := map[string]interface{}{
@@ -985,38 +1127,129 @@ func (pr *gatewayAPIImplementationComponent) envoyProxyConfig(className string,
 						},
 					},
 					Format: &envoyapi.ProxyAccessLogFormat{
-						Type: "JSON",
-						JSON: map[string]string{
-							"reporter":                         "gateway",
-							"start_time":                       "%START_TIME%",
-							"duration":                         "%DURATION%",
-							"response_code":                    "%RESPONSE_CODE%",
-							"bytes_sent":                       "%BYTES_SENT%",
-							"bytes_received":                   "%BYTES_RECEIVED%",
-							"user_agent":                       "%REQ(USER-AGENT)%",
-							"request_path":                     "%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%",
-							"request_method":                   "%REQ(:METHOD)%",
-							"request_id":                       "%REQ(X-REQUEST-ID)%",
-							"type":                             "{{.}}",
-							"downstream_remote_address":        "%DOWNSTREAM_REMOTE_ADDRESS%",
-							"downstream_local_address":         "%DOWNSTREAM_LOCAL_ADDRESS%",
-							"downstream_direct_remote_address": "%DOWNSTREAM_DIRECT_REMOTE_ADDRESS%",
-							"domain":                           "%REQ(HOST?:AUTHORITY)%",
-							"upstream_host":                    "%UPSTREAM_HOST%",
-							"upstream_local_address":           "%UPSTREAM_LOCAL_ADDRESS%",
-							"upstream_service_time":            "%RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)%",
-							"route_name":                       "%ROUTE_NAME%",
-						},
+						Type: envoyapi.ProxyAccessLogFormatTypeJSON,
+						JSON: l7AccessLogFields(),
 					},
-					Type: &AccessLogType,
+					Matches: accessLogSamplingMatches(classSpec.Telemetry),
+					Type:    &AccessLogType,
 				},
 			}
+			l7LogCollection = true
 		}
 	}
 
+	applyAccessLogs(envoyProxy, classSpec.Telemetry, l7LogCollection)
+
 	return envoyProxy
 }
 
+// l7AccessLogFields returns the fields of the access logs that are read by the L7 log collector.
+func l7AccessLogFields() map[string]string {
+	return map[string]string{
+		"reporter":                         "gateway",
+		"start_time":                       "%START_TIME%",
+		"duration":                         "%DURATION%",
+		"response_code":                    "%RESPONSE_CODE%",
+		"bytes_sent":                       "%BYTES_SENT%",
+		"bytes_received":                   "%BYTES_RECEIVED%",
+		"user_agent":                       "%REQ(USER-AGENT)%",
+		"request_path":                     "%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%",
+		"request_method":                   "%REQ(:METHOD)%",
+		"request_id":                       "%REQ(X-REQUEST-ID)%",
+		"type":                             "{{.}}",
+		"downstream_remote_address":        "%DOWNSTREAM_REMOTE_ADDRESS%",
+		"downstream_local_address":         "%DOWNSTREAM_LOCAL_ADDRESS%",
+		"downstream_direct_remote_address": "%DOWNSTREAM_DIRECT_REMOTE_ADDRESS%",
+		"domain":                           "%REQ(HOST?:AUTHORITY)%",
+		"upstream_host":                    "%UPSTREAM_HOST%",
+		"upstream_local_address":           "%UPSTREAM_LOCAL_ADDRESS%",
+		"upstream_service_time":            "%RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)%",
+		"route_name":                       "%ROUTE_NAME%",
+	}
+}
+
+// applyAccessLogs applies the access log settings from the GatewayClass telemetry.  When the
+// access logs are already set up for the L7 log collector, the standard output logs are added
+// alongside; otherwise they replace any access log settings from the custom EnvoyProxy.
+func applyAccessLogs(envoyProxy *envoyapi.EnvoyProxy, telemetry *operatorv1.GatewayTelemetry, l7LogCollection bool) {
+	if telemetry == nil || telemetry.AccessLogs == nil || telemetry.AccessLogs.Format == nil {
+		return
+	}
+	if envoyProxy.Spec.Telemetry == nil {
+		envoyProxy.Spec.Telemetry = &envoyapi.ProxyTelemetry{}
+	}
+	if envoyProxy.Spec.Telemetry.AccessLog == nil || !l7LogCollection {
+		envoyProxy.Spec.Telemetry.AccessLog = &envoyapi.ProxyAccessLog{
+			Settings: []envoyapi.ProxyAccessLogSetting{},
+		}
+	}
+
+	accessLogs := telemetry.AccessLogs
+	format := &envoyapi.ProxyAccessLogFormat{}
+	switch *accessLogs.Format {
+	case operatorv1.GatewayAccessLogFormatText:
+		format.Type = envoyapi.ProxyAccessLogFormatTypeText
+		format.Text = accessLogs.Text
+	default:
+		format.Type = envoyapi.ProxyAccessLogFormatTypeJSON
+		format.JSON = accessLogs.JSON
+		if len(format.JSON) == 0 {
+			format.JSON = l7AccessLogFields()
+		}
+	}
+	envoyProxy.Spec.Telemetry.AccessLog.Settings = append(envoyProxy.Spec.Telemetry.AccessLog.Settings, envoyapi.ProxyAccessLogSetting{
+		Format:  format,
+		Matches: accessLogSamplingMatches(telemetry),
+		Sinks: []envoyapi.ProxyAccessLogSink{
+			{
+				Type: envoyapi.ProxyAccessLogSinkTypeFile,
+				File: &envoyapi.FileEnvoyProxyAccessLog{
+					Path: "/dev/stdout",
+				},
+			},
+		},
+	})
+}
+
+// accessLogSamplingMatches returns the CEL match conditions that sample the configured percentage
+// of requests.  Envoy generates request IDs as random UUIDs, so the leading byte of the request
+// ID is used as the sampling key.  This gives a granularity of 1/256, and means that the same
+// requests are sampled by every access log sink.
+func accessLogSamplingMatches(telemetry *operatorv1.GatewayTelemetry) []string {
+	if telemetry == nil || telemetry.AccessLogs == nil || telemetry.AccessLogs.SamplingPercentage == nil {
+		return nil
+	}
+	buckets := int(math.Round(float64(*telemetry.AccessLogs.SamplingPercentage) * 256 / 100))
+	if buckets >= 256 {
+		return nil
+	}
+	if buckets < 1 {
+		buckets = 1
+	}
+
+	// Match the leading byte when it is less than the number of buckets.
+	high, low := buckets/16, buckets%16
+	var patterns []string
+	if high > 0 {
+		patterns = append(patterns, fmt.Sprintf("[%s][0-9a-f]", hexDigitRange(high-1)))
+	}
+	if low > 0 {
+		patterns = append(patterns, fmt.Sprintf("%x[%s]", high, hexDigitRange(low-1)))
+	}
+	return []string{fmt.Sprintf("request.id.matches('^(%s)')", strings.Join(patterns, "|"))}
+}
+
+// hexDigitRange returns a regex character class range matching the hex digits from 0 to last.
+func hexDigitRange(last int) string {
+	if last == 0 {
+		return "0"
+	}
+	if last < 10 {
+		return fmt.Sprintf("0-%d", last)
+	}
+	return fmt.Sprintf("0-9a-%x", last)
+}
+
 func (pr *gatewayAPIImplementationComponent) gatewayClass(className, controllerName string, proxyConfig *envoyapi.EnvoyProxy) *gapi.GatewayClass {
 	// Provision a GatewayClass that references the EnvoyProxy config and the controllerName
 	// that the gateway controller expects.
@@ -1134,3 +1367,279 @@ func (pr *gatewayAPIImplementationComponent) wafHttpFilterClusterRoleBinding() *
 		},
 	}
 }
+
+// serviceMonitorEnabled returns whether the gateways of the given class should be scraped by the
+// Prometheus instance of the Monitor resource.
+func serviceMonitorEnabled(classSpec *operatorv1.GatewayClassSpec) bool {
+	if classSpec.Telemetry == nil || classSpec.Telemetry.Metrics == nil || classSpec.Telemetry.Metrics.ServiceMonitor == nil {
+		return true
+	}
+	return *classSpec.Telemetry.Metrics.ServiceMonitor == operatorv1.GatewayServiceMonitorEnabled
+}
+
+func metricsServiceName(className string) string {
+	return fmt.Sprintf("%s-metrics", className)
+}
+
+func serviceMonitorName(className string) string {
+	return fmt.Sprintf("gateway-%s", className)
+}
+
+// metricsService creates a headless Service that selects the gateway pods of the given class, so
+// that a ServiceMonitor can discover them.
+func (pr *gatewayAPIImplementationComponent) metricsService(className string) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metricsServiceName(className),
+			Namespace: "tigera-gateway",
+			Labels:    map[string]string{GatewayClassLabel: className},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{GatewayClassLabel: className},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       envoyProxyMetricsPortName,
+					Port:       envoyProxyMetricsPort,
+					TargetPort: intstr.FromInt32(envoyProxyMetricsPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+func (pr *gatewayAPIImplementationComponent) serviceMonitor(classSpec *operatorv1.GatewayClassSpec) *monitoringv1.ServiceMonitor {
+	interval := defaultScrapeInterval
+	if classSpec.Telemetry != nil && classSpec.Telemetry.Metrics != nil && classSpec.Telemetry.Metrics.ScrapeInterval != nil {
+		interval = *classSpec.Telemetry.Metrics.ScrapeInterval
+	}
+	return &monitoringv1.ServiceMonitor{
+		TypeMeta: metav1.TypeMeta{Kind: monitoringv1.ServiceMonitorsKind, APIVersion: monitor.MonitoringAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceMonitorName(classSpec.Name),
+			Namespace: common.TigeraPrometheusNamespace,
+			Labels:    map[string]string{"team": "network-operators"},
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Selector:          metav1.LabelSelector{MatchLabels: map[string]string{GatewayClassLabel: classSpec.Name}},
+			NamespaceSelector: monitoringv1.NamespaceSelector{MatchNames: []string{"tigera-gateway"}},
+			Endpoints: []monitoringv1.Endpoint{
+				{
+					HonorLabels: true,
+					Interval:    monitoringv1.Duration(interval),
+					Port:        envoyProxyMetricsPortName,
+					Path:        "/stats/prometheus",
+					Scheme:      "http",
+				},
+			},
+		},
+	}
+}
+
+// metricsObjects returns the metrics objects of the given class, for deletion.
+func (pr *gatewayAPIImplementationComponent) metricsObjects(className string) []client.Object {
+	// The metrics objects are only ever created with the Monitor CR, which requires the ServiceMonitor CRD. Without
+	// the CRD there is nothing to clean up, and deleting a ServiceMonitor would fail.
+	if !pr.cfg.ServiceMonitorCRDExists {
+		return nil
+	}
+	return []client.Object{
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      metricsServiceName(className),
+				Namespace: "tigera-gateway",
+			},
+		},
+		&monitoringv1.ServiceMonitor{
+			TypeMeta: metav1.TypeMeta{Kind: monitoringv1.ServiceMonitorsKind, APIVersion: monitor.MonitoringAPIVersion},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      serviceMonitorName(className),
+				Namespace: common.TigeraPrometheusNamespace,
+			},
+		},
+	}
+}
+
+// wafEnabledForClass returns whether the Web Application Firewall is enabled for the gateways of
+// the given class.  The WAF HTTP filter runs as a sidecar, which is only supported for gateways
+// deployed as Deployments.
+func (pr *gatewayAPIImplementationComponent) wafEnabledForClass(classSpec *operatorv1.GatewayClassSpec) bool {
+	if pr.cfg.Installation.Variant != operatorv1.TigeraSecureEnterprise || classSpec.WebApplicationFirewall == nil {
+		return false
+	}
+	if custom := pr.cfg.CustomEnvoyProxies[classSpec.Name]; custom != nil && custom.Spec.Provider != nil && custom.Spec.Provider.Kubernetes != nil {
+		if custom.Spec.Provider.Kubernetes.EnvoyDaemonSet != nil {
+			return false
+		}
+		if custom.Spec.Provider.Kubernetes.EnvoyDeployment != nil {
+			return true
+		}
+	}
+	return classSpec.GatewayKind == nil || *classSpec.GatewayKind != operatorv1.GatewayKindDaemonSet
+}
+
+// wafEnabled returns whether the Web Application Firewall is enabled for any GatewayClass.
+func (pr *gatewayAPIImplementationComponent) wafEnabled() bool {
+	for i := range pr.cfg.GatewayAPI.Spec.GatewayClasses {
+		if pr.wafEnabledForClass(&pr.cfg.GatewayAPI.Spec.GatewayClasses[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// wafRulesetConfigMaps creates the WAF ruleset and the core ruleset that it includes.  The rule
+// engine is turned on when any GatewayClass blocks; the WAF HTTP filter of each class then decides
+// from its mode whether to enforce the verdict.
+func (pr *gatewayAPIImplementationComponent) wafRulesetConfigMaps() []client.Object {
+	settings := &operatorv1.WAFSettings{}
+	if pr.cfg.WAFSettings != nil {
+		settings = pr.cfg.WAFSettings.DeepCopy()
+		settings.Mode = nil
+		settings.NamespaceModes = nil
+	}
+	for i := range pr.cfg.GatewayAPI.Spec.GatewayClasses {
+		classSpec := &pr.cfg.GatewayAPI.Spec.GatewayClasses[i]
+		if pr.wafEnabledForClass(classSpec) && classSpec.WebApplicationFirewall.Mode != nil && *classSpec.WebApplicationFirewall.Mode == operatorv1.WAFModeBlocking {
+			settings.Mode = classSpec.WebApplicationFirewall.Mode
+		}
+	}
+	wafRuleset := &corev1.ConfigMap{}
+	if pr.cfg.WAFRulesetConfigMap != nil {
+		wafRuleset = ruleset.ApplyWAFSettings(pr.cfg.WAFRulesetConfigMap, settings, pr.cfg.WAFRulePacks)
+	}
+	coreRuleset := &corev1.ConfigMap{}
+	if pr.cfg.DefaultCoreRulesetConfigMap != nil {
+		coreRuleset = pr.cfg.DefaultCoreRulesetConfigMap
+	}
+	return []client.Object{
+		&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      applicationlayer.WAFRulesetConfigMapName,
+				Namespace: "tigera-gateway",
+			},
+			Data:       wafRuleset.Data,
+			BinaryData: wafRuleset.BinaryData,
+		},
+		&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      applicationlayer.DefaultCoreRuleset,
+				Namespace: "tigera-gateway",
+			},
+			Data:       coreRuleset.Data,
+			BinaryData: coreRuleset.BinaryData,
+		},
+	}
+}
+
+// WAFPolicyName returns the name of the Backend and EnvoyExtensionPolicy that attach the WAF to
+// the given Gateway.
+func WAFPolicyName(gatewayName string) string {
+	return fmt.Sprintf("%s-waf", gatewayName)
+}
+
+// wafExtensionPolicies returns, for each Gateway of a class with the WAF enabled, a Backend for
+// the socket of the WAF HTTP filter and an EnvoyExtensionPolicy that sends the Gateway's requests
+// to it.  These are created in the Gateway's namespace because an EnvoyExtensionPolicy can only
+// target Gateways in its own namespace.  The policies of Gateways that no longer have the WAF
+// enabled are returned for deletion.
+func (pr *gatewayAPIImplementationComponent) wafExtensionPolicies() ([]client.Object, []client.Object) {
+	wafClasses := map[string]*operatorv1.GatewayClassSpec{}
+	for i := range pr.cfg.GatewayAPI.Spec.GatewayClasses {
+		classSpec := &pr.cfg.GatewayAPI.Spec.GatewayClasses[i]
+		if pr.wafEnabledForClass(classSpec) {
+			wafClasses[classSpec.Name] = classSpec
+		}
+	}
+
+	var objs, objsToDelete []client.Object
+	current := set.New[string]()
+	if pr.cfg.CurrentWAFPolicies != nil {
+		current = pr.cfg.CurrentWAFPolicies.Clone()
+	}
+	for _, gw := range pr.cfg.Gateways {
+		classSpec, ok := wafClasses[string(gw.Spec.GatewayClassName)]
+		if !ok {
+			continue
+		}
+		name := WAFPolicyName(gw.Name)
+		current.Delete(types.NamespacedName{Name: name, Namespace: gw.Namespace}.String())
+		objs = append(objs,
+			&envoyapi.Backend{
+				TypeMeta: metav1.TypeMeta{Kind: "Backend", APIVersion: "gateway.envoyproxy.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: gw.Namespace,
+				},
+				Spec: envoyapi.BackendSpec{
+					Endpoints: []envoyapi.BackendEndpoint{
+						{Unix: &envoyapi.UnixSocket{Path: wafSocketPath}},
+					},
+				},
+			},
+			&envoyapi.EnvoyExtensionPolicy{
+				TypeMeta: metav1.TypeMeta{Kind: "EnvoyExtensionPolicy", APIVersion: "gateway.envoyproxy.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: gw.Namespace,
+				},
+				Spec: envoyapi.EnvoyExtensionPolicySpec{
+					PolicyTargetReferences: envoyapi.PolicyTargetReferences{
+						TargetRefs: []gapiv1a2.LocalPolicyTargetReferenceWithSectionName{
+							{
+								LocalPolicyTargetReference: gapiv1a2.LocalPolicyTargetReference{
+									Group: gapi.GroupName,
+									Kind:  "Gateway",
+									Name:  gapi.ObjectName(gw.Name),
+								},
+							},
+						},
+					},
+					ExtProc: []envoyapi.ExtProc{
+						{
+							BackendCluster: envoyapi.BackendCluster{
+								BackendRefs: []envoyapi.BackendRef{
+									{
+										BackendObjectReference: gapi.BackendObjectReference{
+											Group: ptr.ToPtr(gapi.Group("gateway.envoyproxy.io")),
+											Kind:  ptr.ToPtr(gapi.Kind("Backend")),
+											Name:  gapi.ObjectName(name),
+										},
+									},
+								},
+							},
+							FailOpen: classSpec.WebApplicationFirewall.FailOpen,
+							ProcessingMode: &envoyapi.ExtProcProcessingMode{
+								Request: &envoyapi.ProcessingModeOptions{
+									Body: ptr.ToPtr(envoyapi.BufferedExtProcBodyProcessingMode),
+								},
+							},
+						},
+					},
+				},
+			},
+		)
+	}
+
+	for _, key := range current.UnsortedList() {
+		ns, n, _ := strings.Cut(key, "/")
+		nn := types.NamespacedName{Name: n, Namespace: ns}
+		log.V(1).Info("Will delete WAF EnvoyExtensionPolicy and Backend", "name", nn.Name, "namespace", nn.Namespace)
+		objsToDelete = append(objsToDelete,
+			&envoyapi.EnvoyExtensionPolicy{
+				TypeMeta:   metav1.TypeMeta{Kind: "EnvoyExtensionPolicy", APIVersion: "gateway.envoyproxy.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+			},
+			&envoyapi.Backend{
+				TypeMeta:   metav1.TypeMeta{Kind: "Backend", APIVersion: "gateway.envoyproxy.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+			},
+		)
+	}
+	return objs, objsToDelete
+}
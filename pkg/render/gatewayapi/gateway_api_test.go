@@ -21,6 +21,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	envoyapi "github.com/envoyproxy/gateway/api/v1alpha1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	operatorv1 "github.com/tigera/operator/api/v1"
 	"github.com/tigera/operator/pkg/components"
 	"github.com/tigera/operator/pkg/ptr"
@@ -31,6 +32,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/set"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gapi "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/yaml" // gopkg.in/yaml.v2 didn't parse all the fields but this package did
@@ -900,6 +902,54 @@ var _ = Describe("Gateway API rendering tests", func() {
 		Expect(proxy.Spec.Telemetry.AccessLog.Settings).To(Equal(AccessLogSettings))
 	})
 
+	It("should update the waf-http-filter of a custom proxy when its args change", func() {
+		installation := &operatorv1.InstallationSpec{
+			Variant: operatorv1.TigeraSecureEnterprise,
+		}
+		gatewayAPI := &operatorv1.GatewayAPI{
+			Spec: operatorv1.GatewayAPISpec{
+				GatewayClasses: []operatorv1.GatewayClassSpec{{
+					Name: "custom-class",
+					EnvoyProxyRef: &operatorv1.NamespacedName{
+						Namespace: "default",
+						Name:      "my-proxy",
+					},
+				}},
+			},
+		}
+		renderProxy := func(custom *envoyapi.EnvoyProxy) *envoyapi.EnvoyProxy {
+			objsToCreate, _ := GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+				Installation:       installation,
+				GatewayAPI:         gatewayAPI,
+				CustomEnvoyProxies: map[string]*envoyapi.EnvoyProxy{"custom-class": custom},
+			}).Objects()
+			proxy, err := rtest.GetResourceOfType[*envoyapi.EnvoyProxy](objsToCreate, "custom-class", "tigera-gateway")
+			Expect(err).NotTo(HaveOccurred())
+			return proxy
+		}
+		custom := &envoyapi.EnvoyProxy{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-proxy", Namespace: "default"},
+			Spec: envoyapi.EnvoyProxySpec{
+				Provider: &envoyapi.EnvoyProxyProvider{
+					Type: envoyapi.ProviderTypeKubernetes,
+					Kubernetes: &envoyapi.EnvoyProxyKubernetesProvider{
+						EnvoyDeployment: &envoyapi.KubernetesDeploymentSpec{},
+					},
+				},
+			},
+		}
+		wafHTTPFilter := renderProxy(custom).Spec.Provider.Kubernetes.EnvoyDeployment.InitContainers[0]
+		Expect(wafHTTPFilter.Name).To(Equal("waf-http-filter"))
+		Expect(wafHTTPFilter.Args).NotTo(BeEmpty())
+
+		// A stale filter with the same number of args is replaced.
+		stale := wafHTTPFilter.DeepCopy()
+		stale.Args[len(stale.Args)-1] = "stale"
+		custom.Spec.Provider.Kubernetes.EnvoyDeployment.InitContainers = []corev1.Container{*stale}
+		initContainers := renderProxy(custom).Spec.Provider.Kubernetes.EnvoyDeployment.InitContainers
+		Expect(initContainers[0].Args).To(Equal(wafHTTPFilter.Args))
+	})
+
 	It("should deploy waf-http-filter for Enterprise when using a custom proxy", func() {
 		installation := &operatorv1.InstallationSpec{
 			Variant: operatorv1.TigeraSecureEnterprise,
@@ -1044,4 +1094,355 @@ var _ = Describe("Gateway API rendering tests", func() {
 		Expect(envoyDeployment.Pod.Volumes[4].CSI.Driver).To(Equal("csi.tigera.io"))
 		Expect(proxy.Spec.Telemetry.AccessLog.Settings).To(Equal(AccessLogSettings))
 	})
+
+	It("should configure access logs to standard output and sampling from the telemetry settings", func() {
+		installation := &operatorv1.InstallationSpec{
+			Variant: operatorv1.TigeraSecureEnterprise,
+		}
+		textFormat := operatorv1.GatewayAccessLogFormatText
+		gatewayAPI := &operatorv1.GatewayAPI{
+			Spec: operatorv1.GatewayAPISpec{
+				GatewayClasses: []operatorv1.GatewayClassSpec{{
+					Name: "tigera-gateway-class",
+					Telemetry: &operatorv1.GatewayTelemetry{
+						AccessLogs: &operatorv1.GatewayAccessLogs{
+							Format:             &textFormat,
+							Text:               ptr.ToPtr("%RESPONSE_CODE%\n"),
+							SamplingPercentage: ptr.Int32ToPtr(25),
+						},
+					},
+				}},
+			},
+		}
+		gatewayComp := GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation: installation,
+			GatewayAPI:   gatewayAPI,
+		})
+
+		objsToCreate, _ := gatewayComp.Objects()
+		proxy, err := rtest.GetResourceOfType[*envoyapi.EnvoyProxy](objsToCreate, "tigera-gateway-class", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+
+		// 25% of 256 is 64, so request IDs starting with 00 to 3f are sampled.
+		sampling := []string{"request.id.matches('^([0-3][0-9a-f])')"}
+		settings := proxy.Spec.Telemetry.AccessLog.Settings
+		Expect(settings).To(HaveLen(2))
+		Expect(settings[0].Sinks[0].File.Path).To(Equal("/access_logs/access.log"))
+		Expect(settings[0].Matches).To(Equal(sampling))
+		Expect(settings[1].Sinks[0].File.Path).To(Equal("/dev/stdout"))
+		Expect(settings[1].Format.Type).To(Equal(envoyapi.ProxyAccessLogFormatTypeText))
+		Expect(*settings[1].Format.Text).To(Equal("%RESPONSE_CODE%\n"))
+		Expect(settings[1].Matches).To(Equal(sampling))
+	})
+
+	It("should replace custom access logs for open-source when a format is configured", func() {
+		installation := &operatorv1.InstallationSpec{
+			Variant: operatorv1.Calico,
+		}
+		jsonFormat := operatorv1.GatewayAccessLogFormatJSON
+		gatewayAPI := &operatorv1.GatewayAPI{
+			Spec: operatorv1.GatewayAPISpec{
+				GatewayClasses: []operatorv1.GatewayClassSpec{{
+					Name:          "tigera-gateway-class",
+					EnvoyProxyRef: &operatorv1.NamespacedName{Namespace: "default", Name: "custom"},
+					Telemetry: &operatorv1.GatewayTelemetry{
+						AccessLogs: &operatorv1.GatewayAccessLogs{
+							Format:             &jsonFormat,
+							SamplingPercentage: ptr.Int32ToPtr(10),
+						},
+					},
+				}},
+			},
+		}
+		customProxy := &envoyapi.EnvoyProxy{
+			Spec: envoyapi.EnvoyProxySpec{
+				Telemetry: &envoyapi.ProxyTelemetry{
+					AccessLog: &envoyapi.ProxyAccessLog{
+						Settings: []envoyapi.ProxyAccessLogSetting{{
+							Sinks: []envoyapi.ProxyAccessLogSink{{Type: envoyapi.ProxyAccessLogSinkTypeOpenTelemetry}},
+						}},
+					},
+				},
+			},
+		}
+		gatewayComp := GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation:       installation,
+			GatewayAPI:         gatewayAPI,
+			CustomEnvoyProxies: map[string]*envoyapi.EnvoyProxy{"tigera-gateway-class": customProxy},
+		})
+
+		objsToCreate, _ := gatewayComp.Objects()
+		proxy, err := rtest.GetResourceOfType[*envoyapi.EnvoyProxy](objsToCreate, "tigera-gateway-class", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+
+		// 10% of 256 rounds to 26, so request IDs starting with 00 to 19 are sampled.
+		settings := proxy.Spec.Telemetry.AccessLog.Settings
+		Expect(settings).To(HaveLen(1))
+		Expect(settings[0].Sinks[0].File.Path).To(Equal("/dev/stdout"))
+		Expect(settings[0].Format.Type).To(Equal(envoyapi.ProxyAccessLogFormatTypeJSON))
+		Expect(settings[0].Format.JSON).To(HaveKeyWithValue("response_code", "%RESPONSE_CODE%"))
+		Expect(settings[0].Matches).To(Equal([]string{"request.id.matches('^([0][0-9a-f]|1[0-9])')"}))
+	})
+
+	It("should render ServiceMonitors for gateways only when the Monitor CR exists", func() {
+		installation := &operatorv1.InstallationSpec{
+			Variant: operatorv1.TigeraSecureEnterprise,
+		}
+		disabled := operatorv1.GatewayServiceMonitorDisabled
+		gatewayAPI := &operatorv1.GatewayAPI{
+			Spec: operatorv1.GatewayAPISpec{
+				GatewayClasses: []operatorv1.GatewayClassSpec{
+					{
+						Name: "class-a",
+						Telemetry: &operatorv1.GatewayTelemetry{
+							Metrics: &operatorv1.GatewayMetrics{ScrapeInterval: ptr.ToPtr("10s")},
+						},
+					},
+					{
+						Name:        "class-b",
+						GatewayKind: ptr.ToPtr(operatorv1.GatewayKindDaemonSet),
+					},
+					{
+						Name: "class-c",
+						Telemetry: &operatorv1.GatewayTelemetry{
+							Metrics: &operatorv1.GatewayMetrics{ServiceMonitor: &disabled},
+						},
+					},
+				},
+			},
+		}
+
+		objsToCreate, objsToDelete := GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation:          installation,
+			GatewayAPI:            gatewayAPI,
+			CurrentGatewayClasses: set.New[string](),
+		}).Objects()
+		for _, obj := range append(objsToCreate, objsToDelete...) {
+			Expect(obj).NotTo(BeAssignableToTypeOf(&monitoringv1.ServiceMonitor{}))
+		}
+		proxy, err := rtest.GetResourceOfType[*envoyapi.EnvoyProxy](objsToCreate, "class-a", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proxy.Spec.Provider.Kubernetes.EnvoyDeployment.Pod.Labels).NotTo(HaveKey(GatewayClassLabel))
+
+		objsToCreate, objsToDelete = GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation:          installation,
+			GatewayAPI:            gatewayAPI,
+			CurrentGatewayClasses: set.New[string]("class-d"),
+			MonitorEnabled:        true,
+
+			ServiceMonitorCRDExists: true,
+		}).Objects()
+
+		proxy, err = rtest.GetResourceOfType[*envoyapi.EnvoyProxy](objsToCreate, "class-a", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proxy.Spec.Provider.Kubernetes.EnvoyDeployment.Pod.Labels).To(HaveKeyWithValue(GatewayClassLabel, "class-a"))
+		proxy, err = rtest.GetResourceOfType[*envoyapi.EnvoyProxy](objsToCreate, "class-b", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proxy.Spec.Provider.Kubernetes.EnvoyDaemonSet.Pod.Labels).To(HaveKeyWithValue(GatewayClassLabel, "class-b"))
+
+		svc, err := rtest.GetResourceOfType[*corev1.Service](objsToCreate, "class-a-metrics", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+		Expect(svc.Spec.Selector).To(Equal(map[string]string{GatewayClassLabel: "class-a"}))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(19001)))
+
+		sm, err := rtest.GetResourceOfType[*monitoringv1.ServiceMonitor](objsToCreate, "gateway-class-a", "tigera-prometheus")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sm.Labels).To(HaveKeyWithValue("team", "network-operators"))
+		Expect(sm.Spec.Selector.MatchLabels).To(Equal(map[string]string{GatewayClassLabel: "class-a"}))
+		Expect(sm.Spec.Endpoints).To(HaveLen(1))
+		Expect(sm.Spec.Endpoints[0].Interval).To(Equal(monitoringv1.Duration("10s")))
+		Expect(sm.Spec.Endpoints[0].Path).To(Equal("/stats/prometheus"))
+
+		sm, err = rtest.GetResourceOfType[*monitoringv1.ServiceMonitor](objsToCreate, "gateway-class-b", "tigera-prometheus")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sm.Spec.Endpoints[0].Interval).To(Equal(monitoringv1.Duration("30s")))
+
+		// The disabled class and the removed class have their metrics objects deleted.
+		_, err = rtest.GetResourceOfType[*monitoringv1.ServiceMonitor](objsToCreate, "gateway-class-c", "tigera-prometheus")
+		Expect(err).To(HaveOccurred())
+		Expect(objsToDelete).To(ContainElements(
+			&matchObject{name: "class-c-metrics"},
+			&matchObject{name: "gateway-class-c"},
+			&matchObject{name: "class-d-metrics"},
+			&matchObject{name: "gateway-class-d"},
+		))
+
+		// Removing the Monitor CR deletes the metrics objects of all classes.
+		_, objsToDelete = GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation:          installation,
+			GatewayAPI:            gatewayAPI,
+			CurrentGatewayClasses: set.New[string](),
+
+			ServiceMonitorCRDExists: true,
+		}).Objects()
+		Expect(objsToDelete).To(ContainElements(
+			&matchObject{name: "class-a-metrics"},
+			&matchObject{name: "gateway-class-a"},
+			&matchObject{name: "class-b-metrics"},
+			&matchObject{name: "gateway-class-b"},
+		))
+	})
+
+	It("should keep the rate limit backend of a custom EnvoyGateway config", func() {
+		installation := &operatorv1.InstallationSpec{
+			Variant: operatorv1.Calico,
+		}
+		gatewayAPI := &operatorv1.GatewayAPI{
+			Spec: operatorv1.GatewayAPISpec{
+				GatewayClasses: []operatorv1.GatewayClassSpec{{Name: "class-a"}},
+			},
+		}
+		customEnvoyGateway := &envoyapi.EnvoyGateway{
+			EnvoyGatewaySpec: envoyapi.EnvoyGatewaySpec{
+				RateLimit: &envoyapi.RateLimit{
+					Backend: envoyapi.RateLimitDatabaseBackend{
+						Type:  envoyapi.RedisBackendType,
+						Redis: &envoyapi.RateLimitRedisSettings{URL: "redis.example.com:6379"},
+					},
+				},
+			},
+		}
+
+		gatewayComp := GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation:       installation,
+			GatewayAPI:         gatewayAPI,
+			CustomEnvoyGateway: customEnvoyGateway,
+		})
+		Expect(gatewayComp.ResolveImages(nil)).NotTo(HaveOccurred())
+		objsToCreate, objsToDelete := gatewayComp.Objects()
+		Expect(objsToDelete).To(HaveLen(0))
+
+		cm, err := rtest.GetResourceOfType[*corev1.ConfigMap](objsToCreate, EnvoyGatewayConfigName, "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		envoyGatewayConfig := &envoyapi.EnvoyGateway{}
+		Expect(yaml.Unmarshal([]byte(cm.Data[EnvoyGatewayConfigKey]), envoyGatewayConfig)).To(Succeed())
+		Expect(envoyGatewayConfig.RateLimit.Backend.Type).To(Equal(envoyapi.RedisBackendType))
+		Expect(envoyGatewayConfig.RateLimit.Backend.Redis.URL).To(Equal("redis.example.com:6379"))
+	})
+
+	It("should attach the WAF to the gateways of classes that enable it", func() {
+		installation := &operatorv1.InstallationSpec{
+			Variant: operatorv1.TigeraSecureEnterprise,
+		}
+		blocking := operatorv1.WAFModeBlocking
+		gatewayAPI := &operatorv1.GatewayAPI{
+			Spec: operatorv1.GatewayAPISpec{
+				GatewayClasses: []operatorv1.GatewayClassSpec{
+					{
+						Name:                   "class-a",
+						WebApplicationFirewall: &operatorv1.GatewayWebApplicationFirewall{Mode: &blocking, FailOpen: ptr.BoolToPtr(true)},
+					},
+					{Name: "class-b"},
+					{
+						Name:                   "class-c",
+						GatewayKind:            ptr.ToPtr(operatorv1.GatewayKindDaemonSet),
+						WebApplicationFirewall: &operatorv1.GatewayWebApplicationFirewall{},
+					},
+				},
+			},
+		}
+		gateways := []*gapi.Gateway{
+			{ObjectMeta: metav1.ObjectMeta{Name: "gw-a", Namespace: "shop"}, Spec: gapi.GatewaySpec{GatewayClassName: "class-a"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "gw-b", Namespace: "shop"}, Spec: gapi.GatewaySpec{GatewayClassName: "class-b"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "gw-c", Namespace: "shop"}, Spec: gapi.GatewaySpec{GatewayClassName: "class-c"}},
+		}
+		wafRuleset := &corev1.ConfigMap{Data: map[string]string{"tigera.conf": "Include coraza.conf\n"}}
+		coreRuleset := &corev1.ConfigMap{Data: map[string]string{"rules.conf": "# rules"}}
+
+		objsToCreate, objsToDelete := GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation:                installation,
+			GatewayAPI:                  gatewayAPI,
+			WAFRulesetConfigMap:         wafRuleset,
+			DefaultCoreRulesetConfigMap: coreRuleset,
+			Gateways:                    gateways,
+			CurrentWAFPolicies:          set.New[string]("shop/gw-a-waf", "other/gw-d-waf"),
+		}).Objects()
+
+		// The ruleset turns the rule engine on, because class-a blocks.
+		cm, err := rtest.GetResourceOfType[*corev1.ConfigMap](objsToCreate, "tigera-waf-config", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cm.Data).To(HaveKeyWithValue("tigera-after-crs.conf", "SecRuleEngine On\n"))
+		cm, err = rtest.GetResourceOfType[*corev1.ConfigMap](objsToCreate, "coreruleset-default", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cm.Data).To(Equal(coreRuleset.Data))
+
+		proxy, err := rtest.GetResourceOfType[*envoyapi.EnvoyProxy](objsToCreate, "class-a", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		envoyDeployment := proxy.Spec.Provider.Kubernetes.EnvoyDeployment
+		Expect(envoyDeployment.InitContainers[0].Name).To(Equal("waf-http-filter"))
+		Expect(envoyDeployment.InitContainers[0].Args).To(ContainElements("-rulesetRootDirectory", "/etc/waf", "-mode", "Blocking"))
+		Expect(envoyDeployment.InitContainers[0].VolumeMounts).To(ContainElements(
+			corev1.VolumeMount{Name: "tigera-waf-config", MountPath: "/etc/waf", ReadOnly: true},
+			corev1.VolumeMount{Name: "coreruleset-default", MountPath: "/etc/waf/coreruleset", ReadOnly: true},
+		))
+		Expect(envoyDeployment.Pod.Annotations).To(HaveKey("hash.operator.tigera.io/tigera-waf-config"))
+
+		proxy, err = rtest.GetResourceOfType[*envoyapi.EnvoyProxy](objsToCreate, "class-b", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proxy.Spec.Provider.Kubernetes.EnvoyDeployment.InitContainers[0].Args).NotTo(ContainElement("-mode"))
+
+		// Only the Deployment gateway of class-a gets the WAF.
+		backend, err := rtest.GetResourceOfType[*envoyapi.Backend](objsToCreate, "gw-a-waf", "shop")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backend.Spec.Endpoints[0].Unix.Path).To(Equal("/var/run/waf-http-filter/extproc.sock"))
+		policy, err := rtest.GetResourceOfType[*envoyapi.EnvoyExtensionPolicy](objsToCreate, "gw-a-waf", "shop")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.Spec.TargetRefs).To(HaveLen(1))
+		Expect(policy.Spec.TargetRefs[0].Kind).To(BeEquivalentTo("Gateway"))
+		Expect(policy.Spec.TargetRefs[0].Name).To(BeEquivalentTo("gw-a"))
+		Expect(policy.Spec.ExtProc).To(HaveLen(1))
+		Expect(policy.Spec.ExtProc[0].BackendRefs[0].Name).To(BeEquivalentTo("gw-a-waf"))
+		Expect(*policy.Spec.ExtProc[0].BackendRefs[0].Kind).To(BeEquivalentTo("Backend"))
+		Expect(*policy.Spec.ExtProc[0].FailOpen).To(BeTrue())
+		Expect(*policy.Spec.ExtProc[0].ProcessingMode.Request.Body).To(Equal(envoyapi.BufferedExtProcBodyProcessingMode))
+		_, err = rtest.GetResourceOfType[*envoyapi.EnvoyExtensionPolicy](objsToCreate, "gw-b-waf", "shop")
+		Expect(err).To(HaveOccurred())
+		_, err = rtest.GetResourceOfType[*envoyapi.EnvoyExtensionPolicy](objsToCreate, "gw-c-waf", "shop")
+		Expect(err).To(HaveOccurred())
+
+		// The policy of a Gateway that no longer has the WAF is deleted.
+		Expect(objsToDelete).To(HaveLen(2))
+		Expect(objsToDelete).To(ContainElements(&matchObject{name: "gw-d-waf"}))
+
+		By("merging in the WAF settings and rule packs of the ApplicationLayer")
+		detectionOnly := operatorv1.WAFModeDetectionOnly
+		objsToCreate, _ = GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation:                installation,
+			GatewayAPI:                  gatewayAPI,
+			WAFRulesetConfigMap:         wafRuleset,
+			DefaultCoreRulesetConfigMap: coreRuleset,
+			WAFSettings: &operatorv1.WAFSettings{
+				Mode:           &detectionOnly,
+				RulePacks:      []operatorv1.WAFRulePack{{ConfigMapName: "my-rules"}},
+				RuleExclusions: []operatorv1.WAFRuleExclusion{{RuleIDs: []int32{942100}}},
+			},
+			WAFRulePacks: []*corev1.ConfigMap{
+				{ObjectMeta: metav1.ObjectMeta{Name: "my-rules"}, Data: map[string]string{"custom.conf": "# custom"}},
+			},
+		}).Objects()
+		cm, err = rtest.GetResourceOfType[*corev1.ConfigMap](objsToCreate, "tigera-waf-config", "tigera-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cm.Data).To(HaveKeyWithValue("my-rules-custom.conf", "# custom"))
+		// The mode of the ApplicationLayer does not turn off the engine that class-a needs to block.
+		Expect(cm.Data).To(HaveKeyWithValue("tigera-after-crs.conf",
+			"SecRuleRemoveById 942100\nSecRuleEngine On\nInclude my-rules-custom.conf\n"))
+
+		By("disabling the WAF")
+		gatewayAPI.Spec.GatewayClasses[0].WebApplicationFirewall = nil
+		objsToCreate, objsToDelete = GatewayAPIImplementationComponent(&GatewayAPIImplementationConfig{
+			Installation:       installation,
+			GatewayAPI:         gatewayAPI,
+			Gateways:           gateways,
+			CurrentWAFPolicies: set.New[string]("shop/gw-a-waf"),
+			WAFRulesetDeployed: true,
+		}).Objects()
+		_, err = rtest.GetResourceOfType[*corev1.ConfigMap](objsToCreate, "tigera-waf-config", "tigera-gateway")
+		Expect(err).To(HaveOccurred())
+		Expect(objsToDelete).To(ContainElements(
+			&matchObject{name: "tigera-waf-config"},
+			&matchObject{name: "coreruleset-default"},
+			&matchObject{name: "gw-a-waf"},
+		))
+	})
 })
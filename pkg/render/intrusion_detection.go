@@ -17,6 +17,7 @@ package render
 import (
 	"crypto/x509"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -28,15 +29,18 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 
 	operatorv1 "github.com/tigera/operator/api/v1"
+	"github.com/tigera/operator/pkg/common"
 	"github.com/tigera/operator/pkg/components"
 	rcomponents "github.com/tigera/operator/pkg/render/common/components"
 	relasticsearch "github.com/tigera/operator/pkg/render/common/elasticsearch"
 	rmeta "github.com/tigera/operator/pkg/render/common/meta"
 	"github.com/tigera/operator/pkg/render/common/networkpolicy"
+	"github.com/tigera/operator/pkg/render/common/secret"
 	"github.com/tigera/operator/pkg/render/common/securitycontext"
 	"github.com/tigera/operator/pkg/render/common/securitycontextconstraints"
 	"github.com/tigera/operator/pkg/tls/certificatemanagement"
@@ -68,8 +72,50 @@ const (
 	adDetectorPrefixName        = "tigera.io.detector."
 	adDetectorName              = "anomaly-detectors"
 	ADDetectorPolicyName        = networkpolicy.TigeraComponentPolicyPrefix + adDetectorName
+
+	// ThreatFeedLabel is set on the GlobalThreatFeeds, header secrets and tier that are created from the
+	// IntrusionDetection CR.
+	ThreatFeedLabel = "operator.tigera.io/threat-feed"
+	// ThreatFeedPrefix is the prefix of the built-in GlobalThreatFeeds and of the copies of the header secrets, so that
+	// they do not collide with the resources of the user or of the component.
+	ThreatFeedPrefix = "tigera.io.threatfeed."
+	// ThreatFeedBlockLabel is set on the GlobalNetworkSets of threat feeds whose addresses are blocked.
+	ThreatFeedBlockLabel      = "operator.tigera.io/threat-feed-block"
+	ThreatFeedTierName        = "threat-feeds"
+	ThreatFeedBlockPolicyName = ThreatFeedTierName + ".block"
+
+	// CustomAlertTemplateLabel is set on the GlobalAlertTemplates that are read from the ConfigMaps listed in the
+	// IntrusionDetection CR. Its value is the name of the ConfigMap.
+	CustomAlertTemplateLabel = "operator.tigera.io/custom-alert-template"
+	// CustomAlertTemplateConfigMapLabel must be set to "true" on the ConfigMaps that hold custom alert templates, so
+	// that only they are watched.
+	CustomAlertTemplateConfigMapLabel = "operator.tigera.io/alert-templates"
 )
 
+// The tier that blocks threat feed addresses is evaluated after the allow-tigera tier.
+var threatFeedTierOrder = 110.0
+
+// builtInThreatFeeds are the well-known threat feeds that can be enabled in the IntrusionDetection CR.
+var builtInThreatFeeds = map[operatorv1.BuiltInThreatFeedName]struct {
+	name        string
+	description string
+	content     v3.ThreatFeedContent
+	url         string
+}{
+	operatorv1.BuiltInThreatFeedAlienVaultIPs: {
+		name:        ThreatFeedPrefix + "alienvault-ips",
+		description: "AlienVault IP Block List",
+		content:     v3.ThreatFeedContentIPset,
+		url:         "https://installer.calicocloud.io/feeds/v1/ips",
+	},
+	operatorv1.BuiltInThreatFeedAlienVaultDomains: {
+		name:        ThreatFeedPrefix + "alienvault-domains",
+		description: "AlienVault Domain Block List",
+		content:     v3.ThreatFeedContentDomainNameSet,
+		url:         "https://installer.calicocloud.io/feeds/v1/domains",
+	},
+}
+
 // Register secret/certs that need Server and Client Key usage
 var (
 	intrusionDetectionNamespaceSelector = fmt.Sprintf("projectcalico.org/name == '%s'", IntrusionDetectionNamespace)
@@ -116,6 +162,23 @@ type IntrusionDetectionConfiguration struct {
 	BindNamespaces  []string
 	Tenant          *operatorv1.Tenant
 	ExternalElastic bool
+
+	// The secrets in the operator namespace that hold the values of threat feed headers.
+	ThreatFeedSecrets []*corev1.Secret
+	// The names of the GlobalThreatFeeds created from the IntrusionDetection CR that currently exist, so that those
+	// that are no longer listed can be deleted.
+	ThreatFeedsDeployed []string
+	// The names of the copies of threat feed header secrets that currently exist in the intrusion detection namespace,
+	// so that those that are no longer referenced can be deleted.
+	ThreatFeedSecretsDeployed []string
+	// Whether the threat feed tier that was created by the operator exists, so that it is only deleted if it is ours.
+	ThreatFeedTierDeployed bool
+
+	// The valid GlobalAlertTemplates read from the ConfigMaps listed in the IntrusionDetection CR.
+	CustomAlertTemplates []*v3.GlobalAlertTemplate
+	// The names of the custom GlobalAlertTemplates that currently exist, so that those that are no longer provided by
+	// a ConfigMap can be deleted.
+	CustomAlertTemplatesDeployed []string
 }
 
 type intrusionDetectionComponent struct {
@@ -154,9 +217,61 @@ func (c *intrusionDetectionComponent) SupportedOSType() rmeta.OSType {
 func (c *intrusionDetectionComponent) Objects() ([]client.Object, []client.Object) {
 	objs := []client.Object{}
 
+	var objsToDelete []client.Object
 	if !c.cfg.Tenant.MultiTenant() {
-		// GlobalAlertTemplates are not used in multi-tenant management clusters.
+		// GlobalAlertTemplates and threat feeds are not used in multi-tenant management clusters.
 		objs = append(objs, c.globalAlertTemplates()...)
+
+		renderedTemplates := map[string]bool{}
+		for _, t := range c.cfg.CustomAlertTemplates {
+			objs = append(objs, t)
+			renderedTemplates[t.Name] = true
+		}
+		for _, name := range c.cfg.CustomAlertTemplatesDeployed {
+			if !renderedTemplates[name] {
+				objsToDelete = append(objsToDelete, &v3.GlobalAlertTemplate{
+					TypeMeta:   metav1.TypeMeta{Kind: "GlobalAlertTemplate", APIVersion: "projectcalico.org/v3"},
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+				})
+			}
+		}
+
+		feeds := c.globalThreatFeeds()
+		rendered := map[string]bool{}
+		block := false
+		for _, feed := range feeds {
+			objs = append(objs, feed)
+			rendered[feed.Name] = true
+			block = block || feed.Spec.GlobalNetworkSet != nil
+		}
+		for _, name := range c.cfg.ThreatFeedsDeployed {
+			if !rendered[name] {
+				objsToDelete = append(objsToDelete, &v3.GlobalThreatFeed{
+					TypeMeta:   metav1.TypeMeta{Kind: "GlobalThreatFeed", APIVersion: "projectcalico.org/v3"},
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+				})
+			}
+		}
+		renderedSecrets := map[string]bool{}
+		for _, s := range c.threatFeedSecrets() {
+			objs = append(objs, s)
+			renderedSecrets[s.Name] = true
+		}
+		for _, name := range c.cfg.ThreatFeedSecretsDeployed {
+			if !renderedSecrets[name] {
+				objsToDelete = append(objsToDelete, &corev1.Secret{
+					TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.cfg.Namespace},
+				})
+			}
+		}
+
+		// The policy must be created after, and deleted before, its tier.
+		if block {
+			objs = append(objs, c.threatFeedTier(), c.threatFeedBlockPolicy())
+		} else if c.cfg.ThreatFeedTierDeployed {
+			objsToDelete = append(objsToDelete, c.threatFeedBlockPolicy(), c.threatFeedTier())
+		}
 	}
 
 	objs = append(objs,
@@ -177,12 +292,12 @@ func (c *intrusionDetectionComponent) Objects() ([]client.Object, []client.Objec
 		objs = append(objs, c.multiTenantManagedClustersAccess()...)
 	}
 
-	objsToDelete := []client.Object{
+	objsToDelete = append(objsToDelete,
 		// PSPs have been removed from the Kubernetes API since v1.25, so we can delete
 		// any resources related to them that might still exist.
 		c.intrusionDetectionPSPClusterRole(),
 		c.intrusionDetectionPSPClusterRoleBinding(),
-	}
+	)
 
 	if !c.cfg.ManagedCluster && !c.cfg.Tenant.MultiTenant() {
 		// Delete any anomaly detection components that might still exist.
@@ -1000,6 +1115,242 @@ func (c *intrusionDetectionComponent) globalAlertTemplates() []client.Object {
 	return globalAlertTemplates
 }
 
+// CustomAlertTemplates returns the valid GlobalAlertTemplates held by the given ConfigMaps, and the entries that could
+// not be parsed or validated. Every key ending in ".yaml" must hold a GlobalAlertTemplate manifest.
+func CustomAlertTemplates(configMaps []*corev1.ConfigMap) ([]*v3.GlobalAlertTemplate, []operatorv1.InvalidAlertTemplate) {
+	builtIn := map[string]bool{}
+	for _, t := range (&intrusionDetectionComponent{}).globalAlertTemplates() {
+		builtIn[t.GetName()] = true
+	}
+
+	var templates []*v3.GlobalAlertTemplate
+	var invalid []operatorv1.InvalidAlertTemplate
+	seen := map[string]string{}
+	for _, cm := range configMaps {
+		keys := make([]string, 0, len(cm.Data))
+		for k := range cm.Data {
+			if strings.HasSuffix(k, ".yaml") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			t := &v3.GlobalAlertTemplate{}
+			var reason string
+			if err := yaml.UnmarshalStrict([]byte(cm.Data[k]), t); err != nil {
+				reason = fmt.Sprintf("failed to parse: %v", err)
+			} else if t.Kind != "GlobalAlertTemplate" {
+				reason = fmt.Sprintf("is a %q, expected a GlobalAlertTemplate", t.Kind)
+			} else if t.Name == "" {
+				reason = "has no name"
+			} else if builtIn[t.Name] {
+				reason = fmt.Sprintf("cannot replace the %s template", t.Name)
+			} else if other, ok := seen[t.Name]; ok {
+				reason = fmt.Sprintf("the template %s is also provided by ConfigMap %s", t.Name, other)
+			} else if t.Spec.Description == "" {
+				reason = "has no description"
+			} else if t.Spec.Severity < 1 || t.Spec.Severity > 100 {
+				reason = fmt.Sprintf("has severity %d, expected a value from 1 to 100", t.Spec.Severity)
+			}
+			if reason != "" {
+				invalid = append(invalid, operatorv1.InvalidAlertTemplate{ConfigMapName: cm.Name, Key: k, Reason: reason})
+				continue
+			}
+			seen[t.Name] = cm.Name
+
+			labels := map[string]string{}
+			for lk, lv := range t.Labels {
+				labels[lk] = lv
+			}
+			labels[CustomAlertTemplateLabel] = cm.Name
+			templates = append(templates, &v3.GlobalAlertTemplate{
+				TypeMeta: metav1.TypeMeta{Kind: "GlobalAlertTemplate", APIVersion: "projectcalico.org/v3"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        t.Name,
+					Labels:      labels,
+					Annotations: t.Annotations,
+				},
+				Spec: t.Spec,
+			})
+		}
+	}
+	return templates, invalid
+}
+
+// ValidateThreatFeeds checks that the threat feeds in the IntrusionDetection CR can be rendered.
+func ValidateThreatFeeds(feeds *operatorv1.ThreatFeeds) error {
+	if feeds == nil {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, f := range feeds.BuiltIn {
+		def, ok := builtInThreatFeeds[f.Name]
+		if !ok {
+			return fmt.Errorf("unknown built-in threat feed %s", f.Name)
+		}
+		if names[def.name] {
+			return fmt.Errorf("the built-in threat feed %s is listed more than once", f.Name)
+		}
+		names[def.name] = true
+		if f.Block && def.content != v3.ThreatFeedContentIPset {
+			return fmt.Errorf("the built-in threat feed %s does not contain IP addresses and cannot be blocked", f.Name)
+		}
+	}
+	for _, def := range builtInThreatFeeds {
+		names[def.name] = true
+	}
+	for _, f := range feeds.Custom {
+		if names[f.Name] {
+			return fmt.Errorf("the threat feed name %s is already in use", f.Name)
+		}
+		if strings.HasPrefix(f.Name, ThreatFeedPrefix) {
+			return fmt.Errorf("the threat feed name %s uses the reserved prefix %s", f.Name, ThreatFeedPrefix)
+		}
+		names[f.Name] = true
+		if f.Block && f.Content == operatorv1.ThreatFeedContentDomainNameSet {
+			return fmt.Errorf("the threat feed %s does not contain IP addresses and cannot be blocked", f.Name)
+		}
+		for _, h := range f.Headers {
+			if (h.Value == "") == (h.SecretKeyRef == nil) {
+				return fmt.Errorf("header %s of threat feed %s must set exactly one of value and secretKeyRef", h.Name, f.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// globalThreatFeeds returns the GlobalThreatFeeds for the threat feeds listed in the IntrusionDetection CR.
+func (c *intrusionDetectionComponent) globalThreatFeeds() []*v3.GlobalThreatFeed {
+	if c.cfg.IntrusionDetection == nil || c.cfg.IntrusionDetection.Spec.ThreatFeeds == nil {
+		return nil
+	}
+	var feeds []*v3.GlobalThreatFeed
+	for _, f := range c.cfg.IntrusionDetection.Spec.ThreatFeeds.BuiltIn {
+		def := builtInThreatFeeds[f.Name]
+		feed := newGlobalThreatFeed(def.name, v3.ThreatFeedTypeBuiltin, f.Mode, f.Block)
+		feed.Spec.Description = def.description
+		feed.Spec.Content = def.content
+		feed.Spec.Pull = &v3.Pull{
+			Period: "24h",
+			HTTP: &v3.HTTPPull{
+				Format: v3.ThreatFeedFormat{NewlineDelimited: &v3.ThreatFeedFormatNewlineDelimited{}},
+				URL:    def.url,
+			},
+		}
+		feeds = append(feeds, feed)
+	}
+	for _, f := range c.cfg.IntrusionDetection.Spec.ThreatFeeds.Custom {
+		feed := newGlobalThreatFeed(f.Name, v3.ThreatFeedTypeCustom, f.Mode, f.Block)
+		feed.Spec.Description = f.Description
+		feed.Spec.Content = v3.ThreatFeedContentIPset
+		if f.Content == operatorv1.ThreatFeedContentDomainNameSet {
+			feed.Spec.Content = v3.ThreatFeedContentDomainNameSet
+		}
+		period := "24h"
+		if f.PullPeriod != nil {
+			period = f.PullPeriod.Duration.String()
+		}
+		format := v3.ThreatFeedFormat{NewlineDelimited: &v3.ThreatFeedFormatNewlineDelimited{}}
+		if f.JSONPath != "" {
+			format = v3.ThreatFeedFormat{JSON: &v3.ThreatFeedFormatJSON{Path: f.JSONPath}}
+		}
+		var headers []v3.HTTPHeader
+		for _, h := range f.Headers {
+			header := v3.HTTPHeader{Name: h.Name, Value: h.Value}
+			if h.SecretKeyRef != nil {
+				ref := h.SecretKeyRef.DeepCopy()
+				ref.Name = ThreatFeedPrefix + ref.Name
+				header.ValueFrom = &v3.HTTPHeaderSource{SecretKeyRef: ref}
+			}
+			headers = append(headers, header)
+		}
+		feed.Spec.Pull = &v3.Pull{
+			Period: period,
+			HTTP: &v3.HTTPPull{
+				Format:  format,
+				URL:     f.URL,
+				Headers: headers,
+			},
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds
+}
+
+func newGlobalThreatFeed(name string, feedType v3.ThreatFeedType, mode *operatorv1.ThreatFeedMode, block bool) *v3.GlobalThreatFeed {
+	feedMode := v3.ThreatFeedModeEnabled
+	if mode != nil && *mode == operatorv1.ThreatFeedModeDisabled {
+		feedMode = v3.ThreatFeedModeDisabled
+	}
+	feed := &v3.GlobalThreatFeed{
+		TypeMeta: metav1.TypeMeta{Kind: "GlobalThreatFeed", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{ThreatFeedLabel: "true"},
+		},
+		Spec: v3.GlobalThreatFeedSpec{
+			Mode:     &feedMode,
+			FeedType: &feedType,
+		},
+	}
+	if block {
+		feed.Spec.GlobalNetworkSet = &v3.GlobalNetworkSetSync{Labels: map[string]string{ThreatFeedBlockLabel: "true"}}
+	}
+	return feed
+}
+
+// threatFeedSecrets copies the secrets that hold the values of threat feed headers into the intrusion detection
+// namespace under a prefixed name, so that they cannot replace the secrets of the component.
+func (c *intrusionDetectionComponent) threatFeedSecrets() []*corev1.Secret {
+	secrets := secret.CopyToNamespace(c.cfg.Namespace, c.cfg.ThreatFeedSecrets...)
+	for _, s := range secrets {
+		s.Name = ThreatFeedPrefix + s.Name
+		s.Labels = map[string]string{ThreatFeedLabel: "true"}
+	}
+	return secrets
+}
+
+func (c *intrusionDetectionComponent) threatFeedTier() *v3.Tier {
+	return &v3.Tier{
+		TypeMeta: metav1.TypeMeta{Kind: "Tier", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ThreatFeedTierName,
+			Labels: map[string]string{ThreatFeedLabel: "true"},
+		},
+		Spec: v3.TierSpec{
+			Order: &threatFeedTierOrder,
+		},
+	}
+}
+
+// threatFeedBlockPolicy denies traffic to and from the GlobalNetworkSets of blocked threat feeds, and passes all other
+// traffic to subsequent tiers. Host endpoints and the pods of Calico and Tigera components are not selected, so that
+// a threat feed can never cut off the cluster from its own control plane.
+func (c *intrusionDetectionComponent) threatFeedBlockPolicy() *v3.GlobalNetworkPolicy {
+	blocked := fmt.Sprintf("%s == 'true'", ThreatFeedBlockLabel)
+	selector := fmt.Sprintf("has(projectcalico.org/namespace) && projectcalico.org/namespace != '%s' && !(projectcalico.org/namespace starts_with 'tigera-')", common.CalicoNamespace)
+	return &v3.GlobalNetworkPolicy{
+		TypeMeta: metav1.TypeMeta{Kind: "GlobalNetworkPolicy", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ThreatFeedBlockPolicyName,
+		},
+		Spec: v3.GlobalNetworkPolicySpec{
+			Tier:     ThreatFeedTierName,
+			Selector: selector,
+			Types:    []v3.PolicyType{v3.PolicyTypeIngress, v3.PolicyTypeEgress},
+			Ingress: []v3.Rule{
+				{Action: v3.Deny, Source: v3.EntityRule{Selector: blocked}},
+				{Action: v3.Pass},
+			},
+			Egress: []v3.Rule{
+				{Action: v3.Deny, Destination: v3.EntityRule{Selector: blocked}},
+				{Action: v3.Pass},
+			},
+		},
+	}
+}
+
 func (c *intrusionDetectionComponent) intrusionDetectionAnnotations() map[string]string {
 	return c.cfg.TrustedCertBundle.HashAnnotations()
 }
@@ -16,6 +16,8 @@ package render_test
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -661,6 +663,215 @@ var _ = Describe("Intrusion Detection rendering tests", func() {
 		Expect(cr.Rules).NotTo(ContainElements(expectedRules))
 	})
 
+	Context("threat feeds", func() {
+		disabled := operatorv1.ThreatFeedModeDisabled
+
+		BeforeEach(func() {
+			cfg.IntrusionDetection = &operatorv1.IntrusionDetection{Spec: operatorv1.IntrusionDetectionSpec{
+				ThreatFeeds: &operatorv1.ThreatFeeds{
+					BuiltIn: []operatorv1.BuiltInThreatFeed{
+						{Name: operatorv1.BuiltInThreatFeedAlienVaultIPs, Block: true},
+						{Name: operatorv1.BuiltInThreatFeedAlienVaultDomains, Mode: &disabled},
+					},
+					Custom: []operatorv1.CustomThreatFeed{{
+						Name:       "partner-ips",
+						URL:        "https://feeds.example.com/ips.json",
+						JSONPath:   "$.ips",
+						PullPeriod: &metav1.Duration{Duration: time.Hour},
+						Headers: []operatorv1.ThreatFeedHTTPHeader{
+							{Name: "Accept", Value: "application/json"},
+							{Name: "Authorization", SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "partner-feed-token"},
+								Key:                  "token",
+							}},
+						},
+					}},
+				},
+			}}
+			cfg.ThreatFeedSecrets = []*corev1.Secret{{
+				TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "partner-feed-token", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"token": []byte("Bearer abc")},
+			}}
+		})
+
+		It("should render the listed threat feeds and block those that are configured to be blocked", func() {
+			cfg.ThreatFeedsDeployed = []string{"partner-ips", "old-feed"}
+			resources, toDelete := render.IntrusionDetection(cfg).Objects()
+
+			enabled, disabledMode := v3.ThreatFeedModeEnabled, v3.ThreatFeedModeDisabled
+			builtin, custom := v3.ThreatFeedTypeBuiltin, v3.ThreatFeedTypeCustom
+			ips := rtest.GetResource(resources, "tigera.io.threatfeed.alienvault-ips", "", "projectcalico.org", "v3", "GlobalThreatFeed").(*v3.GlobalThreatFeed)
+			Expect(ips.Labels).To(Equal(map[string]string{render.ThreatFeedLabel: "true"}))
+			Expect(ips.Spec.Mode).To(Equal(&enabled))
+			Expect(ips.Spec.FeedType).To(Equal(&builtin))
+			Expect(ips.Spec.Content).To(Equal(v3.ThreatFeedContentIPset))
+			Expect(ips.Spec.GlobalNetworkSet).To(Equal(&v3.GlobalNetworkSetSync{Labels: map[string]string{render.ThreatFeedBlockLabel: "true"}}))
+
+			domains := rtest.GetResource(resources, "tigera.io.threatfeed.alienvault-domains", "", "projectcalico.org", "v3", "GlobalThreatFeed").(*v3.GlobalThreatFeed)
+			Expect(domains.Spec.Mode).To(Equal(&disabledMode))
+			Expect(domains.Spec.Content).To(Equal(v3.ThreatFeedContentDomainNameSet))
+			Expect(domains.Spec.GlobalNetworkSet).To(BeNil())
+
+			partner := rtest.GetResource(resources, "partner-ips", "", "projectcalico.org", "v3", "GlobalThreatFeed").(*v3.GlobalThreatFeed)
+			Expect(partner.Spec.FeedType).To(Equal(&custom))
+			Expect(partner.Spec.GlobalNetworkSet).To(BeNil())
+			Expect(partner.Spec.Pull).To(Equal(&v3.Pull{
+				Period: "1h0m0s",
+				HTTP: &v3.HTTPPull{
+					Format: v3.ThreatFeedFormat{JSON: &v3.ThreatFeedFormatJSON{Path: "$.ips"}},
+					URL:    "https://feeds.example.com/ips.json",
+					Headers: []v3.HTTPHeader{
+						{Name: "Accept", Value: "application/json"},
+						{Name: "Authorization", ValueFrom: &v3.HTTPHeaderSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "tigera.io.threatfeed.partner-feed-token"},
+							Key:                  "token",
+						}}},
+					},
+				},
+			}))
+			Expect(rtest.GetResource(toDelete, "old-feed", "", "projectcalico.org", "v3", "GlobalThreatFeed")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, "partner-ips", "", "projectcalico.org", "v3", "GlobalThreatFeed")).To(BeNil())
+
+			token := rtest.GetResource(resources, "tigera.io.threatfeed.partner-feed-token", render.IntrusionDetectionNamespace, "", "v1", "Secret").(*corev1.Secret)
+			Expect(token.Labels).To(Equal(map[string]string{render.ThreatFeedLabel: "true"}))
+			Expect(token.Data).To(Equal(map[string][]byte{"token": []byte("Bearer abc")}))
+			Expect(rtest.GetResource(resources, "partner-feed-token", render.IntrusionDetectionNamespace, "", "v1", "Secret")).To(BeNil())
+
+			tier := rtest.GetResource(resources, render.ThreatFeedTierName, "", "projectcalico.org", "v3", "Tier").(*v3.Tier)
+			Expect(tier.Labels).To(Equal(map[string]string{render.ThreatFeedLabel: "true"}))
+			Expect(*tier.Spec.Order).To(Equal(110.0))
+			policy := rtest.GetResource(resources, render.ThreatFeedBlockPolicyName, "", "projectcalico.org", "v3", "GlobalNetworkPolicy").(*v3.GlobalNetworkPolicy)
+			Expect(policy.Spec.Tier).To(Equal(render.ThreatFeedTierName))
+			Expect(policy.Spec.Selector).To(Equal("has(projectcalico.org/namespace) && projectcalico.org/namespace != 'calico-system' && !(projectcalico.org/namespace starts_with 'tigera-')"))
+			Expect(policy.Spec.Egress).To(Equal([]v3.Rule{
+				{Action: v3.Deny, Destination: v3.EntityRule{Selector: "operator.tigera.io/threat-feed-block == 'true'"}},
+				{Action: v3.Pass},
+			}))
+			Expect(policy.Spec.Ingress).To(Equal([]v3.Rule{
+				{Action: v3.Deny, Source: v3.EntityRule{Selector: "operator.tigera.io/threat-feed-block == 'true'"}},
+				{Action: v3.Pass},
+			}))
+		})
+
+		It("should remove the blocking policy when no threat feed is blocked", func() {
+			cfg.IntrusionDetection.Spec.ThreatFeeds.BuiltIn[0].Block = false
+			cfg.ThreatFeedTierDeployed = true
+			resources, toDelete := render.IntrusionDetection(cfg).Objects()
+
+			Expect(rtest.GetResource(resources, render.ThreatFeedTierName, "", "projectcalico.org", "v3", "Tier")).To(BeNil())
+			Expect(rtest.GetResource(toDelete, render.ThreatFeedTierName, "", "projectcalico.org", "v3", "Tier")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, render.ThreatFeedBlockPolicyName, "", "projectcalico.org", "v3", "GlobalNetworkPolicy")).NotTo(BeNil())
+		})
+
+		It("should not remove a threat feed tier that the operator did not create", func() {
+			cfg.IntrusionDetection.Spec.ThreatFeeds.BuiltIn[0].Block = false
+			_, toDelete := render.IntrusionDetection(cfg).Objects()
+
+			Expect(rtest.GetResource(toDelete, render.ThreatFeedTierName, "", "projectcalico.org", "v3", "Tier")).To(BeNil())
+			Expect(rtest.GetResource(toDelete, render.ThreatFeedBlockPolicyName, "", "projectcalico.org", "v3", "GlobalNetworkPolicy")).To(BeNil())
+		})
+
+		It("should remove copies of header secrets that are no longer referenced", func() {
+			cfg.ThreatFeedSecretsDeployed = []string{"tigera.io.threatfeed.partner-feed-token", "tigera.io.threatfeed.old-token"}
+			_, toDelete := render.IntrusionDetection(cfg).Objects()
+
+			Expect(rtest.GetResource(toDelete, "tigera.io.threatfeed.old-token", render.IntrusionDetectionNamespace, "", "v1", "Secret")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, "tigera.io.threatfeed.partner-feed-token", render.IntrusionDetectionNamespace, "", "v1", "Secret")).To(BeNil())
+		})
+
+		It("should accept a valid configuration", func() {
+			Expect(render.ValidateThreatFeeds(cfg.IntrusionDetection.Spec.ThreatFeeds)).NotTo(HaveOccurred())
+			Expect(render.ValidateThreatFeeds(nil)).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("should reject invalid threat feeds", func(modify func(*operatorv1.ThreatFeeds)) {
+			feeds := cfg.IntrusionDetection.Spec.ThreatFeeds
+			modify(feeds)
+			Expect(render.ValidateThreatFeeds(feeds)).To(HaveOccurred())
+		},
+			Entry("a duplicate built-in feed", func(f *operatorv1.ThreatFeeds) {
+				f.BuiltIn = append(f.BuiltIn, operatorv1.BuiltInThreatFeed{Name: operatorv1.BuiltInThreatFeedAlienVaultIPs})
+			}),
+			Entry("a blocked domain feed", func(f *operatorv1.ThreatFeeds) { f.BuiltIn[1].Block = true }),
+			Entry("a custom feed with the name of a built-in feed", func(f *operatorv1.ThreatFeeds) { f.Custom[0].Name = "tigera.io.threatfeed.alienvault-ips" }),
+			Entry("a custom feed with the reserved prefix", func(f *operatorv1.ThreatFeeds) { f.Custom[0].Name = "tigera.io.threatfeed.partner" }),
+			Entry("a blocked custom domain feed", func(f *operatorv1.ThreatFeeds) {
+				f.Custom[0].Content = operatorv1.ThreatFeedContentDomainNameSet
+				f.Custom[0].Block = true
+			}),
+			Entry("a header without a value", func(f *operatorv1.ThreatFeeds) { f.Custom[0].Headers[0].Value = "" }),
+			Entry("a header with a value and a secret", func(f *operatorv1.ThreatFeeds) { f.Custom[0].Headers[1].Value = "x" }),
+		)
+	})
+
+	Context("custom alert templates", func() {
+		alertTemplate := func(name string) string {
+			return `apiVersion: projectcalico.org/v3
+kind: GlobalAlertTemplate
+metadata:
+  name: ` + name + `
+  labels:
+    team: soc
+spec:
+  description: Custom detection
+  summary: custom detection triggered
+  severity: 80
+  dataSet: flows
+  query: action=deny
+`
+		}
+
+		It("should render the valid templates held by the ConfigMaps and report the invalid ones", func() {
+			templates, invalid := render.CustomAlertTemplates([]*corev1.ConfigMap{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "soc-templates"},
+					Data: map[string]string{
+						"egress.yaml":  alertTemplate("soc.egress"),
+						"README.md":    "ignored",
+						"builtin.yaml": alertTemplate("policy.pod"),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "more-templates"},
+					Data:       map[string]string{"egress.yaml": alertTemplate("soc.egress")},
+				},
+			})
+			Expect(invalid).To(HaveLen(2))
+			Expect(invalid[0].ConfigMapName).To(Equal("soc-templates"))
+			Expect(invalid[0].Key).To(Equal("builtin.yaml"))
+			Expect(invalid[1].ConfigMapName).To(Equal("more-templates"))
+			Expect(invalid[1].Key).To(Equal("egress.yaml"))
+			Expect(templates).To(HaveLen(1))
+
+			cfg.CustomAlertTemplates = templates
+			cfg.CustomAlertTemplatesDeployed = []string{"soc.egress", "soc.removed"}
+			resources, toDelete := render.IntrusionDetection(cfg).Objects()
+
+			t := rtest.GetResource(resources, "soc.egress", "", "projectcalico.org", "v3", "GlobalAlertTemplate").(*v3.GlobalAlertTemplate)
+			Expect(t.Labels).To(Equal(map[string]string{"team": "soc", render.CustomAlertTemplateLabel: "soc-templates"}))
+			Expect(t.Spec.Severity).To(Equal(80))
+			Expect(t.Spec.Query).To(Equal("action=deny"))
+			Expect(rtest.GetResource(toDelete, "soc.removed", "", "projectcalico.org", "v3", "GlobalAlertTemplate")).NotTo(BeNil())
+			Expect(rtest.GetResource(toDelete, "soc.egress", "", "projectcalico.org", "v3", "GlobalAlertTemplate")).To(BeNil())
+		})
+
+		DescribeTable("should report invalid templates", func(data string) {
+			templates, invalid := render.CustomAlertTemplates([]*corev1.ConfigMap{
+				{ObjectMeta: metav1.ObjectMeta{Name: "soc-templates"}, Data: map[string]string{"template.yaml": data}},
+			})
+			Expect(templates).To(BeEmpty())
+			Expect(invalid).To(HaveLen(1))
+			Expect(invalid[0].Reason).NotTo(BeEmpty())
+		},
+			Entry("not a GlobalAlertTemplate", strings.Replace(alertTemplate("soc.egress"), "GlobalAlertTemplate", "GlobalAlert", 1)),
+			Entry("no name", alertTemplate("")),
+			Entry("no description", strings.Replace(alertTemplate("soc.egress"), "description: Custom detection", "", 1)),
+			Entry("an invalid severity", strings.Replace(alertTemplate("soc.egress"), "severity: 80", "severity: 101", 1)),
+			Entry("an unknown field", alertTemplate("soc.egress")+"  unknown: true\n"),
+		)
+	})
+
 	Context("multi-tenant rendering", func() {
 		tenantANamespace := "tenant-a-ns"
 		tenantBNamespace := "tenant-b-ns"
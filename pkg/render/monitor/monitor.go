@@ -1191,6 +1191,14 @@ func allowTigeraPrometheusPolicy(cfg *Config) *v3.NetworkPolicy {
 				Ports: networkpolicy.Ports(9900),
 			},
 		},
+		{
+			Action:   v3.Allow,
+			Protocol: &networkpolicy.TCPProtocol,
+			Destination: v3.EntityRule{
+				// Egress access for Gateway API proxy metrics
+				Ports: networkpolicy.Ports(19001),
+			},
+		},
 		{
 			Action:   v3.Allow,
 			Protocol: &networkpolicy.TCPProtocol,
@@ -56,6 +56,10 @@ const (
 	bgpLayoutHashAnnotation   = "hash.operator.tigera.io/bgp-layout"
 	bgpBindModeHashAnnotation = "hash.operator.tigera.io/bgp-bind-mode"
 
+	// CalicoNodeTemplateHashAnnotation holds a hash of the calico-node pod template when the operator rolls out
+	// calico-node itself, so that pods which have not been updated yet can be identified.
+	CalicoNodeTemplateHashAnnotation = "hash.operator.tigera.io/calico-node-template"
+
 	BGPLayoutConfigMapName            = "bgp-layout"
 	BGPLayoutConfigMapKey             = "earlyNetworkConfiguration"
 	BGPLayoutVolumeName               = "bgp-layout"
@@ -70,6 +74,18 @@ const (
 	CalicoCNIPluginObjectName     = "calico-cni-plugin"
 	BPFVolumeName                 = "bpffs"
 
+	// MTUDetectContainerName is the calico-node init container that reports the MTU of each host interface in its
+	// termination message, one "<interface> <mtu>" pair per line.
+	MTUDetectContainerName = "mtu-detect"
+
+	// HostInterfacePattern matches the host interfaces that the mtu-detect init container reports. It is the default
+	// value of the MTUIfacePattern setting that Felix uses to detect the host MTU.
+	HostInterfacePattern = `^((en|wl|ww|sl|ib)[Pcopsvx].*|(eth|wlan|wwan).*)`
+
+	// CalicoNodeGroupLabel is set to the name of the node group on the calico-node DaemonSet of each node group and
+	// on its pods.
+	CalicoNodeGroupLabel = "operator.tigera.io/calico-node-group"
+
 	goldmaneDomainName = "goldmane.calico-system.svc"
 )
 
@@ -82,6 +98,57 @@ var (
 	NodeTLSSecretNameNonClusterHost = NodeTLSSecretName + TyphaNonClusterHostSuffix
 )
 
+// CalicoNodeFelixEnvVars lists every FELIX_* environment variable that may be set on the calico-node container. Felix
+// settings from the environment take precedence over the FelixConfiguration, so the matching FelixConfiguration fields
+// are owned by the operator. This must be kept up to date when adding environment variables to calico-node.
+var CalicoNodeFelixEnvVars = []string{
+	"FELIX_AWSSRCDSTCHECK",
+	"FELIX_BPFEXTTOSERVICECONNMARK",
+	"FELIX_DATAPLANEDRIVER",
+	"FELIX_DEFAULTENDPOINTTOHOSTACTION",
+	"FELIX_DNSLOGSFILEENABLED",
+	"FELIX_DNSLOGSFILEPERNODELIMIT",
+	"FELIX_ENDPOINTSTATUSPATHPREFIX",
+	"FELIX_FLOWLOGSCOLLECTPROCESSINFO",
+	"FELIX_FLOWLOGSCOLLECTPROCESSPATH",
+	"FELIX_FLOWLOGSENABLENETWORKSETS",
+	"FELIX_FLOWLOGSFILEENABLED",
+	"FELIX_FLOWLOGSFILEINCLUDELABELS",
+	"FELIX_FLOWLOGSFILEINCLUDEPOLICIES",
+	"FELIX_FLOWLOGSFILEINCLUDESERVICE",
+	"FELIX_FLOWLOGSFLUSHINTERVAL",
+	"FELIX_FLOWLOGSGOLDMANESERVER",
+	"FELIX_HEALTHENABLED",
+	"FELIX_HEALTHPORT",
+	"FELIX_INTERFACEPREFIX",
+	"FELIX_IPINIPMTU",
+	"FELIX_IPTABLESFILTERALLOWACTION",
+	"FELIX_IPTABLESMANGLEALLOWACTION",
+	"FELIX_IPV6SUPPORT",
+	"FELIX_PROMETHEUSMETRICSENABLED",
+	"FELIX_PROMETHEUSMETRICSPORT",
+	"FELIX_PROMETHEUSREPORTERCAFILE",
+	"FELIX_PROMETHEUSREPORTERCERTFILE",
+	"FELIX_PROMETHEUSREPORTERENABLED",
+	"FELIX_PROMETHEUSREPORTERKEYFILE",
+	"FELIX_PROMETHEUSREPORTERPORT",
+	"FELIX_ROUTESOURCE",
+	"FELIX_TYPHACAFILE",
+	"FELIX_TYPHACERTFILE",
+	"FELIX_TYPHACN",
+	"FELIX_TYPHAK8SNAMESPACE",
+	"FELIX_TYPHAK8SSERVICENAME",
+	"FELIX_TYPHAKEYFILE",
+	"FELIX_TYPHAURISAN",
+	"FELIX_USEINTERNALDATAPLANEDRIVER",
+	"FELIX_VXLANMTU",
+	"FELIX_VXLANMTUV6",
+	"FELIX_WIREGUARDHOSTENCRYPTIONENABLED",
+	"FELIX_WIREGUARDMTU",
+	"FELIX_WIREGUARDMTUV6",
+	"FELIX_XDPENABLED",
+}
+
 // TyphaNodeTLS holds configuration for Node and Typha to establish TLS.
 type TyphaNodeTLS struct {
 	TrustedBundle             certificatemanagement.TrustedBundle
@@ -148,6 +215,10 @@ type NodeConfiguration struct {
 	FelixPrometheusMetricsEnabled bool
 
 	FelixPrometheusMetricsPort int
+
+	// RemovedNodeGroups are node groups that still have a calico-node DaemonSet but are no longer in the Installation.
+	// Their DaemonSets are deleted.
+	RemovedNodeGroups []string
 }
 
 // Node creates the node daemonset and other resources for the daemonset to operate normally.
@@ -249,7 +320,17 @@ func (c *nodeComponent) Objects() ([]client.Object, []client.Object) {
 		objs = append(objs, c.clusterAdminClusterRoleBinding())
 	}
 
-	objs = append(objs, c.nodeDaemonset(cniConfig))
+	objs = append(objs, c.nodeDaemonset(cniConfig, nil))
+	for i := range c.cfg.Installation.CalicoNodeGroups {
+		group := &c.cfg.Installation.CalicoNodeGroups[i]
+		objs = append(objs, c.forNodeGroup(group).nodeDaemonset(cniConfig, group))
+	}
+	for _, name := range c.cfg.RemovedNodeGroups {
+		objsToDelete = append(objsToDelete, &appsv1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: CalicoNodeGroupDaemonSetName(name), Namespace: common.CalicoNamespace},
+		})
+	}
 
 	if c.cfg.MigrateNamespaces {
 		objs = append(objs, migration.ClusterRoleForKubeSystemNode())
@@ -890,8 +971,44 @@ func (c *nodeComponent) clusterAdminClusterRoleBinding() *rbacv1.ClusterRoleBind
 	return crb
 }
 
-// nodeDaemonset creates the node daemonset.
-func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.DaemonSet {
+// CalicoNodeGroupDaemonSetName returns the name of the calico-node DaemonSet of the given node group.
+func CalicoNodeGroupDaemonSetName(group string) string {
+	return common.NodeDaemonSetName + "-" + group
+}
+
+// CalicoNodeGroupNameReserved returns true if the DaemonSet of the given node group would take the name of another
+// calico-node object, such as the Windows DaemonSet.
+func CalicoNodeGroupNameReserved(group string) bool {
+	switch CalicoNodeGroupDaemonSetName(group) {
+	case WindowsNodeObjectName, WindowsNodeMetricsService, CalicoNodeMetricsService,
+		NodePrometheusTLSServerSecret, CalicoNodeBGPPasswordsRoleName:
+		return true
+	}
+	return false
+}
+
+// forNodeGroup returns a copy of the component that renders calico-node with the address autodetection settings of
+// the given node group.
+func (c *nodeComponent) forNodeGroup(group *operatorv1.CalicoNodeGroup) *nodeComponent {
+	install := c.cfg.Installation.DeepCopy()
+	if install.CalicoNetwork != nil {
+		if group.NodeAddressAutodetectionV4 != nil {
+			install.CalicoNetwork.NodeAddressAutodetectionV4 = group.NodeAddressAutodetectionV4.DeepCopy()
+		}
+		if group.NodeAddressAutodetectionV6 != nil {
+			install.CalicoNetwork.NodeAddressAutodetectionV6 = group.NodeAddressAutodetectionV6.DeepCopy()
+		}
+	}
+	cfg := *c.cfg
+	cfg.Installation = install
+	gc := *c
+	gc.cfg = &cfg
+	return &gc
+}
+
+// nodeDaemonset creates the node daemonset. If a node group is given, the daemonset only runs on the nodes of the
+// group. Otherwise it runs on all nodes that are not in a node group.
+func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap, group *operatorv1.CalicoNodeGroup) *appsv1.DaemonSet {
 	var terminationGracePeriod int64 = nodeTerminationGracePeriodSeconds
 	var initContainers []corev1.Container
 	nodeContainer := c.nodeContainer()
@@ -943,6 +1060,10 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 		initContainers = append(initContainers, c.hostPathInitContainer())
 	}
 
+	if c.mtuDetectionEnabled() {
+		initContainers = append(initContainers, c.mtuDetectInitContainer())
+	}
+
 	var affinity *corev1.Affinity
 	if c.cfg.Installation.KubernetesProvider.IsAKS() {
 		affinity = &corev1.Affinity{
@@ -1034,18 +1155,165 @@ func (c *nodeComponent) nodeDaemonset(cniCfgMap *corev1.ConfigMap) *appsv1.Daemo
 		migration.LimitDaemonSetToMigratedNodes(&ds)
 	}
 
-	if overrides := c.cfg.Installation.CalicoNodeDaemonSet; overrides != nil {
-		// If the overrides specify the legacy mount-bpffs init container, then we rename it to the new value: ebpf-bootstrap.
-		for index := range rcomp.GetInitContainers(overrides) {
-			if overrides.Spec.Template.Spec.InitContainers[index].Name == "mount-bpffs" {
-				overrides.Spec.Template.Spec.InitContainers[index].Name = "ebpf-bootstrap"
+	applyCalicoNodeDaemonSetOverrides(&ds, c.cfg.Installation.CalicoNodeDaemonSet)
+	if group != nil {
+		applyCalicoNodeGroupOverrides(&ds, group.CalicoNodeDaemonSet)
+		setNodeGroup(&ds, group)
+	} else {
+		excludeNodeGroups(&ds.Spec.Template.Spec, c.cfg.Installation.CalicoNodeGroups)
+	}
+
+	if c.cfg.Installation.NodeRollout != nil {
+		if hash, err := CalicoNodeTemplateHash(&ds.Spec.Template); err != nil {
+			// Without the hash the outdated pods cannot be identified, so leave the rollout to the DaemonSet.
+			log.Error(err, "Failed to hash the calico-node pod template, falling back to a rolling update")
+		} else {
+			// The operator deletes the calico-node pods itself as the rollout progresses, so the DaemonSet must not
+			// replace them on its own.
+			ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+			if ds.Spec.Template.Annotations == nil {
+				ds.Spec.Template.Annotations = map[string]string{}
 			}
+			ds.Spec.Template.Annotations[CalicoNodeTemplateHashAnnotation] = hash
 		}
-		rcomp.ApplyDaemonSetOverrides(&ds, overrides)
 	}
 	return &ds
 }
 
+// applyCalicoNodeDaemonSetOverrides applies the given calico-node DaemonSet overrides, if any.
+func applyCalicoNodeDaemonSetOverrides(ds *appsv1.DaemonSet, overrides *operatorv1.CalicoNodeDaemonSet) {
+	if overrides == nil {
+		return
+	}
+	// If the overrides specify the legacy mount-bpffs init container, then we rename it to the new value: ebpf-bootstrap.
+	for index := range rcomp.GetInitContainers(overrides) {
+		if overrides.Spec.Template.Spec.InitContainers[index].Name == "mount-bpffs" {
+			overrides.Spec.Template.Spec.InitContainers[index].Name = "ebpf-bootstrap"
+		}
+	}
+	rcomp.ApplyDaemonSetOverrides(ds, overrides)
+}
+
+// applyCalicoNodeGroupOverrides applies the resources, environment variables and tolerations configured for a node
+// group, if any.
+func applyCalicoNodeGroupOverrides(ds *appsv1.DaemonSet, overrides *operatorv1.CalicoNodeGroupDaemonSet) {
+	if overrides == nil {
+		return
+	}
+	for i := range ds.Spec.Template.Spec.Containers {
+		c := &ds.Spec.Template.Spec.Containers[i]
+		if c.Name != CalicoNodeObjectName {
+			continue
+		}
+		if overrides.Resources != nil {
+			c.Resources = *overrides.Resources
+		}
+		for _, env := range overrides.Env {
+			replaced := false
+			for j := range c.Env {
+				if c.Env[j].Name == env.Name {
+					c.Env[j] = env
+					replaced = true
+				}
+			}
+			if !replaced {
+				c.Env = append(c.Env, env)
+			}
+		}
+	}
+	if overrides.Tolerations != nil {
+		ds.Spec.Template.Spec.Tolerations = overrides.Tolerations
+	}
+}
+
+// setNodeGroup limits the DaemonSet to the nodes of the given node group. The pods keep the calico-node k8s-app label
+// so that they are treated like any other calico-node pod, and are told apart from the pods of the other calico-node
+// DaemonSets by the node group label.
+func setNodeGroup(ds *appsv1.DaemonSet, group *operatorv1.CalicoNodeGroup) {
+	ds.Name = CalicoNodeGroupDaemonSetName(group.Name)
+	if ds.Labels == nil {
+		ds.Labels = map[string]string{}
+	}
+	ds.Labels[CalicoNodeGroupLabel] = group.Name
+
+	selector := map[string]string{"k8s-app": CalicoNodeObjectName, CalicoNodeGroupLabel: group.Name}
+	ds.Spec.Selector = &metav1.LabelSelector{MatchLabels: selector}
+	if ds.Spec.Template.Labels == nil {
+		ds.Spec.Template.Labels = map[string]string{}
+	}
+	for k, v := range selector {
+		ds.Spec.Template.Labels[k] = v
+	}
+
+	if ds.Spec.Template.Spec.NodeSelector == nil {
+		ds.Spec.Template.Spec.NodeSelector = map[string]string{}
+	}
+	for k, v := range group.NodeSelector {
+		ds.Spec.Template.Spec.NodeSelector[k] = v
+	}
+}
+
+// excludeNodeGroups adds required node affinity to the pod spec so that it is not scheduled on the nodes of any node
+// group. A node is not in a group if it doesn't match one of the group's labels, so the resulting terms are all
+// combinations of one non-matching label per group, each combined with the terms that were already required.
+func excludeNodeGroups(spec *corev1.PodSpec, groups []operatorv1.CalicoNodeGroup) {
+	if len(groups) == 0 {
+		return
+	}
+	// Copy the affinity since it may come from the overrides in the Installation.
+	affinity := spec.Affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+
+	terms := required.NodeSelectorTerms
+	if len(terms) == 0 {
+		terms = []corev1.NodeSelectorTerm{{}}
+	}
+	for _, g := range groups {
+		keys := make([]string, 0, len(g.NodeSelector))
+		for k := range g.NodeSelector {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var next []corev1.NodeSelectorTerm
+		for _, t := range terms {
+			for _, k := range keys {
+				term := t.DeepCopy()
+				term.MatchExpressions = append(term.MatchExpressions, corev1.NodeSelectorRequirement{
+					Key:      k,
+					Operator: corev1.NodeSelectorOpNotIn,
+					Values:   []string{g.NodeSelector[k]},
+				})
+				next = append(next, *term)
+			}
+		}
+		terms = next
+	}
+	required.NodeSelectorTerms = terms
+	spec.Affinity = affinity
+}
+
+// CalicoNodeTemplateHash returns the hash of the calico-node pod template, excluding the template hash annotation.
+func CalicoNodeTemplateHash(template *corev1.PodTemplateSpec) (string, error) {
+	t := template.DeepCopy()
+	delete(t.Annotations, CalicoNodeTemplateHashAnnotation)
+	// The template is hashed in its serialized form since it contains pointers.
+	b, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return rmeta.AnnotationHash(string(b)), nil
+}
+
 // nodeVolumes creates the node's volumes.
 func (c *nodeComponent) nodeVolumes() []corev1.Volume {
 	fileOrCreate := corev1.HostPathFileOrCreate
@@ -1165,6 +1433,21 @@ func (c *nodeComponent) vppDataplaneEnabled() bool {
 		*c.cfg.Installation.CalicoNetwork.LinuxDataplane == operatorv1.LinuxDataplaneVPP
 }
 
+// hostLocalVXLANEnabled returns true if Calico CNI is used with host-local IPAM and one of the IP pools uses VXLAN
+// encapsulation. This is how clusters migrated from flannel are networked.
+func (c *nodeComponent) hostLocalVXLANEnabled() bool {
+	cni := c.cfg.Installation.CNI
+	if cni == nil || cni.Type != operatorv1.PluginCalico || cni.IPAM == nil || cni.IPAM.Type != operatorv1.IPAMPluginHostLocal {
+		return false
+	}
+	for _, pool := range c.cfg.IPPools {
+		if pool.Encapsulation == operatorv1.EncapsulationVXLAN || pool.Encapsulation == operatorv1.EncapsulationVXLANCrossSubnet {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *nodeComponent) collectProcessPathEnabled() bool {
 	return c.cfg.LogCollector != nil &&
 		c.cfg.LogCollector.Spec.CollectProcessPath != nil &&
@@ -1254,6 +1537,28 @@ func (c *nodeComponent) bpfBootstrapInitContainer() corev1.Container {
 	}
 }
 
+// mtuDetectionEnabled returns true if the operator should discover the MTU of the host interfaces.
+func (c *nodeComponent) mtuDetectionEnabled() bool {
+	cn := c.cfg.Installation.CalicoNetwork
+	return cn != nil && cn.MTUDetection != nil && *cn.MTUDetection != operatorv1.MTUDetectionDisabled
+}
+
+// mtuDetectInitContainer creates an init container that writes the MTU of each host interface to its termination
+// message, where the Installation controller picks it up. calico-node runs in the host network namespace, so
+// /sys/class/net lists the host's interfaces. Only the interfaces matching HostInterfacePattern are written, since the
+// termination message is truncated at 4KiB and nodes may have many other interfaces. grep fails when no interface
+// matches, which must not stop calico-node from starting.
+func (c *nodeComponent) mtuDetectInitContainer() corev1.Container {
+	script := fmt.Sprintf(`for i in /sys/class/net/*; do echo "${i##*/} $(cat $i/mtu)"; done | grep -E '%s' > /dev/termination-log || true`, HostInterfacePattern)
+	return corev1.Container{
+		Name:            MTUDetectContainerName,
+		Image:           c.nodeImage,
+		ImagePullPolicy: ImagePullPolicy(),
+		Command:         []string{"sh", "-c", script},
+		SecurityContext: securitycontext.NewNonRootContext(),
+	}
+}
+
 // bpffsEnvvars creates the environment variables for the BPF filesystem init container.
 func (c *nodeComponent) bpffsEnvvars() []corev1.EnvVar {
 	envVars := []corev1.EnvVar{}
@@ -1566,10 +1871,13 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 	// Configure whether or not BGP should be enabled.
 	if !bgpEnabled(c.cfg.Installation) {
 		if c.cfg.Installation.CNI.Type == operatorv1.PluginCalico {
-			if c.cfg.Installation.CNI.IPAM.Type == operatorv1.IPAMPluginHostLocal {
-				// If BGP is disabled and using HostLocal, then that means routing is done
-				// by Cloud routing, so networking backend is none. (because we don't support
-				// vxlan with HostLocal.)
+			if c.hostLocalVXLANEnabled() {
+				// If BGP is disabled and using HostLocal with a VXLAN pool, then routing is done by VXLAN
+				// between the nodes' pod CIDRs.
+				nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "CALICO_NETWORKING_BACKEND", Value: "vxlan"})
+			} else if c.cfg.Installation.CNI.IPAM.Type == operatorv1.IPAMPluginHostLocal {
+				// If BGP is disabled and using HostLocal without VXLAN, then that means routing is done
+				// by Cloud routing, so networking backend is none.
 				nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "CALICO_NETWORKING_BACKEND", Value: "none"})
 			} else {
 				// If BGP is disabled, then set the networking backend to "vxlan". This means that BIRD will be
@@ -1677,16 +1985,8 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 	}
 
 	// Configure provider specific environment variables here.
-	switch c.cfg.Installation.KubernetesProvider {
-	// For AKS/AzureVNET and EKS/VPCCNI, we must explicitly ask felix to add host IP's to wireguard ifaces
-	case operatorv1.ProviderAKS:
-		if c.cfg.Installation.CNI.Type == operatorv1.PluginAzureVNET {
-			nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"})
-		}
-	case operatorv1.ProviderEKS:
-		if c.cfg.Installation.CNI.Type == operatorv1.PluginAmazonVPC {
-			nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"})
-		}
+	if WireGuardHostEncryptionRequired(c.cfg.Installation) {
+		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_WIREGUARDHOSTENCRYPTIONENABLED", Value: "true"})
 	}
 
 	switch c.cfg.Installation.CNI.Type {
@@ -1703,7 +2003,9 @@ func (c *nodeComponent) nodeEnvVars() []corev1.EnvVar {
 		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_INTERFACEPREFIX", Value: "azv"})
 	}
 
-	if c.cfg.Installation.CNI.Type != operatorv1.PluginCalico {
+	// Without Calico IPAM there are no IPAM blocks to derive routes from, so Felix must program routes
+	// based on the workload IPs instead.
+	if c.cfg.Installation.CNI.Type != operatorv1.PluginCalico || c.hostLocalVXLANEnabled() {
 		nodeEnv = append(nodeEnv, corev1.EnvVar{Name: "FELIX_ROUTESOURCE", Value: "WorkloadIPs"})
 	}
 
@@ -1926,6 +2228,21 @@ func bgpEnabled(instance *operatorv1.InstallationSpec) bool {
 		*instance.CalicoNetwork.BGP == operatorv1.BGPEnabled
 }
 
+// WireGuardHostEncryptionRequired returns true if Felix must add the host IPs to the WireGuard interfaces, which is the
+// case for AKS with the AzureVNET CNI and EKS with the AmazonVPC CNI.
+func WireGuardHostEncryptionRequired(instance *operatorv1.InstallationSpec) bool {
+	if instance.CNI == nil {
+		return false
+	}
+	switch instance.KubernetesProvider {
+	case operatorv1.ProviderAKS:
+		return instance.CNI.Type == operatorv1.PluginAzureVNET
+	case operatorv1.ProviderEKS:
+		return instance.CNI.Type == operatorv1.PluginAmazonVPC
+	}
+	return false
+}
+
 // getMTU returns the MTU configured in the Installation if there is one, nil otherwise.
 func getMTU(instance *operatorv1.InstallationSpec) *int32 {
 	var mtu *int32
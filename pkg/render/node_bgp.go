@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	rmeta "github.com/tigera/operator/pkg/render/common/meta"
+	"github.com/tigera/operator/pkg/render/common/secret"
+	"github.com/tigera/operator/pkg/render/common/selector"
+)
+
+const (
+	// BGPPeerNamePrefix is prepended to the names of the BGPPeers created for the peers in the Installation.
+	BGPPeerNamePrefix = "tigera-operator."
+
+	// BGPManagedByLabel marks the BGPPeers and BGP password secrets that are managed by the operator.
+	BGPManagedByLabel      = "app.kubernetes.io/managed-by"
+	BGPManagedByLabelValue = "tigera-operator"
+
+	// BGPPasswordSecretLabel marks the copies of the BGP password secrets in the calico-system namespace.
+	BGPPasswordSecretLabel = "operator.tigera.io/bgp-password"
+
+	CalicoNodeBGPPasswordsRoleName = "calico-node-bgp-passwords"
+)
+
+// NodeBGPConfiguration is the configuration for the BGP peers of calico/node.
+type NodeBGPConfiguration struct {
+	Installation *operatorv1.InstallationSpec
+
+	// PasswordSecrets are the secrets in the operator namespace that are referenced by the BGP peers.
+	PasswordSecrets []*corev1.Secret
+
+	// ExistingPeers and ExistingPasswordSecrets are the names of the BGPPeers and password secrets created by the
+	// operator, so that those no longer needed can be removed.
+	ExistingPeers           []string
+	ExistingPasswordSecrets []string
+}
+
+// NodeBGP returns a component that renders the BGPPeers from the Installation, along with the password secrets that
+// calico/node needs to read for them.
+func NodeBGP(cfg *NodeBGPConfiguration) Component {
+	return &nodeBGPComponent{cfg: cfg}
+}
+
+type nodeBGPComponent struct {
+	cfg *NodeBGPConfiguration
+}
+
+func (c *nodeBGPComponent) ResolveImages(is *operatorv1.ImageSet) error {
+	return nil
+}
+
+func (c *nodeBGPComponent) Objects() ([]client.Object, []client.Object) {
+	var objsToCreate, objsToDelete []client.Object
+
+	desiredPeers := map[string]bool{}
+	if c.cfg.Installation.CalicoNetwork != nil && c.cfg.Installation.CalicoNetwork.BGPConfiguration != nil {
+		for _, peer := range c.cfg.Installation.CalicoNetwork.BGPConfiguration.Peers {
+			p := c.bgpPeer(peer)
+			desiredPeers[p.Name] = true
+			objsToCreate = append(objsToCreate, p)
+		}
+	}
+	for _, name := range c.cfg.ExistingPeers {
+		if !desiredPeers[name] {
+			objsToDelete = append(objsToDelete, &crdv1.BGPPeer{
+				TypeMeta:   metav1.TypeMeta{Kind: "BGPPeer", APIVersion: "crd.projectcalico.org/v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+			})
+		}
+	}
+
+	desiredSecrets := map[string]bool{}
+	var secretNames []string
+	for _, s := range secret.CopyToNamespace(common.CalicoNamespace, c.cfg.PasswordSecrets...) {
+		s.Labels = map[string]string{
+			BGPManagedByLabel:      BGPManagedByLabelValue,
+			BGPPasswordSecretLabel: "true",
+		}
+		desiredSecrets[s.Name] = true
+		secretNames = append(secretNames, s.Name)
+		objsToCreate = append(objsToCreate, s)
+	}
+	for _, name := range c.cfg.ExistingPasswordSecrets {
+		if !desiredSecrets[name] {
+			objsToDelete = append(objsToDelete, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.CalicoNamespace}})
+		}
+	}
+
+	rbac := []client.Object{c.passwordsRole(secretNames), c.passwordsRoleBinding()}
+	if len(secretNames) > 0 {
+		objsToCreate = append(objsToCreate, rbac...)
+	} else {
+		objsToDelete = append(objsToDelete, rbac...)
+	}
+
+	return objsToCreate, objsToDelete
+}
+
+func (c *nodeBGPComponent) Ready() bool {
+	return true
+}
+
+func (c *nodeBGPComponent) SupportedOSType() rmeta.OSType {
+	return rmeta.OSTypeAny
+}
+
+func (c *nodeBGPComponent) bgpPeer(peer operatorv1.BGPPeer) *crdv1.BGPPeer {
+	p := &crdv1.BGPPeer{
+		TypeMeta: metav1.TypeMeta{Kind: "BGPPeer", APIVersion: "crd.projectcalico.org/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   BGPPeerNamePrefix + peer.Name,
+			Labels: map[string]string{BGPManagedByLabel: BGPManagedByLabelValue},
+		},
+		Spec: crdv1.BGPPeerSpec{
+			NodeSelector: selector.CalicoSelector(peer.NodeSelector),
+			PeerIP:       peer.PeerIP,
+			PeerSelector: selector.CalicoSelector(peer.PeerSelector),
+		},
+	}
+	if peer.ASNumber != nil {
+		p.Spec.ASNumber = numorstring.ASNumber(*peer.ASNumber)
+	}
+	if peer.Password != nil {
+		p.Spec.Password = &crdv1.BGPPassword{SecretKeyRef: peer.Password.DeepCopy()}
+	}
+	return p
+}
+
+// passwordsRole allows calico/node to read the BGP password secrets, which it does through the Kubernetes API.
+func (c *nodeBGPComponent) passwordsRole(secretNames []string) *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta:   metav1.TypeMeta{Kind: "Role", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: CalicoNodeBGPPasswordsRoleName, Namespace: common.CalicoNamespace},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"secrets"},
+				ResourceNames: secretNames,
+				Verbs:         []string{"get", "list", "watch"},
+			},
+		},
+	}
+}
+
+func (c *nodeBGPComponent) passwordsRoleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta:   metav1.TypeMeta{Kind: "RoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: CalicoNodeBGPPasswordsRoleName, Namespace: common.CalicoNamespace},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     CalicoNodeBGPPasswordsRoleName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      CalicoNodeObjectName,
+				Namespace: common.CalicoNamespace,
+			},
+		},
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/tigera/operator/api/v1"
+	crdv1 "github.com/tigera/operator/pkg/apis/crd.projectcalico.org/v1"
+	"github.com/tigera/operator/pkg/common"
+	"github.com/tigera/operator/pkg/render"
+	rtest "github.com/tigera/operator/pkg/render/common/test"
+)
+
+var _ = Describe("calico-node BGP rendering tests", func() {
+	var cfg *render.NodeBGPConfiguration
+	asNumber := uint32(64513)
+
+	BeforeEach(func() {
+		cfg = &render.NodeBGPConfiguration{
+			Installation: &operatorv1.InstallationSpec{
+				CalicoNetwork: &operatorv1.CalicoNetworkSpec{
+					BGPConfiguration: &operatorv1.CalicoBGPConfiguration{
+						Peers: []operatorv1.BGPPeer{
+							{
+								Name: "rack-1-tor",
+								NodeSelector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{"rack": "1"},
+									MatchExpressions: []metav1.LabelSelectorRequirement{
+										{Key: "route-reflector", Operator: metav1.LabelSelectorOpDoesNotExist},
+									},
+								},
+								PeerIP:   "10.0.1.1",
+								ASNumber: &asNumber,
+								Password: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "bgp-secrets"}, Key: "rack-1"},
+							},
+							{
+								Name:         "route-reflectors",
+								PeerSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"route-reflector": "true"}},
+							},
+						},
+					},
+				},
+			},
+			PasswordSecrets: []*corev1.Secret{{
+				ObjectMeta: metav1.ObjectMeta{Name: "bgp-secrets", Namespace: common.OperatorNamespace()},
+				Data:       map[string][]byte{"rack-1": []byte("secret")},
+			}},
+		}
+	})
+
+	It("should render the BGP peers and their password secrets", func() {
+		toCreate, toDelete := render.NodeBGP(cfg).Objects()
+
+		tor, err := rtest.GetResourceOfType[*crdv1.BGPPeer](toCreate, "tigera-operator.rack-1-tor", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tor.Labels).To(HaveKeyWithValue(render.BGPManagedByLabel, render.BGPManagedByLabelValue))
+		Expect(tor.Spec.NodeSelector).To(Equal("rack == '1' && !has(route-reflector)"))
+		Expect(tor.Spec.PeerIP).To(Equal("10.0.1.1"))
+		Expect(tor.Spec.ASNumber).To(Equal(numorstring.ASNumber(64513)))
+		Expect(tor.Spec.Password.SecretKeyRef.Name).To(Equal("bgp-secrets"))
+
+		rr, err := rtest.GetResourceOfType[*crdv1.BGPPeer](toCreate, "tigera-operator.route-reflectors", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rr.Spec.NodeSelector).To(BeEmpty())
+		Expect(rr.Spec.PeerSelector).To(Equal("route-reflector == 'true'"))
+
+		s, err := rtest.GetResourceOfType[*corev1.Secret](toCreate, "bgp-secrets", common.CalicoNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Labels).To(HaveKeyWithValue(render.BGPPasswordSecretLabel, "true"))
+		Expect(s.Data).To(Equal(cfg.PasswordSecrets[0].Data))
+
+		role, err := rtest.GetResourceOfType[*rbacv1.Role](toCreate, render.CalicoNodeBGPPasswordsRoleName, common.CalicoNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(role.Rules[0].ResourceNames).To(ConsistOf("bgp-secrets"))
+		_, err = rtest.GetResourceOfType[*rbacv1.RoleBinding](toCreate, render.CalicoNodeBGPPasswordsRoleName, common.CalicoNamespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(toDelete).To(BeEmpty())
+	})
+
+	It("should remove the peers and secrets that are no longer configured", func() {
+		cfg.Installation.CalicoNetwork.BGPConfiguration = nil
+		cfg.PasswordSecrets = nil
+		cfg.ExistingPeers = []string{"tigera-operator.rack-1-tor"}
+		cfg.ExistingPasswordSecrets = []string{"bgp-secrets"}
+
+		toCreate, toDelete := render.NodeBGP(cfg).Objects()
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(4))
+		_, err := rtest.GetResourceOfType[*crdv1.BGPPeer](toDelete, "tigera-operator.rack-1-tor", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rtest.GetResourceOfType[*corev1.Secret](toDelete, "bgp-secrets", common.CalicoNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rtest.GetResourceOfType[*rbacv1.Role](toDelete, render.CalicoNodeBGPPasswordsRoleName, common.CalicoNamespace)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -147,6 +147,25 @@ var _ = Describe("Node rendering tests", func() {
 				}
 			})
 
+			It("should only set FELIX_* env vars that are listed in CalicoNodeFelixEnvVars", func() {
+				mtu := int32(1450)
+				defaultInstance.CalicoNetwork.MTU = &mtu
+				cfg.NodeReporterMetricsPort = 9081
+				cfg.FelixPrometheusMetricsEnabled = true
+				for _, variant := range []operatorv1.ProductVariant{operatorv1.Calico, operatorv1.TigeraSecureEnterprise} {
+					defaultInstance.Variant = variant
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, _ := component.Objects()
+					ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					for _, env := range ds.Spec.Template.Spec.Containers[0].Env {
+						if strings.HasPrefix(env.Name, "FELIX_") {
+							Expect(render.CalicoNodeFelixEnvVars).To(ContainElement(env.Name))
+						}
+					}
+				}
+			})
+
 			It("should render SecurityContextConstrains properly when provider is OpenShift", func() {
 				cfg.Installation.KubernetesProvider = operatorv1.ProviderOpenShift
 				component := render.Node(&cfg)
@@ -2269,6 +2288,110 @@ var _ = Describe("Node rendering tests", func() {
 				Expect(ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable).To(Equal(&two))
 			})
 
+			It("should use the OnDelete update strategy when the operator rolls out calico-node", func() {
+				defaultInstance.NodeRollout = &operatorv1.CalicoNodeRollout{}
+				component := render.Node(&cfg)
+				Expect(component.ResolveImages(nil)).To(BeNil())
+				resources, _ := component.Objects()
+
+				dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
+				Expect(dsResource).ToNot(BeNil())
+				ds := dsResource.(*appsv1.DaemonSet)
+				Expect(ds.Spec.UpdateStrategy).To(Equal(appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}))
+				hash := ds.Spec.Template.Annotations[render.CalicoNodeTemplateHashAnnotation]
+				Expect(hash).NotTo(BeEmpty())
+				Expect(render.CalicoNodeTemplateHash(&ds.Spec.Template)).To(Equal(hash))
+
+				// The hash changes with the pod template.
+				defaultInstance.NodeMetricsPort = ptr.To(int32(9091))
+				resources, _ = render.Node(&cfg).Objects()
+				ds = rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+				Expect(ds.Spec.Template.Annotations[render.CalicoNodeTemplateHashAnnotation]).NotTo(Equal(hash))
+			})
+
+			Describe("node groups", func() {
+				toleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}
+				gpuResources := corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				}
+
+				BeforeEach(func() {
+					defaultInstance.CalicoNodeGroups = []operatorv1.CalicoNodeGroup{
+						{
+							Name:         "gpu",
+							NodeSelector: map[string]string{"pool": "gpu"},
+							CalicoNodeDaemonSet: &operatorv1.CalicoNodeGroupDaemonSet{
+								Resources:   &gpuResources,
+								Env:         []corev1.EnvVar{{Name: "FELIX_DEFAULTENDPOINTTOHOSTACTION", Value: "DROP"}, {Name: "GPU_POOL", Value: "true"}},
+								Tolerations: []corev1.Toleration{toleration},
+							},
+						},
+						{
+							Name:                       "edge",
+							NodeSelector:               map[string]string{"pool": "edge", "zone": "far"},
+							NodeAddressAutodetectionV4: &operatorv1.NodeAddressAutodetection{Interface: "wg0"},
+						},
+					}
+				})
+
+				It("should render a calico-node DaemonSet for each node group", func() {
+					resources, _ := render.Node(&cfg).Objects()
+
+					ds := rtest.GetResource(resources, "calico-node-gpu", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(ds.Labels).To(HaveKeyWithValue(render.CalicoNodeGroupLabel, "gpu"))
+					Expect(ds.Spec.Selector.MatchLabels).To(Equal(map[string]string{"k8s-app": "calico-node", render.CalicoNodeGroupLabel: "gpu"}))
+					Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue("k8s-app", "calico-node"))
+					Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue(render.CalicoNodeGroupLabel, "gpu"))
+					Expect(ds.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("pool", "gpu"))
+					Expect(ds.Spec.Template.Spec.Tolerations).To(ConsistOf(toleration))
+					node := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "calico-node")
+					Expect(node.Resources).To(Equal(gpuResources))
+					rtest.ExpectEnv(node.Env, "FELIX_DEFAULTENDPOINTTOHOSTACTION", "DROP")
+					rtest.ExpectEnv(node.Env, "GPU_POOL", "true")
+					gpuEnv := node.Env
+
+					ds = rtest.GetResource(resources, "calico-node-edge", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(ds.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("pool", "edge"))
+					Expect(ds.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("zone", "far"))
+					edgeEnv := ds.Spec.Template.Spec.Containers[0].Env
+
+					// The group settings are not applied to the default DaemonSet.
+					ds = rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					Expect(ds.Spec.Selector).To(BeNil())
+					Expect(ds.Spec.Template.Spec.Tolerations).NotTo(ContainElement(toleration))
+
+					if enableIPv4 {
+						rtest.ExpectEnv(gpuEnv, "IP_AUTODETECTION_METHOD", "first-found")
+						rtest.ExpectEnv(edgeEnv, "IP_AUTODETECTION_METHOD", "interface=wg0")
+						rtest.ExpectEnv(ds.Spec.Template.Spec.Containers[0].Env, "IP_AUTODETECTION_METHOD", "first-found")
+					}
+				})
+
+				It("should keep the default calico-node DaemonSet off the nodes of the node groups", func() {
+					defaultInstance.KubernetesProvider = operatorv1.ProviderEKS
+					defaultCNIConfDir, defaultCNIBinDir := render.DefaultCNIDirectories(defaultInstance.KubernetesProvider)
+					defaultInstance.CNI.ConfDir, defaultInstance.CNI.BinDir = &defaultCNIConfDir, &defaultCNIBinDir
+					resources, _ := render.Node(&cfg).Objects()
+
+					ds := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet").(*appsv1.DaemonSet)
+					fargate := corev1.NodeSelectorRequirement{Key: "eks.amazonaws.com/compute-type", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"fargate"}}
+					notIn := func(key, value string) corev1.NodeSelectorRequirement {
+						return corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpNotIn, Values: []string{value}}
+					}
+					Expect(ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{fargate, notIn("pool", "gpu"), notIn("pool", "edge")}},
+						{MatchExpressions: []corev1.NodeSelectorRequirement{fargate, notIn("pool", "gpu"), notIn("zone", "far")}},
+					}))
+				})
+
+				It("should delete the DaemonSets of removed node groups", func() {
+					cfg.RemovedNodeGroups = []string{"cpu"}
+					resources, toDelete := render.Node(&cfg).Objects()
+					Expect(rtest.GetResource(resources, "calico-node-gpu", "calico-system", "apps", "v1", "DaemonSet")).NotTo(BeNil())
+					Expect(rtest.GetResource(toDelete, "calico-node-cpu", "calico-system", "apps", "v1", "DaemonSet")).NotTo(BeNil())
+				})
+			})
+
 			It("should render LinuxPolicySetupTimeoutSeconds if a custom value was set", func() {
 				two := int32(2)
 				defaultInstance.CalicoNetwork.LinuxPolicySetupTimeoutSeconds = &two
@@ -2290,6 +2413,31 @@ var _ = Describe("Node rendering tests", func() {
 				}
 			})
 
+			It("should render the mtu-detect init container only when MTU detection is enabled", func() {
+				getDS := func() *appsv1.DaemonSet {
+					component := render.Node(&cfg)
+					Expect(component.ResolveImages(nil)).To(BeNil())
+					resources, _ := component.Objects()
+					dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
+					Expect(dsResource).ToNot(BeNil())
+					return dsResource.(*appsv1.DaemonSet)
+				}
+				Expect(rtest.GetContainer(getDS().Spec.Template.Spec.InitContainers, render.MTUDetectContainerName)).To(BeNil())
+
+				disabled := operatorv1.MTUDetectionDisabled
+				defaultInstance.CalicoNetwork.MTUDetection = &disabled
+				Expect(rtest.GetContainer(getDS().Spec.Template.Spec.InitContainers, render.MTUDetectContainerName)).To(BeNil())
+
+				report := operatorv1.MTUDetectionReport
+				defaultInstance.CalicoNetwork.MTUDetection = &report
+				ds := getDS()
+				mtuDetect := rtest.GetContainer(ds.Spec.Template.Spec.InitContainers, render.MTUDetectContainerName)
+				Expect(mtuDetect).NotTo(BeNil())
+				Expect(mtuDetect.Image).To(Equal(rtest.GetContainer(ds.Spec.Template.Spec.Containers, "calico-node").Image))
+				Expect(mtuDetect.Command[2]).To(ContainSubstring("/dev/termination-log"))
+				Expect(mtuDetect.Command[2]).To(ContainSubstring("grep -E '" + render.HostInterfacePattern + "'"))
+			})
+
 			It("should render cni config without portmap when HostPorts disabled", func() {
 				expectedResources := []struct {
 					name    string
@@ -2711,6 +2859,33 @@ var _ = Describe("Node rendering tests", func() {
 }`))
 			})
 
+			It("should render calico-node with the VXLAN backend for host-local IPAM with a VXLAN pool", func() {
+				bgpDisabled := operatorv1.BGPDisabled
+				defaultInstance.CNI.IPAM.Type = operatorv1.IPAMPluginHostLocal
+				defaultInstance.CalicoNetwork.BGP = &bgpDisabled
+				defaultInstance.CalicoNetwork.IPPools = []operatorv1.IPPool{
+					{
+						CIDR:          "10.244.0.0/16",
+						Encapsulation: operatorv1.EncapsulationVXLAN,
+						NATOutgoing:   operatorv1.NATOutgoingEnabled,
+						NodeSelector:  "all()",
+					},
+				}
+				cfg.IPPools = defaultInstance.CalicoNetwork.IPPools
+
+				component := render.Node(&cfg)
+				Expect(component.ResolveImages(nil)).To(BeNil())
+				resources, _ := component.Objects()
+
+				dsResource := rtest.GetResource(resources, "calico-node", "calico-system", "apps", "v1", "DaemonSet")
+				Expect(dsResource).ToNot(BeNil())
+				ds := dsResource.(*appsv1.DaemonSet)
+				nodeContainer := rtest.GetContainer(ds.Spec.Template.Spec.Containers, "calico-node")
+				rtest.ExpectEnv(nodeContainer.Env, "CALICO_NETWORKING_BACKEND", "vxlan")
+				rtest.ExpectEnv(nodeContainer.Env, "USE_POD_CIDR", "true")
+				rtest.ExpectEnv(nodeContainer.Env, "FELIX_ROUTESOURCE", "WorkloadIPs")
+			})
+
 			It("should render cni config with k8s endpoint", func() {
 				k8sServiceEp.Host = "k8shost"
 				k8sServiceEp.Port = "1234"
@@ -15,6 +15,8 @@
 package nonclusterhost
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,10 +31,45 @@ import (
 
 const (
 	NonClusterHostObjectName = "tigera-noncluster-host"
+
+	// EnrollmentObjectName is the name of the ClusterRole and ClusterRoleBinding that allow enrollment tokens to
+	// request certificates.
+	EnrollmentObjectName = NonClusterHostObjectName + "-enrollment"
+
+	// HostnameLabel is set by non-cluster hosts on their certificate signing requests.
+	HostnameLabel = "nonclusterhost.tigera.io/hostname"
+
+	// EnrollmentLabel is set to the hostname on the objects that hold the enrollment token of a host.
+	EnrollmentLabel = "operator.tigera.io/noncluster-host-enrollment"
 )
 
 type Config struct {
 	NonClusterHost operatorv1.NonClusterHostSpec
+
+	// PendingHosts are the hosts that need an enrollment token.
+	PendingHosts []string
+
+	// StaleEnrollments are the hosts that have an enrollment token that is no longer needed, including the hosts
+	// that have used their token to enroll.
+	StaleEnrollments []string
+
+	// EnrollmentRBACDeployed is true if the enrollment ClusterRole is present in the cluster.
+	EnrollmentRBACDeployed bool
+}
+
+// EnrollmentName returns the name of the ServiceAccount and Secret that hold the enrollment token of a host.
+func EnrollmentName(hostname string) string {
+	return fmt.Sprintf("%s-%s", EnrollmentObjectName, hostname)
+}
+
+// Username returns the user that an enrolled host authenticates as when it uses the non-cluster host token.
+func Username() string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", common.CalicoNamespace, NonClusterHostObjectName)
+}
+
+// EnrollmentUsername returns the user that a host authenticates as when it uses its enrollment token.
+func EnrollmentUsername(hostname string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", common.CalicoNamespace, EnrollmentName(hostname))
 }
 
 func NonClusterHost(cfg *Config) render.Component {
@@ -60,7 +97,20 @@ func (c *nonClusterHostComponent) Objects() ([]client.Object, []client.Object) {
 		c.clusterRole(),
 		c.clusterRoleBinding(),
 	}
-	return toCreate, nil
+	var toDelete []client.Object
+
+	for _, host := range c.cfg.PendingHosts {
+		toCreate = append(toCreate, c.enrollmentServiceAccount(host), c.enrollmentTokenSecret(host))
+	}
+	for _, host := range c.cfg.StaleEnrollments {
+		toDelete = append(toDelete, c.enrollmentServiceAccount(host), c.enrollmentTokenSecret(host))
+	}
+	if len(c.cfg.PendingHosts) > 0 {
+		toCreate = append(toCreate, c.enrollmentClusterRole(), c.enrollmentClusterRoleBinding())
+	} else if c.cfg.EnrollmentRBACDeployed {
+		toDelete = append(toDelete, c.enrollmentClusterRole(), c.enrollmentClusterRoleBinding())
+	}
+	return toCreate, toDelete
 }
 
 func (c *nonClusterHostComponent) Ready() bool {
@@ -247,3 +297,84 @@ func (c *nonClusterHostComponent) clusterRoleBinding() *rbacv1.ClusterRoleBindin
 		},
 	}
 }
+
+func (c *nonClusterHostComponent) enrollmentServiceAccount(hostname string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EnrollmentName(hostname),
+			Namespace: common.CalicoNamespace,
+			Labels:    map[string]string{EnrollmentLabel: hostname},
+		},
+	}
+}
+
+func (c *nonClusterHostComponent) enrollmentTokenSecret(hostname string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EnrollmentName(hostname),
+			Namespace: common.CalicoNamespace,
+			Labels:    map[string]string{EnrollmentLabel: hostname},
+			// The token is revoked when the service account is deleted, once the host has enrolled, has been revoked,
+			// or the token has expired before the host enrolled.
+			Annotations: map[string]string{
+				"kubernetes.io/service-account.name": EnrollmentName(hostname),
+			},
+		},
+		Type: "kubernetes.io/service-account-token",
+	}
+}
+
+// enrollmentClusterRole only allows the holder of an enrollment token to request the non-cluster host certificate
+// it enrolls with.
+func (c *nonClusterHostComponent) enrollmentClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: EnrollmentObjectName,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"certificates.k8s.io"},
+				Resources: []string{"certificatesigningrequests"},
+				Verbs:     []string{"create", "list", "watch"},
+			},
+			{
+				APIGroups:     []string{"certificates.tigera.io"},
+				Resources:     []string{"certificatesigningrequests/common-name"},
+				Verbs:         []string{"create"},
+				ResourceNames: []string{render.TyphaCommonName + render.TyphaNonClusterHostSuffix},
+			},
+		},
+	}
+}
+
+func (c *nonClusterHostComponent) enrollmentClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	subjects := []rbacv1.Subject{}
+	for _, host := range c.cfg.PendingHosts {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:      "ServiceAccount",
+			Name:      EnrollmentName(host),
+			Namespace: common.CalicoNamespace,
+		})
+	}
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: EnrollmentObjectName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     EnrollmentObjectName,
+		},
+		Subjects: subjects,
+	}
+}
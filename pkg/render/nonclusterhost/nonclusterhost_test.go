@@ -165,4 +165,39 @@ var _ = Describe("NonClusterHost rendering tests", func() {
 			},
 		))
 	})
+
+	It("should render an enrollment token for each pending host", func() {
+		cfg.PendingHosts = []string{"host-a", "host-b"}
+		cfg.StaleEnrollments = []string{"host-c"}
+
+		toCreate, toDelete := nonclusterhost.NonClusterHost(cfg).Objects()
+		for _, host := range []string{"host-a", "host-b"} {
+			name := "tigera-noncluster-host-enrollment-" + host
+			sa := rtest.GetResource(toCreate, name, "calico-system", "", "v1", "ServiceAccount").(*corev1.ServiceAccount)
+			Expect(sa.Labels).To(HaveKeyWithValue("operator.tigera.io/noncluster-host-enrollment", host))
+			secret := rtest.GetResource(toCreate, name, "calico-system", "", "v1", "Secret").(*corev1.Secret)
+			Expect(secret.Annotations).To(HaveKeyWithValue("kubernetes.io/service-account.name", name))
+		}
+
+		crb := rtest.GetResource(toCreate, "tigera-noncluster-host-enrollment", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding").(*rbacv1.ClusterRoleBinding)
+		Expect(crb.Subjects).To(ConsistOf(
+			rbacv1.Subject{Kind: "ServiceAccount", Name: "tigera-noncluster-host-enrollment-host-a", Namespace: "calico-system"},
+			rbacv1.Subject{Kind: "ServiceAccount", Name: "tigera-noncluster-host-enrollment-host-b", Namespace: "calico-system"},
+		))
+		clusterRole := rtest.GetResource(toCreate, "tigera-noncluster-host-enrollment", "", "rbac.authorization.k8s.io", "v1", "ClusterRole").(*rbacv1.ClusterRole)
+		Expect(clusterRole.Rules).To(HaveLen(2))
+
+		Expect(toDelete).To(HaveLen(2))
+		Expect(rtest.GetResource(toDelete, "tigera-noncluster-host-enrollment-host-c", "calico-system", "", "v1", "ServiceAccount")).NotTo(BeNil())
+		Expect(rtest.GetResource(toDelete, "tigera-noncluster-host-enrollment-host-c", "calico-system", "", "v1", "Secret")).NotTo(BeNil())
+	})
+
+	It("should remove the enrollment RBAC when no hosts are pending", func() {
+		cfg.EnrollmentRBACDeployed = true
+
+		_, toDelete := nonclusterhost.NonClusterHost(cfg).Objects()
+		Expect(toDelete).To(HaveLen(2))
+		Expect(rtest.GetResource(toDelete, "tigera-noncluster-host-enrollment", "", "rbac.authorization.k8s.io", "v1", "ClusterRole")).NotTo(BeNil())
+		Expect(rtest.GetResource(toDelete, "tigera-noncluster-host-enrollment", "", "rbac.authorization.k8s.io", "v1", "ClusterRoleBinding")).NotTo(BeNil())
+	})
 })
@@ -17,6 +17,7 @@ package render
 import (
 	"crypto/x509"
 	"fmt"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -52,8 +53,14 @@ const (
 	PolicyRecommendationTLSSecretName                                   = "policy-recommendation-tls"
 	PolicyRecommendationMultiTenantManagedClustersAccessRoleBindingName = "tigera-policy-recommendation-managed-cluster-access"
 	PolicyRecommendationManagedClustersWatchRoleBindingName             = "tigera-policy-recommendation-managed-cluster-watch"
+
+	// PolicyRecommendationScopeName is the name of the PolicyRecommendationScope read by the recommendation engine.
+	PolicyRecommendationScopeName = "default"
 )
 
+// policyRecommendationExcludedNamespacePrefixes are the prefixes of namespaces that never receive recommendations.
+var policyRecommendationExcludedNamespacePrefixes = []string{"tigera-", "calico-", "kube-"}
+
 // Register secret/certs that need Server and Client Key usage
 func init() {
 	certkeyusage.SetCertKeyUsage(PolicyRecommendationTLSSecretName, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth})
@@ -112,6 +119,12 @@ func (pr *policyRecommendationComponent) Objects() ([]client.Object, []client.Ob
 
 	var objs []client.Object
 
+	// The recommendation engine reads the scope from the cluster it generates recommendations for, so
+	// it is rendered for managed clusters as well.
+	if scope := pr.policyRecommendationScope(); scope != nil {
+		objs = append(objs, scope)
+	}
+
 	// Guardian has RBAC permissions to handle policy recommendation requests in managed clusters,
 	// so clean up the resources left behind in older clusters during upgrade.
 	if pr.cfg.ManagedCluster {
@@ -120,14 +133,14 @@ func (pr *policyRecommendationComponent) Objects() ([]client.Object, []client.Ob
 
 	// Management and managed clusters need API access to the resources defined in the policy
 	// recommendation cluster role
-	objs = []client.Object{
+	objs = append(objs,
 		pr.allowTigeraPolicyForPolicyRecommendation(),
 		pr.serviceAccount(),
 		pr.clusterRole(),
 		pr.clusterRoleBinding(),
 		pr.managedClustersWatchRoleBinding(),
 		pr.deployment(),
-	}
+	)
 	if pr.cfg.Tenant.MultiTenant() {
 		objs = append(objs, pr.multiTenantManagedClustersAccess()...)
 	}
@@ -442,6 +455,63 @@ func (pr *policyRecommendationComponent) allowTigeraPolicyForPolicyRecommendatio
 	}
 }
 
+// PolicyRecommendationNamespaceSelector returns the namespace selector of the PolicyRecommendationScope. Namespaces
+// belonging to Calico, Tigera, Kubernetes and, on OpenShift, OpenShift itself are always excluded. If include is not
+// empty, only the listed namespaces are selected.
+func PolicyRecommendationNamespaceSelector(openShift bool, include, exclude []string) string {
+	prefixes := policyRecommendationExcludedNamespacePrefixes
+	if openShift {
+		prefixes = append(prefixes[:len(prefixes):len(prefixes)], "openshift-")
+	}
+
+	var terms []string
+	if len(include) > 0 {
+		terms = append(terms, fmt.Sprintf("projectcalico.org/name in {%s}", quotedNamespaceList(include)))
+	}
+	for _, prefix := range prefixes {
+		terms = append(terms, fmt.Sprintf("!(projectcalico.org/name starts with '%s')", prefix))
+	}
+	if len(exclude) > 0 {
+		terms = append(terms, fmt.Sprintf("!(projectcalico.org/name in {%s})", quotedNamespaceList(exclude)))
+	}
+	return strings.Join(terms, " && ")
+}
+
+func quotedNamespaceList(namespaces []string) string {
+	quoted := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		quoted[i] = fmt.Sprintf("'%s'", ns)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// policyRecommendationScope returns the PolicyRecommendationScope configured by the PolicyRecommendation CR, or nil
+// if the CR does not configure a scope.
+func (pr *policyRecommendationComponent) policyRecommendationScope() *v3.PolicyRecommendationScope {
+	if pr.cfg.PolicyRecommendation == nil || pr.cfg.PolicyRecommendation.Spec.Scope == nil || pr.cfg.Tenant.MultiTenant() {
+		return nil
+	}
+	scope := pr.cfg.PolicyRecommendation.Spec.Scope
+
+	recStatus := v3.PolicyRecommendationScopeDisabled
+	if scope.Mode != nil && *scope.Mode != operatorv1.PolicyRecommendationModeDisabled {
+		recStatus = v3.PolicyRecommendationScopeEnabled
+	}
+
+	return &v3.PolicyRecommendationScope{
+		TypeMeta:   metav1.TypeMeta{Kind: "PolicyRecommendationScope", APIVersion: "projectcalico.org/v3"},
+		ObjectMeta: metav1.ObjectMeta{Name: PolicyRecommendationScopeName},
+		Spec: v3.PolicyRecommendationScopeSpec{
+			Interval:            scope.Interval,
+			StabilizationPeriod: scope.StabilizationPeriod,
+			NamespaceSpec: v3.PolicyRecommendationScopeNamespaceSpec{
+				RecStatus: recStatus,
+				Selector:  PolicyRecommendationNamespaceSelector(pr.cfg.OpenShift, scope.IncludeNamespaces, scope.ExcludeNamespaces),
+			},
+		},
+	}
+}
+
 func (pr *policyRecommendationComponent) deprecatedObjects(isManagedCluster bool) []client.Object {
 
 	var deprecatedObjs []client.Object
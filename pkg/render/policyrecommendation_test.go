@@ -16,6 +16,7 @@ package render_test
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -432,6 +433,55 @@ var _ = Describe("Policy recommendation rendering tests", func() {
 			func(envVar corev1.EnvVar) string { return envVar.Name }, Equal("MANAGED_CLUSTER_TYPE"))))
 	})
 
+	Context("policy recommendation scope", func() {
+		It("should not render a PolicyRecommendationScope when the CR does not configure one", func() {
+			cfg.PolicyRecommendation = &operatorv1.PolicyRecommendation{}
+			resources, _ := render.PolicyRecommendation(cfg).Objects()
+			Expect(rtest.GetResource(resources, render.PolicyRecommendationScopeName, "", "projectcalico.org", "v3", "PolicyRecommendationScope")).To(BeNil())
+		})
+
+		It("should render the PolicyRecommendationScope from the CR", func() {
+			mode := operatorv1.PolicyRecommendationModeLearn
+			cfg.PolicyRecommendation = &operatorv1.PolicyRecommendation{
+				Spec: operatorv1.PolicyRecommendationSpec{
+					Scope: &operatorv1.PolicyRecommendationScopeConfig{
+						Mode:                &mode,
+						IncludeNamespaces:   []string{"app-a", "app-b"},
+						ExcludeNamespaces:   []string{"app-c"},
+						StabilizationPeriod: &metav1.Duration{Duration: 30 * time.Minute},
+						Interval:            &metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+			}
+			resources, _ := render.PolicyRecommendation(cfg).Objects()
+
+			scope := rtest.GetResource(resources, render.PolicyRecommendationScopeName, "", "projectcalico.org", "v3", "PolicyRecommendationScope").(*v3.PolicyRecommendationScope)
+			Expect(scope.Spec.NamespaceSpec.RecStatus).To(Equal(v3.PolicyRecommendationScopeEnabled))
+			Expect(scope.Spec.NamespaceSpec.Selector).To(Equal("projectcalico.org/name in {'app-a', 'app-b'} && " +
+				"!(projectcalico.org/name starts with 'tigera-') && !(projectcalico.org/name starts with 'calico-') && " +
+				"!(projectcalico.org/name starts with 'kube-') && !(projectcalico.org/name in {'app-c'})"))
+			Expect(scope.Spec.StabilizationPeriod.Duration).To(Equal(30 * time.Minute))
+			Expect(scope.Spec.Interval.Duration).To(Equal(5 * time.Minute))
+		})
+
+		It("should render a disabled PolicyRecommendationScope excluding OpenShift namespaces by default", func() {
+			cfg.OpenShift = true
+			cfg.ManagedCluster = true
+			cfg.PolicyRecommendation = &operatorv1.PolicyRecommendation{
+				Spec: operatorv1.PolicyRecommendationSpec{Scope: &operatorv1.PolicyRecommendationScopeConfig{}},
+			}
+			resources, _ := render.PolicyRecommendation(cfg).Objects()
+
+			Expect(resources).To(HaveLen(1))
+			scope := rtest.GetResource(resources, render.PolicyRecommendationScopeName, "", "projectcalico.org", "v3", "PolicyRecommendationScope").(*v3.PolicyRecommendationScope)
+			Expect(scope.Spec.NamespaceSpec.RecStatus).To(Equal(v3.PolicyRecommendationScopeDisabled))
+			Expect(scope.Spec.NamespaceSpec.Selector).To(Equal("!(projectcalico.org/name starts with 'tigera-') && " +
+				"!(projectcalico.org/name starts with 'calico-') && !(projectcalico.org/name starts with 'kube-') && " +
+				"!(projectcalico.org/name starts with 'openshift-')"))
+			Expect(scope.Spec.Interval).To(BeNil())
+		})
+	})
+
 	Context("allow-tigera rendering", func() {
 		policyName := types.NamespacedName{Name: "allow-tigera.tigera-policy-recommendation", Namespace: "calico-system"}
 
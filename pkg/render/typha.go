@@ -16,6 +16,7 @@ package render
 
 import (
 	"fmt"
+	"net"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -806,15 +807,25 @@ func typhaNonClusterHostAllowTigeraPolicy(cfg *TyphaConfiguration) *v3.NetworkPo
 		},
 	}...)
 
-	ingressRules := []v3.Rule{
-		{
-			Action:   v3.Allow,
+	ingressRules := []v3.Rule{}
+	if nets := RevokedNonClusterHostNets(cfg.NonClusterHost); len(nets) > 0 {
+		// Refuse connections from non-cluster hosts that have been revoked.
+		ingressRules = append(ingressRules, v3.Rule{
+			Action:   v3.Deny,
 			Protocol: &networkpolicy.TCPProtocol,
+			Source:   v3.EntityRule{Nets: nets},
 			Destination: v3.EntityRule{
-				Ports: networkpolicy.Ports(uint16(TyphaPort), uint16(typhaHealthPort(cfg))),
+				Ports: networkpolicy.Ports(uint16(TyphaPort)),
 			},
-		},
+		})
 	}
+	ingressRules = append(ingressRules, v3.Rule{
+		Action:   v3.Allow,
+		Protocol: &networkpolicy.TCPProtocol,
+		Destination: v3.EntityRule{
+			Ports: networkpolicy.Ports(uint16(TyphaPort), uint16(typhaHealthPort(cfg))),
+		},
+	})
 
 	if r, err := cfg.K8sServiceEp.DestinationEntityRule(); r != nil && err == nil {
 		egressRules = append(egressRules, v3.Rule{
@@ -840,3 +851,35 @@ func typhaNonClusterHostAllowTigeraPolicy(cfg *TyphaConfiguration) *v3.NetworkPo
 		},
 	}
 }
+
+// RevokedNonClusterHostNets returns the addresses of the revoked non-cluster hosts, as CIDRs.
+func RevokedNonClusterHostNets(nch *operatorv1.NonClusterHost) []string {
+	if nch == nil {
+		return nil
+	}
+	var nets []string
+	for _, h := range nch.Status.Hosts {
+		if h.State != operatorv1.NonClusterHostRevoked {
+			continue
+		}
+		nets = append(nets, NonClusterHostNets(h)...)
+	}
+	return nets
+}
+
+// NonClusterHostNets returns the addresses of the HostEndpoint of a non-cluster host, as CIDRs.
+func NonClusterHostNets(h operatorv1.NonClusterHostInventory) []string {
+	var nets []string
+	for _, addr := range h.Addresses {
+		if ip := net.ParseIP(addr); ip != nil {
+			if ip.To4() != nil {
+				nets = append(nets, addr+"/32")
+			} else {
+				nets = append(nets, addr+"/128")
+			}
+		} else if _, _, err := net.ParseCIDR(addr); err == nil {
+			nets = append(nets, addr)
+		}
+	}
+	return nets
+}
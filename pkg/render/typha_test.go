@@ -21,6 +21,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gstruct"
 
+	v3 "github.com/tigera/api/pkg/apis/projectcalico/v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -501,6 +502,24 @@ var _ = Describe("Typha rendering tests", func() {
 		}))
 	})
 
+	It("should refuse Typha connections from revoked non-cluster hosts", func() {
+		policy := func() *v3.NetworkPolicy {
+			objs, _ := render.NewTyphaNonClusterHostPolicy(&cfg).Objects()
+			return rtest.GetResource(objs, "allow-tigera.typha-noncluster-host-access", "calico-system", "projectcalico.org", "v3", "NetworkPolicy").(*v3.NetworkPolicy)
+		}
+		Expect(policy().Spec.Ingress).To(HaveLen(1))
+
+		cfg.NonClusterHost.Status.Hosts = []operatorv1.NonClusterHostInventory{
+			{Hostname: "host-a", State: operatorv1.NonClusterHostEnrolled, Addresses: []string{"10.0.0.1"}},
+			{Hostname: "host-b", State: operatorv1.NonClusterHostRevoked, Addresses: []string{"10.0.0.2", "fd00::2"}},
+		}
+		ingress := policy().Spec.Ingress
+		Expect(ingress).To(HaveLen(2))
+		Expect(ingress[0].Action).To(Equal(v3.Action(v3.Deny)))
+		Expect(ingress[0].Source.Nets).To(Equal([]string{"10.0.0.2/32", "fd00::2/128"}))
+		Expect(ingress[1].Action).To(Equal(v3.Action(v3.Allow)))
+	})
+
 	Context("With typha deployment overrides", func() {
 		rr1 := corev1.ResourceRequirements{
 			Limits: corev1.ResourceList{
@@ -83,7 +83,7 @@ var _ = Describe("pkg/active with apiserver", func() {
 		defer cancel()
 		finished := false
 		go func() {
-			active.WaitUntilActive(cs, c, ctx, log)
+			active.WaitUntilActive(cs, c, ctx, log, nil)
 			finished = true
 		}()
 
@@ -111,7 +111,7 @@ var _ = Describe("pkg/active with apiserver", func() {
 		})).ShouldNot(HaveOccurred())
 		finished := false
 		go func() {
-			active.WaitUntilActive(cs, c, ctx, log)
+			active.WaitUntilActive(cs, c, ctx, log, nil)
 			finished = true
 		}()
 